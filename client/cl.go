@@ -19,31 +19,52 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/crypto/encryption"
 	pb "github.com/xlab-si/emmy/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// revocationTokenMetadata is the key of the gRPC metadata holding the token which
+// permits the revocation of credentials (see server.CL_REVOCATION_TOKEN_METADATA).
+const revocationTokenMetadata = "revocation-token"
+
 type CLClient struct {
 	genericClient
 	grpcClient pb.CLClient
+	// IDs of the public keys of the inspectors for which the attributes can be escrowed
+	trustedInspectors map[string]bool
 }
 
 func NewCLClient(conn *grpc.ClientConn) (*CLClient, error) {
 	return &CLClient{
-		genericClient: newGenericClient(),
-		grpcClient:    pb.NewCLClient(conn),
+		genericClient:     newGenericClient(),
+		grpcClient:        pb.NewCLClient(conn),
+		trustedInspectors: make(map[string]bool),
 	}, nil
 }
 
-func (c *CLClient) GetCredentialStructure() (*cl.RawCred, error) {
-	cred, err := c.grpcClient.GetCredentialStructure(context.Background(), &empty.Empty{})
+// AddTrustedInspector allows the server to require that an attribute is escrowed (encrypted)
+// under the given public key of an inspector. Proofs with escrow under other keys are refused,
+// as the server could otherwise decrypt the attribute itself.
+func (c *CLClient) AddTrustedInspector(pubKey *encryption.CSPaillierPubKey) {
+	c.trustedInspectors[pubKey.GetID()] = true
+}
+
+// GetCredentialStructure returns the structure of the credentials issued by the organization
+// with the given name.
+func (c *CLClient) GetCredentialStructure(orgName string) (*cl.RawCred, error) {
+	cred, err := c.grpcClient.GetCredentialStructure(context.Background(),
+		&pb.CLOrg{OrgName: orgName})
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve credential structure info: %v", err)
 	}
@@ -55,54 +76,84 @@ func (c *CLClient) GetCredentialStructure() (*cl.RawCred, error) {
 	)
 	rc := cl.NewRawCred(count)
 
-	attrs := cred.Attributes
-	for _, a := range attrs {
+	for _, a := range cred.Attributes {
+		var attr *pb.Attribute
+		var attrType string
+		var values []string
 		switch u := a.Type.(type) { // TODO make more intuitive
 		case *pb.CredAttribute_StringAttr:
-			fmt.Println("Client received string attribute", u.StringAttr)
-			strA := a.GetStringAttr().Attr
-			err := rc.AddEmptyStrAttr(strA.Name, strA.Known)
-			if err != nil {
-				return nil, err
-			}
+			attr, attrType = u.StringAttr.Attr, cl.StrAttrType
 		case *pb.CredAttribute_IntAttr:
-			fmt.Println("Client received int attribute", u.IntAttr)
-			intA := a.GetIntAttr().Attr
-			err := rc.AddEmptyInt64Attr(intA.Name, intA.Known)
-			if err != nil {
-				return nil, err
-			}
+			attr, attrType = u.IntAttr.Attr, cl.Int64AttrType
+		case *pb.CredAttribute_DateAttr:
+			attr, attrType = u.DateAttr.Attr, cl.DateAttrType
+		case *pb.CredAttribute_BoolAttr:
+			attr, attrType = u.BoolAttr.Attr, cl.BoolAttrType
+		case *pb.CredAttribute_EnumAttr:
+			attr, attrType = u.EnumAttr.Attr, cl.EnumAttrType
+			values = u.EnumAttr.Values
+		case *pb.CredAttribute_HashedStringAttr:
+			attr, attrType = u.HashedStringAttr.Attr, cl.HashedStrAttrType
+		default:
+			return nil, fmt.Errorf("unsupported attribute type")
+		}
+
+		ca, err := cl.NewEmptyAttr(attr.Name, attrType, attr.Known, attr.Hidden, values...)
+		if err != nil {
+			return nil, err
+		}
+		if err := rc.AddEmptyAttr(ca); err != nil {
+			return nil, err
 		}
 	}
 
 	return rc, nil
 }
 
-func (c *CLClient) GetAcceptableCreds() (map[string][]string, error) {
+// GetIssuerKeyID returns the ID of the key under which the organization with the given name
+// currently issues the credentials (see cl.PubKey.GetID). The credential manager used for
+// issuing a credential needs to hold the public key with this ID.
+func (c *CLClient) GetIssuerKeyID(orgName string) (string, error) {
+	cred, err := c.grpcClient.GetCredentialStructure(context.Background(),
+		&pb.CLOrg{OrgName: orgName})
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve credential structure info: %v", err)
+	}
+
+	return cred.KeyId, nil
+}
+
+// GetAcceptableCreds returns the verification policies (by the names of organizations)
+// which the proofs of the credentials of the accepted organizations need to satisfy.
+func (c *CLClient) GetAcceptableCreds() (map[string]*cl.VerificationPolicy, error) {
 	creds, err := c.grpcClient.GetAcceptableCredentials(context.Background(), &empty.Empty{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve acceptable credentials info: %v", err)
 	}
 
-	accCreds := make(map[string][]string)
+	accCreds := make(map[string]*cl.VerificationPolicy)
 	for _, cred := range creds.Creds {
-		var attrs []string
-		for _, attr := range cred.GetRevealedAttrs() {
-			attrs = append(attrs, attr)
+		var policy cl.VerificationPolicy
+		if err := json.Unmarshal([]byte(cred.GetPolicy()), &policy); err != nil {
+			return nil, fmt.Errorf("invalid verification policy for %s: %v", cred.GetOrgName(), err)
 		}
-		accCreds[cred.GetOrgName()] = attrs
+		accCreds[cred.GetOrgName()] = &policy
 	}
 	return accCreds, nil
 }
 
-func (c *CLClient) IssueCredential(credManager *cl.CredManager, regKey string) (*cl.Cred, error) {
+// IssueCredential obtains a credential from the organization with the given name. The
+// registration key regKey needs to be provisioned by the organization.
+func (c *CLClient) IssueCredential(orgName string, credManager *cl.CredManager,
+	regKey string) (*cl.Cred, error) {
 	if err := c.openStream(c.grpcClient, "IssueCredential"); err != nil {
 		return nil, err
 	}
 	defer c.closeStream()
 
 	initData := pb.RegKey{
-		RegKey: regKey,
+		RegKey:  regKey,
+		OrgName: orgName,
 	}
 
 	initMsg := &pb.Message{
@@ -143,28 +194,42 @@ func (c *CLClient) IssueCredential(credManager *cl.CredManager, regKey string) (
 		return nil, err
 	}
 
-	if userVerified {
-		return credential, nil
+	if !userVerified {
+		return nil, fmt.Errorf("credential not valid")
 	}
 
-	return nil, fmt.Errorf("credential not valid")
+	if err := setWitness(credManager, credential, pbCred.GetWitness()); err != nil {
+		return nil, err
+	}
+
+	return credential, nil
 }
 
-func (c *CLClient) UpdateCredential(credManager *cl.CredManager, rawCred *cl.RawCred) (*cl.Cred,
-	error) {
-	// refresh credManager with new credential values, works only for known attributes
-	credManager.Update(rawCred)
-	newKnownAttrs := rawCred.GetKnownVals()
+// UpdateCredential obtains a credential with the new attribute values from rawCred. The
+// credential needs to be issued by the organization with the given name. The update is
+// permitted only in the session (given by sessionKey) in which a credential of the
+// organization was proved (see ProveCredential).
+func (c *CLClient) UpdateCredential(orgName string, credManager *cl.CredManager,
+	rawCred *cl.RawCred, sessionKey string) (*cl.Cred, error) {
+	// refresh credManager with new credential values, changed committed attributes
+	// are sent as new commitments together with proofs of their openings
+	if err := credManager.Update(rawCred); err != nil {
+		return nil, err
+	}
 
-	if err := c.openStream(c.grpcClient, "UpdateCredential"); err != nil {
+	ctx := context.Background()
+	if sessionKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, sessionKeyMetadata, sessionKey)
+	}
+	if err := c.openStreamContext(ctx, c.grpcClient, "UpdateCredential"); err != nil {
 		return nil, err
 	}
 	defer c.closeStream()
 
 	initMsg := &pb.Message{
 		ClientId: c.id,
-		Content: &pb.Message_UpdateClCredential{
-			pb.ToPbUpdateCLCredential(credManager.Nym, credManager.CredReqNonce, newKnownAttrs),
+		Content: &pb.Message_ClOrg{
+			&pb.CLOrg{OrgName: orgName},
 		},
 	}
 
@@ -172,6 +237,22 @@ func (c *CLClient) UpdateCredential(credManager *cl.CredManager, rawCred *cl.Raw
 	if err != nil {
 		return nil, err
 	}
+	// the update request is computed for the nonce of the organization
+	nonceOrg := new(big.Int).SetBytes(resp.GetBigint().X1)
+	updateReq, err := credManager.GetCredUpdateRequest(nonceOrg)
+	if err != nil {
+		return nil, err
+	}
+	updateMsg := &pb.Message{
+		Content: &pb.Message_UpdateClCredential{
+			pb.ToPbUpdateCLCredential(updateReq),
+		},
+	}
+
+	resp, err = c.getResponseTo(updateMsg)
+	if err != nil {
+		return nil, err
+	}
 
 	pbCred := resp.GetCLCredential()
 	credential, AProof, err := pbCred.GetNativeType()
@@ -184,17 +265,25 @@ func (c *CLClient) UpdateCredential(credManager *cl.CredManager, rawCred *cl.Raw
 		return nil, err
 	}
 
-	if userVerified {
-		return credential, nil
+	if !userVerified {
+		return nil, fmt.Errorf("credential not valid")
+	}
+
+	if err := setWitness(credManager, credential, pbCred.GetWitness()); err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("credential not valid")
+	return credential, nil
 }
 
-// ProveCredential proves the possession of a valid credential and reveals only the attributes the user desires
-// to reveal.
-func (c *CLClient) ProveCredential(credManager *cl.CredManager, cred *cl.Cred,
-	revealedAttrs []string) (*string, error) {
+// ProveCredential proves the possession of a valid credential issued by the organization with the given name
+// and reveals only the attributes the user desires to reveal. For each of the predicates and set memberships (on unrevealed attributes) a proof is provided
+// that the attribute satisfies it. When the server requires a domain pseudonym for its scope, the pseudonym
+// (derived from the master secret) is sent to the server as well. When the server requires an attribute
+// to be escrowed under the key of a trusted inspector (see AddTrustedInspector), the attribute is encrypted
+// for the inspector.
+func (c *CLClient) ProveCredential(orgName string, credManager *cl.CredManager, cred *cl.Cred,
+	revealedAttrs []string, predicates []*cl.Predicate, setMemberships []*cl.SetMembership) (*string, error) {
 	var revealedKnownAttrsIndices []int
 	var revealedCommitmentsOfAttrsIndices []int
 
@@ -204,6 +293,10 @@ func (c *CLClient) ProveCredential(credManager *cl.CredManager, cred *cl.Cred,
 			return nil, status.Errorf(codes.InvalidArgument,
 				"unexpected attribute: %s", a)
 		}
+		if attr.IsHidden() {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"hidden attribute cannot be revealed: %s", a)
+		}
 		ind, err := credManager.RawCred.GetAttrInternalIndex(a)
 		if err != nil {
 			return nil, err
@@ -222,6 +315,9 @@ func (c *CLClient) ProveCredential(credManager *cl.CredManager, cred *cl.Cred,
 
 	initMsg := &pb.Message{
 		ClientId: c.id,
+		Content: &pb.Message_ClOrg{
+			&pb.CLOrg{OrgName: orgName},
+		},
 	}
 
 	resp, err := c.getResponseTo(initMsg)
@@ -229,20 +325,115 @@ func (c *CLClient) ProveCredential(credManager *cl.CredManager, cred *cl.Cred,
 		return nil, err
 	}
 
-	nonce := new(big.Int).SetBytes(resp.GetBigint().X1)
+	proofReq := resp.GetClProofRequest()
+	nonce := new(big.Int).SetBytes(proofReq.Nonce)
+	var scope []byte
+	if len(proofReq.Scope) > 0 {
+		scope = proofReq.Scope
+	}
+	escrow := proofReq.Escrow.GetNativeType()
+	if escrow != nil && (escrow.PubKey == nil || !c.trustedInspectors[escrow.PubKey.GetID()]) {
+		return nil, fmt.Errorf("attribute needs to be escrowed under an untrusted inspector key")
+	}
 
-	randCred, proof, err := credManager.BuildProof(cred, revealedKnownAttrsIndices,
-		revealedCommitmentsOfAttrsIndices, nonce)
+	proof, err := credManager.BuildProof(cred, revealedKnownAttrsIndices,
+		revealedCommitmentsOfAttrsIndices, &cl.ProofOptions{
+			Predicates:     predicates,
+			SetMemberships: setMemberships,
+			Scope:          scope,
+			Escrow:         escrow,
+		}, nonce)
 	if err != nil {
 		return nil, fmt.Errorf("error when building credential proof: %v", err)
 	}
 
-	filteredKnownAttrs, filteredCommitmentsOfAttrs := credManager.FilterAttributes(revealedKnownAttrsIndices,
-		revealedCommitmentsOfAttrsIndices)
+	pbProof := pb.ToPbCLCredProof(proof)
+	pbProof.KeyId = credManager.PubKey.GetID()
+	proveMsg := &pb.Message{
+		Content: &pb.Message_ProveClCredential{pbProof},
+	}
+	resp, err = c.getResponseTo(proveMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	sessKey := resp.GetSessionKey().Value
+	return &sessKey, nil
+}
+
+// ProveCredentials proves the possession of several credentials (issued by organizations
+// orgNames[i]) in a single proof. The credentials need to contain the same master secret,
+// which proves to the server that they all belong to the same user. When the server
+// requires a domain pseudonym, it is included in the proofs of all credentials, while
+// the attribute which needs to be escrowed is escrowed in the credentials of the
+// organization named by the server. The equalities of attributes required by the server
+// are proved for the credentials of the organizations named in them.
+func (c *CLClient) ProveCredentials(orgNames []string,
+	presentations []*cl.CredPresentation) (*string, error) {
+	if len(orgNames) == 0 || len(orgNames) != len(presentations) {
+		return nil, fmt.Errorf("the number of organizations and credentials does not match")
+	}
+
+	if err := c.openStream(c.grpcClient, "ProveCredentials"); err != nil {
+		return nil, err
+	}
+	defer c.closeStream()
+
+	initMsg := &pb.Message{
+		ClientId: c.id,
+		Content: &pb.Message_ClOrg{
+			&pb.CLOrg{OrgName: orgNames[0]},
+		},
+	}
+
+	resp, err := c.getResponseTo(initMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	proofReq := resp.GetClProofRequest()
+	nonce := new(big.Int).SetBytes(proofReq.Nonce)
+	escrow := proofReq.Escrow.GetNativeType()
+	if escrow != nil && (escrow.PubKey == nil || !c.trustedInspectors[escrow.PubKey.GetID()]) {
+		return nil, fmt.Errorf("attribute needs to be escrowed under an untrusted inspector key")
+	}
+	if len(proofReq.Scope) > 0 || escrow != nil {
+		// the presentations of the caller are not modified
+		required := make([]*cl.CredPresentation, len(presentations))
+		for i, p := range presentations {
+			rp := *p
+			if len(proofReq.Scope) > 0 {
+				rp.Scope = proofReq.Scope
+			}
+			if strings.EqualFold(orgNames[i], proofReq.EscrowOrgName) {
+				rp.Escrow = escrow
+			}
+			required[i] = &rp
+		}
+		presentations = required
+	}
+
+	proofs, err := cl.BuildMultiProof(presentations, proofReq.GetNativeAttrEqualities(orgNames),
+		nonce)
+	if err != nil {
+		return nil, fmt.Errorf("error when building credentials proof: %v", err)
+	}
+
+	pbProofs := make([]*pb.CLCredProof, len(proofs))
+	for i, p := range proofs {
+		pbProofs[i] = &pb.CLCredProof{
+			OrgName: orgNames[i],
+			Proof:   pb.ToPbCLCredProof(p),
+		}
+		pbProofs[i].Proof.KeyId = presentations[i].CredManager.PubKey.GetID()
+	}
 
 	proveMsg := &pb.Message{
-		Content: &pb.Message_ProveClCredential{pb.ToPbProveCLCredential(randCred.A, proof, filteredKnownAttrs,
-			filteredCommitmentsOfAttrs, revealedKnownAttrsIndices, revealedCommitmentsOfAttrsIndices)},
+		Content: &pb.Message_ProveClCredentials{
+			&pb.ProveCLCredentials{
+				Proofs: pbProofs,
+			},
+		},
 	}
 	resp, err = c.getResponseTo(proveMsg)
 	if err != nil {
@@ -252,3 +443,68 @@ func (c *CLClient) ProveCredential(credManager *cl.CredManager, cred *cl.Cred,
 	sessKey := resp.GetSessionKey().Value
 	return &sessKey, nil
 }
+
+// RevokeCredential revokes the credential which was issued to the given nym by the organization
+// with the given name. The token needs to be the token of the issuer of the organization or
+// the admin token configured on the server. It returns the resulting accumulator update.
+func (c *CLClient) RevokeCredential(orgName string, nym *big.Int, token string) (*cl.AccumulatorUpdate,
+	error) {
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, revocationTokenMetadata, token)
+	}
+	update, err := c.grpcClient.RevokeCredential(ctx,
+		&pb.CLRevokeCredential{Nym: nym.Bytes(), OrgName: orgName})
+	if err != nil {
+		return nil, err
+	}
+
+	return update.GetNativeType(), nil
+}
+
+// GetWitnessUpdates retrieves all updates of the accumulator of the organization with the given
+// name and the key with the given ID (of the currently active key when keyID is empty) which
+// happened after the given epoch.
+func (c *CLClient) GetWitnessUpdates(orgName, keyID string, epoch int) ([]*cl.AccumulatorUpdate,
+	error) {
+	resp, err := c.grpcClient.GetWitnessUpdates(context.Background(),
+		&pb.CLWitnessUpdatesRequest{Epoch: int32(epoch), KeyId: keyID, OrgName: orgName})
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]*cl.AccumulatorUpdate, len(resp.Updates))
+	for i, u := range resp.Updates {
+		updates[i] = u.GetNativeType()
+	}
+
+	return updates, nil
+}
+
+// UpdateWitness retrieves accumulator updates of the organization with the given name and
+// updates the witness of the credential cred. It needs to be called before proving the
+// possession of a credential whenever some other credential has been revoked.
+func (c *CLClient) UpdateWitness(orgName string, credManager *cl.CredManager, cred *cl.Cred) error {
+	if credManager.Witness == nil {
+		return fmt.Errorf("witness is not set")
+	}
+
+	updates, err := c.GetWitnessUpdates(orgName, credManager.PubKey.GetID(), credManager.Witness.Epoch)
+	if err != nil {
+		return err
+	}
+
+	return credManager.UpdateWitness(cred, updates)
+}
+
+// setWitness stores the witness received together with the credential, if the issuer
+// supports revocation.
+func setWitness(credManager *cl.CredManager, cred *cl.Cred,
+	pbWitness *pb.CLWitness) error {
+	witness := pbWitness.GetNativeType()
+	if witness == nil {
+		return nil
+	}
+
+	return credManager.SetWitness(cred, witness)
+}
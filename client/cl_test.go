@@ -18,28 +18,39 @@
 package client
 
 import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/crypto/encryption"
+	"github.com/xlab-si/emmy/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // TestCL requires a running server.
 func TestCL(t *testing.T) {
-	params := cl.GetDefaultParamSizes()
-	pubKey := new(cl.PubKey)
-	cl.ReadGob("testdata/clPubKey.gob", pubKey)
+	params, pubKey, err := cl.ReadPubKey("testdata/clPubKey.json")
+	require.NoError(t, err)
 
 	client, err := NewCLClient(testGrpcClientConn)
 	if err != nil {
 		t.Errorf("Error when initializing NewCLClient")
 	}
 
-	rc, err := client.GetCredentialStructure()
+	rc, err := client.GetCredentialStructure("org1")
 	if err != nil {
 		t.Errorf("error when retrieving credential structure: %v", err)
 	}
+	_, err = client.GetCredentialStructure("org3")
+	assert.Error(t, err, "organization which is not hosted should not be found")
 
 	name, _ := rc.GetAttr("Name")
 	err = name.UpdateValue("Jack")
@@ -61,43 +72,402 @@ func TestCL(t *testing.T) {
 	age, _ := rc.GetAttr("Age")
 	err = age.UpdateValue(50)
 	assert.NoError(t, err)
+	address, _ := rc.GetAttr("Address")
+	err = address.UpdateValue("Main Street 1")
+	assert.NoError(t, err)
+
+	// hidden attributes are never sent to the server
+	deviceKey, _ := rc.GetAttr("DeviceKey")
+	err = deviceKey.UpdateValue("device-secret")
+	assert.NoError(t, err)
 
 	masterSecret := pubKey.GenerateUserMasterSecret()
 
 	cm, err := cl.NewCredManager(params, pubKey, masterSecret, rc)
 	require.NoError(t, err)
 
-	credManagerPath := "../client/testdata/credManager.gob"
-	cl.WriteGob(credManagerPath, cm)
+	// the issuer provisioned a different name for testRegKey6
+	_, err = client.IssueCredential("org1", cm, "testRegKey6")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match the value provisioned")
+
+	// only the public key of org2 is configured, so it cannot issue credentials
+	_, err = client.IssueCredential("org2", cm, "testRegKey5")
+	assert.Error(t, err, "organization without a secret key should not issue credentials")
 
-	cred, err := client.IssueCredential(cm, "testRegKey5")
+	cred, err := client.IssueCredential("org1", cm, "testRegKey5")
 	require.NoError(t, err)
 
-	// create new CredManager (updating or proving usually does not happen at the same time
-	// as issuing)
-	cl.ReadGob(credManagerPath, cm)
+	// store the credential to the wallet and restore CredManager from it (updating or
+	// proving usually does not happen at the same time as issuing)
+	walletDir, err := ioutil.TempDir("", "wallet")
+	require.NoError(t, err)
+	defer os.RemoveAll(walletDir)
+	walletPath := filepath.Join(walletDir, "wallet.json")
+	w, err := wallet.Create(walletPath, "passphrase")
 	require.NoError(t, err)
+	err = w.Add(wallet.NewCLCredential("org1", cred, cm))
+	require.NoError(t, err)
+	w, err = wallet.Open(walletPath, "passphrase")
+	require.NoError(t, err)
+	stored, err := w.Get("org1")
+	require.NoError(t, err)
+	cm, cred = stored.CL.CredManager, stored.CL.Cred
+	rc = cm.RawCred
+	address, _ = rc.GetAttr("Address")
 
 	acceptableCreds, err := client.GetAcceptableCreds()
 	require.NoError(t, err)
-	revealedAttrs := acceptableCreds["org1"] // FIXME
-
-	//revealedAttrs = []string{"Name", "Gender"}
-	sessKey, err := client.ProveCredential(cm, cred, revealedAttrs)
+	require.Contains(t, acceptableCreds, "org1")
+	// the policy is satisfied when the revealed DateMin and DateMax are in the required ranges
+	revealedAttrs := append(acceptableCreds["org1"].Revealed, "DateMin", "DateMax")
+	sessKey, err := client.ProveCredential("org1", cm, cred, revealedAttrs, nil, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, sessKey, "possesion of a credential proof failed")
+	_, err = client.ProveCredential("org2", cm, cred, revealedAttrs, nil, nil)
+	assert.Error(t, err, "credential should not be accepted as a credential of another organization")
+
+	// the session key gives access to the revealed attributes until the logout
+	sessClient, err := NewSessionClient(testGrpcClientConn, *sessKey)
+	require.NoError(t, err)
+	session, err := sessClient.GetSession()
+	require.NoError(t, err)
+	require.Len(t, session.Creds, 1)
+	assert.Equal(t, "org1", session.Creds[0].OrgName)
+	assert.Equal(t, map[string]string{
+		"Name":    "Jack",
+		"DateMin": "2017-12-07T10:36:40Z",
+		"DateMax": "2020-06-20T08:50:00Z",
+	}, session.Creds[0].Attrs)
+	assert.Equal(t, acceptableCreds["org1"].Condition, session.Creds[0].Policy.Condition)
+	assert.True(t, session.Expiry.After(time.Now()))
+	err = sessClient.Logout()
+	require.NoError(t, err)
+	_, err = sessClient.GetSession()
+	assert.Error(t, err, "session should not be accessible after the logout")
+	invalidSessClient, err := NewSessionClient(testGrpcClientConn, "invalid")
+	require.NoError(t, err)
+	_, err = invalidSessClient.GetSession()
+	assert.Error(t, err, "session should not be accessible with an invalid key")
+
+	// the credential is proved again to obtain the session in which it can be updated
+	updateSessKey, err := client.ProveCredential("org1", cm, cred, revealedAttrs, nil, nil)
+	require.NoError(t, err)
 
 	// modify some attributes and get updated credential
-	name, err = rc.GetAttr("Name")
+	name, _ = rc.GetAttr("Name")
 	err = name.UpdateValue("Jim")
 	assert.NoError(t, err)
+	// committed attributes are updated by sending new commitments
+	err = address.UpdateValue("Main Street 2")
+	assert.NoError(t, err)
 
-	cred1, err := client.UpdateCredential(cm, rc)
+	// the credential can be updated only in a session in which it was proved
+	_, err = client.UpdateCredential("org1", cm, rc, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session key is missing")
+	_, err = client.UpdateCredential("org1", cm, rc, "invalid")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid session key")
+	_, err = client.UpdateCredential("org1", cm, rc, *sessKey)
+	assert.Error(t, err, "credential should not be updated in a session which has ended")
+	// Name was provisioned for testRegKey5, so it cannot be changed by the holder
+	_, err = client.UpdateCredential("org1", cm, rc, *updateSessKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be changed")
+	err = name.UpdateValue("Jack")
+	assert.NoError(t, err)
+	graduated, _ = rc.GetAttr("Graduated")
+	err = graduated.UpdateValue("yes")
+	assert.NoError(t, err)
+	cred1, err := client.UpdateCredential("org1", cm, rc, *updateSessKey)
+	require.NoError(t, err)
+	err = w.Update(wallet.NewCLCredential("org1", cred1, cm))
 	require.NoError(t, err)
 
-	sessKey, err = client.ProveCredential(cm, cred1, revealedAttrs)
+	sessKey, err = client.ProveCredential("org1", cm, cred1, revealedAttrs, nil, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, sessKey,
 		"possesion of an updated credential proof failed")
 
+	// instead of revealing DateMin and DateMax, prove that they satisfy the conditions
+	predicates := []*cl.Predicate{
+		cl.NewRangePredicate(3, cl.EncodeInt64(0), cl.EncodeInt64(1562643000)),
+		cl.NewRangePredicate(4, cl.EncodeInt64(1562643000), cl.EncodeInt64(2000000000)),
+	}
+	sessKey, err = client.ProveCredential("org1", cm, cred1, []string{"Name"}, predicates, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of a credential with predicates proof failed")
+	// Age (the attribute with index 5) is known to the issuer, so it can be proved to be over 18
+	agePredicates := append(predicates, cl.NewGreaterPredicate(params, 5, cl.EncodeInt64(18)))
+	sessKey, err = client.ProveCredential("org1", cm, cred1, []string{"Name"}, agePredicates, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of a credential with Age > 18 proof failed")
+	agePredicates = append(predicates, cl.NewGreaterPredicate(params, 5, cl.EncodeInt64(50)))
+	_, err = client.ProveCredential("org1", cm, cred1, []string{"Name"}, agePredicates, nil)
+	assert.Error(t, err, "predicate which the attribute does not satisfy should not be proved")
+
+	// prove that Graduated is one of the acceptable values without revealing it
+	sets := []*cl.SetMembership{
+		cl.NewSetMembership(2, []*big.Int{
+			new(big.Int).SetBytes([]byte("true")),
+			new(big.Int).SetBytes([]byte("yes")),
+		}),
+	}
+	sessKey, err = client.ProveCredential("org1", cm, cred1, []string{"Name"}, nil, sets)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of a credential with set membership proof failed")
+
+	// when the server requires the escrow of Gender, it is encrypted under the key of
+	// the inspector, which needs to be trusted by the client
+	viper.Set("escrow.attr", "Gender")
+	_, err = client.ProveCredential("org1", cm, cred1, []string{"Name"}, predicates, nil)
+	assert.Error(t, err, "attribute should not be escrowed under an untrusted key")
+	inspectorPubKey, err := encryption.ReadCSPaillierPubKey("testdata/inspectorPubKey.json")
+	require.NoError(t, err)
+	client.AddTrustedInspector(inspectorPubKey)
+	sessKey, err = client.ProveCredential("org1", cm, cred1, []string{"Name"}, predicates, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of a credential with escrow proof failed")
+	_, err = client.ProveCredential("org1", cm, cred1, []string{"Name", "Gender"}, predicates, nil)
+	assert.Error(t, err, "revealed attribute should not be escrowed")
+	viper.Set("escrow.attr", "")
+
+	// prove credentials of two organizations which are bound to the same master secret
+	org2, err := cl.LoadOrg("testdata/clPubKey2.json", "testdata/clSecKey2.json")
+	require.NoError(t, err)
+	cm2, err := cl.NewCredManager(params, org2.Keys.Pub, masterSecret, rc)
+	require.NoError(t, err)
+	issueNonce, err := org2.GetCredIssueNonce()
+	require.NoError(t, err)
+	credReq, err := cm2.GetCredRequest(issueNonce)
+	require.NoError(t, err)
+	res, err := org2.IssueCred(credReq, issueNonce)
+	require.NoError(t, err)
+	err = cm2.SetWitness(res.Cred, res.Witness)
+	require.NoError(t, err)
+
+	presentations := []*cl.CredPresentation{
+		cl.NewCredPresentation(cm, cred1, []int{0, 3, 4}, []int{}),
+		cl.NewCredPresentation(cm2, res.Cred, []int{1}, []int{}),
+	}
+	sessKey, err = client.ProveCredentials([]string{"org1", "org2"}, presentations)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of credentials of two organizations proof failed")
+
+	// credentials of unknown organizations are not accepted
+	_, err = client.ProveCredentials([]string{"org1", "org3"}, presentations)
+	assert.Error(t, err, "credential of unknown organization should not be accepted")
+
+	// the credentials of org2 can have a different structure, which the server uses to
+	// check the policy and to decode the revealed attributes
+	viper.Set("cl_attributes.org2", map[string]string{
+		"0": "Gender, string, true", "1": "Email, string, true", "2": "Name, string, true",
+		"3": "DateMin, date, true", "4": "DateMax, date, true", "5": "Age, int64, true",
+		"6": "Address, string, false", "7": "DeviceKey, string, hidden",
+	})
+	_, err = rc.GetAttr("Email")
+	assert.Error(t, err, "structure of org1 should not contain the attributes of org2")
+	// the structure is given only by the organizations which issue credentials (the keys
+	// of org1 are set as well, otherwise they would be shadowed by the keys of org2)
+	viper.Set("cl_keys.org1", []map[string]interface{}{
+		{"pubkey": "clPubKey.json", "seckey": "clSecKey.json"},
+	})
+	viper.Set("cl_keys.org2", []map[string]interface{}{
+		{"pubkey": "clPubKey2.json", "seckey": "clSecKey2.json"},
+	})
+	require.NoError(t, testServer.LoadCLOrgs())
+	rc2, err := client.GetCredentialStructure("org2")
+	require.NoError(t, err)
+	viper.Set("cl_keys.org2", []map[string]interface{}{{"pubkey": "clPubKey2.json"}})
+	require.NoError(t, testServer.LoadCLOrgs())
+	for name, val := range map[string]interface{}{"Gender": "F", "Email": "jill@example.com",
+		"Name": "Jill", "DateMin": 1512643000, "DateMax": 1592643000, "Age": 30,
+		"Address": "Main Street 3", "DeviceKey": "device-secret"} {
+		a, err := rc2.GetAttr(name)
+		require.NoError(t, err)
+		require.NoError(t, a.UpdateValue(val))
+	}
+	cm3, err := cl.NewCredManager(params, org2.Keys.Pub, masterSecret, rc2)
+	require.NoError(t, err)
+	issueNonce, err = org2.GetCredIssueNonce()
+	require.NoError(t, err)
+	credReq, err = cm3.GetCredRequest(issueNonce)
+	require.NoError(t, err)
+	res3, err := org2.IssueCred(credReq, issueNonce)
+	require.NoError(t, err)
+	require.NoError(t, cm3.SetWitness(res3.Cred, res3.Witness))
+	_, err = client.ProveCredentials([]string{"org1", "org2"}, []*cl.CredPresentation{
+		cl.NewCredPresentation(cm, cred1, []int{0, 3, 4}, []int{}),
+		cl.NewCredPresentation(cm3, res3.Cred, []int{1}, []int{}),
+	})
+	assert.Error(t, err, "credential of org2 which does not reveal Gender should not be accepted")
+	sessKey, err = client.ProveCredentials([]string{"org1", "org2"}, []*cl.CredPresentation{
+		cl.NewCredPresentation(cm, cred1, []int{0, 3, 4}, []int{}),
+		cl.NewCredPresentation(cm3, res3.Cred, []int{0}, []int{}),
+	})
+	require.NoError(t, err)
+	sessClient, err = NewSessionClient(testGrpcClientConn, *sessKey)
+	require.NoError(t, err)
+	session, err = sessClient.GetSession()
+	require.NoError(t, err)
+	require.Len(t, session.Creds, 2)
+	assert.Equal(t, "Jack", session.Creds[0].Attrs["Name"])
+	assert.Equal(t, map[string]string{"Gender": "F"}, session.Creds[1].Attrs)
+
+	// the server can require attributes of the credentials to be equal without revealing
+	// them (DateMin is not revealed, the policy of org1 is satisfied by Graduated)
+	equalityPresentations := func() []*cl.CredPresentation {
+		p1 := cl.NewCredPresentation(cm, cred1, []int{0}, []int{})
+		p1.SetMemberships = sets
+		return []*cl.CredPresentation{p1, cl.NewCredPresentation(cm3, res3.Cred, []int{0}, []int{})}
+	}
+	viper.Set("attr_equalities", [][]string{{"org1.DateMin", "org2.DateMin"}})
+	sessKey, err = client.ProveCredentials([]string{"org1", "org2"}, equalityPresentations())
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "credentials with equal attributes not accepted")
+	viper.Set("attr_equalities", [][]string{{"org1.DateMax", "org2.DateMin"}})
+	_, err = client.ProveCredentials([]string{"org1", "org2"}, equalityPresentations())
+	assert.Error(t, err, "credentials with different attributes should not be accepted")
+	viper.Set("attr_equalities", nil)
+	viper.Set("cl_attributes.org2", nil)
+
+	// the escrow required by the server applies to the credential of org1 also when it is
+	// proved together with other credentials
+	viper.Set("escrow.attr", "Gender")
+	sessKey, err = client.ProveCredentials([]string{"org1", "org2"}, presentations)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of credentials with escrow proof failed")
+	_, err = client.ProveCredentials([]string{"org1", "org2"}, []*cl.CredPresentation{
+		cl.NewCredPresentation(cm, cred1, []int{0, 1, 3, 4}, []int{}),
+		cl.NewCredPresentation(cm2, res.Cred, []int{1}, []int{}),
+	})
+	assert.Error(t, err, "revealed attribute should not be escrowed")
+	viper.Set("escrow.attr", "")
+
+	// when the server requires a domain pseudonym, each credential is accepted only once
+	viper.Set("service_info.pseudonym_scope", "e-voting")
+	sessKey, err = client.ProveCredential("org1", cm, cred1, revealedAttrs, nil, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of a credential with domain pseudonym proof failed")
+	_, err = client.ProveCredential("org1", cm, cred1, []string{"Name"}, predicates, nil)
+	assert.Error(t, err, "credential should not be accepted twice for the same scope")
+	_, err = client.ProveCredentials([]string{"org1", "org2"}, presentations)
+	assert.Error(t, err, "credential should not be accepted twice for the same scope "+
+		"in a multi-credential proof")
+	viper.Set("service_info.pseudonym_scope", "e-voting-2")
+	sessKey, err = client.ProveCredentials([]string{"org1", "org2"}, presentations)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of credentials with domain pseudonym proofs failed")
+	_, err = client.ProveCredential("org1", cm, cred1, revealedAttrs, nil, nil)
+	assert.Error(t, err, "credential should not be accepted again after a multi-credential proof")
+	viper.Set("service_info.pseudonym_scope", "")
+
+	// after the rotation of the keys, the credentials are issued under the new key, while
+	// the credentials issued under the old key are accepted until the old key is retired
+	yesterday := time.Now().AddDate(0, 0, -1).Format(time.RFC3339)
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.RFC3339)
+	rotateKeys := func(oldKeyNotAfter string) {
+		viper.Set("cl_keys.org1", []map[string]interface{}{
+			{"pubkey": "clPubKey.json", "seckey": "clSecKey.json", "not_after": oldKeyNotAfter},
+			{"pubkey": "clPubKey2.json", "seckey": "clSecKey2.json", "not_before": yesterday},
+		})
+		require.NoError(t, testServer.LoadCLOrgs())
+	}
+	rotateKeys(tomorrow)
+	keyID, err := client.GetIssuerKeyID("org1")
+	require.NoError(t, err)
+	assert.Equal(t, org2.Keys.Pub.GetID(), keyID, "credentials should be issued under the new key")
+	sessKey, err = client.ProveCredential("org1", cm, cred1, revealedAttrs, nil, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of a credential issued under the old key proof failed")
+
+	rotateKeys(yesterday)
+	_, err = client.ProveCredential("org1", cm, cred1, revealedAttrs, nil, nil)
+	assert.Error(t, err, "credential issued under a retired key should not be accepted")
+	viper.Set("cl_keys.org1", []map[string]interface{}{
+		{"pubkey": "clPubKey.json", "seckey": "clSecKey.json"},
+	})
+	require.NoError(t, testServer.LoadCLOrgs())
+	keyID, err = client.GetIssuerKeyID("org1")
+	require.NoError(t, err)
+	assert.Equal(t, pubKey.GetID(), keyID)
+
+	// only the issuer or the admin can revoke credentials
+	viper.Set("cl_revocation.admin_token", "testAdminToken")
+	viper.Set("cl_revocation.issuer_tokens", map[string]string{"org1": "testIssuerToken"})
+	defer func() {
+		viper.Set("cl_revocation.admin_token", "")
+		viper.Set("cl_revocation.issuer_tokens", map[string]string{})
+	}()
+	_, err = client.RevokeCredential("org1", cm.Nym, "")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err),
+		"revocation without a token should not be permitted")
+	_, err = client.RevokeCredential("org1", cm.Nym, "wrongToken")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err),
+		"revocation with a wrong token should not be permitted")
+	_, err = client.RevokeCredential("org2", cm.Nym, "testIssuerToken")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err),
+		"revocation with the token of other issuer should not be permitted")
+	_, err = client.RevokeCredential("org1", cm.Nym, "testAdminToken")
+	require.NoError(t, err, "revocation by the admin failed")
+	// the credential has already been revoked, but the issuer is permitted to try
+	_, err = client.RevokeCredential("org1", cm.Nym, "testIssuerToken")
+	assert.NotContains(t, []codes.Code{codes.Unauthenticated, codes.PermissionDenied},
+		status.Code(err), "revocation by the issuer should be permitted")
+
+	// after the revocation the credential cannot be proved anymore
+
+	err = client.UpdateWitness("org1", cm, cred1)
+	assert.Error(t, err, "witness of a revoked credential should not be updatable")
+
+	_, err = client.ProveCredential("org1", cm, cred1, revealedAttrs, nil, nil)
+	assert.Error(t, err, "revoked credential should not be accepted")
+}
+
+// TestCLThresholdIssuance requires a running server, which holds the shares of the secret key
+// of org2 (see TestMain) and coordinates the threshold issuance.
+func TestCLThresholdIssuance(t *testing.T) {
+	// the first party is not available, the other two hold enough shares
+	viper.Set("cl_keys.org2", []map[string]interface{}{
+		{"pubkey": "clPubKey2.json", "seckey": "clSecKey2Coordinator.json", "threshold": 2,
+			"parties": []string{"localhost:4321", testGrpcServerEndpoint, testGrpcServerEndpoint}},
+	})
+	require.NoError(t, testServer.LoadCLOrgs())
+	defer func() {
+		viper.Set("cl_keys.org2", []map[string]interface{}{
+			{"pubkey": "clPubKey2.json"},
+		})
+		require.NoError(t, testServer.LoadCLOrgs())
+	}()
+
+	params, pubKey, err := cl.ReadPubKey("testdata/clPubKey2.json")
+	require.NoError(t, err)
+	client, err := NewCLClient(testGrpcClientConn)
+	require.NoError(t, err)
+
+	rc, err := client.GetCredentialStructure("org2")
+	require.NoError(t, err)
+	for name, val := range map[string]interface{}{"Name": "Jane", "Gender": "F",
+		"Graduated": "true", "DateMin": 1512643000, "DateMax": 1592643000, "Age": 30,
+		"Address": "Main Street 4", "DeviceKey": "device-secret"} {
+		attr, err := rc.GetAttr(name)
+		require.NoError(t, err)
+		require.NoError(t, attr.UpdateValue(val))
+	}
+
+	cm, err := cl.NewCredManager(params, pubKey, pubKey.GenerateUserMasterSecret(), rc)
+	require.NoError(t, err)
+	cred, err := client.IssueCredential("org2", cm, "testRegKey7")
+	require.NoError(t, err)
+
+	sessKey, err := client.ProveCredential("org2", cm, cred, []string{"Gender"}, nil, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, sessKey, "possesion of a credential issued by the parties proof failed")
+
+	// the parties do not accept the requests without the token
+	viper.Set("cl_threshold.token", "")
+	_, err = client.IssueCredential("org2", cm, "testRegKey8")
+	assert.Error(t, err, "parties should refuse threshold issuance without the token")
+	viper.Set("cl_threshold.token", "testThresholdToken")
 }
@@ -176,9 +176,16 @@ func (c *genericClient) getResponseTo(msg *pb.Message) (*pb.Message, error) {
 // to provide appropriate grpcClient and streamGenFunc.
 // This function has to be called explicitly at the beginning of the protocol execution function.
 func (c *genericClient) openStream(grpcClient interface{}, streamGenFunc string) error {
+	return c.openStreamContext(context.Background(), grpcClient, streamGenFunc)
+}
+
+// openStreamContext opens a pb.ClientStream as openStream, but with the given context
+// (for example a context which holds the metadata of the call).
+func (c *genericClient) openStreamContext(ctx context.Context, grpcClient interface{},
+	streamGenFunc string) error {
 	// Create structs compatible with reflect package
-	client := reflect.ValueOf(grpcClient)                            // we want to call streamGenFunc on this struct
-	params := []reflect.Value{reflect.ValueOf(context.Background())} // we want to pass these params to streamGenFunc
+	client := reflect.ValueOf(grpcClient)           // we want to call streamGenFunc on this struct
+	params := []reflect.Value{reflect.ValueOf(ctx)} // we want to pass these params to streamGenFunc
 
 	// Safety check for existence of the requested stream generation method on a given grpc client
 	f := client.MethodByName(streamGenFunc)
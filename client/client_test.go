@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/xlab-si/emmy/crypto/cl"
 	"github.com/xlab-si/emmy/log"
@@ -41,6 +42,9 @@ var testGrpcServerEndpoint = "localhost:7008"
 // testGrpcClientConn is re-used for all the test clients
 var testGrpcClientConn *grpc.ClientConn
 
+// testServer is the server which the test clients connect to
+var testServer *server.Server
+
 var testRedis = flag.Bool(
 	"db",
 	false,
@@ -59,7 +63,13 @@ func TestMain(m *testing.M) {
 	flag.Parse()
 
 	var regKeyDB server.RegistrationManager
-	testRegKeys := []string{"testRegKey1", "testRegKey2", "testRegKey3", "testRegKey4", "testRegKey5"}
+	testRegKeys := []string{"testRegKey1", "testRegKey2", "testRegKey3", "testRegKey4", "testRegKey5",
+		"testRegKey6", "testRegKey7", "testRegKey8"}
+	// known attributes which the issuer provisioned for registration keys
+	testAttrs := map[string]map[string]string{
+		"testRegKey5": {"Name": "Jack", "Gender": "M"},
+		"testRegKey6": {"Name": "Jane"},
+	}
 
 	var recDB cl.ReceiverRecordManager
 
@@ -84,19 +94,38 @@ func TestMain(m *testing.M) {
 			}
 		}
 
-		regKeyDB = server.NewRedisClient(c)
+		redisRegKeyDB := server.NewRedisClient(c)
+		for regKey, attrs := range testAttrs {
+			if err := redisRegKeyDB.SetAttributes(regKey, attrs); err != nil {
+				fmt.Println("cannot insert test attributes to redis:", err)
+				os.Exit(1)
+			}
+		}
+		regKeyDB = redisRegKeyDB
 		recDB = cl.NewRedisClient(c)
 	} else { // use mock storage
 		fmt.Println("Using mock storage")
 		// prepare mocks
-		mock := &mockRegKeyDB{}
+		mock := &mockRegKeyDB{
+			attrs: testAttrs,
+		}
 		mock.insert(testRegKeys...)
 		regKeyDB = mock
 		recDB = cl.NewMockRecordManager()
 	}
 
+	// the test server holds all shares of the secret key of org2 (split with emmy keygen
+	// cl-split), so that it can also coordinate their threshold issuance
+	viper.Set("cl_threshold.shares", []map[string]interface{}{
+		{"pubkey": "clPubKey2.json", "share": "clShare2-1.json"},
+		{"pubkey": "clPubKey2.json", "share": "clShare2-2.json"},
+		{"pubkey": "clPubKey2.json", "share": "clShare2-3.json"},
+	})
+	viper.Set("cl_threshold.token", "testThresholdToken")
+
 	logger, _ := log.NewStdoutLogger("testServer", log.NOTICE, log.FORMAT_LONG)
-	server, err := server.NewServer("testdata/server.pem", "testdata/server.key",
+	var err error
+	testServer, err = server.NewServer("testdata/server.pem", "testdata/server.key",
 		regKeyDB, recDB, logger)
 	if err != nil {
 		fmt.Println(err)
@@ -107,7 +136,7 @@ func TestMain(m *testing.M) {
 	clientLogger, _ := log.NewStdoutLogger("client", log.NOTICE, log.FORMAT_SHORT)
 	SetLogger(clientLogger)
 
-	go server.Start(7008)
+	go testServer.Start(7008)
 
 	// Establish a connection to previously started server
 	testCert, err := ioutil.ReadFile("testdata/server.pem")
@@ -126,7 +155,7 @@ func TestMain(m *testing.M) {
 	returnCode := m.Run()
 
 	// Cleanup - close connection, stop the server and exit
-	server.Teardown()
+	testServer.Teardown()
 	testGrpcClientConn.Close()
 	os.Exit(returnCode)
 }
@@ -153,9 +182,11 @@ func TestConnectionTimeout(t *testing.T) {
 }
 
 // mockRegKeyDB mocks storage of registration keys. It is a
-// slice that will hold the keys.
+// slice that will hold the keys, attributes provisioned for the
+// keys are held in a map.
 type mockRegKeyDB struct {
-	data []string
+	data  []string
+	attrs map[string]map[string]string
 }
 
 // insert inserts multiple registration keys to mockRegKeyDB,
@@ -188,3 +219,12 @@ func (m *mockRegKeyDB) CheckRegistrationKey(key string) (bool, error) {
 
 	return false, nil
 }
+
+// GetAttributes returns the attributes provisioned for registration
+// key key, removing them.
+func (m *mockRegKeyDB) GetAttributes(key string) (map[string]string, error) {
+	attrs := m.attrs[key]
+	delete(m.attrs, key)
+
+	return attrs, nil
+}
@@ -71,4 +71,9 @@
 //	TranscriptEC
 // 	Pseudonym
 // 	PseudonymEC
+//
+// Wallet holding pseudonym system credentials encrypted at rest:
+//	Wallet
+//	StoredCredential
+//	StoredCredentialEC
 package compatibility
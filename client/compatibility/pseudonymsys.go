@@ -50,6 +50,15 @@ func NewCredential(aToGamma, bToGamma, AToGamma, BToGamma string,
 	return credential
 }
 
+// newCredential translates emmy's native pseudsys.Cred to compatibility Credential.
+func newCredential(c *pseudsys.Cred) *Credential {
+	t1 := NewTranscript(c.T1.A.String(), c.T1.B.String(), c.T1.Hash.String(), c.T1.ZAlpha.String())
+	t2 := NewTranscript(c.T2.A.String(), c.T2.B.String(), c.T2.Hash.String(), c.T2.ZAlpha.String())
+
+	return NewCredential(c.SmallAToGamma.String(), c.SmallBToGamma.String(), c.AToGamma.String(),
+		c.BToGamma.String(), t1, t2)
+}
+
 // getNativeType translates compatibility Credential to emmy's native pseudsys.Cred.
 func (c *Credential) getNativeType() (*pseudsys.Cred, error) {
 	atG, atGOk := new(big.Int).SetString(c.SmallAToGamma, 10)
@@ -212,24 +221,7 @@ func (c *PseudonymsysClient) ObtainCredential(userSecret string,
 	}
 
 	// Translate from native emmy types to compatibility types
-	t1 := NewTranscript(
-		credential.T1.A.String(),
-		credential.T1.B.String(),
-		credential.T1.Hash.String(),
-		credential.T1.ZAlpha.String())
-	t2 := NewTranscript(
-		credential.T2.A.String(),
-		credential.T2.B.String(),
-		credential.T2.Hash.String(),
-		credential.T2.ZAlpha.String())
-
-	return NewCredential(
-		credential.SmallAToGamma.String(),
-		credential.SmallBToGamma.String(),
-		credential.AToGamma.String(),
-		credential.BToGamma.String(),
-		t1,
-		t2), nil
+	return newCredential(credential), nil
 }
 
 func (c *PseudonymsysClient) TransferCredential(orgName, userSecret string,
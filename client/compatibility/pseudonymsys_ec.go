@@ -118,6 +118,17 @@ func NewCredentialEC(aToGamma, bToGamma, AToGamma, BToGamma *ECGroupElement,
 	}
 }
 
+// newCredentialEC translates emmy's native ecpseudsys.Cred to compatibility CredentialEC.
+func newCredentialEC(c *ecpseudsys.Cred) *CredentialEC {
+	t1 := NewTranscriptEC(c.T1.Alpha_1.String(), c.T1.Alpha_2.String(), c.T1.Beta_1.String(),
+		c.T1.Beta_2.String(), c.T1.Hash.String(), c.T1.ZAlpha.String())
+	t2 := NewTranscriptEC(c.T2.Alpha_1.String(), c.T2.Alpha_2.String(), c.T2.Beta_1.String(),
+		c.T2.Beta_2.String(), c.T2.Hash.String(), c.T2.ZAlpha.String())
+
+	return NewCredentialEC(newECGroupElement(c.SmallAToGamma), newECGroupElement(c.SmallBToGamma),
+		newECGroupElement(c.AToGamma), newECGroupElement(c.BToGamma), t1, t2)
+}
+
 // getNativeType translates compatibility CredentialEC to emmy's native ecpseudsys.Cred.
 func (c *CredentialEC) getNativeType() (*ecpseudsys.Cred, error) {
 	aTg, err := c.SmallAToGamma.getNativeType()
@@ -229,38 +240,7 @@ func (c *PseudonymsysClientEC) ObtainCredential(userSecret string,
 	}
 
 	// Translate from native emmy types to compatibility types
-	t1 := NewTranscriptEC(
-		credential.T1.Alpha_1.String(),
-		credential.T1.Alpha_2.String(),
-		credential.T1.Beta_1.String(),
-		credential.T1.Beta_2.String(),
-		credential.T1.Hash.String(),
-		credential.T1.ZAlpha.String())
-	t2 := NewTranscriptEC(
-		credential.T2.Alpha_1.String(),
-		credential.T2.Alpha_2.String(),
-		credential.T2.Beta_1.String(),
-		credential.T2.Beta_2.String(),
-		credential.T2.Hash.String(),
-		credential.T2.ZAlpha.String())
-	smallAToGamma := NewECGroupElement(
-		credential.SmallAToGamma.X.String(),
-		credential.SmallAToGamma.Y.String(),
-	)
-	smallBToGamma := NewECGroupElement(
-		credential.SmallBToGamma.X.String(),
-		credential.SmallBToGamma.Y.String(),
-	)
-	aToGamma := NewECGroupElement(
-		credential.AToGamma.X.String(),
-		credential.AToGamma.Y.String(),
-	)
-	bToGamma := NewECGroupElement(
-		credential.BToGamma.X.String(),
-		credential.BToGamma.Y.String(),
-	)
-
-	return NewCredentialEC(smallAToGamma, smallBToGamma, aToGamma, bToGamma, t1, t2), nil
+	return newCredentialEC(credential), nil
 }
 
 func (c *PseudonymsysClientEC) TransferCredential(orgName, userSecret string,
@@ -49,6 +49,11 @@ func NewECGroupElement(x, y string) *ECGroupElement {
 	}
 }
 
+// newECGroupElement translates emmy's native ec.GroupElement to compatibility ECGroupElement.
+func newECGroupElement(e *ec.GroupElement) *ECGroupElement {
+	return NewECGroupElement(e.X.String(), e.Y.String())
+}
+
 // getNativeType translates compatibility ECGroupElement to emmy's native ec.GroupElement.
 func (e *ECGroupElement) getNativeType() (*ec.GroupElement, error) {
 	x, xOk := new(big.Int).SetString(e.X, 10)
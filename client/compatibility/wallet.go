@@ -0,0 +1,207 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package compatibility
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/ec"
+	"github.com/xlab-si/emmy/wallet"
+)
+
+// StoredCredential is a pseudonym system credential held in the Wallet together
+// with the data needed to transfer it (see PseudonymsysClient.TransferCredential).
+type StoredCredential struct {
+	OrgName    string
+	UserSecret string
+	Nym        *Pseudonym
+	Cred       *Credential
+	PubKey     *PubKey
+}
+
+// StoredCredentialEC is a pseudonym system credential (elliptic curves) held in the
+// Wallet together with the data needed to transfer it
+// (see PseudonymsysClientEC.TransferCredential).
+type StoredCredentialEC struct {
+	OrgName    string
+	Curve      int
+	UserSecret string
+	Nym        *PseudonymEC
+	Cred       *CredentialEC
+	PubKey     *PubKeyEC
+}
+
+// Wallet wraps around wallet.Wallet to conform to type restrictions of Go language
+// binding tools. It stores pseudonym system credentials, credentials are listed
+// by their names (see Count and Name).
+type Wallet struct {
+	*wallet.Wallet
+}
+
+// CreateWallet creates a new empty wallet at path, protected by passphrase.
+func CreateWallet(path, passphrase string) (*Wallet, error) {
+	w, err := wallet.Create(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		Wallet: w,
+	}, nil
+}
+
+// OpenWallet opens an existing wallet at path, protected by passphrase.
+func OpenWallet(path, passphrase string) (*Wallet, error) {
+	w, err := wallet.Open(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		Wallet: w,
+	}, nil
+}
+
+// Count returns the number of credentials in the wallet.
+func (w *Wallet) Count() int {
+	return len(w.Wallet.List())
+}
+
+// Name returns the name of the i-th credential, credentials are ordered by their names.
+func (w *Wallet) Name(i int) (string, error) {
+	creds := w.Wallet.List()
+	if i < 0 || i >= len(creds) {
+		return "", fmt.Errorf("no credential at index %d", i)
+	}
+
+	return creds[i].Name, nil
+}
+
+// Type returns the type of the credential with the given name
+// (pseudonymsys or pseudonymsys_ec).
+func (w *Wallet) Type(name string) (string, error) {
+	c, err := w.Wallet.Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Type, nil
+}
+
+// SelectedName returns the name of the selected credential.
+func (w *Wallet) SelectedName() (string, error) {
+	c, err := w.Wallet.Selected()
+	if err != nil {
+		return "", err
+	}
+
+	return c.Name, nil
+}
+
+// AddPseudonymsysCredential adds a credential which was obtained from the organization
+// orgName for the nym to the wallet.
+func (w *Wallet) AddPseudonymsysCredential(name, orgName, userSecret string,
+	nym *Pseudonym, cred *Credential, pubKey *PubKey) error {
+	secret, secretOk := new(big.Int).SetString(userSecret, 10)
+	if !secretOk {
+		return fmt.Errorf("secret (%s): %s", userSecret, ArgsConversionError)
+	}
+	pseudonym, err := nym.getNativeType()
+	if err != nil {
+		return err
+	}
+	credential, err := cred.getNativeType()
+	if err != nil {
+		return err
+	}
+	key, err := pubKey.getNativeType()
+	if err != nil {
+		return err
+	}
+
+	return w.Wallet.Add(wallet.NewPseudonymsysCredential(name, orgName, secret, pseudonym,
+		credential, key))
+}
+
+// GetPseudonymsysCredential returns the pseudonym system credential with the given name.
+func (w *Wallet) GetPseudonymsysCredential(name string) (*StoredCredential, error) {
+	c, err := w.Wallet.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if c.Type != wallet.PseudonymsysType {
+		return nil, fmt.Errorf("credential %s is of type %s", name, c.Type)
+	}
+
+	p := c.Pseudonymsys
+	return &StoredCredential{
+		OrgName:    p.OrgName,
+		UserSecret: p.UserSecret.String(),
+		Nym:        NewPseudonym(p.Nym.A.String(), p.Nym.B.String()),
+		Cred:       newCredential(p.Cred),
+		PubKey:     NewPubKey(p.PubKey.H1.String(), p.PubKey.H2.String()),
+	}, nil
+}
+
+// AddPseudonymsysECCredential adds a credential which was obtained from the organization
+// orgName for the nym to the wallet.
+func (w *Wallet) AddPseudonymsysECCredential(name, orgName string, curve int, userSecret string,
+	nym *PseudonymEC, cred *CredentialEC, pubKey *PubKeyEC) error {
+	secret, secretOk := new(big.Int).SetString(userSecret, 10)
+	if !secretOk {
+		return fmt.Errorf("secret (%s): %s", userSecret, ArgsConversionError)
+	}
+	pseudonym, err := nym.getNativeType()
+	if err != nil {
+		return err
+	}
+	credential, err := cred.getNativeType()
+	if err != nil {
+		return err
+	}
+	key, err := pubKey.getNativeType()
+	if err != nil {
+		return err
+	}
+
+	return w.Wallet.Add(wallet.NewPseudonymsysECCredential(name, orgName, ec.Curve(curve), secret,
+		pseudonym, credential, key))
+}
+
+// GetPseudonymsysECCredential returns the pseudonym system credential (elliptic curves)
+// with the given name.
+func (w *Wallet) GetPseudonymsysECCredential(name string) (*StoredCredentialEC, error) {
+	c, err := w.Wallet.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if c.Type != wallet.PseudonymsysECType {
+		return nil, fmt.Errorf("credential %s is of type %s", name, c.Type)
+	}
+
+	p := c.PseudonymsysEC
+	return &StoredCredentialEC{
+		OrgName:    p.OrgName,
+		Curve:      int(p.Curve),
+		UserSecret: p.UserSecret.String(),
+		Nym:        NewPseudonymEC(newECGroupElement(p.Nym.A), newECGroupElement(p.Nym.B)),
+		Cred:       newCredentialEC(p.Cred),
+		PubKey:     NewPubKeyEC(newECGroupElement(p.PubKey.H1), newECGroupElement(p.PubKey.H2)),
+	}, nil
+}
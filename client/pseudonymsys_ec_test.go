@@ -57,7 +57,10 @@ func TestPseudonymsysEC(t *testing.T) {
 	assert.NotNil(t, err, "Should produce an error")
 
 	orgName := "org1"
-	orgPubKeys := config.LoadPseudonymsysOrgPubKeysEC(orgName)
+	orgPubKeys, err := config.LoadPseudonymsysOrgPubKeysEC(orgName)
+	if err != nil {
+		t.Errorf(err.Error())
+	}
 	credential, err := c1.ObtainCredential(userSecret, nym1, orgPubKeys)
 	if err != nil {
 		t.Errorf(err.Error())
@@ -58,7 +58,10 @@ func TestPseudonymsys(t *testing.T) {
 	assert.NotNil(t, err, "Should produce an error")
 
 	orgName := "org1"
-	orgPubKeys := config.LoadPseudonymsysOrgPubKeys(orgName)
+	orgPubKeys, err := config.LoadPseudonymsysOrgPubKeys(orgName)
+	if err != nil {
+		t.Errorf(err.Error())
+	}
 	credential, err := c1.ObtainCredential(userSecret, nym1, orgPubKeys)
 	if err != nil {
 		t.Errorf(err.Error())
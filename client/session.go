@@ -0,0 +1,107 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/xlab-si/emmy/crypto/cl"
+	pb "github.com/xlab-si/emmy/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// sessionKeyMetadata is the key of the gRPC metadata holding the session key
+// (see server.SESSION_KEY_METADATA).
+const sessionKeyMetadata = "session-key"
+
+// Session holds what the server learned about the user when the session was
+// established by proving the possession of credentials.
+type Session struct {
+	Creds  []*SessionCred
+	Expiry time.Time
+}
+
+// SessionCred describes a credential proved when the session was established.
+type SessionCred struct {
+	OrgName string
+	// Attrs are the revealed known attributes (mapped by attribute names)
+	Attrs map[string]string
+	// Policy is the verification policy satisfied by the credential
+	Policy *cl.VerificationPolicy
+}
+
+// SessionClient accesses the session on the server with the session key obtained
+// by proving credentials (see CLClient.ProveCredential).
+type SessionClient struct {
+	grpcClient pb.SessionClient
+	sessionKey string
+}
+
+func NewSessionClient(conn *grpc.ClientConn, sessionKey string) (*SessionClient, error) {
+	return &SessionClient{
+		grpcClient: pb.NewSessionClient(conn),
+		sessionKey: sessionKey,
+	}, nil
+}
+
+// context returns the context of the calls, which holds the session key.
+func (c *SessionClient) context() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), sessionKeyMetadata,
+		c.sessionKey)
+}
+
+// GetSession returns the session. It fails when the session has expired or ended.
+func (c *SessionClient) GetSession() (*Session, error) {
+	info, err := c.grpcClient.GetSession(c.context(), &empty.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]*SessionCred, len(info.Creds))
+	for i, cred := range info.Creds {
+		var policy cl.VerificationPolicy
+		if err := json.Unmarshal([]byte(cred.GetPolicy()), &policy); err != nil {
+			return nil, fmt.Errorf("invalid verification policy for %s: %v", cred.GetOrgName(), err)
+		}
+		attrs := make(map[string]string, len(cred.Attrs))
+		for _, a := range cred.Attrs {
+			attrs[a.GetName()] = a.GetValue()
+		}
+		creds[i] = &SessionCred{
+			OrgName: cred.GetOrgName(),
+			Attrs:   attrs,
+			Policy:  &policy,
+		}
+	}
+
+	return &Session{
+		Creds:  creds,
+		Expiry: time.Unix(info.GetExpiry(), 0),
+	}, nil
+}
+
+// Logout ends the session, the session key cannot be used afterwards.
+func (c *SessionClient) Logout() error {
+	_, err := c.grpcClient.Logout(c.context(), &empty.Empty{})
+	return err
+}
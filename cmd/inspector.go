@@ -0,0 +1,122 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/crypto/encryption"
+)
+
+var InspectorCmd = cli.Command{
+	Name:  "inspector",
+	Usage: "An inspector that can reveal the attributes escrowed in CL proofs",
+	Subcommands: []cli.Command{
+		{
+			Name:  "decrypt",
+			Usage: "Decrypts the escrowed attribute stored by emmy server",
+			Flags: append(storageFlags,
+				// idFlag keeps the ID of the escrow record, as reported by the server.
+				&cli.StringFlag{
+					Name:  "id",
+					Value: "",
+					Usage: "`ID` of the escrow record",
+				},
+				// seckeyFlag keeps the path to the secret key of the inspector, which is
+				// known only to the inspector (and not to emmy server).
+				&cli.StringFlag{
+					Name:  "seckey",
+					Value: "inspectorSecKey.json",
+					Usage: "`PATH` of the secret key of the inspector",
+				}),
+			Action: func(ctx *cli.Context) error {
+				st, err := openStorage(ctx)
+				if err != nil {
+					return cli.NewExitError(err, 1)
+				}
+				defer st.close()
+				val, err := decryptEscrowRecord(st.recMgr, ctx.String("id"), ctx.String("seckey"))
+				if err != nil {
+					return cli.NewExitError(err, 1)
+				}
+				fmt.Println(val)
+
+				return nil
+			},
+		},
+	},
+}
+
+// decryptEscrowRecord loads the escrow record with the given ID from the storage of emmy
+// server and decrypts it with the secret key of the inspector at secKeyPath (the public key
+// is given in the configuration of the escrow). The decrypted attribute is decoded
+// according to the credential structure of the organization which requires the escrow.
+func decryptEscrowRecord(records cl.EscrowRecordManager, id, secKeyPath string) (interface{},
+	error) {
+	if id == "" {
+		return nil, fmt.Errorf("ID of the escrow record is missing")
+	}
+
+	rec, err := records.LoadEscrowRecord(id)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := config.LoadEscrow()
+	pubKey, err := encryption.ReadCSPaillierPubKey(conf.PubKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	if rec.KeyID != pubKey.GetID() {
+		return nil, fmt.Errorf("attribute is escrowed to another inspector (key %s)",
+			rec.KeyID)
+	}
+	secKey, err := encryption.ReadCSPaillierSecKey(secKeyPath, pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := rec.Decrypt(secKey)
+	if err != nil {
+		return nil, err
+	}
+
+	structure, err := config.LoadCredentialStructure(conf.OrgName)
+	if err != nil {
+		return nil, err
+	}
+	attrs, _, err := cl.ParseAttrs(structure)
+	if err != nil {
+		return nil, err
+	}
+	// the escrowed attribute is given by its index among known attributes
+	known := 0
+	for _, a := range attrs {
+		if a != nil && a.IsKnown() {
+			if known == rec.AttrIndex {
+				return a.FromInternalValue(m)
+			}
+			known++
+		}
+	}
+
+	return m, nil
+}
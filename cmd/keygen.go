@@ -0,0 +1,288 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/crypto/ec"
+	"github.com/xlab-si/emmy/crypto/ecpseudsys"
+	"github.com/xlab-si/emmy/crypto/encryption"
+	"github.com/xlab-si/emmy/crypto/pseudsys"
+)
+
+var KeygenCmd = cli.Command{
+	Name:  "keygen",
+	Usage: "Generates keys of the organizations (issuers) and the CA",
+	Subcommands: []cli.Command{
+		{
+			Name: "cl",
+			Usage: "Generates CL issuer keys for the credential structure given in the " +
+				"configuration",
+			Flags: append(keyFileFlags("clPubKey.json", "clSecKey.json"),
+				// profileFlag keeps the name of the CL parameters profile.
+				&cli.StringFlag{
+					Name:  "profile",
+					Value: config.LoadCLParamsProfile(),
+					Usage: "`NAME` of the CL parameters profile (test, 2048 or 3072)",
+				},
+				// paramsFlag keeps the path to CL parameters in JSON format (optional).
+				&cli.StringFlag{
+					Name:  "params",
+					Value: "",
+					Usage: "`PATH` to CL parameters (cl.Params) in JSON format, the " +
+						"parameters of the profile are used if not set",
+				},
+				// orgFlag keeps the name of the organization whose credential structure is used.
+				&cli.StringFlag{
+					Name:  "org",
+					Value: "",
+					Usage: "`NAME` of the organization whose credential structure (cl_attributes) " +
+						"is used, the default structure (attributes) is used if not set",
+				}),
+			Action: func(ctx *cli.Context) error {
+				return keygen(func() (string, error) {
+					return generateCLKeys(ctx.String("org"), ctx.String("profile"),
+						ctx.String("params"), ctx.String("pubkey"), ctx.String("seckey"))
+				})
+			},
+		},
+		{
+			Name: "cl-split",
+			Usage: "Splits the CL issuer secret key among the parties of the threshold " +
+				"issuance",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "pubkey",
+					Value: "clPubKey.json",
+					Usage: "`PATH` to the public key",
+				},
+				&cli.StringFlag{
+					Name:  "seckey",
+					Value: "clSecKey.json",
+					Usage: "`PATH` to the secret key which is split (it should be deleted afterwards)",
+				},
+				&cli.IntFlag{
+					Name:  "threshold",
+					Value: 2,
+					Usage: "`NUMBER` of parties needed to issue a credential",
+				},
+				&cli.IntFlag{
+					Name:  "parties",
+					Value: 3,
+					Usage: "`NUMBER` of all parties",
+				},
+				&cli.StringFlag{
+					Name:  "shares",
+					Value: "clShare%d.json",
+					Usage: "`PATTERN` of the paths where the shares will be written (%d is " +
+						"replaced by the index of the party)",
+				},
+				&cli.StringFlag{
+					Name:  "coordinator-seckey",
+					Value: "clSecKeyCoordinator.json",
+					Usage: "`PATH` where the secret key of the coordinator will be written",
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				return keygen(func() (string, error) {
+					return splitCLKeys(ctx.String("pubkey"), ctx.String("seckey"),
+						ctx.Int("threshold"), ctx.Int("parties"), ctx.String("shares"),
+						ctx.String("coordinator-seckey"))
+				})
+			},
+		},
+		{
+			Name:  "inspector",
+			Usage: "Generates keys of the inspector for identity escrow (Camenisch-Shoup)",
+			Flags: append(keyFileFlags("inspectorPubKey.json", "inspectorSecKey.json"),
+				// bitLenFlag keeps the bit length of the primes of the inspector's modulus.
+				&cli.IntFlag{
+					Name:  "bitlen",
+					Value: minInspectorBitLen,
+					Usage: fmt.Sprintf("`BITS` of the (Sophie Germain) primes of the modulus, "+
+						"which is twice as long, at least %d", minInspectorBitLen),
+				}),
+			Action: func(ctx *cli.Context) error {
+				return keygen(func() (string, error) {
+					return generateInspectorKeys(ctx.Int("bitlen"), ctx.String("pubkey"),
+						ctx.String("seckey"))
+				})
+			},
+		},
+		{
+			Name:  "pseudonymsys",
+			Usage: "Generates keys of an organization for pseudonym system (modular arithmetic)",
+			Flags: keyFileFlags("pseudonymsysPubKey.json", "pseudonymsysSecKey.json"),
+			Action: func(ctx *cli.Context) error {
+				return keygen(func() (string, error) {
+					secKey, pubKey := pseudsys.GenerateKeyPair(config.LoadSchnorrGroup())
+					return pubKey.GetID(), pseudsys.WriteKeyPair(ctx.String("pubkey"),
+						ctx.String("seckey"), secKey, pubKey)
+				})
+			},
+		},
+		{
+			Name:  "ecpseudonymsys",
+			Usage: "Generates keys of an organization for pseudonym system (EC arithmetic)",
+			Flags: keyFileFlags("ecpseudonymsysPubKey.json", "ecpseudonymsysSecKey.json"),
+			Action: func(ctx *cli.Context) error {
+				return keygen(func() (string, error) {
+					secKey, pubKey := ecpseudsys.GenerateKeyPair(ec.NewGroup(ec.P256))
+					return pubKey.GetID(), ecpseudsys.WriteKeyPair(ctx.String("pubkey"),
+						ctx.String("seckey"), secKey, pubKey)
+				})
+			},
+		},
+		{
+			Name:  "pseudonymsys-ca",
+			Usage: "Generates keys of the CA for pseudonym system",
+			Flags: keyFileFlags("pseudonymsysCAPubKey.json", "pseudonymsysCASecKey.json"),
+			Action: func(ctx *cli.Context) error {
+				return keygen(func() (string, error) {
+					d, pubKey, err := pseudsys.GenerateCAKeyPair()
+					if err != nil {
+						return "", err
+					}
+					return pubKey.GetID(), pseudsys.WriteCAKeyPair(ctx.String("pubkey"),
+						ctx.String("seckey"), d, pubKey)
+				})
+			},
+		},
+	},
+}
+
+// keyFileFlags returns the flags for the paths of the public and the secret key file.
+func keyFileFlags(pubKeyPath, secKeyPath string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "pubkey",
+			Value: pubKeyPath,
+			Usage: "`PATH` where the public key will be written",
+		},
+		&cli.StringFlag{
+			Name:  "seckey",
+			Value: secKeyPath,
+			Usage: "`PATH` where the secret key will be written (readable only by the owner)",
+		},
+	}
+}
+
+// keygen runs the key generation function gen and reports the ID of the generated key.
+func keygen(gen func() (string, error)) error {
+	keyID, err := gen()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Printf("Generated key %s\n", keyID)
+
+	return nil
+}
+
+// generateCLKeys generates CL issuer keys for the credential structure of the organization
+// orgName from the configuration (the master secret is encoded as an additional hidden
+// attribute) and returns the ID of the public key. The parameters are read from paramsPath if
+// set, otherwise the parameters of the given profile are used.
+func generateCLKeys(orgName, profile, paramsPath, pubKeyPath, secKeyPath string) (string, error) {
+	var params *cl.Params
+	var err error
+	if paramsPath != "" {
+		params, err = cl.ReadParams(paramsPath)
+	} else {
+		params, err = cl.GetParamProfile(profile)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	structure, err := config.LoadCredentialStructure(orgName)
+	if err != nil {
+		return "", err
+	}
+	_, attrCount, err := cl.ParseAttrs(structure)
+	if err != nil {
+		return "", err
+	}
+	attrCount.Hidden++
+	params.KnownAttrsNum = attrCount.Known
+	params.CommittedAttrsNum = attrCount.Committed
+	params.HiddenAttrsNum = attrCount.Hidden
+
+	keys, err := cl.GenerateKeyPair(params, attrCount)
+	if err != nil {
+		return "", err
+	}
+
+	return keys.Pub.GetID(), cl.WriteKeyPair(pubKeyPath, secKeyPath, params, keys)
+}
+
+// minInspectorBitLen is the smallest bit length of the primes of the inspector's modulus
+// which emmy keygen accepts (it gives a modulus of 2048 bits).
+const minInspectorBitLen = 1024
+
+// generateInspectorKeys generates the Camenisch-Shoup keys of the inspector whose modulus
+// is the product of safe primes 2p'+1, where p' has bitLen bits, and returns the ID of
+// the public key.
+func generateInspectorKeys(bitLen int, pubKeyPath, secKeyPath string) (string, error) {
+	if bitLen < minInspectorBitLen {
+		return "", fmt.Errorf("bit length of the primes needs to be at least %d",
+			minInspectorBitLen)
+	}
+
+	csp := encryption.NewCSPaillier(&encryption.CSPaillierSecParams{
+		L:        bitLen,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	})
+
+	return csp.PubKey.GetID(), encryption.WriteCSPaillierKeyPair(pubKeyPath, secKeyPath,
+		csp.SecKey, csp.PubKey)
+}
+
+// splitCLKeys splits the CL issuer secret key into shares for the threshold issuance and
+// writes the shares and the secret key of the coordinator (the secret key without the part
+// which is split). It returns the ID of the public key.
+func splitCLKeys(pubKeyPath, secKeyPath string, threshold, parties int, sharesPath,
+	coordinatorSecKeyPath string) (string, error) {
+	params, pubKey, err := cl.ReadPubKey(pubKeyPath)
+	if err != nil {
+		return "", err
+	}
+	secKey, err := cl.ReadSecKey(secKeyPath, pubKey)
+	if err != nil {
+		return "", err
+	}
+
+	shares, coordinatorKey, err := cl.SplitSecKey(params, &cl.KeyPair{Pub: pubKey, Sec: secKey},
+		threshold, parties)
+	if err != nil {
+		return "", err
+	}
+	for _, share := range shares {
+		path := fmt.Sprintf(sharesPath, share.Index)
+		if err := cl.WriteThresholdKeyShare(path, pubKey, share); err != nil {
+			return "", err
+		}
+	}
+
+	return pubKey.GetID(), cl.WriteSecKey(coordinatorSecKeyPath, pubKey, coordinatorKey)
+}
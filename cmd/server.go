@@ -19,13 +19,12 @@ package cmd
 
 import (
 	"path/filepath"
+	"strings"
 
 	"fmt"
 
-	"github.com/go-redis/redis"
 	"github.com/urfave/cli"
 	"github.com/xlab-si/emmy/config"
-	"github.com/xlab-si/emmy/crypto/cl"
 	"github.com/xlab-si/emmy/log"
 	"github.com/xlab-si/emmy/server"
 )
@@ -37,13 +36,18 @@ var ServerCmd = cli.Command{
 		{
 			Name:  "start",
 			Usage: "Starts emmy server",
-			Flags: serverFlags,
+			Flags: append(serverFlags, storageFlags...),
 			Action: func(ctx *cli.Context) error {
-				err := startEmmyServer(
+				st, err := openStorage(ctx)
+				if err != nil {
+					return cli.NewExitError(err, 1)
+				}
+				defer st.close()
+				err = startEmmyServer(
 					ctx.Int("port"),
 					ctx.String("cert"),
 					ctx.String("key"),
-					ctx.String("db"),
+					st,
 					ctx.String("logfile"),
 					ctx.String("loglevel"))
 				if err != nil {
@@ -52,6 +56,23 @@ var ServerCmd = cli.Command{
 				return nil
 			},
 		},
+		{
+			Name:      "regkey",
+			Usage:     "Adds registration keys to the storage of emmy server",
+			ArgsUsage: "KEY...",
+			Flags: append(storageFlags,
+				// attrFlag keeps the values of the known attributes provisioned for the keys.
+				&cli.StringSliceFlag{
+					Name:  "attr",
+					Usage: "`NAME=VALUE` of a credential attribute provisioned for the keys",
+				}),
+			Action: func(ctx *cli.Context) error {
+				if err := addRegistrationKeys(ctx); err != nil {
+					return cli.NewExitError(err, 1)
+				}
+				return nil
+			},
+		},
 	},
 }
 
@@ -77,12 +98,6 @@ var serverFlags = []cli.Flag{
 		Value: filepath.Join(config.LoadTestdataDir(), "server.key"),
 		Usage: "`PATH` to server key file",
 	},
-	// dbEndpointFlag points to the endpoint at which emmy server will contact redis database.
-	&cli.StringFlag{
-		Name:  "db",
-		Value: config.LoadRegistrationDBAddress(),
-		Usage: "`URI` of redis database to hold registration keys, in the form redisHost:redisPort",
-	},
 	// logFilePathFlag indicates a path to the log file used by the server (optional).
 	&cli.StringFlag{
 		Name:  "logfile",
@@ -93,7 +108,8 @@ var serverFlags = []cli.Flag{
 }
 
 // startEmmyServer configures and starts the gRPC server at the desired port
-func startEmmyServer(port int, certPath, keyPath, dbAddress, logFilePath, logLevel string) error {
+func startEmmyServer(port int, certPath, keyPath string, st *storage, logFilePath,
+	logLevel string) error {
 	var err error
 	var logger log.Logger
 
@@ -107,26 +123,47 @@ func startEmmyServer(port int, certPath, keyPath, dbAddress, logFilePath, logLev
 		return err
 	}
 
-	c := redis.NewClient(&redis.Options{
-		Addr: dbAddress,
-	})
-	err = c.Ping().Err()
-	if err != nil {
-		return fmt.Errorf("unable to connect to redis database (%s)", err)
-	}
-
-	registrationManager := server.NewRedisClient(c)
+	srv, err := server.NewServer(certPath, keyPath, st.regMgr, st.recMgr, logger)
 	if err != nil {
 		return err
 	}
 
-	recordManager := cl.NewRedisClient(c)
+	srv.EnableTracing()
+	return srv.Start(port)
+}
+
+// addRegistrationKeys adds the registration keys given as arguments, with the attributes
+// given by the attr flags, to the storage backend selected by storageFlags.
+func addRegistrationKeys(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return fmt.Errorf("registration keys are missing")
+	}
+	attrs := make(map[string]string)
+	for _, a := range ctx.StringSlice("attr") {
+		kv := strings.SplitN(a, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("attribute %s is not given as NAME=VALUE", a)
+		}
+		attrs[kv[0]] = kv[1]
+	}
 
-	srv, err := server.NewServer(certPath, keyPath, registrationManager, recordManager, logger)
+	st, err := openStorage(ctx)
 	if err != nil {
 		return err
 	}
+	defer st.close()
 
-	srv.EnableTracing()
-	return srv.Start(port)
+	adder, ok := st.regMgr.(interface {
+		AddRegistrationKey(string, map[string]string) error
+	})
+	if !ok {
+		return fmt.Errorf("storage backend does not support adding registration keys")
+	}
+	for _, key := range ctx.Args() {
+		if err := adder.AddRegistrationKey(key, attrs); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
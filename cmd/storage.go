@@ -0,0 +1,143 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/urfave/cli"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/server"
+	bolt "go.etcd.io/bbolt"
+)
+
+// storageFlags select the storage backend holding registration keys, CL receiver records
+// and escrow records.
+var storageFlags = []cli.Flag{
+	// storageFlag keeps the name of the storage backend.
+	&cli.StringFlag{
+		Name:  "storage",
+		Value: "redis",
+		Usage: "`BACKEND` holding registration keys and CL records: redis, bolt (embedded " +
+			"database in a file) or sql",
+	},
+	// dbEndpointFlag points to the database of the storage backend.
+	&cli.StringFlag{
+		Name:  "db",
+		Value: "",
+		Usage: "`URI` of the database: redisHost:redisPort for redis (registration_db_address " +
+			"from the configuration by default), a file path for bolt (emmy.db by default) " +
+			"or the data source name for sql",
+	},
+	// sqlDriverFlag keeps the name of the database/sql driver used by the sql backend.
+	&cli.StringFlag{
+		Name:  "sql-driver",
+		Value: "postgres",
+		Usage: "`NAME` of the SQL driver for the sql backend (postgres or sqlite3)",
+	},
+}
+
+// storage holds the managers of the selected storage backend.
+type storage struct {
+	regMgr server.RegistrationManager
+	recMgr interface {
+		cl.ReceiverRecordManager
+		cl.EscrowRecordManager
+	}
+	close func() error
+}
+
+// openStorage connects to the storage backend selected by storageFlags.
+func openStorage(ctx *cli.Context) (*storage, error) {
+	db := ctx.String("db")
+	switch ctx.String("storage") {
+	case "redis":
+		if db == "" {
+			db = config.LoadRegistrationDBAddress()
+		}
+		c := redis.NewClient(&redis.Options{
+			Addr: db,
+		})
+		if err := c.Ping().Err(); err != nil {
+			return nil, fmt.Errorf("unable to connect to redis database (%s)", err)
+		}
+		return &storage{
+			regMgr: server.NewRedisClient(c),
+			recMgr: cl.NewRedisClient(c),
+			close:  c.Close,
+		}, nil
+	case "bolt":
+		if db == "" {
+			db = "emmy.db"
+		}
+		// the database can be opened by one process at a time
+		boltDB, err := bolt.Open(db, 0600, &bolt.Options{Timeout: time.Second})
+		if err != nil {
+			return nil, fmt.Errorf("unable to open bolt database %s (%s)", db, err)
+		}
+		regMgr, err := server.NewBoltRegistrationManager(boltDB)
+		if err != nil {
+			boltDB.Close()
+			return nil, err
+		}
+		recMgr, err := cl.NewBoltRecordManager(boltDB)
+		if err != nil {
+			boltDB.Close()
+			return nil, err
+		}
+		return &storage{
+			regMgr: regMgr,
+			recMgr: recMgr,
+			close:  boltDB.Close,
+		}, nil
+	case "sql":
+		sqlDB, err := sql.Open(ctx.String("sql-driver"), db)
+		if err == nil {
+			err = sqlDB.Ping()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to sql database (%s)", err)
+		}
+		if ctx.String("sql-driver") == "sqlite3" {
+			// SQLite allows only one writer at a time
+			sqlDB.SetMaxOpenConns(1)
+		}
+		regMgr, err := server.NewSQLRegistrationManager(sqlDB)
+		if err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+		recMgr, err := cl.NewSQLRecordManager(sqlDB)
+		if err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+		return &storage{
+			regMgr: regMgr,
+			recMgr: recMgr,
+			close:  sqlDB.Close,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %s", ctx.String("storage"))
+	}
+}
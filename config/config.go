@@ -20,18 +20,18 @@ package config
 import (
 	"fmt"
 	"math/big"
-	"strconv"
 	"strings"
+	"time"
 
 	"os"
 	"path/filepath"
 
 	"github.com/spf13/viper"
-	"github.com/xlab-si/emmy/crypto/ec"
 	"github.com/xlab-si/emmy/crypto/ecpseudsys"
 	"github.com/xlab-si/emmy/crypto/pseudsys"
 	"github.com/xlab-si/emmy/crypto/qr"
 	"github.com/xlab-si/emmy/crypto/schnorr"
+	"gopkg.in/yaml.v2"
 )
 
 // init loads the default config file
@@ -53,6 +53,9 @@ func setDefaults() {
 	viper.SetDefault("port", 7007)
 	viper.SetDefault("timeout", 5000)
 	viper.SetDefault("key_folder", "/tmp")
+	viper.SetDefault("cl_params", "test")
+	viper.SetDefault("session_ttl", 3600)
+	viper.SetDefault("escrow.org", "org1")
 
 	viper.SetDefault("schnorr_group",
 		map[string]string{
@@ -61,21 +64,22 @@ func setDefaults() {
 			"q": "98208916160055856584884864196345443685461747768186057136819930381973920107591",
 		})
 
-	pseudonymSysConfig := map[string]interface{}{
-		"user1": "10501840420714326611674814933629820564884994433464121609699657686381725481917946560951300989428757857663890749444810669658158959171443678666294156633031855300155147813954782039163197859065107569638424682758546743970421679581497316473363590677852615245790857416631205041294470157319811083478928657427332727532272060990285330797695681228920548209293494826378319240408357619741465896984159808329187249915415180748872721286083954030337580803742552856969769146625693488160927221403705265205532491725454404938155197720048433342625635727130205282673205600167729513490481034307616261949529737060447713783467988717455504863857",
-		"org1": map[string]string{
-			"h1": "11253748020267515701977135421640400742511414782332660443524776235731592618314865082641495270379529602832564697632543178140373575666207325449816651443326295587329200580969897900340682863137274403743213121482058992744156278265298975875832815615008349379091580640663544863825594755871212120449589876097254391036951735135790415340694042060640287135597503154554767593490141558733646631257590898412097094878970047567251318564175378758713497120310233239160479122314980866111775954564694480706227862890375180173977176588970220883117212300621045744043530072238840577201003052170999723878986905807102656657527667244456412473985",
-			"h2": "76168773256070905782197510623595125058465077612447809025568517977679494145178174622864958684725961070073576803345724904501942931513809178875449022568661712955904784104680061168715431907736821341951579763867969478146743783132963349845621343504647834967006527983684679901491401571352045358450346417143743546169924539113192750473927517206655311791719866371386836092309758541857984471638917674114075906273800379335165008797874367104743232737728633294061064784890416168238586934819945486226202990710177343797354424869474259809902990704930592533690341526792158132580375587182781640673464871125845158432761445006356929132",
-			"s1": "12506074624757438676805734108203754691894440935285828326752482161724637860737614838944853691950924021955680525939780169779888653151633785040698255721224889673095292103687696155341406413918220576785413168329472933244872017244493792250782071009945084029853097333491235700618768793380791519193695496653451014859995982030252835982728985237780700293860028372794252498821615457701308171489000104682637461824347934289263165371702030406332522768141151117618446117035451332086067049461921041400592944133730824346746397649572514314171499080783864209863802530233234409464167893803459953492866757869441725196031561816682693694247",
-			"s2": "130203329326872103700165538490407573774885754016819320468128775954825516",
+	viper.SetDefault("pseudonymsys", map[string]interface{}{
+		"org1": map[string]interface{}{
+			"dlog": map[string]string{
+				"pubkey": "pseudonymsysPubKey.json",
+				"seckey": "pseudonymsysSecKey.json",
+			},
+			"ecdlog": map[string]string{
+				"pubkey": "ecpseudonymsysPubKey.json",
+				"seckey": "ecpseudonymsysSecKey.json",
+			},
 		},
 		"ca": map[string]string{
-			"d":  "16249832937458088685598605121372353939294367897674422016342660883663371677076",
-			"x":  "65326558506481070730591115387915499623679021660430456972125964980023301473231",
-			"y1": "37526396936964061204061100652712760357856013823850948443144488667237183893571",
+			"pubkey": "pseudonymsysCAPubKey.json",
+			"seckey": "pseudonymsysCASecKey.json",
 		},
-	}
-	viper.SetDefault("pseudonymsys", pseudonymSysConfig)
+	})
 }
 
 // loadConfig reads in the config file with configName being the name of the file (without suffix)
@@ -144,43 +148,231 @@ func LoadQRRSA() *qr.RSA {
 	return qr
 }
 
-func LoadPseudonymsysOrgSecrets(orgName, dlogType string) *pseudsys.SecKey {
-	org := viper.GetStringMap(fmt.Sprintf("pseudonymsys.%s.%s", orgName, dlogType))
-	s1, _ := new(big.Int).SetString(org["s1"].(string), 10)
-	s2, _ := new(big.Int).SetString(org["s2"].(string), 10)
-	return pseudsys.NewSecKey(s1, s2)
+// loadKeyPath returns the path of the key file set under key in the configuration.
+// Relative paths are relative to the testdata directory.
+func loadKeyPath(key string) (string, error) {
+	path := viper.GetString(key)
+	if path == "" {
+		return "", fmt.Errorf("key file %s is not configured", key)
+	}
+
+	return keyPath(path), nil
+}
+
+// keyPath resolves the path of a key file relative to the testdata directory.
+func keyPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(LoadTestdataDir(), path)
+}
+
+// LoadPseudonymsysOrgSecrets loads the secret key of the organization for the given
+// dlogType (dlog or ecdlog) from the key file set in the configuration.
+func LoadPseudonymsysOrgSecrets(orgName, dlogType string) (*pseudsys.SecKey, error) {
+	path, err := loadKeyPath(fmt.Sprintf("pseudonymsys.%s.%s.seckey", orgName, dlogType))
+	if err != nil {
+		return nil, err
+	}
+	if dlogType == "ecdlog" {
+		pubKey, err := LoadPseudonymsysOrgPubKeysEC(orgName)
+		if err != nil {
+			return nil, err
+		}
+		return ecpseudsys.ReadSecKey(path, pubKey)
+	}
+
+	pubKey, err := LoadPseudonymsysOrgPubKeys(orgName)
+	if err != nil {
+		return nil, err
+	}
+	return pseudsys.ReadSecKey(path, pubKey)
+}
+
+func LoadPseudonymsysOrgPubKeys(orgName string) (*pseudsys.PubKey, error) {
+	path, err := loadKeyPath(fmt.Sprintf("pseudonymsys.%s.dlog.pubkey", orgName))
+	if err != nil {
+		return nil, err
+	}
+	return pseudsys.ReadPubKey(path)
+}
+
+func LoadPseudonymsysOrgPubKeysEC(orgName string) (*ecpseudsys.PubKey, error) {
+	path, err := loadKeyPath(fmt.Sprintf("pseudonymsys.%s.ecdlog.pubkey", orgName))
+	if err != nil {
+		return nil, err
+	}
+	return ecpseudsys.ReadPubKey(path)
+}
+
+func LoadPseudonymsysCASecret() (*big.Int, error) {
+	pubKey, err := LoadPseudonymsysCAPubKey()
+	if err != nil {
+		return nil, err
+	}
+	path, err := loadKeyPath("pseudonymsys.ca.seckey")
+	if err != nil {
+		return nil, err
+	}
+	return pseudsys.ReadCASecKey(path, pubKey)
 }
 
-func LoadPseudonymsysOrgPubKeys(orgName string) *pseudsys.PubKey {
-	org := viper.GetStringMap(fmt.Sprintf("pseudonymsys.%s.%s", orgName, "dlog"))
-	h1, _ := new(big.Int).SetString(org["h1"].(string), 10)
-	h2, _ := new(big.Int).SetString(org["h2"].(string), 10)
-	return pseudsys.NewPubKey(h1, h2)
+func LoadPseudonymsysCAPubKey() (*pseudsys.PubKey, error) {
+	path, err := loadKeyPath("pseudonymsys.ca.pubkey")
+	if err != nil {
+		return nil, err
+	}
+	return pseudsys.ReadCAPubKey(path)
 }
 
-func LoadPseudonymsysOrgPubKeysEC(orgName string) *ecpseudsys.PubKey {
-	org := viper.GetStringMap(fmt.Sprintf("pseudonymsys.%s.%s", orgName, "ecdlog"))
-	h1X, _ := new(big.Int).SetString(org["h1x"].(string), 10)
-	h1Y, _ := new(big.Int).SetString(org["h1y"].(string), 10)
-	h2X, _ := new(big.Int).SetString(org["h2x"].(string), 10)
-	h2Y, _ := new(big.Int).SetString(org["h2y"].(string), 10)
-	return ecpseudsys.NewPubKey(
-		ec.NewGroupElement(h1X, h1Y),
-		ec.NewGroupElement(h2X, h2Y),
-	)
+// CLKeyVersion describes a version of the keys of a CL organization. SecKeyPath is empty
+// when only the public key is configured. Zero NotBefore (NotAfter) means that the validity
+// of the keys is not bounded from below (above).
+// When Threshold is not zero, the secret key does not allow issuing credentials - the
+// credentials are issued by Threshold of the Parties (addresses of the servers holding
+// the shares of the secret key, the i-th party holds the share with index i+1).
+type CLKeyVersion struct {
+	PubKeyPath string
+	SecKeyPath string
+	NotBefore  time.Time
+	NotAfter   time.Time
+	Threshold  int
+	Parties    []string
 }
 
-func LoadPseudonymsysCASecret() *big.Int {
-	ca := viper.GetStringMap("pseudonymsys.ca")
-	s, _ := new(big.Int).SetString(ca["d"].(string), 10)
-	return s
+// LoadCLOrgNames returns the (lowercase) names of all CL organizations whose keys are
+// configured.
+func LoadCLOrgNames() []string {
+	var names []string
+	for name := range viper.GetStringMap("cl_keys") {
+		names = append(names, name)
+	}
+
+	return names
 }
 
-func LoadPseudonymsysCAPubKey() *pseudsys.PubKey {
-	ca := viper.GetStringMap("pseudonymsys.ca")
-	x, _ := new(big.Int).SetString(ca["x"].(string), 10)
-	y, _ := new(big.Int).SetString(ca["y1"].(string), 10)
-	return pseudsys.NewPubKey(x, y)
+// LoadCLKeyVersions returns all versions of the keys of the CL organization.
+func LoadCLKeyVersions(orgName string) ([]*CLKeyVersion, error) {
+	key := fmt.Sprintf("cl_keys.%s", orgName)
+	if !viper.IsSet(key) {
+		return nil, fmt.Errorf("keys of organization %s are not configured", orgName)
+	}
+
+	var entries []struct {
+		PubKey    string   `mapstructure:"pubkey"`
+		SecKey    string   `mapstructure:"seckey"`
+		NotBefore string   `mapstructure:"not_before"`
+		NotAfter  string   `mapstructure:"not_after"`
+		Threshold int      `mapstructure:"threshold"`
+		Parties   []string `mapstructure:"parties"`
+	}
+	if err := viper.UnmarshalKey(key, &entries); err != nil {
+		return nil, fmt.Errorf("error when reading keys of organization %s: %v", orgName, err)
+	}
+
+	versions := make([]*CLKeyVersion, len(entries))
+	for i, e := range entries {
+		if e.PubKey == "" {
+			return nil, fmt.Errorf("public key %d of organization %s is not configured", i, orgName)
+		}
+		if e.Threshold != 0 && (e.SecKey == "" || e.Threshold > len(e.Parties)) {
+			return nil, fmt.Errorf("threshold issuance with key %d of organization %s is "+
+				"not configured correctly", i, orgName)
+		}
+		v := &CLKeyVersion{
+			PubKeyPath: keyPath(e.PubKey),
+			Threshold:  e.Threshold,
+			Parties:    e.Parties,
+		}
+		if e.SecKey != "" {
+			v.SecKeyPath = keyPath(e.SecKey)
+		}
+		var err error
+		if v.NotBefore, err = parseKeyDate(e.NotBefore); err != nil {
+			return nil, err
+		}
+		if v.NotAfter, err = parseKeyDate(e.NotAfter); err != nil {
+			return nil, err
+		}
+		versions[i] = v
+	}
+
+	return versions, nil
+}
+
+// parseKeyDate parses the date (2006-01-02) or the time in RFC 3339 format. Empty
+// string is parsed as zero time.
+func parseKeyDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid key validity date %s", s)
+	}
+
+	return t, nil
+}
+
+// CLThresholdShare describes a share of a CL issuer secret key held by the server as
+// a party of the threshold issuance.
+type CLThresholdShare struct {
+	PubKeyPath string
+	SharePath  string
+}
+
+// LoadCLThresholdShares returns the shares of CL issuer secret keys held by the server.
+func LoadCLThresholdShares() ([]*CLThresholdShare, error) {
+	var entries []struct {
+		PubKey string `mapstructure:"pubkey"`
+		Share  string `mapstructure:"share"`
+	}
+	if err := viper.UnmarshalKey("cl_threshold.shares", &entries); err != nil {
+		return nil, fmt.Errorf("error when reading secret key shares: %v", err)
+	}
+
+	shares := make([]*CLThresholdShare, len(entries))
+	for i, e := range entries {
+		if e.PubKey == "" || e.Share == "" {
+			return nil, fmt.Errorf("secret key share %d is not configured", i)
+		}
+		shares[i] = &CLThresholdShare{
+			PubKeyPath: keyPath(e.PubKey),
+			SharePath:  keyPath(e.Share),
+		}
+	}
+
+	return shares, nil
+}
+
+// LoadCLThresholdToken returns the token which the coordinator of the threshold issuance
+// presents to the parties.
+func LoadCLThresholdToken() string {
+	return viper.GetString("cl_threshold.token")
+}
+
+// LoadCLRevocationTokens returns the tokens which permit the revocation of the credentials
+// of the organization with the given name - the token of its issuer (empty when it is not
+// configured) and the admin token (empty when it is not configured).
+func LoadCLRevocationTokens(orgName string) (issuerToken, adminToken string) {
+	issuerTokens := viper.GetStringMapString("cl_revocation.issuer_tokens")
+	return issuerTokens[strings.ToLower(orgName)], viper.GetString("cl_revocation.admin_token")
+}
+
+// LoadCLThresholdCACert returns the path to the certificate of the CA which issued
+// the TLS certificates of the parties of the threshold issuance.
+func LoadCLThresholdCACert() string {
+	return keyPath(viper.GetString("cl_threshold.ca_cert"))
+}
+
+// LoadCLParamsProfile returns the name of the CL parameters profile (see cl.GetParamProfile)
+// used when generating CL issuer keys.
+func LoadCLParamsProfile() string {
+	return viper.GetString("cl_params")
 }
 
 func LoadServiceInfo() (string, string, string) {
@@ -190,8 +382,48 @@ func LoadServiceInfo() (string, string, string) {
 	return serviceName, serviceProvider, serviceDescription
 }
 
-func LoadCredentialStructure() (map[string]interface{}, error) {
-	m := viper.GetStringMapString("attributes")
+// LoadPseudonymScope returns the scope for which the users need to present a domain
+// pseudonym when proving a CL credential (empty if pseudonyms are not required).
+func LoadPseudonymScope() string {
+	return viper.GetString("service_info.pseudonym_scope")
+}
+
+// EscrowConfig describes the identity escrow required by the server (see cl.Escrow) from
+// the users proving the credentials of organization OrgName. Attr is empty when no escrow
+// is required.
+type EscrowConfig struct {
+	OrgName    string
+	Attr       string
+	PubKeyPath string
+	Label      string
+}
+
+// LoadEscrow returns the configuration of the identity escrow (the path of the public key
+// of the inspector is resolved against the testdata directory). The secret key of the
+// inspector is never needed by the server.
+func LoadEscrow() *EscrowConfig {
+	return &EscrowConfig{
+		OrgName:    strings.ToLower(viper.GetString("escrow.org")),
+		Attr:       viper.GetString("escrow.attr"),
+		PubKeyPath: keyPath(viper.GetString("escrow.inspector_pubkey")),
+		Label:      viper.GetString("escrow.label"),
+	}
+}
+
+// LoadCredentialStructure returns the structure of the credentials of the CL organization
+// (see cl.ParseAttrs) - the structure from cl_attributes when it is configured for the
+// organization, otherwise the default structure from attributes.
+func LoadCredentialStructure(orgName string) (map[string]interface{}, error) {
+	key := "attributes"
+	if orgKey := fmt.Sprintf("cl_attributes.%s", strings.ToLower(orgName)); orgName != "" &&
+		viper.IsSet(orgKey) {
+		key = orgKey
+	}
+	m := viper.GetStringMapString(key)
+	if len(m) == 0 {
+		return nil, fmt.Errorf("credential structure of organization %s is not configured",
+			orgName)
+	}
 
 	attrs := make(map[string]interface{})
 	for k, v := range m {
@@ -206,63 +438,78 @@ func LoadCredentialStructure() (map[string]interface{}, error) {
 	return attrs, nil
 }
 
-func LoadAcceptableCredentials() (map[string][]string, error) {
-	m := viper.GetStringMapString("acceptable_credentials")
-	accCreds := make(map[string][]string)
-	for k, v := range m {
-		vs := strings.Split(v, ",")
-		var attrs []string
-		for _, i := range vs {
-			it := strings.Trim(i, " ")
-			attrs = append(attrs, it)
+// LoadAcceptableCredentials returns the verification policies (in YAML format, see
+// cl.VerificationPolicy) for the credentials of the organizations whose credentials are
+// accepted.
+func LoadAcceptableCredentials() (map[string][]byte, error) {
+	m := viper.GetStringMap("acceptable_credentials")
+	policies := make(map[string][]byte)
+	for name, p := range m {
+		data, err := yaml.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading policy for %s: %v", name, err)
 		}
-		accCreds[k] = attrs
+		policies[name] = data
 	}
 
-	return accCreds, nil
+	return policies, nil
 }
 
-func LoadConditions() (map[int]string, map[int]int, map[int]string, error) {
-	conditions := viper.GetStringMapString("conditions")
-	intValues := viper.GetStringMapString("int_values")
-	strValues := viper.GetStringMapString("str_values")
+// AttrEqualityConfig requires the attribute Attr1 in the credential of organization Org1
+// to have the same value as the attribute Attr2 in the credential of organization Org2
+// when both credentials are proved together (see cl.AttrEquality).
+type AttrEqualityConfig struct {
+	Org1  string
+	Attr1 string
+	Org2  string
+	Attr2 string
+}
 
-	conds := make(map[int]string)
-	for k, v := range conditions {
-		ind, err := strconv.Atoi(k)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-		conds[ind] = v
+// LoadAttrEqualities returns the equalities of attributes required in proofs of several
+// credentials. Each of them is configured as a pair of attributes given as org.attr.
+func LoadAttrEqualities() ([]*AttrEqualityConfig, error) {
+	data, err := yaml.Marshal(viper.Get("attr_equalities"))
+	if err != nil {
+		return nil, fmt.Errorf("error when reading attribute equalities: %v", err)
 	}
-	intVals := make(map[int]int)
-	for k, v := range intValues {
-		ind, err := strconv.Atoi(k)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-		val, err := strconv.Atoi(v)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-		intVals[ind] = val
+	var pairs [][]string
+	if err := yaml.Unmarshal(data, &pairs); err != nil {
+		return nil, fmt.Errorf("error when reading attribute equalities: %v", err)
 	}
-	strVals := make(map[int]string)
-	for k, v := range strValues {
-		ind, err := strconv.Atoi(k)
-		if err != nil {
-			return nil, nil, nil, err
+
+	equalities := make([]*AttrEqualityConfig, len(pairs))
+	for i, pair := range pairs {
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("attribute equality %d is not a pair of attributes", i)
 		}
-		strVals[ind] = v
+		e := new(AttrEqualityConfig)
+		for j, attr := range pair {
+			parts := strings.SplitN(attr, ".", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, fmt.Errorf("attribute %s is not given as org.attr", attr)
+			}
+			if j == 0 {
+				e.Org1, e.Attr1 = strings.ToLower(parts[0]), parts[1]
+			} else {
+				e.Org2, e.Attr2 = strings.ToLower(parts[0]), parts[1]
+			}
+		}
+		equalities[i] = e
 	}
 
-	return conds, intVals, strVals, nil
+	return equalities, nil
 }
 
 func LoadSessionKeyMinByteLen() int {
 	return viper.GetInt("session_key_bytelen")
 }
 
+// LoadSessionTTL returns the time after which the sessions established by proving
+// credentials expire.
+func LoadSessionTTL() time.Duration {
+	return time.Duration(viper.GetInt("session_ttl")) * time.Second
+}
+
 func LoadRegistrationDBAddress() string {
 	return viper.GetString("registration_db_address")
 }
@@ -0,0 +1,441 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+// Revocation of credentials is based on the dynamic accumulator from
+// Camenisch, Lysyanskaya: "Dynamic Accumulators and Application to Efficient
+// Revocation of Anonymous Credentials".
+//
+// The accumulated values are primes e which are part of every credential (see Cred).
+// The issuer knows the factorization of the accumulator modulus, which means it can
+// compute a witness w = V^(1/e) for any e without changing the accumulator value V.
+// Only revocation changes V - revoking e sets V' = V^(1/e). Holders of non-revoked
+// credentials can update their witnesses using only public information (revoked e and V').
+
+// AccumulatorPubKey holds the public parameters of the revocation accumulator.
+type AccumulatorPubKey struct {
+	N *big.Int
+	// G and H are used for commitments in the non-revocation proof
+	G *big.Int
+	H *big.Int
+	// V0 is the initial value of the accumulator
+	V0 *big.Int
+}
+
+// NewAccumulatorPubKey accepts group g, which needs to contain secret parameters,
+// and returns the public key for the revocation accumulator.
+func NewAccumulatorPubKey(g *qr.RSASpecial) (*AccumulatorPubKey, error) {
+	bases := make([]*big.Int, 3)
+	for i, _ := range bases {
+		b, err := g.GetRandomGenerator()
+		if err != nil {
+			return nil, errors.Wrap(err, "error when searching for RSASpecial generator")
+		}
+		bases[i] = b
+	}
+
+	return &AccumulatorPubKey{
+		N:  g.N,
+		G:  bases[0],
+		H:  bases[1],
+		V0: bases[2],
+	}, nil
+}
+
+// AccumulatorUpdate describes a single change of the accumulator - a revocation of
+// prime E which moved the accumulator into Epoch and set its value to Value.
+type AccumulatorUpdate struct {
+	Epoch int
+	E     *big.Int
+	Value *big.Int
+}
+
+func NewAccumulatorUpdate(epoch int, e, value *big.Int) *AccumulatorUpdate {
+	return &AccumulatorUpdate{
+		Epoch: epoch,
+		E:     e,
+		Value: value,
+	}
+}
+
+func (u *AccumulatorUpdate) MarshalBinary() ([]byte, error) {
+	return json.Marshal(u)
+}
+
+func (u *AccumulatorUpdate) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, u)
+}
+
+// Accumulator keeps the current state of the revocation accumulator. When
+// the accumulator is instantiated without the secret key, it can only be
+// used for verifying non-revocation proofs.
+type Accumulator struct {
+	PubKey  *AccumulatorPubKey
+	Group   *qr.RSASpecial
+	value   *big.Int
+	updates []*AccumulatorUpdate // updates[i] moves the accumulator from epoch i to epoch i+1
+	revoked map[string]bool
+	mutex   sync.RWMutex
+}
+
+// NewAccumulator returns a new accumulator with initial value PubKey.V0. Primes can be nil
+// (when the factorization of the accumulator modulus is not known) - such an accumulator
+// can neither issue witnesses nor revoke credentials.
+func NewAccumulator(pubKey *AccumulatorPubKey, primes *qr.RSASpecialPrimes) (*Accumulator, error) {
+	var group *qr.RSASpecial
+	var err error
+	if primes != nil {
+		group, err = qr.NewRSASpecialFromParams(primes)
+		if err != nil {
+			return nil, fmt.Errorf("error when creating RSASpecial group: %s", err)
+		}
+	} else {
+		group = qr.NewRSApecialPublic(pubKey.N)
+	}
+
+	return &Accumulator{
+		PubKey:  pubKey,
+		Group:   group,
+		value:   pubKey.V0,
+		updates: []*AccumulatorUpdate{},
+		revoked: make(map[string]bool),
+	}, nil
+}
+
+// GetValue returns the current epoch and the current value of the accumulator.
+func (a *Accumulator) GetValue() (int, *big.Int) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return len(a.updates), a.value
+}
+
+// IsRevoked returns true if prime e has been revoked.
+func (a *Accumulator) IsRevoked(e *big.Int) bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return a.revoked[e.String()]
+}
+
+// GetWitness returns a witness that e is contained in the current accumulator.
+func (a *Accumulator) GetWitness(e *big.Int) (*Witness, error) {
+	if a.Group.P1 == nil {
+		return nil, fmt.Errorf("witness cannot be computed without the accumulator secret key")
+	}
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if a.revoked[e.String()] {
+		return nil, fmt.Errorf("e has been revoked")
+	}
+
+	eInv, err := a.invert(e)
+	if err != nil {
+		return nil, err
+	}
+	w := a.Group.Exp(a.value, eInv)
+
+	return NewWitness(w, a.value, len(a.updates)), nil
+}
+
+// Revoke removes e from the accumulator and returns the update which is to be
+// applied by the holders of all non-revoked witnesses.
+func (a *Accumulator) Revoke(e *big.Int) (*AccumulatorUpdate, error) {
+	return a.revoke(e, nil)
+}
+
+// revoke removes e from the accumulator. When store is not nil, it is called with the update
+// before the update is applied - when it fails, the accumulator is not changed.
+func (a *Accumulator) revoke(e *big.Int, store func(*AccumulatorUpdate) error) (*AccumulatorUpdate,
+	error) {
+	if a.Group.P1 == nil {
+		return nil, fmt.Errorf("credential cannot be revoked without the accumulator secret key")
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.revoked[e.String()] {
+		return nil, fmt.Errorf("e has already been revoked")
+	}
+
+	eInv, err := a.invert(e)
+	if err != nil {
+		return nil, err
+	}
+	u := NewAccumulatorUpdate(len(a.updates)+1, e, a.Group.Exp(a.value, eInv))
+	if store != nil {
+		if err := store(u); err != nil {
+			return nil, fmt.Errorf("error when storing accumulator update: %v", err)
+		}
+	}
+	a.value = u.Value
+	a.revoked[e.String()] = true
+	a.updates = append(a.updates, u)
+
+	return u, nil
+}
+
+// GetUpdates returns all updates of the accumulator which happened after the given epoch.
+func (a *Accumulator) GetUpdates(epoch int) ([]*AccumulatorUpdate, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if epoch < 0 || epoch > len(a.updates) {
+		return nil, fmt.Errorf("epoch %d does not exist", epoch)
+	}
+
+	return a.updates[epoch:], nil
+}
+
+// ApplyUpdates applies updates published by the issuer to an accumulator which was
+// instantiated without the secret key (for example to verify non-revocation proofs
+// offline). Each update is checked to be a revocation of the previous value: V'^e = V.
+func (a *Accumulator) ApplyUpdates(updates []*AccumulatorUpdate) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, u := range updates {
+		if u.Epoch != len(a.updates)+1 {
+			return fmt.Errorf("update for epoch %d cannot be applied in epoch %d",
+				u.Epoch, len(a.updates))
+		}
+		if a.Group.Exp(u.Value, u.E).Cmp(a.value) != 0 {
+			return fmt.Errorf("accumulator update for epoch %d is not valid", u.Epoch)
+		}
+		a.value = u.Value
+		a.revoked[u.E.String()] = true
+		a.updates = append(a.updates, u)
+	}
+
+	return nil
+}
+
+// invert returns 1/e modulo the order of the accumulator group.
+func (a *Accumulator) invert(e *big.Int) (*big.Int, error) {
+	phiN := new(big.Int).Mul(a.Group.P1, a.Group.Q1)
+	eInv := new(big.Int).ModInverse(e, phiN)
+	if eInv == nil {
+		return nil, fmt.Errorf("e is not invertible modulo the accumulator group order")
+	}
+
+	return eInv, nil
+}
+
+// Witness proves that prime e is contained in the accumulator with value Value,
+// that is W^e = Value. Epoch denotes the accumulator epoch of Value.
+type Witness struct {
+	W     *big.Int
+	Value *big.Int
+	Epoch int
+}
+
+func NewWitness(w, value *big.Int, epoch int) *Witness {
+	return &Witness{
+		W:     w,
+		Value: value,
+		Epoch: epoch,
+	}
+}
+
+// Verify checks whether the witness is valid for prime e.
+func (w *Witness) Verify(pubKey *AccumulatorPubKey, e *big.Int) bool {
+	group := qr.NewRSApecialPublic(pubKey.N)
+	return group.Exp(w.W, e).Cmp(w.Value) == 0
+}
+
+// Update applies accumulator updates to the witness for prime e. Updates that
+// refer to epochs the witness has already reached are skipped. An error is
+// returned if e itself has been revoked.
+func (w *Witness) Update(pubKey *AccumulatorPubKey, e *big.Int, updates []*AccumulatorUpdate) error {
+	group := qr.NewRSApecialPublic(pubKey.N)
+	for _, u := range updates {
+		if u.Epoch <= w.Epoch {
+			continue
+		}
+		if u.Epoch != w.Epoch+1 {
+			return fmt.Errorf("missing accumulator update for epoch %d", w.Epoch+1)
+		}
+		if u.E.Cmp(e) == 0 {
+			return fmt.Errorf("credential has been revoked")
+		}
+
+		// find a, b such that a*e + b*eRevoked = 1, then w' = w^b * V'^a
+		a := new(big.Int)
+		b := new(big.Int)
+		new(big.Int).GCD(a, b, e, u.E)
+		t1 := group.Exp(w.W, b)
+		t2 := group.Exp(u.Value, a)
+		newW := group.Mul(t1, t2)
+		if group.Exp(newW, e).Cmp(u.Value) != 0 {
+			return fmt.Errorf("accumulator update for epoch %d is not valid", u.Epoch)
+		}
+
+		w.W = newW
+		w.Value = u.Value
+		w.Epoch = u.Epoch
+	}
+
+	return nil
+}
+
+// NonRevocationProof demonstrates that the (hidden) prime e of a credential is
+// contained in the accumulator of the given epoch. The response for e is not a part
+// of this proof - the response for e from the credential proof is used instead, which
+// binds both proofs to the same e.
+type NonRevocationProof struct {
+	Epoch int
+	// Commitments are C_e = G^e * H^r1, C_u = w * H^r2, C_r = G^r2 * H^r3
+	Commitments     []*big.Int
+	ProofRandomData []*big.Int
+	ProofData       []*big.Int
+}
+
+func NewNonRevocationProof(epoch int, commitments, proofRandomData,
+	proofData []*big.Int) *NonRevocationProof {
+	return &NonRevocationProof{
+		Epoch:           epoch,
+		Commitments:     commitments,
+		ProofRandomData: proofRandomData,
+		ProofData:       proofData,
+	}
+}
+
+// nonRevocationProver proves the knowledge of e, r1, r2, r3, delta = e*r2 and
+// epsilon = e*r3 such that:
+// C_e = G^e * H^r1
+// C_r = G^r2 * H^r3
+// 1 = C_r^e * G^(-delta) * H^(-epsilon)
+// V = C_u^e * H^(-delta)
+type nonRevocationProver struct {
+	pubKey      *AccumulatorPubKey
+	group       *qr.RSASpecial
+	params      *Params
+	witness     *Witness
+	secrets     []*big.Int // e, r1, r2, r3, delta, epsilon
+	randomVals  []*big.Int
+	commitments []*big.Int
+}
+
+func newNonRevocationProver(params *Params, pubKey *AccumulatorPubKey, witness *Witness,
+	e *big.Int) *nonRevocationProver {
+	group := qr.NewRSApecialPublic(pubKey.N)
+	b := new(big.Int).Div(pubKey.N, big.NewInt(4))
+	r1 := common.GetRandomInt(b)
+	r2 := common.GetRandomInt(b)
+	r3 := common.GetRandomInt(b)
+	delta := new(big.Int).Mul(e, r2)
+	epsilon := new(big.Int).Mul(e, r3)
+
+	cE := group.Mul(group.Exp(pubKey.G, e), group.Exp(pubKey.H, r1))
+	cU := group.Mul(witness.W, group.Exp(pubKey.H, r2))
+	cR := group.Mul(group.Exp(pubKey.G, r2), group.Exp(pubKey.H, r3))
+
+	return &nonRevocationProver{
+		pubKey:      pubKey,
+		group:       group,
+		params:      params,
+		witness:     witness,
+		secrets:     []*big.Int{e, r1, r2, r3, delta, epsilon},
+		commitments: []*big.Int{cE, cU, cR},
+	}
+}
+
+// getProofRandomData returns proof random data for all four relations. Random value
+// rE needs to be the same as the random value used for e in the credential proof.
+func (p *nonRevocationProver) getProofRandomData(rE *big.Int) []*big.Int {
+	nLen := p.pubKey.N.BitLen()
+	b_r := nLen + int(p.params.SecParam+p.params.HashBitLen)
+	b_d := nLen + int(p.params.EBitLen+p.params.SecParam+p.params.HashBitLen)
+
+	p.randomVals = []*big.Int{rE}
+	for _, b := range []int{b_r, b_r, b_r, b_d, b_d} {
+		max := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(b)), nil)
+		p.randomVals = append(p.randomVals, common.GetRandomIntAlsoNeg(max))
+	}
+
+	return nonRevocationRelations(p.group, p.pubKey, p.commitments, p.randomVals)
+}
+
+// getProofData returns responses for r1, r2, r3, delta and epsilon (response for e
+// is part of the credential proof).
+func (p *nonRevocationProver) getProofData(challenge *big.Int) []*big.Int {
+	proofData := make([]*big.Int, len(p.secrets)-1)
+	for i, _ := range proofData {
+		s := new(big.Int).Mul(challenge, p.secrets[i+1])
+		proofData[i] = s.Add(s, p.randomVals[i+1])
+	}
+
+	return proofData
+}
+
+// nonRevocationRelations computes left sides of the relations proved by nonRevocationProver
+// given exponents (e, r1, r2, r3, delta, epsilon).
+func nonRevocationRelations(group *qr.RSASpecial, pubKey *AccumulatorPubKey,
+	commitments, exps []*big.Int) []*big.Int {
+	cU, cR := commitments[1], commitments[2]
+	e, r1, r2, r3, delta, epsilon := exps[0], exps[1], exps[2], exps[3], exps[4], exps[5]
+	negDelta := new(big.Int).Neg(delta)
+	negEpsilon := new(big.Int).Neg(epsilon)
+
+	t1 := group.Mul(group.Exp(pubKey.G, e), group.Exp(pubKey.H, r1))
+	t2 := group.Mul(group.Exp(pubKey.G, r2), group.Exp(pubKey.H, r3))
+	t3 := group.Mul(group.Exp(cR, e), group.Exp(pubKey.G, negDelta))
+	t3 = group.Mul(t3, group.Exp(pubKey.H, negEpsilon))
+	t4 := group.Mul(group.Exp(cU, e), group.Exp(pubKey.H, negDelta))
+
+	return []*big.Int{t1, t2, t3, t4}
+}
+
+// verifyNonRevocationProof verifies proof against accumulator value v. Parameter sE is
+// the response for e from the credential proof.
+func verifyNonRevocationProof(pubKey *AccumulatorPubKey, v *big.Int, proof *NonRevocationProof,
+	challenge, sE *big.Int) bool {
+	if len(proof.Commitments) != 3 || len(proof.ProofRandomData) != 4 ||
+		len(proof.ProofData) != 5 {
+		return false
+	}
+
+	group := qr.NewRSApecialPublic(pubKey.N)
+	exps := append([]*big.Int{sE}, proof.ProofData...)
+	left := nonRevocationRelations(group, pubKey, proof.Commitments, exps)
+
+	// right sides: C_e^c * t1, C_r^c * t2, t3, V^c * t4
+	ys := []*big.Int{proof.Commitments[0], proof.Commitments[2], big.NewInt(1), v}
+	for i, y := range ys {
+		right := group.Mul(group.Exp(y, challenge), proof.ProofRandomData[i])
+		if left[i].Cmp(right) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+func TestAccumulator(t *testing.T) {
+	group, err := qr.NewRSASpecial(128)
+	require.NoError(t, err)
+	pubKey, err := NewAccumulatorPubKey(group)
+	require.NoError(t, err)
+	acc, err := NewAccumulator(pubKey, group.GetPrimes())
+	require.NoError(t, err)
+
+	es := make([]*big.Int, 3)
+	witnesses := make([]*Witness, 3)
+	for i, _ := range es {
+		es[i], _ = rand.Prime(rand.Reader, 160)
+		witnesses[i], err = acc.GetWitness(es[i])
+		require.NoError(t, err)
+		assert.True(t, witnesses[i].Verify(pubKey, es[i]), "witness not valid")
+	}
+
+	_, err = acc.Revoke(es[1])
+	require.NoError(t, err)
+	_, err = acc.Revoke(es[2])
+	require.NoError(t, err)
+	_, err = acc.Revoke(es[2])
+	assert.Error(t, err, "e should not be revoked twice")
+	_, err = acc.GetWitness(es[1])
+	assert.Error(t, err, "witness should not be issued for revoked e")
+
+	updates, err := acc.GetUpdates(witnesses[0].Epoch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(updates))
+
+	err = witnesses[0].Update(pubKey, es[0], updates)
+	require.NoError(t, err)
+	epoch, value := acc.GetValue()
+	assert.Equal(t, epoch, witnesses[0].Epoch)
+	assert.Equal(t, value, witnesses[0].Value)
+	assert.True(t, witnesses[0].Verify(pubKey, es[0]), "updated witness not valid")
+
+	err = witnesses[1].Update(pubKey, es[1], updates)
+	assert.Error(t, err, "witness of revoked e should not be updatable")
+}
+
+func TestNonRevocationProof(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 0)
+
+	org := newTestOrg(t, attrCount, 0)
+
+	issue := func(name string) (*CredManager, *CredResult) {
+		return issueTestCred(t, org, newTestRawCred(t, attrCount, name), nil)
+	}
+
+	prove := func(credMgr *CredManager, cred *Cred) (bool, error) {
+		nonce, err := org.GetProveCredNonce()
+		require.NoError(t, err)
+		proof, err := credMgr.BuildProof(cred, []int{}, []int{}, nil, nonce)
+		require.NoError(t, err)
+
+		verified, _, err := org.ProveCred(proof, nil, nonce)
+		return verified, err
+	}
+
+	credMgr1, res1 := issue("Jack")
+	credMgr2, res2 := issue("Jill")
+
+	verified, err := prove(credMgr2, res2.Cred)
+	require.NoError(t, err)
+	assert.True(t, verified, "non-revoked credential not accepted")
+
+	update, err := org.RevokeCred(res2.Record)
+	require.NoError(t, err)
+
+	// the holder of the revoked credential cannot obtain a new credential by updating it
+	updateNonce, err := org.GetCredIssueNonce()
+	require.NoError(t, err)
+	updateReq, err := credMgr2.GetCredUpdateRequest(updateNonce)
+	require.NoError(t, err)
+	_, err = org.UpdateCred(updateReq, res2.Record, updateNonce)
+	assert.Error(t, err, "revoked credential should not be updated")
+
+	// witness of the first credential is outdated
+	_, err = prove(credMgr1, res1.Cred)
+	assert.Error(t, err, "proof with outdated witness should not be accepted")
+
+	err = credMgr1.UpdateWitness(res1.Cred, []*AccumulatorUpdate{update})
+	require.NoError(t, err)
+	verified, err = prove(credMgr1, res1.Cred)
+	require.NoError(t, err)
+	assert.True(t, verified, "non-revoked credential not accepted after witness update")
+
+	err = credMgr2.UpdateWitness(res2.Cred, []*AccumulatorUpdate{update})
+	assert.Error(t, err, "witness of revoked credential should not be updatable")
+	_, err = prove(credMgr2, res2.Cred)
+	assert.Error(t, err, "revoked credential should not be accepted")
+
+	// a holder of the revoked credential cannot reuse the witness of another credential
+	credMgr2.Witness = credMgr1.Witness
+	verified, _ = prove(credMgr2, res2.Cred)
+	assert.False(t, verified, "revoked credential should not be accepted")
+}
+
+func TestLoadAccumulator(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 0)
+	org := newTestOrg(t, attrCount, 0)
+	records := NewMockRecordManager()
+	org.AccumulatorRecords = records
+
+	results := make([]*CredResult, 3)
+	for i, name := range []string{"Jack", "Jill", "Joe"} {
+		_, results[i] = issueTestCred(t, org, newTestRawCred(t, attrCount, name), nil)
+	}
+	_, err := org.RevokeCred(results[0].Record)
+	require.NoError(t, err)
+
+	// the organization loaded again (for example after a restart) restores the accumulator
+	loaded, err := NewOrgFromParams(org.Params, org.Keys)
+	require.NoError(t, err)
+	loaded.AccumulatorRecords = records
+	require.NoError(t, loaded.LoadAccumulator())
+	assert.True(t, loaded.Accumulator.IsRevoked(results[0].Record.E))
+	assert.False(t, loaded.Accumulator.IsRevoked(results[1].Record.E))
+	epoch, value := org.Accumulator.GetValue()
+	loadedEpoch, loadedValue := loaded.Accumulator.GetValue()
+	assert.Equal(t, epoch, loadedEpoch)
+	assert.Equal(t, value, loadedValue)
+
+	// a revocation by an outdated instance is not stored and does not change its accumulator
+	_, err = org.RevokeCred(results[1].Record)
+	require.NoError(t, err)
+	_, err = loaded.RevokeCred(results[2].Record)
+	assert.Error(t, err, "revocation based on an outdated accumulator should fail")
+	assert.False(t, loaded.Accumulator.IsRevoked(results[2].Record.E))
+
+	require.NoError(t, loaded.LoadAccumulator())
+	assert.True(t, loaded.Accumulator.IsRevoked(results[1].Record.E))
+	_, err = loaded.RevokeCred(results[2].Record)
+	assert.NoError(t, err)
+}
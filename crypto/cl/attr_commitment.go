@@ -0,0 +1,97 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/df"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+// attrCommitter commits to an (unrevealed) known attribute in a DF commitment
+// (using PubKey.N1, PubKey.G, PubKey.H) and proves that the commitment hides the same
+// value as used in the credential proof. Proofs about the attribute (predicates,
+// set membership) are then done on the commitment.
+type attrCommitter struct {
+	params    *Params
+	committer *df.Committer
+	randomR   *big.Int
+}
+
+func newAttrCommitter(params *Params, pubKey *PubKey, attr *big.Int) (*attrCommitter,
+	*big.Int, error) {
+	// committed values (attributes and values derived from attributes, like the differences
+	// to the bounds of a predicate) are bounded by T = 2^(AttrBitLen + 2)
+	t := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(params.AttrBitLen+2)), nil)
+	committer := df.NewCommitter(pubKey.N1, pubKey.G, pubKey.H, t, int(params.SecParam))
+	commitment, err := committer.GetCommitMsg(attr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error when creating DF commitment: %s", err)
+	}
+
+	return &attrCommitter{
+		params:    params,
+		committer: committer,
+	}, commitment, nil
+}
+
+// getProofRandomData returns G^rM * H^randomR. Random value rM needs to be the same
+// as the random value used for the attribute in the credential proof.
+func (c *attrCommitter) getProofRandomData(rM *big.Int) *big.Int {
+	// r (the commitment randomness) is from [0, 2^(B+k)), randomR needs to hide challenge * r
+	b := c.committer.B + c.committer.K + int(c.params.SecParam+c.params.HashBitLen)
+	c.randomR = common.GetRandomIntAlsoNeg(new(big.Int).Exp(big.NewInt(2),
+		big.NewInt(int64(b)), nil))
+
+	return c.committer.ComputeCommit(rM, c.randomR)
+}
+
+func (c *attrCommitter) getProofData(challenge *big.Int) *big.Int {
+	_, r := c.committer.GetDecommitMsg()
+	s := new(big.Int).Mul(challenge, r)
+	return s.Add(s, c.randomR)
+}
+
+// verifyAttrCommitment checks that commitment hides the attribute for which
+// sM is the response in the credential proof.
+func verifyAttrCommitment(pubKey *PubKey, commitment, proofRandomData, proofData,
+	challenge, sM *big.Int) bool {
+	// G^sM * H^proofData = commitment^challenge * proofRandomData
+	group := qr.NewRSApecialPublic(pubKey.N1)
+	left := group.Mul(group.Exp(pubKey.G, sM), group.Exp(pubKey.H, proofData))
+	right := group.Mul(group.Exp(commitment, challenge), proofRandomData)
+
+	return left.Cmp(right) == 0
+}
+
+// unrevealedPosition returns the position of the known attribute with index attrIndex
+// among the unrevealed known attributes (which is also the position of its random value
+// and response in the credential proof).
+func unrevealedPosition(revealedKnownAttrsIndices []int, attrIndex int) int {
+	pos := 0
+	for i := 0; i < attrIndex; i++ {
+		if !common.Contains(revealedKnownAttrsIndices, i) {
+			pos++
+		}
+	}
+
+	return pos
+}
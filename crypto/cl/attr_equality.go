@@ -0,0 +1,210 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/common"
+)
+
+// AttrEquality requires that the attribute Attr1 of the credential at position Cred1 of
+// a multi-credential proof has the same value as the attribute Attr2 of the credential at
+// position Cred2 (the credentials can be issued by different organizations), without
+// revealing the value. Attributes are given by their names in the credentials. Only known
+// attributes which are not revealed can be compared and both of them need to be of the same
+// type, so that the values are encoded in the same way.
+type AttrEquality struct {
+	Cred1 int
+	Attr1 string
+	Cred2 int
+	Attr2 string
+}
+
+func NewAttrEquality(cred1 int, attr1 string, cred2 int, attr2 string) *AttrEquality {
+	return &AttrEquality{
+		Cred1: cred1,
+		Attr1: attr1,
+		Cred2: cred2,
+		Attr2: attr2,
+	}
+}
+
+// checkCreds checks that the equality refers to the credentials among credCount credentials.
+func (e *AttrEquality) checkCreds(credCount int) error {
+	if e == nil {
+		return fmt.Errorf("attribute equality is empty")
+	}
+	if e.Cred1 < 0 || e.Cred1 >= credCount || e.Cred2 < 0 || e.Cred2 >= credCount {
+		return fmt.Errorf("attribute equality refers to unknown credential")
+	}
+
+	return nil
+}
+
+// checkAttrs checks that the attributes a1 and a2 (for Attr1 and Attr2) can be compared.
+func (e *AttrEquality) checkAttrs(a1, a2 CredAttr) error {
+	if !a1.IsKnown() {
+		return fmt.Errorf("attribute %s is not known", e.Attr1)
+	}
+	if !a2.IsKnown() {
+		return fmt.Errorf("attribute %s is not known", e.Attr2)
+	}
+	if fmt.Sprintf("%T", a1) != fmt.Sprintf("%T", a2) {
+		return fmt.Errorf("attributes %s and %s are not of the same type", e.Attr1, e.Attr2)
+	}
+
+	return nil
+}
+
+// attrKey identifies a known attribute (by its index) of the credential at position cred.
+type attrKey struct {
+	cred  int
+	index int
+}
+
+// getAttrEqualityRandoms returns the random values which need to be used for the
+// attributes in the credential proofs of the presentations, so that the verifier can
+// check the equalities - equal attributes have the same random values and thus the same
+// responses (for the shared challenge). randoms[i] maps indices of known attributes of
+// presentations[i] to random values.
+func getAttrEqualityRandoms(presentations []*CredPresentation,
+	equalities []*AttrEquality) ([]map[int]*big.Int, error) {
+	randoms := make([]map[int]*big.Int, len(presentations))
+	if len(equalities) == 0 {
+		return randoms, nil
+	}
+
+	params := presentations[0].CredManager.Params
+	b := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(
+		params.AttrBitLen+params.SecParam+params.HashBitLen)), nil)
+
+	lookup := func(cred int, name string) (CredAttr, attrKey, error) {
+		rawCred := presentations[cred].CredManager.RawCred
+		if rawCred == nil {
+			return nil, attrKey{}, fmt.Errorf("attributes of credential %d are not known", cred)
+		}
+		a, err := rawCred.GetAttr(name)
+		if err != nil {
+			return nil, attrKey{}, err
+		}
+		index, err := rawCred.GetAttrInternalIndex(name)
+		if err != nil {
+			return nil, attrKey{}, err
+		}
+
+		return a, attrKey{cred: cred, index: index}, nil
+	}
+
+	shared := make(map[attrKey]*big.Int)
+	for _, e := range equalities {
+		if err := e.checkCreds(len(presentations)); err != nil {
+			return nil, err
+		}
+		a1, k1, err := lookup(e.Cred1, e.Attr1)
+		if err != nil {
+			return nil, err
+		}
+		a2, k2, err := lookup(e.Cred2, e.Attr2)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.checkAttrs(a1, a2); err != nil {
+			return nil, err
+		}
+
+		// attributes which are equal to the same attribute share the random value as well
+		r1, r2 := shared[k1], shared[k2]
+		switch {
+		case r1 == nil && r2 == nil:
+			r := common.GetRandomIntAlsoNeg(b)
+			shared[k1], shared[k2] = r, r
+		case r1 == nil:
+			shared[k1] = r2
+		case r2 == nil:
+			shared[k2] = r1
+		default:
+			for k, r := range shared {
+				if r == r2 {
+					shared[k] = r1
+				}
+			}
+		}
+	}
+
+	for k, r := range shared {
+		if randoms[k.cred] == nil {
+			randoms[k.cred] = make(map[int]*big.Int)
+		}
+		randoms[k.cred][k.index] = r
+	}
+
+	return randoms, nil
+}
+
+// verifyAttrEqualities checks that the responses for the attributes in attribute
+// equalities are the same. The indices of the attributes are obtained from the policies
+// (which are bound to the structure of the credentials).
+func verifyAttrEqualities(orgs []*Org, policies []*VerificationPolicy, proofs []*CredProof,
+	equalities []*AttrEquality) error {
+	response := func(cred int, name string) (CredAttr, *big.Int, error) {
+		if policies == nil || policies[cred] == nil {
+			return nil, nil, fmt.Errorf("attributes of credential %d are not known", cred)
+		}
+		index, ok := policies[cred].knownIndices[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("attribute %s is not known", name)
+		}
+		p := proofs[cred]
+		if index >= len(orgs[cred].Keys.Pub.RsKnown) {
+			return nil, nil, fmt.Errorf("attribute %s is not in the credential", name)
+		}
+		if common.Contains(p.RevealedKnownAttrsIndices, index) {
+			return nil, nil, fmt.Errorf("attribute %s is revealed", name)
+		}
+		pos := unrevealedPosition(p.RevealedKnownAttrsIndices, index)
+		if pos >= len(p.Proof.ProofData) {
+			return nil, nil, fmt.Errorf("credential proof data is not complete")
+		}
+
+		return policies[cred].knownAttrs[index], p.Proof.ProofData[pos], nil
+	}
+
+	for _, e := range equalities {
+		if err := e.checkCreds(len(proofs)); err != nil {
+			return err
+		}
+		a1, s1, err := response(e.Cred1, e.Attr1)
+		if err != nil {
+			return err
+		}
+		a2, s2, err := response(e.Cred2, e.Attr2)
+		if err != nil {
+			return err
+		}
+		if err := e.checkAttrs(a1, a2); err != nil {
+			return err
+		}
+		if s1.Cmp(s2) != 0 {
+			return fmt.Errorf("attributes %s and %s are not equal", e.Attr1, e.Attr2)
+		}
+	}
+
+	return nil
+}
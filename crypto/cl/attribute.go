@@ -18,9 +18,12 @@
 package cl
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // AttrCount holds the number of known, committed and
@@ -52,6 +55,7 @@ type CredAttr interface {
 	InternalValue() *big.Int
 	SetInternalValue() error
 	IsKnown() bool
+	IsHidden() bool
 	HasVal() bool
 	GetName() string
 	String() string
@@ -62,8 +66,11 @@ type CredAttr interface {
 // access to some internet service (like electronic newspaper), attributes could be
 // Type (for example only news related to politics) of the service and Date of Expiration.
 type attr struct {
-	Name   string
-	Known  bool
+	Name  string
+	Known bool
+	// Hidden attributes are known only to the credential receiver (for example
+	// device keys), the issuer sees neither the value nor a commitment to it
+	Hidden bool
 	valSet bool
 	val    *big.Int
 }
@@ -80,6 +87,10 @@ func (a *attr) IsKnown() bool {
 	return a.Known
 }
 
+func (a *attr) IsHidden() bool {
+	return a.Hidden
+}
+
 func (a *attr) InternalValue() *big.Int {
 	return a.val
 }
@@ -94,12 +105,49 @@ func (a *attr) GetName() string {
 
 func (a *attr) String() string {
 	tag := "known"
-	if !a.IsKnown() {
+	if a.IsHidden() {
+		tag = "hidden"
+	} else if !a.IsKnown() {
 		tag = "revealed"
 	}
 	return fmt.Sprintf("%s (%s)", a.Name, tag)
 }
 
+// The types of attributes as used in the credential structure (see ParseAttrs).
+const (
+	Int64AttrType     = "int64"
+	StrAttrType       = "string"
+	DateAttrType      = "date"
+	BoolAttrType      = "bool"
+	EnumAttrType      = "enum"
+	HashedStrAttrType = "hashed_string"
+)
+
+// maxStrAttrLen is the maximal length (in bytes) of a string attribute - the internal
+// value needs to fit into AttrBitLen (256 by default) bits. Longer strings can be
+// stored in HashedStrAttr.
+const maxStrAttrLen = 32
+
+// int64Offset is added to int64 values to obtain nonnegative internal values which
+// preserve the order of the values - this way range proofs can be used on them.
+var int64Offset = new(big.Int).Lsh(big.NewInt(1), 63)
+
+// EncodeInt64 returns the internal value of an integer (or date) attribute. Bounds of
+// predicates (see NewRangePredicate) on such attributes need to be encoded too.
+func EncodeInt64(val int64) *big.Int {
+	return new(big.Int).Add(big.NewInt(val), int64Offset)
+}
+
+// DecodeInt64 returns the value which was encoded by EncodeInt64.
+func DecodeInt64(val *big.Int) (int64, error) {
+	v := new(big.Int).Sub(val, int64Offset)
+	if !v.IsInt64() {
+		return 0, fmt.Errorf("value is not an encoded int64")
+	}
+
+	return v.Int64(), nil
+}
+
 type Int64Attr struct {
 	val int64
 	*attr
@@ -111,6 +159,12 @@ func NewEmptyInt64Attr(name string, known bool) *Int64Attr {
 	}
 }
 
+func NewEmptyHiddenInt64Attr(name string) *Int64Attr {
+	a := NewEmptyInt64Attr(name, false)
+	a.Hidden = true
+	return a
+}
+
 func NewInt64Attr(name string, val int64, known bool) (*Int64Attr,
 	error) {
 	a := &Int64Attr{
@@ -125,7 +179,7 @@ func NewInt64Attr(name string, val int64, known bool) (*Int64Attr,
 }
 
 func (a *Int64Attr) SetInternalValue() error {
-	a.attr.val = big.NewInt(int64(a.val)) // FIXME
+	a.attr.val = EncodeInt64(a.val)
 	a.valSet = true
 	return nil
 }
@@ -135,15 +189,22 @@ func (a *Int64Attr) GetValue() interface{} {
 }
 
 func (a *Int64Attr) FromInternalValue(val *big.Int) (interface{}, error) {
-	return strconv.Atoi(val.String())
+	v, err := DecodeInt64(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return int(v), nil
 }
 
 func (a *Int64Attr) UpdateValue(n interface{}) error {
-	switch n.(type) {
+	switch v := n.(type) {
 	case int:
-		a.val = int64(n.(int))
+		a.val = int64(v)
 	case int64:
-		a.val = n.(int64)
+		a.val = v
+	default:
+		return fmt.Errorf("invalid value for int64 attribute %s: %v", a.Name, n)
 	}
 	return a.SetInternalValue()
 }
@@ -163,6 +224,12 @@ func NewEmptyStrAttr(name string, known bool) *StrAttr {
 	}
 }
 
+func NewEmptyHiddenStrAttr(name string) *StrAttr {
+	a := NewEmptyStrAttr(name, false)
+	a.Hidden = true
+	return a
+}
+
 func NewStrAttr(name, val string, known bool) (*StrAttr,
 	error) {
 	a := &StrAttr{
@@ -177,7 +244,10 @@ func NewStrAttr(name, val string, known bool) (*StrAttr,
 }
 
 func (a *StrAttr) SetInternalValue() error {
-	a.attr.val = new(big.Int).SetBytes([]byte(a.val)) // FIXME
+	if len(a.val) > maxStrAttrLen {
+		return fmt.Errorf("string attribute %s is longer than %d bytes", a.Name, maxStrAttrLen)
+	}
+	a.attr.val = new(big.Int).SetBytes([]byte(a.val))
 	a.valSet = true
 	return nil
 }
@@ -191,7 +261,11 @@ func (a *StrAttr) FromInternalValue(val *big.Int) (interface{}, error) {
 }
 
 func (a *StrAttr) UpdateValue(s interface{}) error {
-	a.val = s.(string)
+	v, ok := s.(string)
+	if !ok {
+		return fmt.Errorf("invalid value for string attribute %s: %v", a.Name, s)
+	}
+	a.val = v
 	return a.SetInternalValue()
 }
 
@@ -199,11 +273,316 @@ func (a *StrAttr) String() string {
 	return fmt.Sprintf("%s, type = %T", a.attr.String(), a.val)
 }
 
+// HashedStrAttr is a string attribute of arbitrary length - its internal value is
+// the SHA-256 hash of the string. The value cannot be recovered from the internal value,
+// thus FromInternalValue returns the hash (hex encoded).
+type HashedStrAttr struct {
+	val string
+	*attr
+}
+
+func NewEmptyHashedStrAttr(name string, known bool) *HashedStrAttr {
+	return &HashedStrAttr{
+		attr: newAttr(name, known),
+	}
+}
+
+func NewHashedStrAttr(name, val string, known bool) (*HashedStrAttr, error) {
+	a := NewEmptyHashedStrAttr(name, known)
+	if err := a.UpdateValue(val); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (a *HashedStrAttr) SetInternalValue() error {
+	h := sha256.Sum256([]byte(a.val))
+	a.attr.val = new(big.Int).SetBytes(h[:])
+	a.valSet = true
+	return nil
+}
+
+func (a *HashedStrAttr) GetValue() interface{} {
+	return a.val
+}
+
+func (a *HashedStrAttr) FromInternalValue(val *big.Int) (interface{}, error) {
+	return fmt.Sprintf("%064x", val), nil
+}
+
+func (a *HashedStrAttr) UpdateValue(s interface{}) error {
+	v, ok := s.(string)
+	if !ok {
+		return fmt.Errorf("invalid value for string attribute %s: %v", a.Name, s)
+	}
+	a.val = v
+	return a.SetInternalValue()
+}
+
+func (a *HashedStrAttr) String() string {
+	return fmt.Sprintf("%s, type = hashed %T", a.attr.String(), a.val)
+}
+
+// DateAttr is a date (or a timestamp) attribute. It is encoded as Unix time by
+// EncodeInt64, so that predicates (before, after some date) can be proved.
+type DateAttr struct {
+	val time.Time
+	*attr
+}
+
+func NewEmptyDateAttr(name string, known bool) *DateAttr {
+	return &DateAttr{
+		attr: newAttr(name, known),
+	}
+}
+
+func NewDateAttr(name string, val time.Time, known bool) (*DateAttr, error) {
+	a := NewEmptyDateAttr(name, known)
+	if err := a.UpdateValue(val); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (a *DateAttr) SetInternalValue() error {
+	a.attr.val = EncodeInt64(a.val.Unix())
+	a.valSet = true
+	return nil
+}
+
+func (a *DateAttr) GetValue() interface{} {
+	return a.val
+}
+
+func (a *DateAttr) FromInternalValue(val *big.Int) (interface{}, error) {
+	v, err := DecodeInt64(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return time.Unix(v, 0).UTC(), nil
+}
+
+// UpdateValue accepts time.Time, Unix time (int or int64) or a string
+// in RFC 3339 format or in the format 2006-01-02.
+func (a *DateAttr) UpdateValue(d interface{}) error {
+	switch v := d.(type) {
+	case time.Time:
+		a.val = v
+	case int:
+		a.val = time.Unix(int64(v), 0).UTC()
+	case int64:
+		a.val = time.Unix(v, 0).UTC()
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", v); err != nil {
+				return fmt.Errorf("invalid date for attribute %s: %s", a.Name, v)
+			}
+		}
+		a.val = t
+	default:
+		return fmt.Errorf("invalid value for date attribute %s: %v", a.Name, d)
+	}
+	return a.SetInternalValue()
+}
+
+func (a *DateAttr) String() string {
+	return fmt.Sprintf("%s, type = %T", a.attr.String(), a.val)
+}
+
+// BoolAttr is a boolean attribute, false is encoded as 0 and true as 1.
+type BoolAttr struct {
+	val bool
+	*attr
+}
+
+func NewEmptyBoolAttr(name string, known bool) *BoolAttr {
+	return &BoolAttr{
+		attr: newAttr(name, known),
+	}
+}
+
+func NewBoolAttr(name string, val bool, known bool) (*BoolAttr, error) {
+	a := NewEmptyBoolAttr(name, known)
+	if err := a.UpdateValue(val); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (a *BoolAttr) SetInternalValue() error {
+	a.attr.val = big.NewInt(0)
+	if a.val {
+		a.attr.val = big.NewInt(1)
+	}
+	a.valSet = true
+	return nil
+}
+
+func (a *BoolAttr) GetValue() interface{} {
+	return a.val
+}
+
+func (a *BoolAttr) FromInternalValue(val *big.Int) (interface{}, error) {
+	if !val.IsInt64() || val.Int64() > 1 || val.Sign() < 0 {
+		return nil, fmt.Errorf("value is not an encoded bool")
+	}
+
+	return val.Int64() == 1, nil
+}
+
+func (a *BoolAttr) UpdateValue(b interface{}) error {
+	v, ok := b.(bool)
+	if !ok {
+		return fmt.Errorf("invalid value for bool attribute %s: %v", a.Name, b)
+	}
+	a.val = v
+	return a.SetInternalValue()
+}
+
+func (a *BoolAttr) String() string {
+	return fmt.Sprintf("%s, type = %T", a.attr.String(), a.val)
+}
+
+// EnumAttr is an attribute which can take one of the Values, the value is encoded as
+// its index in Values.
+type EnumAttr struct {
+	val    string
+	Values []string
+	*attr
+}
+
+func NewEmptyEnumAttr(name string, values []string, known bool) *EnumAttr {
+	return &EnumAttr{
+		Values: values,
+		attr:   newAttr(name, known),
+	}
+}
+
+func NewEnumAttr(name, val string, values []string, known bool) (*EnumAttr, error) {
+	a := NewEmptyEnumAttr(name, values, known)
+	if err := a.UpdateValue(val); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Encode returns the internal value of v (for example to be used in a set membership proof).
+func (a *EnumAttr) Encode(v string) (*big.Int, error) {
+	for i, val := range a.Values {
+		if val == v {
+			return big.NewInt(int64(i)), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not a valid value for attribute %s", v, a.Name)
+}
+
+func (a *EnumAttr) SetInternalValue() error {
+	val, err := a.Encode(a.val)
+	if err != nil {
+		return err
+	}
+	a.attr.val = val
+	a.valSet = true
+	return nil
+}
+
+func (a *EnumAttr) GetValue() interface{} {
+	return a.val
+}
+
+func (a *EnumAttr) FromInternalValue(val *big.Int) (interface{}, error) {
+	if !val.IsInt64() || val.Sign() < 0 || val.Int64() >= int64(len(a.Values)) {
+		return nil, fmt.Errorf("value is not an encoded value of attribute %s", a.Name)
+	}
+
+	return a.Values[val.Int64()], nil
+}
+
+func (a *EnumAttr) UpdateValue(s interface{}) error {
+	v, ok := s.(string)
+	if !ok {
+		return fmt.Errorf("invalid value for enum attribute %s: %v", a.Name, s)
+	}
+	a.val = v
+	return a.SetInternalValue()
+}
+
+func (a *EnumAttr) String() string {
+	return fmt.Sprintf("%s, type = enum %v", a.attr.String(), a.Values)
+}
+
+// NewEmptyAttr returns an attribute (without the value) of the given type
+// (see Int64AttrType and others). Values are needed only for EnumAttrType.
+func NewEmptyAttr(name, attrType string, known, hidden bool, values ...string) (CredAttr, error) {
+	var a CredAttr
+	var base *attr
+	switch attrType {
+	case Int64AttrType:
+		t := NewEmptyInt64Attr(name, known)
+		a, base = t, t.attr
+	case StrAttrType:
+		t := NewEmptyStrAttr(name, known)
+		a, base = t, t.attr
+	case DateAttrType:
+		t := NewEmptyDateAttr(name, known)
+		a, base = t, t.attr
+	case BoolAttrType:
+		t := NewEmptyBoolAttr(name, known)
+		a, base = t, t.attr
+	case EnumAttrType:
+		if len(values) == 0 {
+			return nil, fmt.Errorf("enum attribute %s needs values", name)
+		}
+		t := NewEmptyEnumAttr(name, values, known)
+		a, base = t, t.attr
+	case HashedStrAttrType:
+		t := NewEmptyHashedStrAttr(name, known)
+		a, base = t, t.attr
+	default:
+		return nil, fmt.Errorf("unsupported attribute type: %s", attrType)
+	}
+	if hidden {
+		base.Known, base.Hidden = false, true
+	}
+
+	return a, nil
+}
+
+// UpdateValueFromString sets the value of the attribute a from its string
+// representation, for example from a value which is stored in a database.
+// Integers are given in base 10, booleans as accepted by strconv.ParseBool
+// and dates as accepted by DateAttr.UpdateValue.
+func UpdateValueFromString(a CredAttr, val string) error {
+	switch a.(type) {
+	case *Int64Attr:
+		v, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value for int64 attribute %s: %s", a.GetName(), val)
+		}
+		return a.UpdateValue(v)
+	case *BoolAttr:
+		v, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("invalid value for bool attribute %s: %s", a.GetName(), val)
+		}
+		return a.UpdateValue(v)
+	default:
+		return a.UpdateValue(val)
+	}
+}
+
 // FIXME make nicer
 // Hook to organization?
 func ParseAttrs(specs map[string]interface{}) ([]CredAttr, *AttrCount, error) {
 	attrs := make([]CredAttr, len(specs))
-	var nKnown, nCommitted int
+	var nKnown, nCommitted, nHidden int
 
 	for name, val := range specs {
 		data, ok := val.(map[string]interface{})
@@ -225,41 +604,44 @@ func ParseAttrs(specs map[string]interface{}) ([]CredAttr, *AttrCount, error) {
 			return nil, nil, fmt.Errorf("index must be string")
 		}
 
-		known := true
+		// known is true, false (only a commitment is known to the issuer) or hidden
+		// (the attribute is known only to the receiver)
+		known, hidden := true, false
 		k, ok := data["known"]
-		if ok {
+		if ok && k.(string) == "hidden" {
+			known, hidden = false, true
+		} else if ok {
 			res, err := strconv.ParseBool(k.(string))
 			if err != nil {
-				return nil, nil, fmt.Errorf("known must be true or false")
+				return nil, nil, fmt.Errorf("known must be true, false or hidden")
 			}
 			known = res
 		}
 
 		if known {
 			nKnown++
+		} else if hidden {
+			nHidden++
 		} else {
 			nCommitted++
 		}
 
-		switch t {
-		case "string":
-			a, err := NewStrAttr(name, "", known) // FIXME
-			if err != nil {
-				return nil, nil, err
-			}
-			attrs[index] = a
-		case "int64":
-			a, err := NewInt64Attr(name, 0, known) // FIXME
-			if err != nil {
-				return nil, nil, err
+		// enum values are given in the type specifier, for example enum(M|F)
+		attrType := t.(string)
+		var values []string
+		if strings.HasPrefix(attrType, EnumAttrType+"(") && strings.HasSuffix(attrType, ")") {
+			for _, v := range strings.Split(attrType[len(EnumAttrType)+1:len(attrType)-1], "|") {
+				values = append(values, strings.TrimSpace(v))
 			}
-			attrs[index] = a
-		default:
-			return nil, nil, fmt.Errorf("unsupported attribute type: %s", t)
+			attrType = EnumAttrType
 		}
 
+		a, err := NewEmptyAttr(name, attrType, known, hidden, values...)
+		if err != nil {
+			return nil, nil, err
+		}
+		attrs[index] = a
 	}
 
-	// TODO hidden params
-	return attrs, NewAttrCount(nKnown, nCommitted, 0), nil
+	return attrs, NewAttrCount(nKnown, nCommitted, nHidden), nil
 }
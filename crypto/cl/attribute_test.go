@@ -19,7 +19,9 @@ package cl
 
 import (
 	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -27,6 +29,101 @@ import (
 func TestNewIntAttribute(t *testing.T) {
 	a, err := NewInt64Attr("a", 100, true)
 	assert.NoError(t, err)
-	assert.Equal(t, big.NewInt(100).Cmp(a.InternalValue()), 0)
+	assert.Equal(t, EncodeInt64(100).Cmp(a.InternalValue()), 0)
 	assert.True(t, a.IsKnown())
 }
+
+func TestInt64AttributeNegative(t *testing.T) {
+	a, err := NewInt64Attr("a", -5, true)
+	assert.NoError(t, err)
+	b, _ := NewInt64Attr("b", 3, true)
+	assert.True(t, a.InternalValue().Sign() > 0, "internal value should be nonnegative")
+	assert.True(t, a.InternalValue().Cmp(b.InternalValue()) < 0, "encoding should preserve order")
+
+	val, err := a.FromInternalValue(a.InternalValue())
+	assert.NoError(t, err)
+	assert.Equal(t, -5, val)
+}
+
+func TestStrAttributeTooLong(t *testing.T) {
+	_, err := NewStrAttr("a", strings.Repeat("x", 33), true)
+	assert.Error(t, err)
+
+	a, err := NewHashedStrAttr("a", strings.Repeat("x", 100), true)
+	assert.NoError(t, err)
+	assert.True(t, a.InternalValue().BitLen() <= 256)
+	val, err := a.FromInternalValue(a.InternalValue())
+	assert.NoError(t, err)
+	assert.Len(t, val, 64)
+}
+
+func TestDateAttribute(t *testing.T) {
+	a := NewEmptyDateAttr("a", true)
+	err := a.UpdateValue("2019-07-09")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, EncodeInt64(1562630400).Cmp(a.InternalValue()))
+
+	val, err := a.FromInternalValue(a.InternalValue())
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, 7, 9, 0, 0, 0, 0, time.UTC), val)
+
+	assert.Error(t, a.UpdateValue("9 July 2019"))
+}
+
+func TestBoolAndEnumAttributes(t *testing.T) {
+	b, err := NewBoolAttr("a", true, true)
+	assert.NoError(t, err)
+	val, err := b.FromInternalValue(b.InternalValue())
+	assert.NoError(t, err)
+	assert.Equal(t, true, val)
+
+	e, err := NewEnumAttr("b", "F", []string{"M", "F", "X"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, big.NewInt(1).Cmp(e.InternalValue()))
+	val, err = e.FromInternalValue(e.InternalValue())
+	assert.NoError(t, err)
+	assert.Equal(t, "F", val)
+
+	assert.Error(t, e.UpdateValue("Y"))
+	_, err = e.FromInternalValue(big.NewInt(3))
+	assert.Error(t, err)
+}
+
+func TestUpdateValueFromString(t *testing.T) {
+	i := NewEmptyInt64Attr("a", true)
+	assert.NoError(t, UpdateValueFromString(i, "-25"))
+	assert.Equal(t, int64(-25), i.GetValue())
+	assert.Error(t, UpdateValueFromString(i, "25.5"))
+
+	b := NewEmptyBoolAttr("b", true)
+	assert.NoError(t, UpdateValueFromString(b, "true"))
+	assert.Equal(t, true, b.GetValue())
+	assert.Error(t, UpdateValueFromString(b, "maybe"))
+
+	d := NewEmptyDateAttr("c", true)
+	assert.NoError(t, UpdateValueFromString(d, "2019-07-09"))
+	assert.Equal(t, time.Date(2019, 7, 9, 0, 0, 0, 0, time.UTC), d.GetValue())
+
+	e := NewEmptyEnumAttr("d", []string{"M", "F"}, true)
+	assert.NoError(t, UpdateValueFromString(e, "F"))
+	assert.Error(t, UpdateValueFromString(e, "X"))
+}
+
+func TestParseAttrs(t *testing.T) {
+	specs := map[string]interface{}{
+		"Name":      map[string]interface{}{"index": "0", "type": "string", "known": "true"},
+		"Gender":    map[string]interface{}{"index": "1", "type": "enum(M| F)", "known": "true"},
+		"BirthDate": map[string]interface{}{"index": "2", "type": "date", "known": "false"},
+		"DeviceKey": map[string]interface{}{"index": "3", "type": "hashed_string", "known": "hidden"},
+	}
+	attrs, count, err := ParseAttrs(specs)
+	assert.NoError(t, err)
+	assert.Equal(t, NewAttrCount(2, 1, 1), count)
+	assert.Equal(t, []string{"M", "F"}, attrs[1].(*EnumAttr).Values)
+	assert.IsType(t, &DateAttr{}, attrs[2])
+	assert.True(t, attrs[3].IsHidden())
+
+	specs["Age"] = map[string]interface{}{"index": "4", "type": "float", "known": "true"}
+	_, _, err = ParseAttrs(specs)
+	assert.Error(t, err)
+}
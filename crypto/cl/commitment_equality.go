@@ -0,0 +1,255 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/ec"
+	"github.com/xlab-si/emmy/crypto/ecpedersen"
+	"github.com/xlab-si/emmy/crypto/pedersen"
+)
+
+// CommitmentEquality states that a known attribute, which is not revealed to the verifier,
+// is equal to the value committed in an external Pedersen commitment - for example
+// a commitment used in a payment or voting protocol. This way the attribute can be linked to
+// other protocols (commit-and-prove) without revealing it. AttrIndex is the index of the
+// attribute among known attributes. The commitment is either Commitment under Params
+// (crypto/pedersen) or ECCommitment under ECParams (crypto/ecpedersen).
+//
+// The committed value is equal to the attribute modulo the order of the group of
+// the commitment. The prover needs to set the randomness of the commitment (see SetRandomness).
+type CommitmentEquality struct {
+	AttrIndex    int
+	Params       *pedersen.Params
+	Commitment   *big.Int
+	ECParams     *ecpedersen.Params
+	ECCommitment *ec.GroupElement
+	r            *big.Int
+}
+
+// NewCommitmentEquality returns CommitmentEquality for the commitment g^m * h^r
+// from crypto/pedersen.
+func NewCommitmentEquality(attrIndex int, params *pedersen.Params,
+	commitment *big.Int) *CommitmentEquality {
+	return &CommitmentEquality{
+		AttrIndex:  attrIndex,
+		Params:     params,
+		Commitment: commitment,
+	}
+}
+
+// NewECCommitmentEquality returns CommitmentEquality for the commitment g^m * h^r
+// from crypto/ecpedersen.
+func NewECCommitmentEquality(attrIndex int, params *ecpedersen.Params,
+	commitment *ec.GroupElement) *CommitmentEquality {
+	return &CommitmentEquality{
+		AttrIndex:    attrIndex,
+		ECParams:     params,
+		ECCommitment: commitment,
+	}
+}
+
+// SetRandomness sets the randomness r of the commitment g^m * h^r (for example as returned
+// by GetDecommitMsg of the committer), which is needed to build the proof.
+func (e *CommitmentEquality) SetRandomness(r *big.Int) {
+	e.r = r
+}
+
+// isComplete returns true if the commitment and the parameters of exactly one kind of
+// commitment are set.
+func (e *CommitmentEquality) isComplete() bool {
+	if e.Params != nil {
+		return e.ECParams == nil && e.ECCommitment == nil && e.Commitment != nil &&
+			e.Params.Group != nil && e.Params.Group.P != nil && e.Params.Group.G != nil &&
+			e.Params.Group.Q != nil && e.Params.H != nil
+	}
+
+	return e.ECParams != nil && e.Commitment == nil && e.ECCommitment != nil &&
+		e.ECCommitment.X != nil && e.ECCommitment.Y != nil &&
+		e.ECParams.Group != nil && e.ECParams.Group.Curve != nil && e.ECParams.Group.Q != nil &&
+		e.ECParams.H != nil && e.ECParams.H.X != nil && e.ECParams.H.Y != nil
+}
+
+// equals returns true if e refers to the same attribute and the same commitment (under
+// the same parameters) as equality.
+func (e *CommitmentEquality) equals(equality *CommitmentEquality) bool {
+	if !e.isComplete() || !equality.isComplete() || e.AttrIndex != equality.AttrIndex {
+		return false
+	}
+	if e.Params != nil {
+		if equality.Params == nil {
+			return false
+		}
+		g1, g2 := e.Params.Group, equality.Params.Group
+		return g1.P.Cmp(g2.P) == 0 && g1.G.Cmp(g2.G) == 0 && g1.Q.Cmp(g2.Q) == 0 &&
+			e.Params.H.Cmp(equality.Params.H) == 0 && e.Commitment.Cmp(equality.Commitment) == 0
+	}
+
+	return equality.ECParams != nil &&
+		e.ECParams.Group.Curve.Params().Name == equality.ECParams.Group.Curve.Params().Name &&
+		e.ECParams.H.Equals(equality.ECParams.H) && e.ECCommitment.Equals(equality.ECCommitment)
+}
+
+// order returns the order of the group of the commitment.
+func (e *CommitmentEquality) order() *big.Int {
+	if e.Params != nil {
+		return e.Params.Group.Q
+	}
+
+	return e.ECParams.Group.Q
+}
+
+// commit returns g^m * h^r in the group of the commitment - either as an element of
+// the Schnorr group or as a point on the elliptic curve.
+func (e *CommitmentEquality) commit(m, r *big.Int) (*big.Int, *ec.GroupElement) {
+	q := e.order()
+	m = new(big.Int).Mod(m, q)
+	r = new(big.Int).Mod(r, q)
+	if e.Params != nil {
+		group := e.Params.Group
+		return group.Mul(group.Exp(group.G, m), group.Exp(e.Params.H, r)), nil
+	}
+
+	group := e.ECParams.Group
+	return nil, group.Mul(group.ExpBaseG(m), group.Exp(e.ECParams.H, r))
+}
+
+// CommitmentEqualityProof proves that the commitment of Equality hides the attribute used in
+// the credential proof. ProofRandomData (or ECProofRandomData for commitments on elliptic
+// curves) holds g^m1 * h^r1, where m1 is the random value used for the attribute in
+// the credential proof. ProofData is the response for the randomness of the commitment (the
+// response for the attribute is taken from the credential proof).
+type CommitmentEqualityProof struct {
+	Equality          *CommitmentEquality
+	ProofRandomData   *big.Int
+	ECProofRandomData *ec.GroupElement
+	ProofData         *big.Int
+}
+
+// isComplete returns true if none of the values of the proof is missing.
+func (p *CommitmentEqualityProof) isComplete() bool {
+	if p.Equality == nil || !p.Equality.isComplete() || p.ProofData == nil {
+		return false
+	}
+	if p.Equality.Params != nil {
+		return p.ProofRandomData != nil && p.ECProofRandomData == nil
+	}
+
+	return p.ProofRandomData == nil && p.ECProofRandomData != nil &&
+		p.ECProofRandomData.X != nil && p.ECProofRandomData.Y != nil
+}
+
+// challengeData returns all values of the proof that need to be included in
+// the computation of the challenge.
+func (p *CommitmentEqualityProof) challengeData() []*big.Int {
+	e := p.Equality
+	l := []*big.Int{big.NewInt(int64(e.AttrIndex))}
+	if e.Params != nil {
+		g := e.Params.Group
+		return append(l, g.P, g.G, g.Q, e.Params.H, e.Commitment, p.ProofRandomData)
+	}
+
+	c := e.ECParams.Group.Curve.Params()
+	return append(l, c.P, c.N, c.Gx, c.Gy, e.ECParams.H.X, e.ECParams.H.Y,
+		e.ECCommitment.X, e.ECCommitment.Y, p.ECProofRandomData.X, p.ECProofRandomData.Y)
+}
+
+type commitmentEqualityProver struct {
+	r       *big.Int
+	randomR *big.Int
+	proof   *CommitmentEqualityProof
+}
+
+func newCommitmentEqualityProver(equality *CommitmentEquality,
+	attr *big.Int) (*commitmentEqualityProver, error) {
+	if !equality.isComplete() {
+		return nil, fmt.Errorf("commitment of attribute %d is not complete", equality.AttrIndex)
+	}
+	if equality.r == nil {
+		return nil, fmt.Errorf("randomness of the commitment of attribute %d is not set",
+			equality.AttrIndex)
+	}
+	c, ecC := equality.commit(attr, equality.r)
+	if c != nil && c.Cmp(equality.Commitment) != 0 ||
+		ecC != nil && !ecC.Equals(equality.ECCommitment) {
+		return nil, fmt.Errorf("commitment does not hide attribute %d", equality.AttrIndex)
+	}
+
+	public := *equality
+	public.r = nil
+
+	return &commitmentEqualityProver{
+		r: equality.r,
+		proof: &CommitmentEqualityProof{
+			Equality: &public,
+		},
+	}, nil
+}
+
+// getProofRandomData returns the data which is included in the computation of the challenge.
+// Parameter rM needs to be the random value used for the attribute in the credential proof.
+func (p *commitmentEqualityProver) getProofRandomData(rM *big.Int) []*big.Int {
+	p.randomR = common.GetRandomInt(p.proof.Equality.order())
+	p.proof.ProofRandomData, p.proof.ECProofRandomData = p.proof.Equality.commit(rM, p.randomR)
+
+	return p.proof.challengeData()
+}
+
+func (p *commitmentEqualityProver) getProof(challenge *big.Int) *CommitmentEqualityProof {
+	s := new(big.Int).Mul(challenge, p.r)
+	s.Add(s, p.randomR)
+	p.proof.ProofData = s.Mod(s, p.proof.Equality.order())
+
+	return p.proof
+}
+
+// verifyCommitmentEqualityProof verifies the proof given the challenge and the response for
+// the attribute from the credential proof.
+func verifyCommitmentEqualityProof(p *CommitmentEqualityProof, challenge, sM *big.Int) bool {
+	if !p.isComplete() {
+		return false
+	}
+	e := p.Equality
+
+	// g^sM * h^ProofData = ProofRandomData * Commitment^challenge
+	left, ecLeft := e.commit(sM, p.ProofData)
+	if e.Params != nil {
+		group := e.Params.Group
+		for _, x := range []*big.Int{e.Commitment, p.ProofRandomData} {
+			if x.Sign() <= 0 || x.Cmp(group.P) >= 0 || !group.IsElementInGroup(x) {
+				return false
+			}
+		}
+		right := group.Mul(p.ProofRandomData, group.Exp(e.Commitment, challenge))
+		return left.Cmp(right) == 0
+	}
+
+	group := e.ECParams.Group
+	for _, x := range []*ec.GroupElement{e.ECCommitment, p.ECProofRandomData} {
+		if !group.Curve.IsOnCurve(x.X, x.Y) {
+			return false
+		}
+	}
+	c := new(big.Int).Mod(challenge, group.Q)
+	right := group.Mul(p.ECProofRandomData, group.Exp(e.ECCommitment, c))
+
+	return ecLeft.Equals(right)
+}
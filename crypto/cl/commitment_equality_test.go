@@ -0,0 +1,126 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xlab-si/emmy/crypto/ec"
+	"github.com/xlab-si/emmy/crypto/ecpedersen"
+	"github.com/xlab-si/emmy/crypto/pedersen"
+)
+
+func TestCommitmentEquality(t *testing.T) {
+	attrCount := NewAttrCount(3, 0, 0)
+
+	org := newTestOrg(t, attrCount, 0)
+
+	rawCred := NewRawCred(attrCount)
+	_ = rawCred.AddStrAttr("Name", "Jack", true)
+	_ = rawCred.AddStrAttr("Gender", "M", true)
+	_ = rawCred.AddInt64Attr("Age", 25, true)
+
+	credMgr, res := issueTestCred(t, org, rawCred, nil)
+
+	// the age is committed in external commitments (for example in a voting protocol),
+	// while only the gender is revealed
+	age := credMgr.Attrs.Known[2]
+	pedersenParams, err := pedersen.GenerateParams(256)
+	require.NoError(t, err)
+	committer := pedersen.NewCommitter(pedersenParams)
+	c, err := committer.GetCommitMsg(age)
+	require.NoError(t, err)
+	_, r := committer.GetDecommitMsg()
+	equality := NewCommitmentEquality(2, pedersenParams, c)
+	equality.SetRandomness(r)
+
+	ecParams := ecpedersen.GenerateParams(ec.P256)
+	ecCommitter := ecpedersen.NewCommitter(ecParams)
+	ecC, err := ecCommitter.GetCommitMsg(age)
+	require.NoError(t, err)
+	_, ecR := ecCommitter.GetDecommitMsg()
+	ecEquality := NewECCommitmentEquality(2, ecParams, ecC)
+	ecEquality.SetRandomness(ecR)
+
+	// the verifier knows only the commitments
+	verifierEqualities := []*CommitmentEquality{
+		NewCommitmentEquality(2, pedersenParams, c),
+		NewECCommitmentEquality(2, ecParams, ecC),
+	}
+
+	revealed := []int{1}
+	prove := func(userEqualities, verifierEqualities []*CommitmentEquality,
+		modify func([]*CommitmentEqualityProof)) (bool, error) {
+		nonce, err := org.GetProveCredNonce()
+		require.NoError(t, err)
+		proof, err := credMgr.BuildProof(res.Cred, revealed, []int{},
+			&ProofOptions{CommitmentEqualities: userEqualities}, nonce)
+		require.NoError(t, err)
+		if modify != nil {
+			modify(proof.CommitmentEqualityProofs)
+		}
+
+		verified, _, err := org.ProveCred(proof,
+			&VerificationPolicy{CommitmentEqualities: verifierEqualities}, nonce)
+		return verified, err
+	}
+
+	verified, err := prove([]*CommitmentEquality{equality, ecEquality}, verifierEqualities, nil)
+	require.NoError(t, err)
+	assert.True(t, verified, "commitment equality proofs not accepted")
+
+	verified, _ = prove([]*CommitmentEquality{equality, ecEquality}, verifierEqualities,
+		func(proofs []*CommitmentEqualityProof) {
+			proofs[0].ProofData.Add(proofs[0].ProofData, big.NewInt(1))
+		})
+	assert.False(t, verified, "modified commitment equality proof should not be accepted")
+	verified, _ = prove([]*CommitmentEquality{equality, ecEquality}, verifierEqualities,
+		func(proofs []*CommitmentEqualityProof) {
+			proofs[1].ECProofRandomData = ecParams.Group.ExpBaseG(big.NewInt(42))
+		})
+	assert.False(t, verified, "modified commitment equality proof should not be accepted")
+
+	// the proofs need to be for the commitments required by the verifier
+	_, err = prove([]*CommitmentEquality{equality}, verifierEqualities, nil)
+	assert.Error(t, err, "missing commitment equality proof should not be accepted")
+	other, err := pedersen.NewCommitter(pedersenParams).GetCommitMsg(age)
+	require.NoError(t, err)
+	_, err = prove([]*CommitmentEquality{equality, ecEquality},
+		[]*CommitmentEquality{NewCommitmentEquality(2, pedersenParams, other), verifierEqualities[1]},
+		nil)
+	assert.Error(t, err, "proof for other commitment should not be accepted")
+
+	// the commitment needs to hide the attribute
+	wrong := NewCommitmentEquality(0, pedersenParams, c)
+	wrong.SetRandomness(r)
+	_, err = credMgr.BuildProof(res.Cred, revealed, []int{},
+		&ProofOptions{CommitmentEqualities: []*CommitmentEquality{wrong}}, org.GenNonce())
+	assert.Error(t, err, "proof for commitment of other attribute should not be built")
+	_, err = credMgr.BuildProof(res.Cred, revealed, []int{}, &ProofOptions{
+		CommitmentEqualities: []*CommitmentEquality{NewCommitmentEquality(2, pedersenParams, c)},
+	}, org.GenNonce())
+	assert.Error(t, err, "proof without the randomness of the commitment should not be built")
+	revealedEquality := NewCommitmentEquality(1, pedersenParams, c)
+	revealedEquality.SetRandomness(r)
+	_, err = credMgr.BuildProof(res.Cred, revealed, []int{},
+		&ProofOptions{CommitmentEqualities: []*CommitmentEquality{revealedEquality}}, org.GenNonce())
+	assert.Error(t, err, "proof for revealed attribute should not be built")
+}
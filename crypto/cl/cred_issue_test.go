@@ -18,6 +18,7 @@
 package cl
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,12 +26,10 @@ import (
 
 func TestCL(t *testing.T) {
 	params := GetDefaultParamSizes()
-	attrCount := NewAttrCount(5, 1, 0) // TODO: integrate this into GetDefaultParamSizes
+	attrCount := NewAttrCount(5, 1, 1) // TODO: integrate this into GetDefaultParamSizes
 
-	org, err := NewOrg(params, attrCount)
-	if err != nil {
-		t.Errorf("error when generating CL org: %v", err)
-	}
+	// the keys need an additional hidden attribute for the master secret
+	org := newTestOrg(t, NewAttrCount(5, 1, 2), 0)
 
 	// to generate new testing keys
 	//WriteGob("../../client/testdata/clPubKey.gob", org.Keys.Pub)
@@ -43,32 +42,35 @@ func TestCL(t *testing.T) {
 	_ = cred.AddStrAttr("Gender", "M", true)
 	_ = cred.AddStrAttr("Graduated", "true", true)
 	_ = cred.AddInt64Attr("DateMin", 22342345, true)
-	_ = cred.AddInt64Attr("DateMax", 32342345, true)
+	_ = cred.AddInt64Attr("DateMax", 1592643000, true)
 	_ = cred.AddInt64Attr("Age", 25, false)
+	_ = cred.AddHiddenStrAttr("DeviceKey", "device-secret")
 
 	credMgr, err := NewCredManager(params, org.Keys.Pub, masterSecret, cred)
 	if err != nil {
 		t.Errorf("error when creating a user: %v", err)
 	}
 
-	credManagerPath := "../client/testdata/credManager.gob"
-	WriteGob(credManagerPath, credMgr)
-
-	credIssueNonceOrg := org.GetCredIssueNonce()
+	credIssueNonceOrg, err := org.GetCredIssueNonce()
+	if err != nil {
+		t.Errorf("error when generating nonce: %v", err)
+	}
 
 	credReq, err := credMgr.GetCredRequest(credIssueNonceOrg)
 	if err != nil {
 		t.Errorf("error when generating credential request: %v", err)
 	}
 
-	res, err := org.IssueCred(credReq)
+	res, err := org.IssueCred(credReq, credIssueNonceOrg)
 	if err != nil {
 		t.Errorf("error when issuing credential: %v", err)
 	}
+	_, err = org.IssueCred(credReq, credIssueNonceOrg)
+	assert.Error(t, err, "credential request should not be accepted twice")
 
 	// Store record to db
 	mockDb := NewMockRecordManager()
-	if err := mockDb.Store(credReq.Nym, res.Record); err != nil {
+	if err := mockDb.Store("org1", credReq.Nym, res.Record); err != nil {
 		t.Errorf("error saving record to db: %v", err)
 	}
 
@@ -78,33 +80,94 @@ func TestCL(t *testing.T) {
 	}
 	assert.Equal(t, true, userVerified, "credential proof not valid")
 
+	err = credMgr.SetWitness(res.Cred, res.Witness)
+	assert.NoError(t, err, "accumulator witness not valid")
+
+	credMgrData, err := EncodeBinary(credMgr)
+	assert.NoError(t, err, "error when encoding credential manager")
+
 	// Before updating a credential, create a new Org object (obtaining and updating
-	// credential usually don't happen at the same time)
+	// credential usually don't happen at the same time). The state of the revocation
+	// accumulator needs to be preserved.
+	acc := org.Accumulator
 	org, err = NewOrgFromParams(params, org.Keys)
 	if err != nil {
 		t.Errorf("error when generating CL org: %v", err)
 	}
+	org.Accumulator = acc
 
 	// create new CredManager (updating or proving usually does not happen at the same time
 	// as issuing)
-	ReadGob(credManagerPath, credMgr)
+	credMgr = new(CredManager)
+	err = DecodeBinary(credMgrData, credMgr)
+	assert.NoError(t, err, "error when decoding credential manager")
 
 	// TODO: update to rawcred
 	a, _ := cred.GetAttr("Name")
 	_ = a.UpdateValue("John")
-	credMgr.Update(cred)
+	a, _ = cred.GetAttr("Age")
+	_ = a.UpdateValue(int64(26))
+	err = credMgr.Update(cred)
+	assert.NoError(t, err, "error when updating credential manager")
 
-	rec, err := mockDb.Load(credMgr.Nym)
+	rec, err := mockDb.Load("org1", credMgr.Nym)
 	if err != nil {
 		t.Errorf("error saving record to db: %v", err)
 	}
 
-	newKnownAttrs := cred.GetKnownVals()
-	res1, err := org.UpdateCred(credMgr.Nym, rec, credReq.Nonce, newKnownAttrs)
+	// the update request needs to be computed for a nonce issued by the organization for
+	// credential requests, the new commitment of Age needs to be accompanied by a valid
+	// proof of its opening
+	updateNonce, err := org.GetCredIssueNonce()
+	assert.NoError(t, err)
+	updateReq, err := credMgr.GetCredUpdateRequest(credIssueNonceOrg)
+	assert.NoError(t, err)
+	assert.NotEqual(t, rec.CommitmentsOfAttrs[0], updateReq.CommitmentsOfAttrs[0],
+		"commitment of the updated attribute not changed")
+	_, err = org.UpdateCred(updateReq, rec, updateNonce)
+	assert.Error(t, err, "commitment with the proof for another nonce should not be accepted")
+	proveNonce, err := org.GetProveCredNonce()
+	assert.NoError(t, err)
+	updateReq, err = credMgr.GetCredUpdateRequest(proveNonce)
+	assert.NoError(t, err)
+	_, err = org.UpdateCred(updateReq, rec, proveNonce)
+	assert.Error(t, err, "nonce for credential proofs should not be accepted for an update")
+
+	// the provisioned attributes cannot be changed (Name is changed by the update)
+	updateNonce, err = org.GetCredIssueNonce()
+	assert.NoError(t, err)
+	updateReq, err = credMgr.GetCredUpdateRequest(updateNonce)
+	assert.NoError(t, err)
+	provisionedRec := *rec
+	provisionedRec.ProvisionedAttrs = []int{0}
+	_, err = org.UpdateCred(updateReq, &provisionedRec, updateNonce)
+	assert.Error(t, err, "provisioned attribute should not be changed")
+
+	// only the owner of the nym can update the credential
+	updateNonce, err = org.GetCredIssueNonce()
+	assert.NoError(t, err)
+	updateReq, err = credMgr.GetCredUpdateRequest(updateNonce)
+	assert.NoError(t, err)
+	otherNym := *updateReq
+	otherNym.Nym = new(big.Int).Add(updateReq.Nym, big.NewInt(1))
+	_, err = org.UpdateCred(&otherNym, rec, updateNonce)
+	assert.Error(t, err, "update request without the proof of the nym opening should not be accepted")
+
+	// Gender is not changed by the update
+	rec.ProvisionedAttrs = []int{1}
+	updateNonce, err = org.GetCredIssueNonce()
+	assert.NoError(t, err)
+	updateReq, err = credMgr.GetCredUpdateRequest(updateNonce)
+	assert.NoError(t, err)
+	res1, err := org.UpdateCred(updateReq, rec, updateNonce)
 	if err != nil {
 		t.Errorf("error when updating credential: %v", err)
 	}
-	if err := mockDb.Store(credMgr.Nym, res1.Record); err != nil {
+	assert.Equal(t, updateReq.CommitmentsOfAttrs, res1.Record.CommitmentsOfAttrs)
+	assert.Equal(t, rec.ProvisionedAttrs, res1.Record.ProvisionedAttrs)
+	_, err = org.UpdateCred(updateReq, rec, updateNonce)
+	assert.Error(t, err, "credential update request should not be accepted twice")
+	if err := mockDb.Store("org1", credMgr.Nym, res1.Record); err != nil {
 		t.Errorf("error saving record to db: %v", err)
 	}
 
@@ -114,31 +177,44 @@ func TestCL(t *testing.T) {
 	}
 	assert.Equal(t, true, userVerified, "credential update failed")
 
+	err = credMgr.SetWitness(res1.Cred, res1.Witness)
+	assert.NoError(t, err, "accumulator witness of the updated credential not valid")
+
 	// Some other organization which would like to verify the credential can instantiate org without sec key.
 	// It only needs Pub key of the organization that issued a credential.
 	org, err = NewOrgFromParams(params, org.Keys)
 	if err != nil {
 		t.Errorf("error when generating CL org: %v", err)
 	}
+	org.Accumulator = acc
 
 	revealedKnownAttrsIndices := []int{0}         // reveal only the first known attribute
 	revealedCommitmentsOfAttrsIndices := []int{0} // reveal only the commitment of the first attribute (of those of which only commitments are known)
 
-	nonce := org.GetProveCredNonce()
-	randCred, proof, err := credMgr.BuildProof(res1.Cred, revealedKnownAttrsIndices,
-		revealedCommitmentsOfAttrsIndices, nonce)
+	// prove that unrevealed DateMin and DateMax satisfy the conditions without revealing them
+	predicates := []*Predicate{
+		NewRangePredicate(3, EncodeInt64(0), EncodeInt64(1562643000)),
+		NewRangePredicate(4, EncodeInt64(1562643000), EncodeInt64(2000000000)),
+	}
+
+	nonce, err := org.GetProveCredNonce()
+	if err != nil {
+		t.Errorf("error when generating nonce: %v", err)
+	}
+	proof, err := credMgr.BuildProof(res1.Cred, revealedKnownAttrsIndices,
+		revealedCommitmentsOfAttrsIndices, &ProofOptions{Predicates: predicates}, nonce)
 	if err != nil {
 		t.Errorf("error when building credential proof: %v", err)
 	}
 
-	revealedKnownAttrs, revealedCommitmentsOfAttrs := credMgr.FilterAttributes(revealedKnownAttrsIndices,
-		revealedCommitmentsOfAttrsIndices)
-
-	cVerified, err := org.ProveCred(randCred.A, proof, revealedKnownAttrsIndices,
-		revealedCommitmentsOfAttrsIndices, revealedKnownAttrs, revealedCommitmentsOfAttrs)
+	cVerified, _, err := org.ProveCred(proof, nil, nonce)
 	if err != nil {
 		t.Errorf("error when verifying credential: %v", err)
 	}
 
 	assert.Equal(t, true, cVerified, "credential verification failed")
+
+	// the proof cannot be replayed, as the nonce can be used only once
+	_, _, err = org.ProveCred(proof, nil, nonce)
+	assert.Error(t, err, "credential proof should not be accepted twice")
 }
@@ -18,6 +18,7 @@
 package cl
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -51,7 +52,11 @@ type CredManager struct {
 	V1                        *big.Int            // v1 is random element in U; U = S^v1 * R_i^m_i where m_i are hidden attributes
 	attrsCommitters           []*df.Committer     // committers for committedAttrs
 	commitmentsOfAttrsProvers []*df.OpeningProver // for proving that you know how to open CommitmentsOfAttrs
+	commitmentsOfAttrsUpdated bool                // true when CommitmentsOfAttrs changed in Update
 	CredReqNonce              *big.Int
+	// Witness proves that the credential is in the issuer's revocation accumulator,
+	// it is nil when the issuer does not support revocation
+	Witness *Witness
 }
 
 type Attrs struct {
@@ -91,6 +96,14 @@ func checkBitLen(s []*big.Int, len int) bool {
 
 func NewCredManager(params *Params, pubKey *PubKey,
 	masterSecret *big.Int, rawCred *RawCred) (*CredManager, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	// the key is accepted only when the issuer proves that it cannot be used to
+	// link the presentations of the credential
+	if err := pubKey.VerifyProof(params); err != nil {
+		return nil, errors.Wrap(err, "public key is not well-formed")
+	}
 	if err := rawCred.missingAttrs(); err != nil {
 		return nil, errors.Wrap(err, "not all expected attributes"+
 			" are present in the raw credential")
@@ -98,30 +111,30 @@ func NewCredManager(params *Params, pubKey *PubKey,
 
 	known := rawCred.GetKnownVals()
 	committed := rawCred.GetCommittedVals()
-	hidden := []*big.Int{} // currently not used
+	// when the issuer's public key supports hidden attributes, the master secret is
+	// encoded into the credential as the first hidden attribute (this way the user can
+	// prove that several credentials belong to the same holder), hidden attributes of
+	// the raw credential follow
+	hidden := []*big.Int{}
+	rawHidden := rawCred.GetHiddenVals()
+	if len(pubKey.RsHidden) > 0 {
+		hidden = append(hidden, masterSecret)
+		hidden = append(hidden, rawHidden...)
+	}
+	if len(hidden) != len(pubKey.RsHidden) {
+		return nil, fmt.Errorf("public key supports %d hidden attributes (including the master secret), "+
+			"raw credential has %d", len(pubKey.RsHidden), len(rawHidden))
+	}
 
 	attrs := NewAttrs(known, committed, hidden)
 	if !checkBitLen(attrs.join(), int(params.AttrBitLen)) {
 		return nil, fmt.Errorf("attributes length not ok")
 	}
 
-	attrsCommitters := make([]*df.Committer, len(attrs.Committed))
-	commitmentsOfAttrs := make([]*big.Int, len(attrs.Committed))
-	for i, attr := range attrs.Committed {
-		committer := df.NewCommitter(pubKey.N1, pubKey.G, pubKey.H,
-			pubKey.N1, int(params.SecParam))
-		com, err := committer.GetCommitMsg(attr)
-		if err != nil {
-			return nil, fmt.Errorf("error when creating Pedersen commitment: %s", err)
-		}
-		commitmentsOfAttrs[i] = com
-		attrsCommitters[i] = committer
-	}
-	commitmentsOfAttrsProvers := make([]*df.OpeningProver, len(commitmentsOfAttrs))
-	for i, _ := range commitmentsOfAttrs {
-		prover := df.NewOpeningProver(attrsCommitters[i],
-			int(params.ChallengeSpace))
-		commitmentsOfAttrsProvers[i] = prover
+	commitmentsOfAttrs, attrsCommitters, commitmentsOfAttrsProvers, err := commitAttrs(params, pubKey,
+		attrs.Committed)
+	if err != nil {
+		return nil, err
 	}
 
 	credManager := CredManager{
@@ -134,7 +147,9 @@ func NewCredManager(params *Params, pubKey *PubKey,
 		commitmentsOfAttrsProvers: commitmentsOfAttrsProvers,
 		masterSecret:              masterSecret,
 	}
-	credManager.generateNym()
+	if err := credManager.generateNym(); err != nil {
+		return nil, err
+	}
 
 	return &credManager, nil
 }
@@ -143,7 +158,11 @@ func NewCredManager(params *Params, pubKey *PubKey,
 // with respect to the pseudonym or not.
 func (m *CredManager) generateNym() error {
 	committer := pedersen.NewCommitter(m.PubKey.PedersenParams)
-	nym, err := committer.GetCommitMsg(m.masterSecret)
+	// the master secret might have been generated with respect to the key of another
+	// issuer (the same master secret is used in credentials of different issuers) - the
+	// commitment does not change when it is reduced modulo the order of the group
+	ms := new(big.Int).Mod(m.masterSecret, m.PubKey.PedersenParams.Group.Q)
+	nym, err := committer.GetCommitMsg(ms)
 	if err != nil {
 		return fmt.Errorf("error when creating Pedersen commitment: %s", err)
 	}
@@ -244,10 +263,140 @@ func (m *CredManager) Verify(cred *Cred, AProof *qr.RepresentationProof) (bool,
 	return ver.Verify(AProof.ProofData), nil
 }
 
-// Update updates credential.
-func (m *CredManager) Update(c *RawCred) {
+// commitAttrs creates DF commitments of the committed attributes together with the provers
+// of the knowledge of their openings.
+func commitAttrs(params *Params, pubKey *PubKey, committed []*big.Int) ([]*big.Int, []*df.Committer,
+	[]*df.OpeningProver, error) {
+	commitmentsOfAttrs := make([]*big.Int, len(committed))
+	attrsCommitters := make([]*df.Committer, len(committed))
+	commitmentsOfAttrsProvers := make([]*df.OpeningProver, len(committed))
+	for i, attr := range committed {
+		committer := df.NewCommitter(pubKey.N1, pubKey.G, pubKey.H,
+			pubKey.N1, int(params.SecParam))
+		com, err := committer.GetCommitMsg(attr)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error when creating Pedersen commitment: %s", err)
+		}
+		commitmentsOfAttrs[i] = com
+		attrsCommitters[i] = committer
+		commitmentsOfAttrsProvers[i] = df.NewOpeningProver(committer, int(params.ChallengeSpace))
+	}
+
+	return commitmentsOfAttrs, attrsCommitters, commitmentsOfAttrsProvers, nil
+}
+
+// Update updates credential. When the values of the committed attributes change, new
+// commitments are created - they need to be sent to the issuer together with the proofs
+// of the knowledge of their openings (see GetCredUpdateRequest).
+func (m *CredManager) Update(c *RawCred) error {
+	committed := c.GetCommittedVals()
+	if len(committed) != len(m.Attrs.Committed) {
+		return fmt.Errorf("raw credential has %d committed attributes, expected %d",
+			len(committed), len(m.Attrs.Committed))
+	}
+	changed := false
+	for i, attr := range committed {
+		if attr.Cmp(m.Attrs.Committed[i]) != 0 {
+			changed = true
+		}
+	}
+
+	if changed {
+		if !checkBitLen(committed, int(m.Params.AttrBitLen)) {
+			return fmt.Errorf("attributes length not ok")
+		}
+		commitmentsOfAttrs, attrsCommitters, commitmentsOfAttrsProvers, err := commitAttrs(m.Params,
+			m.PubKey, committed)
+		if err != nil {
+			return err
+		}
+		m.Attrs.Committed = committed
+		m.CommitmentsOfAttrs = commitmentsOfAttrs
+		m.attrsCommitters = attrsCommitters
+		m.commitmentsOfAttrsProvers = commitmentsOfAttrsProvers
+		m.commitmentsOfAttrsUpdated = true
+	}
+
 	m.RawCred = c
 	m.Attrs.Known = m.RawCred.GetKnownVals()
+
+	return nil
+}
+
+// GetCredUpdateRequest returns the request for the credential with the attribute values
+// set by the last Update, which needs to be computed for nonceOrg (obtained from
+// GetCredIssueNonce of the organization). The request contains the proof of the knowledge
+// of the nym opening and, when the committed attributes have been changed by Update, the
+// new commitments of attributes together with the proofs of the knowledge of their openings.
+func (m *CredManager) GetCredUpdateRequest(nonceOrg *big.Int) (*CredUpdateRequest, error) {
+	nymProver, err := m.getNymProver()
+	if err != nil {
+		return nil, err
+	}
+	nymProofRandomData := nymProver.GetProofRandomData()
+
+	var commitmentsOfAttrs []*big.Int
+	var proofRandomData []*big.Int
+	if m.commitmentsOfAttrsUpdated {
+		commitmentsOfAttrs = m.CommitmentsOfAttrs
+		proofRandomData = make([]*big.Int, len(m.commitmentsOfAttrsProvers))
+		for i, prover := range m.commitmentsOfAttrsProvers {
+			proofRandomData[i] = prover.GetProofRandomData()
+		}
+	}
+
+	challenge := getCredUpdateChallenge(m.PubKey, m.Nym, nonceOrg, m.CredReqNonce,
+		nymProofRandomData, commitmentsOfAttrs, proofRandomData)
+	nymProof := schnorr.NewProof(nymProofRandomData, challenge,
+		nymProver.GetProofData(challenge))
+
+	var proofs []*df.OpeningProof
+	if m.commitmentsOfAttrsUpdated {
+		proofs = make([]*df.OpeningProof, len(m.commitmentsOfAttrsProvers))
+		for i, prover := range m.commitmentsOfAttrsProvers {
+			proofData1, proofData2 := prover.GetProofData(challenge)
+			proofs[i] = df.NewOpeningProof(proofRandomData[i], challenge, proofData1, proofData2)
+		}
+	}
+
+	return NewCredUpdateRequest(m.Nym, nymProof, m.Attrs.Known, commitmentsOfAttrs, proofs,
+		m.CredReqNonce), nil
+}
+
+// getCredUpdateChallenge returns the challenge for the proof of the knowledge of the nym
+// opening and the proofs of the knowledge of the openings of the updated commitments
+// of attributes.
+func getCredUpdateChallenge(pubKey *PubKey, nym, nonceOrg, nonceUser,
+	nymProofRandomData *big.Int, commitmentsOfAttrs, proofRandomData []*big.Int) *big.Int {
+	l := []*big.Int{pubKey.GetContext(), nym, nonceOrg, nonceUser, nymProofRandomData}
+	l = append(l, commitmentsOfAttrs...)
+	l = append(l, proofRandomData...)
+
+	return common.Hash(l...)
+}
+
+// SetWitness checks whether witness w is valid for the credential cred and stores it
+// to be used in the non-revocation proof.
+func (m *CredManager) SetWitness(cred *Cred, w *Witness) error {
+	if m.PubKey.Accumulator == nil {
+		return fmt.Errorf("public key does not support revocation")
+	}
+	if !w.Verify(m.PubKey.Accumulator, cred.E) {
+		return fmt.Errorf("witness is not valid")
+	}
+	m.Witness = w
+
+	return nil
+}
+
+// UpdateWitness applies accumulator updates (revocations of other credentials) to the
+// witness of the credential cred. An error is returned if cred itself has been revoked.
+func (m *CredManager) UpdateWitness(cred *Cred, updates []*AccumulatorUpdate) error {
+	if m.Witness == nil {
+		return fmt.Errorf("witness is not set")
+	}
+
+	return m.Witness.Update(m.PubKey.Accumulator, cred.E, updates)
 }
 
 // FilterAttributes returns only attributes to be revealed to the verifier.
@@ -285,20 +434,123 @@ func (m *CredManager) randomize(cred *Cred) *Cred {
 	return NewCred(A, cred.E, v11)
 }
 
-func (m *CredManager) GetProofChallenge(credProofRandomData, nonceOrg *big.Int) *big.Int {
+// GetProofChallenge returns the challenge for the credential proof. Parameter
+// additionalProofRandomData contains the data of the non-revocation proof, predicate
+// proofs, set membership proofs, domain pseudonym proof, escrow proof and commitment
+// equality proofs (if any).
+func (m *CredManager) GetProofChallenge(credProofRandomData, nonceOrg *big.Int,
+	additionalProofRandomData ...*big.Int) *big.Int {
 	context := m.PubKey.GetContext()
 	l := []*big.Int{context, credProofRandomData, nonceOrg}
+	l = append(l, additionalProofRandomData...)
 	//l = append(l, ...) // TODO: add other values
 
 	return common.Hash(l...)
 }
 
-// BuildProof builds a proof of knowledge for the given credential.
+// ProofOptions holds the optional parts of a credential proof. Predicates and set memberships
+// (which need to refer to unrevealed known attributes) are proved for the attributes. When
+// Scope (supplied by the verifier) is not nil, a domain pseudonym for the scope is computed
+// together with a proof that it is derived from the master secret in the credential. When
+// Escrow (supplied by the verifier) is not nil, the attribute is encrypted under the public
+// key of the inspector and it is proved that the ciphertext holds the attribute. For each
+// of CommitmentEqualities (with the randomness of the commitment set) it is proved that
+// the commitment hides the (unrevealed known) attribute.
+type ProofOptions struct {
+	Predicates           []*Predicate
+	SetMemberships       []*SetMembership
+	Scope                []byte
+	Escrow               *Escrow
+	CommitmentEqualities []*CommitmentEquality
+}
+
+// BuildProof builds a proof of knowledge for the given credential, which reveals the known
+// attributes and commitments of attributes given by revealedKnownAttrsIndices and
+// revealedCommitmentsOfAttrsIndices. When the issuer supports revocation, a proof that the
+// credential has not been revoked is included as well. The accompanying proofs requested
+// by opts (which can be nil) are set in the returned proof. Note that RandCred of the proof
+// holds the whole randomized credential - only RandCred.A is to be sent to the verifier.
 func (m *CredManager) BuildProof(cred *Cred, revealedKnownAttrsIndices,
-	revealedCommitmentsOfAttrsIndices []int, nonceOrg *big.Int) (*Cred,
-	*qr.RepresentationProof, error) {
+	revealedCommitmentsOfAttrsIndices []int, opts *ProofOptions, nonceOrg *big.Int) (*CredProof,
+	error) {
+	prover, err := m.newCredProver(cred, revealedKnownAttrsIndices, revealedCommitmentsOfAttrsIndices,
+		opts, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := m.GetProofChallenge(prover.proofRandomData, nonceOrg,
+		prover.additionalProofRandomData...)
+
+	return prover.getProof(challenge)
+}
+
+// credProver holds the state of a credential proof between the computation of the proof
+// random data and the proof data (the challenge might depend on the proofs of other credentials).
+type credProver struct {
+	m                                 *CredManager
+	rCred                             *Cred
+	prover                            *qr.RepresentationProver
+	proofRandomData                   *big.Int
+	additionalProofRandomData         []*big.Int
+	nonRevProver                      *nonRevocationProver
+	nonRevProofRandomData             []*big.Int
+	predicateProvers                  []*predicateProver
+	setMembershipProvers              []*setMembershipProver
+	domainPseudonymProver             *domainPseudonymProver
+	escrowProver                      *escrowProver
+	commitmentEqualityProvers         []*commitmentEqualityProver
+	revealedKnownAttrsIndices         []int
+	revealedCommitmentsOfAttrsIndices []int
+}
+
+// newCredProver randomizes the credential and computes the proof random data. If
+// masterSecretRandom is not nil, it is used as the random value for the master secret
+// (which is the first hidden attribute) - this way the proof can be linked
+// with proofs of other credentials containing the same master secret. Similarly, attrRandoms
+// maps indices of known attributes to the random values which are to be used for them (to
+// prove the equality with attributes of other credentials). The accompanying proofs are
+// prepared as requested by opts (see ProofOptions).
+func (m *CredManager) newCredProver(cred *Cred, revealedKnownAttrsIndices,
+	revealedCommitmentsOfAttrsIndices []int, opts *ProofOptions, attrRandoms map[int]*big.Int,
+	masterSecretRandom *big.Int) (*credProver, error) {
+	if opts == nil {
+		opts = &ProofOptions{}
+	}
+	predicates, setMemberships := opts.Predicates, opts.SetMemberships
+	scope, escrow, commitmentEqualities := opts.Scope, opts.Escrow, opts.CommitmentEqualities
 	if m.V1 == nil {
-		return nil, nil, fmt.Errorf("v1 is not set (generated in GetCredRequest)")
+		return nil, fmt.Errorf("v1 is not set (generated in GetCredRequest)")
+	}
+	if m.PubKey.Accumulator != nil && m.Witness == nil {
+		return nil, fmt.Errorf("witness is not set (needed for non-revocation proof)")
+	}
+	if (masterSecretRandom != nil || scope != nil) && len(m.Attrs.Hidden) == 0 {
+		return nil, fmt.Errorf("master secret is not encoded in the credential")
+	}
+	attrIndices := []int{}
+	for _, p := range predicates {
+		attrIndices = append(attrIndices, p.AttrIndex)
+	}
+	for _, s := range setMemberships {
+		attrIndices = append(attrIndices, s.AttrIndex)
+	}
+	if escrow != nil {
+		attrIndices = append(attrIndices, escrow.AttrIndex)
+	}
+	for _, e := range commitmentEqualities {
+		attrIndices = append(attrIndices, e.AttrIndex)
+	}
+	for ind := range attrRandoms {
+		attrIndices = append(attrIndices, ind)
+	}
+	for _, ind := range attrIndices {
+		if ind < 0 || ind >= len(m.Attrs.Known) {
+			return nil, fmt.Errorf("proof refers to unknown attribute %d", ind)
+		}
+		if common.Contains(revealedKnownAttrsIndices, ind) {
+			return nil, fmt.Errorf("proof refers to revealed attribute %d", ind)
+		}
 	}
 	rCred := m.randomize(cred)
 	// Z = cred.A^cred.e * S^cred.v11 * R_1^m_1 * ... * R_l^m_l
@@ -373,11 +625,298 @@ func (m *CredManager) BuildProof(cred *Cred, revealedKnownAttrsIndices,
 
 	proofRandomData, err := prover.GetProofRandomDataGivenBoundaries(boundaries, true)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error when generating representation proof random data: %s", err)
+		return nil, fmt.Errorf("error when generating representation proof random data: %s", err)
+	}
+	randomVals := prover.GetRandomValues()
+	// master secret follows unrevealed known attributes and unrevealed commitments of attributes
+	masterSecretPos := len(unrevealedKnownAttrs) + len(unrevealedCommitmentsOfAttrs)
+	if masterSecretRandom != nil || len(attrRandoms) > 0 {
+		if masterSecretRandom != nil {
+			randomVals[masterSecretPos] = masterSecretRandom
+		}
+		for ind, r := range attrRandoms {
+			randomVals[unrevealedPosition(revealedKnownAttrsIndices, ind)] = r
+		}
+		proofRandomData, err = prover.GetProofRandomDataGivenRandomValues(randomVals)
+		if err != nil {
+			return nil, fmt.Errorf("error when generating representation proof random data: %s", err)
+		}
+	}
+
+	p := &credProver{
+		m:                                 m,
+		rCred:                             rCred,
+		prover:                            prover,
+		proofRandomData:                   proofRandomData,
+		revealedKnownAttrsIndices:         revealedKnownAttrsIndices,
+		revealedCommitmentsOfAttrsIndices: revealedCommitmentsOfAttrsIndices,
+	}
+
+	if m.PubKey.Accumulator != nil {
+		p.nonRevProver = newNonRevocationProver(m.Params, m.PubKey.Accumulator, m.Witness, rCred.E)
+		// the same random value as for e in the credential proof needs to be used
+		t := p.nonRevProver.getProofRandomData(randomVals[len(randomVals)-2])
+		p.nonRevProofRandomData = t
+		p.additionalProofRandomData = append(p.additionalProofRandomData, p.nonRevProver.commitments...)
+		p.additionalProofRandomData = append(p.additionalProofRandomData, t...)
+	}
+
+	p.predicateProvers = make([]*predicateProver, len(predicates))
+	for i, pr := range predicates {
+		p.predicateProvers[i], err = newPredicateProver(m.Params, m.PubKey, pr, m.Attrs.Known[pr.AttrIndex])
+		if err != nil {
+			return nil, err
+		}
+		// the same random value as for the attribute in the credential proof needs to be used
+		rM := randomVals[unrevealedPosition(revealedKnownAttrsIndices, pr.AttrIndex)]
+		p.additionalProofRandomData = append(p.additionalProofRandomData,
+			p.predicateProvers[i].getProofRandomData(rM)...)
+	}
+
+	p.setMembershipProvers = make([]*setMembershipProver, len(setMemberships))
+	for i, s := range setMemberships {
+		p.setMembershipProvers[i], err = newSetMembershipProver(m.Params, m.PubKey, s,
+			m.Attrs.Known[s.AttrIndex])
+		if err != nil {
+			return nil, err
+		}
+		rM := randomVals[unrevealedPosition(revealedKnownAttrsIndices, s.AttrIndex)]
+		p.additionalProofRandomData = append(p.additionalProofRandomData,
+			p.setMembershipProvers[i].getProofRandomData(rM)...)
+	}
+
+	if scope != nil {
+		p.domainPseudonymProver, err = newDomainPseudonymProver(m.PubKey, scope, m.Attrs.Hidden[0])
+		if err != nil {
+			return nil, err
+		}
+		// the same random value as for the master secret in the credential proof needs to be used
+		p.additionalProofRandomData = append(p.additionalProofRandomData,
+			p.domainPseudonymProver.getProofRandomData(randomVals[masterSecretPos])...)
+	}
+
+	if escrow != nil {
+		p.escrowProver, err = newEscrowProver(m.Params, escrow, m.Attrs.Known[escrow.AttrIndex])
+		if err != nil {
+			return nil, err
+		}
+		rM := randomVals[unrevealedPosition(revealedKnownAttrsIndices, escrow.AttrIndex)]
+		p.additionalProofRandomData = append(p.additionalProofRandomData,
+			p.escrowProver.getProofRandomData(rM)...)
+	}
+
+	p.commitmentEqualityProvers = make([]*commitmentEqualityProver, len(commitmentEqualities))
+	for i, e := range commitmentEqualities {
+		p.commitmentEqualityProvers[i], err = newCommitmentEqualityProver(e, m.Attrs.Known[e.AttrIndex])
+		if err != nil {
+			return nil, err
+		}
+		rM := randomVals[unrevealedPosition(revealedKnownAttrsIndices, e.AttrIndex)]
+		p.additionalProofRandomData = append(p.additionalProofRandomData,
+			p.commitmentEqualityProvers[i].getProofRandomData(rM)...)
+	}
+
+	return p, nil
+}
+
+func (p *credProver) getProof(challenge *big.Int) (*CredProof, error) {
+	m := p.m
+	proofData := p.prover.GetProofData(challenge)
+
+	var nonRevProof *NonRevocationProof
+	if p.nonRevProver != nil {
+		nonRevProof = NewNonRevocationProof(m.Witness.Epoch, p.nonRevProver.commitments,
+			p.nonRevProofRandomData, p.nonRevProver.getProofData(challenge))
+	}
+
+	predicateProofs := make([]*PredicateProof, len(p.predicateProvers))
+	for i, pr := range p.predicateProvers {
+		var err error
+		predicateProofs[i], err = pr.getProof(challenge)
+		if err != nil {
+			return nil, fmt.Errorf("error when generating predicate proof: %s", err)
+		}
 	}
 
-	challenge := m.GetProofChallenge(proofRandomData, nonceOrg)
-	proofData := prover.GetProofData(challenge)
+	setMembershipProofs := make([]*SetMembershipProof, len(p.setMembershipProvers))
+	for i, s := range p.setMembershipProvers {
+		setMembershipProofs[i] = s.getProof(challenge)
+	}
+
+	var domainPseudonymProof *DomainPseudonymProof
+	if p.domainPseudonymProver != nil {
+		domainPseudonymProof = p.domainPseudonymProver.getProof()
+	}
 
-	return rCred, qr.NewRepresentationProof(proofRandomData, challenge, proofData), nil
+	var escrowProof *EscrowProof
+	if p.escrowProver != nil {
+		escrowProof = p.escrowProver.getProof(challenge)
+	}
+
+	commitmentEqualityProofs := make([]*CommitmentEqualityProof, len(p.commitmentEqualityProvers))
+	for i, e := range p.commitmentEqualityProvers {
+		commitmentEqualityProofs[i] = e.getProof(challenge)
+	}
+
+	revealedKnownAttrs, revealedCommitmentsOfAttrs := m.FilterAttributes(p.revealedKnownAttrsIndices,
+		p.revealedCommitmentsOfAttrsIndices)
+
+	return &CredProof{
+		RandCred:                          p.rCred,
+		Proof:                             qr.NewRepresentationProof(p.proofRandomData, challenge, proofData),
+		NonRevProof:                       nonRevProof,
+		PredicateProofs:                   predicateProofs,
+		SetMembershipProofs:               setMembershipProofs,
+		DomainPseudonymProof:              domainPseudonymProof,
+		EscrowProof:                       escrowProof,
+		CommitmentEqualityProofs:          commitmentEqualityProofs,
+		RevealedKnownAttrsIndices:         p.revealedKnownAttrsIndices,
+		RevealedCommitmentsOfAttrsIndices: p.revealedCommitmentsOfAttrsIndices,
+		RevealedKnownAttrs:                revealedKnownAttrs,
+		RevealedCommitmentsOfAttrs:        revealedCommitmentsOfAttrs,
+	}, nil
+}
+
+// credManagerJSON is the JSON encoding of CredManager. Besides the exported fields it holds
+// the master secret and the randomness of the commitments (nym and commitments of attributes),
+// which are needed to restore the committers.
+type credManagerJSON struct {
+	Params                       *Params
+	PubKey                       *PubKey
+	RawCred                      *RawCred
+	Nym                          *big.Int
+	NymRandomness                *big.Int
+	MasterSecret                 *big.Int
+	Attrs                        *Attrs
+	CommitmentsOfAttrs           []*big.Int
+	CommitmentsOfAttrsRandomness []*big.Int
+	CommitmentsOfAttrsUpdated    bool
+	V1                           *big.Int
+	CredReqNonce                 *big.Int
+	Witness                      *Witness
+}
+
+// MarshalJSON encodes the credential manager together with the secrets of the credential
+// holder - the encoding needs to be kept confidential.
+func (m *CredManager) MarshalJSON() ([]byte, error) {
+	j, err := m.toJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(j)
+}
+
+// MarshalCanonical returns the value the credential manager is encoded as in the canonical
+// encoding (see EncodeJSON) - the same as in MarshalJSON, the encoding needs to be kept
+// confidential.
+func (m *CredManager) MarshalCanonical() (interface{}, error) {
+	return m.toJSON()
+}
+
+func (m *CredManager) toJSON() (*credManagerJSON, error) {
+	if m.nymCommitter == nil {
+		return nil, fmt.Errorf("credential manager has no nym")
+	}
+	_, nymRandomness := m.nymCommitter.GetDecommitMsg()
+	randomness := make([]*big.Int, len(m.attrsCommitters))
+	for i, committer := range m.attrsCommitters {
+		_, randomness[i] = committer.GetDecommitMsg()
+	}
+
+	return &credManagerJSON{
+		Params:                       m.Params,
+		PubKey:                       m.PubKey,
+		RawCred:                      m.RawCred,
+		Nym:                          m.Nym,
+		NymRandomness:                nymRandomness,
+		MasterSecret:                 m.masterSecret,
+		Attrs:                        m.Attrs,
+		CommitmentsOfAttrs:           m.CommitmentsOfAttrs,
+		CommitmentsOfAttrsRandomness: randomness,
+		CommitmentsOfAttrsUpdated:    m.commitmentsOfAttrsUpdated,
+		V1:                           m.V1,
+		CredReqNonce:                 m.CredReqNonce,
+		Witness:                      m.Witness,
+	}, nil
+}
+
+// UnmarshalJSON restores the credential manager encoded by MarshalJSON.
+func (m *CredManager) UnmarshalJSON(data []byte) error {
+	var j credManagerJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	return m.fromJSON(&j)
+}
+
+// UnmarshalCanonical restores the credential manager from the canonical encoding.
+func (m *CredManager) UnmarshalCanonical(decode func(v interface{}) error) error {
+	var j credManagerJSON
+	if err := decode(&j); err != nil {
+		return err
+	}
+
+	return m.fromJSON(&j)
+}
+
+func (m *CredManager) fromJSON(j *credManagerJSON) error {
+	if j.Params == nil || j.PubKey == nil || j.RawCred == nil || j.Attrs == nil ||
+		j.Nym == nil || j.NymRandomness == nil || j.MasterSecret == nil ||
+		j.PubKey.PedersenParams == nil || j.PubKey.PedersenParams.Group == nil {
+		return fmt.Errorf("credential manager is not complete")
+	}
+	if err := j.Params.Validate(); err != nil {
+		return err
+	}
+	if len(j.CommitmentsOfAttrs) != len(j.Attrs.Committed) ||
+		len(j.CommitmentsOfAttrsRandomness) != len(j.Attrs.Committed) {
+		return fmt.Errorf("commitments of attributes do not match the committed attributes")
+	}
+
+	nymCommitter := pedersen.NewCommitter(j.PubKey.PedersenParams)
+	ms := new(big.Int).Mod(j.MasterSecret, j.PubKey.PedersenParams.Group.Q)
+	nym, err := nymCommitter.GetCommitMsgWithGivenR(ms, j.NymRandomness)
+	if err != nil {
+		return fmt.Errorf("error when restoring Pedersen commitment: %s", err)
+	}
+	if nym.Cmp(j.Nym) != 0 {
+		return fmt.Errorf("nym does not match the master secret")
+	}
+
+	attrsCommitters := make([]*df.Committer, len(j.Attrs.Committed))
+	commitmentsOfAttrsProvers := make([]*df.OpeningProver, len(j.Attrs.Committed))
+	for i, attr := range j.Attrs.Committed {
+		committer := df.NewCommitter(j.PubKey.N1, j.PubKey.G, j.PubKey.H,
+			j.PubKey.N1, int(j.Params.SecParam))
+		com, err := committer.GetCommitMsgWithGivenR(attr, j.CommitmentsOfAttrsRandomness[i])
+		if err != nil {
+			return fmt.Errorf("error when restoring commitment of attribute: %s", err)
+		}
+		if com.Cmp(j.CommitmentsOfAttrs[i]) != 0 {
+			return fmt.Errorf("commitment of attribute does not match the attribute")
+		}
+		attrsCommitters[i] = committer
+		commitmentsOfAttrsProvers[i] = df.NewOpeningProver(committer, int(j.Params.ChallengeSpace))
+	}
+
+	*m = CredManager{
+		Params:                    j.Params,
+		PubKey:                    j.PubKey,
+		RawCred:                   j.RawCred,
+		nymCommitter:              nymCommitter,
+		Nym:                       j.Nym,
+		masterSecret:              j.MasterSecret,
+		Attrs:                     j.Attrs,
+		CommitmentsOfAttrs:        j.CommitmentsOfAttrs,
+		V1:                        j.V1,
+		attrsCommitters:           attrsCommitters,
+		commitmentsOfAttrsProvers: commitmentsOfAttrsProvers,
+		commitmentsOfAttrsUpdated: j.CommitmentsOfAttrsUpdated,
+		CredReqNonce:              j.CredReqNonce,
+		Witness:                   j.Witness,
+	}
+
+	return nil
 }
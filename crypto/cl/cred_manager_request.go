@@ -53,6 +53,34 @@ func NewCredRequest(nym *big.Int, knownAttrs, commitmentsOfAttrs []*big.Int, nym
 	}
 }
 
+// CredUpdateRequest is a request for a credential with new attribute values, sent by
+// the holder of the credential with nym Nym (see CredManager.GetCredUpdateRequest and
+// Org.UpdateCred). NymProof proves the knowledge of the opening of Nym, so that only
+// its owner can update the credential. CommitmentsOfAttrs and CommitmentsOfAttrsProofs
+// are nil when the committed attributes are not changed.
+type CredUpdateRequest struct {
+	Nym                      *big.Int
+	NymProof                 *schnorr.Proof
+	NewKnownAttrs            []*big.Int
+	CommitmentsOfAttrs       []*big.Int
+	CommitmentsOfAttrsProofs []*df.OpeningProof
+	// Nonce is the nonce of the user, used in the proof of the new credential
+	Nonce *big.Int
+}
+
+func NewCredUpdateRequest(nym *big.Int, nymProof *schnorr.Proof, newKnownAttrs,
+	commitmentsOfAttrs []*big.Int, commitmentsOfAttrsProofs []*df.OpeningProof,
+	nonce *big.Int) *CredUpdateRequest {
+	return &CredUpdateRequest{
+		Nym:                      nym,
+		NymProof:                 nymProof,
+		NewKnownAttrs:            newKnownAttrs,
+		CommitmentsOfAttrs:       commitmentsOfAttrs,
+		CommitmentsOfAttrsProofs: commitmentsOfAttrsProofs,
+		Nonce:                    nonce,
+	}
+}
+
 // computeU computes U = S^v1 * R_1^m_1 * ... * R_NumAttrs^m_NumAttrs (mod n) where only hiddenAttrs are used and
 // where v1 is random from +-{0,1}^(NLength + SecParam)
 func (m *CredManager) computeU() (*big.Int, *big.Int) {
@@ -0,0 +1,251 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package cl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// boltRecordsBucket holds a nested bucket with the receiver records of each
+	// organization (by its name), keyed by nyms
+	boltRecordsBucket    = []byte("cl_records")
+	boltEscrowBucket     = []byte("cl_escrow_records")
+	boltPseudonymsBucket = []byte("cl_pseudonyms")
+	// boltAccumulatorsBucket holds a nested bucket with the updates of each accumulator
+	// (by key ID), keyed by their epochs
+	boltAccumulatorsBucket = []byte("cl_accumulators")
+)
+
+// BoltRecordManager stores receiver records, escrow records, domain pseudonyms and
+// accumulator updates in an embedded BoltDB database (a single file, which can be opened
+// by one process at a time). It is safe for concurrent use.
+type BoltRecordManager struct {
+	db *bolt.DB
+}
+
+// NewBoltRecordManager creates the buckets for the records in db (if they
+// do not exist yet) and returns an instance of BoltRecordManager.
+func NewBoltRecordManager(db *bolt.DB) (*BoltRecordManager, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{boltRecordsBucket, boltEscrowBucket, boltPseudonymsBucket,
+			boltAccumulatorsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error when creating buckets for records: %v", err)
+	}
+
+	return &BoltRecordManager{
+		db: db,
+	}, nil
+}
+
+func (m *BoltRecordManager) Store(orgName string, nym *big.Int, r *ReceiverRecord) error {
+	data, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return m.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(boltRecordsBucket).CreateBucketIfNotExists([]byte(orgName))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(nym.String()), data)
+	})
+}
+
+// getRecord returns the receiver record of the organization orgName and nym stored
+// in the transaction tx, nil if there is no such record.
+func getRecord(tx *bolt.Tx, orgName string, nym *big.Int) []byte {
+	b := tx.Bucket(boltRecordsBucket).Bucket([]byte(orgName))
+	if b == nil {
+		return nil
+	}
+
+	return b.Get([]byte(nym.String()))
+}
+
+func (m *BoltRecordManager) Load(orgName string, nym *big.Int) (*ReceiverRecord, error) {
+	var rec ReceiverRecord
+	err := m.db.View(func(tx *bolt.Tx) error {
+		data := getRecord(tx, orgName, nym)
+		if data == nil {
+			return fmt.Errorf("record does not exist")
+		}
+		return rec.UnmarshalBinary(data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+func (m *BoltRecordManager) Delete(orgName string, nym *big.Int) error {
+	return m.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltRecordsBucket).Bucket([]byte(orgName))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(nym.String()))
+	})
+}
+
+func (m *BoltRecordManager) Exists(orgName string, nym *big.Int) (bool, error) {
+	exists := false
+	err := m.db.View(func(tx *bolt.Tx) error {
+		exists = getRecord(tx, orgName, nym) != nil
+		return nil
+	})
+
+	return exists, err
+}
+
+func (m *BoltRecordManager) List(orgName string) ([]*big.Int, error) {
+	var nyms []string
+	err := m.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltRecordsBucket).Bucket([]byte(orgName))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, _ []byte) error {
+			nyms = append(nyms, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNyms(nyms)
+}
+
+func (m *BoltRecordManager) StoreEscrowRecord(r *EscrowRecord) error {
+	data, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return m.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltEscrowBucket).Put([]byte(r.GetID()), data)
+	})
+}
+
+func (m *BoltRecordManager) LoadEscrowRecord(id string) (*EscrowRecord, error) {
+	var rec EscrowRecord
+	err := m.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltEscrowBucket).Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("escrow record does not exist")
+		}
+		return rec.UnmarshalBinary(data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+func (m *BoltRecordManager) ClaimPseudonym(scope []byte, pseudonym *big.Int) (bool, error) {
+	claimed := false
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltPseudonymsBucket)
+		key := []byte(pseudonymKey(scope, pseudonym))
+		if b.Get(key) != nil {
+			return nil
+		}
+		claimed = true
+		return b.Put(key, []byte{1})
+	})
+
+	return claimed && err == nil, err
+}
+
+// boltEpochKey returns the key of the accumulator update for the epoch (the keys are
+// ordered by epochs).
+func boltEpochKey(epoch int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(epoch))
+	return key
+}
+
+func (m *BoltRecordManager) StoreAccumulatorUpdate(keyID string, u *AccumulatorUpdate) error {
+	data, err := u.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return m.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(boltAccumulatorsBucket).CreateBucketIfNotExists([]byte(keyID))
+		if err != nil {
+			return err
+		}
+		if n := b.Stats().KeyN; u.Epoch != n+1 {
+			return fmt.Errorf("update for epoch %d does not follow the %d stored updates",
+				u.Epoch, n)
+		}
+		return b.Put(boltEpochKey(u.Epoch), data)
+	})
+}
+
+func (m *BoltRecordManager) LoadAccumulatorUpdates(keyID string) ([]*AccumulatorUpdate, error) {
+	updates := []*AccumulatorUpdate{}
+	err := m.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltAccumulatorsBucket).Bucket([]byte(keyID))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, data []byte) error {
+			u := new(AccumulatorUpdate)
+			if err := u.UnmarshalBinary(data); err != nil {
+				return err
+			}
+			updates = append(updates, u)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// Backup writes a consistent copy of the whole database (not only the records) to w,
+// while the database remains available. It returns the number of bytes written.
+func (m *BoltRecordManager) Backup(w io.Writer) (int64, error) {
+	var n int64
+	err := m.db.View(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+
+	return n, err
+}
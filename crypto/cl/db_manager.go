@@ -19,6 +19,8 @@ package cl
 
 import (
 	"math/big"
+	"sync"
+	"time"
 
 	"fmt"
 
@@ -26,17 +28,75 @@ import (
 )
 
 // ReceiverRecordManager manages receiver records
-// tied to particular nyms.
+// tied to particular nyms. The records are kept per organization
+// (given by its name), as the same nym can be used with several
+// organizations.
 type ReceiverRecordManager interface {
-	// Store stores the nym and the corresponding ReceiverRecord,
-	// returning error in case the data was not successfully stored.
-	Store(*big.Int, *ReceiverRecord) error
+	// Store stores the nym and the corresponding ReceiverRecord of the
+	// organization, returning error in case the data was not successfully
+	// stored.
+	Store(string, *big.Int, *ReceiverRecord) error
 
 	// Load loads the ReceiverRecord associated with the given
-	// nym, returning an error in case no record was found, or
-	// in case of error in the interaction with the
+	// organization and nym, returning an error in case no record was
+	// found, or in case of error in the interaction with the
 	// storage backend.
-	Load(*big.Int) (*ReceiverRecord, error)
+	Load(string, *big.Int) (*ReceiverRecord, error)
+
+	// Delete deletes the ReceiverRecord associated with the given
+	// organization and nym. Deleting a record which does not exist
+	// is not an error.
+	Delete(string, *big.Int) error
+
+	// Exists returns true if a ReceiverRecord is associated with
+	// the given organization and nym.
+	Exists(string, *big.Int) (bool, error)
+
+	// List returns the nyms of all stored ReceiverRecords of the
+	// organization.
+	List(string) ([]*big.Int, error)
+}
+
+// EscrowRecordManager manages the records of the attributes escrowed in credential
+// proofs (see EscrowRecord).
+type EscrowRecordManager interface {
+	// StoreEscrowRecord stores the record under its ID (see EscrowRecord.GetID),
+	// returning error in case the data was not successfully stored.
+	StoreEscrowRecord(*EscrowRecord) error
+
+	// LoadEscrowRecord loads the record with the given ID, returning an error
+	// in case no record was found.
+	LoadEscrowRecord(string) (*EscrowRecord, error)
+}
+
+// PseudonymRecordManager records the domain pseudonyms presented in credential proofs
+// (see DomainPseudonymProof), so that a credential can be shown only once for a scope.
+type PseudonymRecordManager interface {
+	// ClaimPseudonym records the pseudonym for the scope. It returns false (and records
+	// nothing) when the pseudonym has already been recorded for the scope. The check and
+	// the recording are done atomically, so that a pseudonym cannot be claimed twice by
+	// concurrent requests.
+	ClaimPseudonym(scope []byte, pseudonym *big.Int) (bool, error)
+}
+
+// AccumulatorRecordManager stores the updates of the revocation accumulators (per ID of
+// the key of the organization), which determine the state of an accumulator - its value
+// and the revoked credentials (see Org.LoadAccumulator).
+type AccumulatorRecordManager interface {
+	// StoreAccumulatorUpdate appends the update to the stored updates of the accumulator.
+	// It returns an error when the update does not follow the last stored update (its
+	// epoch is not one more than the number of stored updates), so that concurrent
+	// revocations cannot overwrite each other.
+	StoreAccumulatorUpdate(keyID string, u *AccumulatorUpdate) error
+
+	// LoadAccumulatorUpdates loads all stored updates of the accumulator, ordered by
+	// their epochs (no updates are stored when no credential has been revoked).
+	LoadAccumulatorUpdates(keyID string) ([]*AccumulatorUpdate, error)
+}
+
+// pseudonymKey returns the key under which the pseudonym for the scope is recorded.
+func pseudonymKey(scope []byte, pseudonym *big.Int) string {
+	return fmt.Sprintf("%x:%x", scope, pseudonym.Bytes())
 }
 
 // RedisClient wraps a redis client in order to interact with the
@@ -53,37 +113,201 @@ func NewRedisClient(c *redis.Client) *RedisClient {
 	}
 }
 
-func (m *RedisClient) Store(nym *big.Int, r *ReceiverRecord) error {
-	return m.Set(nym.String(), r, 0).Err()
+// recordKey returns the key of the redis entry holding the receiver record of the nym
+// of the organization orgName.
+func recordKey(orgName string, nym *big.Int) string {
+	return fmt.Sprintf("record:%s:%s", orgName, nym)
 }
 
-func (m *RedisClient) Load(nym *big.Int) (*ReceiverRecord, error) {
-	r, err := m.Get(nym.String()).Result()
+// recordsKey returns the key of the redis set holding the nyms of all receiver records
+// of the organization orgName.
+func recordsKey(orgName string) string {
+	return "records:" + orgName
+}
+
+func (m *RedisClient) Store(orgName string, nym *big.Int, r *ReceiverRecord) error {
+	_, err := m.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Set(recordKey(orgName, nym), r, 0)
+		pipe.SAdd(recordsKey(orgName), nym.String())
+		return nil
+	})
+
+	return err
+}
+
+func (m *RedisClient) Load(orgName string, nym *big.Int) (*ReceiverRecord, error) {
+	r, err := m.Get(recordKey(orgName, nym)).Result()
 	if err != nil {
 		return nil, err
 	}
 	var rec ReceiverRecord
-	rec.UnmarshalBinary([]byte(r))
+	if err := rec.UnmarshalBinary([]byte(r)); err != nil {
+		return nil, err
+	}
 
 	return &rec, nil
 }
 
-// MockRecordManager is a mock implementation of the ReceiverRecordManager
-// interface. It stores key-value pairs of nyms and corresponding
-// receiver records in a map.
+func (m *RedisClient) Delete(orgName string, nym *big.Int) error {
+	_, err := m.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(recordKey(orgName, nym))
+		pipe.SRem(recordsKey(orgName), nym.String())
+		return nil
+	})
+
+	return err
+}
+
+func (m *RedisClient) Exists(orgName string, nym *big.Int) (bool, error) {
+	n, err := m.Client.Exists(recordKey(orgName, nym)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+func (m *RedisClient) List(orgName string) ([]*big.Int, error) {
+	members, err := m.SMembers(recordsKey(orgName)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNyms(members)
+}
+
+// parseNyms parses the nyms stored as decimal strings.
+func parseNyms(s []string) ([]*big.Int, error) {
+	nyms := make([]*big.Int, len(s))
+	for i, v := range s {
+		nym, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid nym %s", v)
+		}
+		nyms[i] = nym
+	}
+
+	return nyms, nil
+}
+
+func (m *RedisClient) StoreEscrowRecord(r *EscrowRecord) error {
+	return m.Set("escrow:"+r.GetID(), r, 0).Err()
+}
+
+func (m *RedisClient) LoadEscrowRecord(id string) (*EscrowRecord, error) {
+	r, err := m.Get("escrow:" + id).Result()
+	if err != nil {
+		return nil, err
+	}
+	var rec EscrowRecord
+	if err := rec.UnmarshalBinary([]byte(r)); err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+func (m *RedisClient) ClaimPseudonym(scope []byte, pseudonym *big.Int) (bool, error) {
+	return m.SetNX("pseudonym:"+pseudonymKey(scope, pseudonym), 1, 0).Result()
+}
+
+// accumulatorKey returns the key of the redis list holding the updates of the accumulator.
+func accumulatorKey(keyID string) string {
+	return "accumulator:" + keyID
+}
+
+func (m *RedisClient) StoreAccumulatorUpdate(keyID string, u *AccumulatorUpdate) error {
+	key := accumulatorKey(keyID)
+	return m.Watch(func(tx *redis.Tx) error {
+		n, err := tx.LLen(key).Result()
+		if err != nil {
+			return err
+		}
+		if int64(u.Epoch) != n+1 {
+			return fmt.Errorf("update for epoch %d does not follow the %d stored updates",
+				u.Epoch, n)
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.RPush(key, u)
+			return nil
+		})
+		return err
+	}, key)
+}
+
+func (m *RedisClient) LoadAccumulatorUpdates(keyID string) ([]*AccumulatorUpdate, error) {
+	data, err := m.LRange(accumulatorKey(keyID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]*AccumulatorUpdate, len(data))
+	for i, d := range data {
+		updates[i] = new(AccumulatorUpdate)
+		if err := updates[i].UnmarshalBinary([]byte(d)); err != nil {
+			return nil, err
+		}
+	}
+
+	return updates, nil
+}
+
+// nonceKey returns the key of the redis entry holding the nonce issued for the purpose.
+func nonceKey(purpose NoncePurpose, nonce *big.Int) string {
+	return "nonce:" + nonceID(purpose, nonce)
+}
+
+// StoreNonce stores the nonce, which is removed by redis when it expires.
+func (m *RedisClient) StoreNonce(purpose NoncePurpose, nonce *big.Int, ttl time.Duration) error {
+	return m.Set(nonceKey(purpose, nonce), 1, ttl).Err()
+}
+
+func (m *RedisClient) UseNonce(purpose NoncePurpose, nonce *big.Int) (bool, error) {
+	if nonce == nil {
+		return false, nil
+	}
+	resp := m.Del(nonceKey(purpose, nonce))
+	if err := resp.Err(); err != nil {
+		return false, err
+	}
+
+	return resp.Val() == 1, nil // the nonce was present if one entry was deleted
+}
+
+// MockRecordManager is a mock implementation of the ReceiverRecordManager,
+// EscrowRecordManager, PseudonymRecordManager and AccumulatorRecordManager
+// interfaces. It stores key-value pairs of nyms (with the names of organizations) and
+// corresponding receiver records (and escrow records, pseudonyms and accumulator updates)
+// in a map.
 type MockRecordManager struct {
-	data map[string]ReceiverRecord
+	data         map[mockRecordID]ReceiverRecord
+	escrows      map[string]EscrowRecord
+	pseudonyms   map[string]bool
+	accumulators map[string][]AccumulatorUpdate
+	mutex        sync.RWMutex
 }
 
-// NewMockRecordManager initializes the map that will hold the data.
+// NewMockRecordManager initializes the maps that will hold the data.
 func NewMockRecordManager() *MockRecordManager {
 	return &MockRecordManager{
-		data: make(map[string]ReceiverRecord),
+		data:         make(map[mockRecordID]ReceiverRecord),
+		escrows:      make(map[string]EscrowRecord),
+		pseudonyms:   make(map[string]bool),
+		accumulators: make(map[string][]AccumulatorUpdate),
 	}
 }
 
-func (rm *MockRecordManager) Load(nym *big.Int) (*ReceiverRecord, error) {
-	r, present := rm.data[nym.String()]
+// mockRecordID identifies a receiver record in MockRecordManager.
+type mockRecordID struct {
+	orgName string
+	nym     string
+}
+
+func (rm *MockRecordManager) Load(orgName string, nym *big.Int) (*ReceiverRecord, error) {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	r, present := rm.data[mockRecordID{orgName, nym.String()}]
 	if !present {
 		return nil, fmt.Errorf("record does not exist")
 	}
@@ -91,7 +315,99 @@ func (rm *MockRecordManager) Load(nym *big.Int) (*ReceiverRecord, error) {
 	return &r, nil
 }
 
-func (rm *MockRecordManager) Store(nym *big.Int, r *ReceiverRecord) error {
-	rm.data[nym.String()] = *r
+func (rm *MockRecordManager) Store(orgName string, nym *big.Int, r *ReceiverRecord) error {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	rm.data[mockRecordID{orgName, nym.String()}] = *r
+	return nil
+}
+
+func (rm *MockRecordManager) Delete(orgName string, nym *big.Int) error {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	delete(rm.data, mockRecordID{orgName, nym.String()})
+	return nil
+}
+
+func (rm *MockRecordManager) Exists(orgName string, nym *big.Int) (bool, error) {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	_, present := rm.data[mockRecordID{orgName, nym.String()}]
+	return present, nil
+}
+
+func (rm *MockRecordManager) List(orgName string) ([]*big.Int, error) {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	var nyms []string
+	for id := range rm.data {
+		if id.orgName == orgName {
+			nyms = append(nyms, id.nym)
+		}
+	}
+
+	return parseNyms(nyms)
+}
+
+func (rm *MockRecordManager) StoreEscrowRecord(r *EscrowRecord) error {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	rm.escrows[r.GetID()] = *r
+	return nil
+}
+
+func (rm *MockRecordManager) LoadEscrowRecord(id string) (*EscrowRecord, error) {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	r, present := rm.escrows[id]
+	if !present {
+		return nil, fmt.Errorf("escrow record does not exist")
+	}
+
+	return &r, nil
+}
+
+func (rm *MockRecordManager) ClaimPseudonym(scope []byte, pseudonym *big.Int) (bool, error) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	key := pseudonymKey(scope, pseudonym)
+	if rm.pseudonyms[key] {
+		return false, nil
+	}
+	rm.pseudonyms[key] = true
+
+	return true, nil
+}
+
+func (rm *MockRecordManager) StoreAccumulatorUpdate(keyID string, u *AccumulatorUpdate) error {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	n := len(rm.accumulators[keyID])
+	if u.Epoch != n+1 {
+		return fmt.Errorf("update for epoch %d does not follow the %d stored updates", u.Epoch, n)
+	}
+	rm.accumulators[keyID] = append(rm.accumulators[keyID], *u)
+
 	return nil
 }
+
+func (rm *MockRecordManager) LoadAccumulatorUpdates(keyID string) ([]*AccumulatorUpdate, error) {
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
+	updates := make([]*AccumulatorUpdate, len(rm.accumulators[keyID]))
+	for i := range rm.accumulators[keyID] {
+		u := rm.accumulators[keyID][i]
+		updates[i] = &u
+	}
+
+	return updates, nil
+}
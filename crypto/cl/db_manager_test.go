@@ -0,0 +1,212 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package cl
+
+import (
+	"bytes"
+	"database/sql"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+type testRecordManager interface {
+	ReceiverRecordManager
+	EscrowRecordManager
+	PseudonymRecordManager
+	AccumulatorRecordManager
+}
+
+// testRecords checks the record manager m, which needs to be empty.
+func testRecords(t *testing.T, m testRecordManager) {
+	nym1, nym2 := big.NewInt(1234567890), big.NewInt(987654321)
+	rec := NewReceiverRecord([]*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(3)},
+		big.NewInt(4), big.NewInt(5), big.NewInt(6), big.NewInt(7))
+
+	_, err := m.Load("org1", nym1)
+	assert.Error(t, err, "record which was not stored should not be loaded")
+	exists, err := m.Exists("org1", nym1)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, m.Store("org1", nym1, rec))
+	loaded, err := m.Load("org1", nym1)
+	require.NoError(t, err)
+	assert.Equal(t, rec, loaded)
+
+	rec.V11 = big.NewInt(8)
+	require.NoError(t, m.Store("org1", nym1, rec))
+	loaded, err = m.Load("org1", nym1)
+	require.NoError(t, err)
+	assert.Equal(t, rec.V11, loaded.V11, "record should be replaced")
+
+	// the same nym can be used with another organization
+	_, err = m.Load("org2", nym1)
+	assert.Error(t, err, "record of another organization should not be loaded")
+	otherRec := NewReceiverRecord([]*big.Int{big.NewInt(11)}, nil, big.NewInt(12),
+		big.NewInt(13), big.NewInt(14), big.NewInt(15))
+	require.NoError(t, m.Store("org2", nym1, otherRec))
+	loaded, err = m.Load("org1", nym1)
+	require.NoError(t, err)
+	assert.Equal(t, rec.V11, loaded.V11, "record of another organization should not be replaced")
+	loaded, err = m.Load("org2", nym1)
+	require.NoError(t, err)
+	assert.Equal(t, otherRec.V11, loaded.V11)
+
+	// concurrent stores
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, m.Store("org1", nym2, rec))
+		}()
+	}
+	wg.Wait()
+
+	nyms, err := m.List("org1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*big.Int{nym1, nym2}, nyms)
+	nyms, err = m.List("org3")
+	require.NoError(t, err)
+	assert.Empty(t, nyms)
+
+	require.NoError(t, m.Delete("org1", nym1))
+	require.NoError(t, m.Delete("org1", nym1), "deleting a deleted record should not fail")
+	require.NoError(t, m.Delete("org3", nym1),
+		"deleting a record of unknown organization should not fail")
+	exists, err = m.Exists("org1", nym1)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = m.Exists("org1", nym2)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = m.Exists("org2", nym1)
+	require.NoError(t, err)
+	assert.True(t, exists, "record of another organization should not be deleted")
+	nyms, err = m.List("org1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*big.Int{nym2}, nyms)
+
+	escrow := &EscrowRecord{KeyID: "key", AttrIndex: 1, Label: []byte("label"), U: big.NewInt(1),
+		E: big.NewInt(2), V: big.NewInt(3)}
+	require.NoError(t, m.StoreEscrowRecord(escrow))
+	loadedEscrow, err := m.LoadEscrowRecord(escrow.GetID())
+	require.NoError(t, err)
+	assert.Equal(t, escrow, loadedEscrow)
+	_, err = m.LoadEscrowRecord("unknown")
+	assert.Error(t, err, "escrow record which was not stored should not be loaded")
+
+	// a pseudonym can be claimed only once for a scope, also by concurrent claims
+	pseudonym := big.NewInt(12345)
+	claims := make(chan bool, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimed, err := m.ClaimPseudonym([]byte("scope"), pseudonym)
+			assert.NoError(t, err)
+			claims <- claimed
+		}()
+	}
+	wg.Wait()
+	close(claims)
+	n := 0
+	for claimed := range claims {
+		if claimed {
+			n++
+		}
+	}
+	assert.Equal(t, 1, n, "pseudonym should be claimed exactly once")
+	claimed, err := m.ClaimPseudonym([]byte("other scope"), pseudonym)
+	require.NoError(t, err)
+	assert.True(t, claimed, "pseudonym should be claimed for a different scope")
+
+	// accumulator updates are stored only in the order of their epochs
+	updates, err := m.LoadAccumulatorUpdates("key")
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+	u1 := NewAccumulatorUpdate(1, big.NewInt(11), big.NewInt(12))
+	u2 := NewAccumulatorUpdate(2, big.NewInt(21), big.NewInt(22))
+	assert.Error(t, m.StoreAccumulatorUpdate("key", u2), "epoch 1 should be stored first")
+	require.NoError(t, m.StoreAccumulatorUpdate("key", u1))
+	assert.Error(t, m.StoreAccumulatorUpdate("key", u1), "epoch should not be stored twice")
+	require.NoError(t, m.StoreAccumulatorUpdate("key", u2))
+	require.NoError(t, m.StoreAccumulatorUpdate("other key", u1))
+	updates, err = m.LoadAccumulatorUpdates("key")
+	require.NoError(t, err)
+	assert.Equal(t, []*AccumulatorUpdate{u1, u2}, updates)
+}
+
+func TestMockRecordManager(t *testing.T) {
+	testRecords(t, NewMockRecordManager())
+}
+
+func TestBoltRecordManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "records")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := bolt.Open(filepath.Join(dir, "records.db"), 0600, nil)
+	require.NoError(t, err)
+	defer db.Close()
+	m, err := NewBoltRecordManager(db)
+	require.NoError(t, err)
+	testRecords(t, m)
+
+	// the backup contains the records
+	var backup bytes.Buffer
+	_, err = m.Backup(&backup)
+	require.NoError(t, err)
+	backupPath := filepath.Join(dir, "backup.db")
+	require.NoError(t, ioutil.WriteFile(backupPath, backup.Bytes(), 0600))
+	backupDB, err := bolt.Open(backupPath, 0600, nil)
+	require.NoError(t, err)
+	defer backupDB.Close()
+	m, err = NewBoltRecordManager(backupDB)
+	require.NoError(t, err)
+	nyms, err := m.List("org1")
+	require.NoError(t, err)
+	assert.Len(t, nyms, 1)
+}
+
+func TestSQLRecordManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "records")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "records.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	// SQLite allows only one writer at a time
+	db.SetMaxOpenConns(1)
+	m, err := NewSQLRecordManager(db)
+	require.NoError(t, err)
+	testRecords(t, m)
+
+	// the tables are not created again
+	_, err = NewSQLRecordManager(db)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package cl
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+)
+
+// sqlRecordTables are the statements creating the tables for the records. The statements
+// used by SQLRecordManager work with PostgreSQL and SQLite.
+var sqlRecordTables = []string{
+	`CREATE TABLE IF NOT EXISTS cl_records (
+		org_name TEXT NOT NULL,
+		nym TEXT NOT NULL,
+		record BYTEA NOT NULL,
+		PRIMARY KEY (org_name, nym)
+	)`,
+	`CREATE TABLE IF NOT EXISTS cl_escrow_records (
+		id TEXT PRIMARY KEY,
+		record BYTEA NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS cl_pseudonyms (
+		id TEXT PRIMARY KEY
+	)`,
+	`CREATE TABLE IF NOT EXISTS cl_accumulator_updates (
+		key_id TEXT NOT NULL,
+		epoch INTEGER NOT NULL,
+		record BYTEA NOT NULL,
+		PRIMARY KEY (key_id, epoch)
+	)`,
+}
+
+// SQLRecordManager stores receiver records, escrow records, domain pseudonyms and
+// accumulator updates in an SQL database accessed through database/sql. It is safe
+// for concurrent use.
+type SQLRecordManager struct {
+	db *sql.DB
+}
+
+// NewSQLRecordManager creates the tables for the records in db (if they do
+// not exist yet) and returns an instance of SQLRecordManager.
+func NewSQLRecordManager(db *sql.DB) (*SQLRecordManager, error) {
+	for _, stmt := range sqlRecordTables {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("error when creating tables for records: %v", err)
+		}
+	}
+
+	return &SQLRecordManager{
+		db: db,
+	}, nil
+}
+
+func (m *SQLRecordManager) Store(orgName string, nym *big.Int, r *ReceiverRecord) error {
+	data, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	_, err = m.db.Exec(`INSERT INTO cl_records (org_name, nym, record) VALUES ($1, $2, $3)
+		ON CONFLICT (org_name, nym) DO UPDATE SET record = excluded.record`,
+		orgName, nym.String(), data)
+
+	return err
+}
+
+func (m *SQLRecordManager) Load(orgName string, nym *big.Int) (*ReceiverRecord, error) {
+	var data []byte
+	err := m.db.QueryRow(`SELECT record FROM cl_records WHERE org_name = $1 AND nym = $2`,
+		orgName, nym.String()).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("record does not exist")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec ReceiverRecord
+	if err := rec.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+func (m *SQLRecordManager) Delete(orgName string, nym *big.Int) error {
+	_, err := m.db.Exec(`DELETE FROM cl_records WHERE org_name = $1 AND nym = $2`,
+		orgName, nym.String())
+	return err
+}
+
+func (m *SQLRecordManager) Exists(orgName string, nym *big.Int) (bool, error) {
+	var n int
+	err := m.db.QueryRow(`SELECT COUNT(*) FROM cl_records WHERE org_name = $1 AND nym = $2`,
+		orgName, nym.String()).Scan(&n)
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+func (m *SQLRecordManager) List(orgName string) ([]*big.Int, error) {
+	rows, err := m.db.Query(`SELECT nym FROM cl_records WHERE org_name = $1`, orgName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nyms []string
+	for rows.Next() {
+		var nym string
+		if err := rows.Scan(&nym); err != nil {
+			return nil, err
+		}
+		nyms = append(nyms, nym)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return parseNyms(nyms)
+}
+
+func (m *SQLRecordManager) StoreEscrowRecord(r *EscrowRecord) error {
+	data, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	_, err = m.db.Exec(`INSERT INTO cl_escrow_records (id, record) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET record = excluded.record`, r.GetID(), data)
+
+	return err
+}
+
+func (m *SQLRecordManager) LoadEscrowRecord(id string) (*EscrowRecord, error) {
+	var data []byte
+	err := m.db.QueryRow(`SELECT record FROM cl_escrow_records WHERE id = $1`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("escrow record does not exist")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec EscrowRecord
+	if err := rec.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+func (m *SQLRecordManager) ClaimPseudonym(scope []byte, pseudonym *big.Int) (bool, error) {
+	res, err := m.db.Exec(`INSERT INTO cl_pseudonyms (id) VALUES ($1) ON CONFLICT (id) DO NOTHING`,
+		pseudonymKey(scope, pseudonym))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil // the pseudonym was not recorded yet if a row was inserted
+}
+
+func (m *SQLRecordManager) StoreAccumulatorUpdate(keyID string, u *AccumulatorUpdate) error {
+	data, err := u.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	// the update is inserted only when it follows the stored updates, concurrent inserts
+	// of the same epoch violate the primary key
+	res, err := m.db.Exec(`INSERT INTO cl_accumulator_updates (key_id, epoch, record)
+		SELECT $1, $2, $3 WHERE (SELECT COUNT(*) FROM cl_accumulator_updates
+			WHERE key_id = $1) = $4`, keyID, u.Epoch, data, u.Epoch-1)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return fmt.Errorf("update for epoch %d does not follow the stored updates", u.Epoch)
+	}
+
+	return nil
+}
+
+func (m *SQLRecordManager) LoadAccumulatorUpdates(keyID string) ([]*AccumulatorUpdate, error) {
+	rows, err := m.db.Query(`SELECT record FROM cl_accumulator_updates WHERE key_id = $1
+		ORDER BY epoch`, keyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updates := []*AccumulatorUpdate{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		u := new(AccumulatorUpdate)
+		if err := u.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		updates = append(updates, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/schnorr"
+)
+
+// DomainPseudonymProof holds a domain pseudonym Pseudonym = G_scope^ms, where ms is the
+// master secret (the first hidden attribute of the credential) and G_scope is an element
+// of the Pedersen group (PubKey.PedersenParams.Group) derived from Scope. The same
+// credential always produces the same pseudonym for the same scope, while pseudonyms for
+// different scopes cannot be linked.
+//
+// ProofRandomData proves that the discrete logarithm of Pseudonym is the master secret
+// used in the credential proof (the response for the master secret is taken from
+// the credential proof).
+type DomainPseudonymProof struct {
+	Scope           []byte
+	Pseudonym       *big.Int
+	ProofRandomData *big.Int
+}
+
+// challengeData returns all values of the proof that need to be included in
+// the computation of the challenge.
+func (p *DomainPseudonymProof) challengeData() []*big.Int {
+	return []*big.Int{new(big.Int).SetBytes(p.Scope), p.Pseudonym, p.ProofRandomData}
+}
+
+// getDomainBase maps scope to an element of the group (the discrete logarithm of
+// the element with respect to group.G is not known).
+func getDomainBase(group *schnorr.Group, scope []byte) *big.Int {
+	cofactor := new(big.Int).Sub(group.P, big.NewInt(1))
+	cofactor.Div(cofactor, group.Q)
+	one := big.NewInt(1)
+	for counter := byte(0); ; counter++ {
+		h := sha512.Sum512(append([]byte{counter}, scope...))
+		x := new(big.Int).SetBytes(h[:])
+		x.Mod(x, group.P)
+		base := group.Exp(x, cofactor)
+		if base.Cmp(one) != 0 && base.Sign() != 0 {
+			return base
+		}
+	}
+}
+
+type domainPseudonymProver struct {
+	group *schnorr.Group
+	base  *big.Int
+	proof *DomainPseudonymProof
+}
+
+func newDomainPseudonymProver(pubKey *PubKey, scope []byte,
+	masterSecret *big.Int) (*domainPseudonymProver, error) {
+	if len(scope) == 0 {
+		return nil, fmt.Errorf("scope of the domain pseudonym is empty")
+	}
+	group := pubKey.PedersenParams.Group
+	base := getDomainBase(group, scope)
+
+	return &domainPseudonymProver{
+		group: group,
+		base:  base,
+		proof: &DomainPseudonymProof{
+			Scope:     scope,
+			Pseudonym: group.Exp(base, masterSecret),
+		},
+	}, nil
+}
+
+// getProofRandomData returns the data which is included in the computation of the challenge.
+// Parameter r needs to be the random value used for the master secret in the credential proof.
+func (p *domainPseudonymProver) getProofRandomData(r *big.Int) []*big.Int {
+	p.proof.ProofRandomData = p.group.Exp(p.base, r)
+
+	return p.proof.challengeData()
+}
+
+func (p *domainPseudonymProver) getProof() *DomainPseudonymProof {
+	return p.proof
+}
+
+// verifyDomainPseudonymProof verifies the proof given the challenge and the response for
+// the master secret from the credential proof.
+func verifyDomainPseudonymProof(pubKey *PubKey, p *DomainPseudonymProof, challenge,
+	sM *big.Int) bool {
+	group := pubKey.PedersenParams.Group
+	if len(p.Scope) == 0 || p.Pseudonym == nil || p.ProofRandomData == nil ||
+		!group.IsElementInGroup(p.Pseudonym) {
+		return false
+	}
+	base := getDomainBase(group, p.Scope)
+
+	// base^sM = ProofRandomData * Pseudonym^challenge
+	left := group.Exp(base, sM)
+	right := group.Mul(p.ProofRandomData, group.Exp(p.Pseudonym, challenge))
+
+	return left.Cmp(right) == 0
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainPseudonym(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 1)
+
+	org := newTestOrg(t, attrCount, 0)
+
+	issue := func(org *Org, masterSecret *big.Int, name string) (*CredManager, *Cred) {
+		credMgr, res := issueTestCred(t, org, newTestRawCred(t, attrCount, name), masterSecret)
+		return credMgr, res.Cred
+	}
+
+	revealed := []int{0}
+	prove := func(credMgr *CredManager, cred *Cred, scope, verifierScope []byte) (bool, *big.Int, error) {
+		nonce, err := org.GetProveCredNonce()
+		require.NoError(t, err)
+		proof, err := credMgr.BuildProof(cred, revealed, []int{}, &ProofOptions{Scope: scope}, nonce)
+		require.NoError(t, err)
+
+		return org.ProveCred(proof, &VerificationPolicy{Scope: verifierScope}, nonce)
+	}
+
+	masterSecret := org.Keys.Pub.GenerateUserMasterSecret()
+	credMgr1, cred1 := issue(org, masterSecret, "Jack")
+	credMgr2, cred2 := issue(org, masterSecret, "John")
+	credMgr3, cred3 := issue(org, org.Keys.Pub.GenerateUserMasterSecret(), "Jack")
+
+	scope := []byte("e-voting 2018")
+	verified, nym1, err := prove(credMgr1, cred1, scope, scope)
+	require.NoError(t, err)
+	assert.True(t, verified, "domain pseudonym proof not accepted")
+	require.NotNil(t, nym1)
+
+	// the pseudonym is the same each time the credential is shown for the same scope and
+	// for all credentials with the same master secret
+	verified, nym, err := prove(credMgr1, cred1, scope, scope)
+	require.NoError(t, err)
+	assert.True(t, verified, "domain pseudonym proof not accepted")
+	assert.Equal(t, nym1, nym, "pseudonyms for the same scope differ")
+
+	verified, nym, err = prove(credMgr2, cred2, scope, scope)
+	require.NoError(t, err)
+	assert.True(t, verified, "domain pseudonym proof not accepted")
+	assert.Equal(t, nym1, nym, "pseudonyms for the same master secret differ")
+
+	// pseudonyms differ for different scopes and different master secrets
+	otherScope := []byte("e-voting 2019")
+	verified, nym, err = prove(credMgr1, cred1, otherScope, otherScope)
+	require.NoError(t, err)
+	assert.True(t, verified, "domain pseudonym proof not accepted")
+	assert.NotEqual(t, nym1, nym, "pseudonyms for different scopes are the same")
+
+	verified, nym, err = prove(credMgr3, cred3, scope, scope)
+	require.NoError(t, err)
+	assert.True(t, verified, "domain pseudonym proof not accepted")
+	assert.NotEqual(t, nym1, nym, "pseudonyms for different master secrets are the same")
+
+	// the pseudonym needs to be for the scope required by the verifier
+	_, _, err = prove(credMgr1, cred1, otherScope, scope)
+	assert.Error(t, err, "domain pseudonym for a different scope should not be accepted")
+	_, _, err = prove(credMgr1, cred1, nil, scope)
+	assert.Error(t, err, "missing domain pseudonym should not be accepted")
+
+	// the pseudonym cannot be replaced
+	nonce, err := org.GetProveCredNonce()
+	require.NoError(t, err)
+	proof, err := credMgr1.BuildProof(cred1, revealed, []int{}, &ProofOptions{Scope: scope}, nonce)
+	require.NoError(t, err)
+	proof.DomainPseudonymProof.Pseudonym = nym
+	verified, _, _ = org.ProveCred(proof, &VerificationPolicy{Scope: scope}, nonce)
+	assert.False(t, verified, "replaced domain pseudonym should not be accepted")
+
+	// a credential without the master secret cannot produce a domain pseudonym
+	org4 := newTestOrg(t, NewAttrCount(5, 1, 0), 0)
+	credMgr4, cred4 := issue(org4, masterSecret, "Jack")
+	_, err = credMgr4.BuildProof(cred4, revealed, []int{}, &ProofOptions{Scope: scope},
+		org4.GenNonce())
+	assert.Error(t, err, "domain pseudonym without master secret should not be built")
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+// Types of the values in the canonical encoding.
+const (
+	ParamsEncodingType              = "cl-params"
+	PubKeyEncodingType              = "cl-public-key"
+	CredEncodingType                = "cl-credential"
+	RepresentationProofEncodingType = "qr-representation-proof"
+	CredRequestEncodingType         = "cl-credential-request"
+	ReceiverRecordEncodingType      = "cl-receiver-record"
+	CredManagerEncodingType         = "cl-credential-manager"
+)
+
+// encodingType returns the type of v in the canonical encoding.
+func encodingType(v interface{}) (string, error) {
+	switch v.(type) {
+	case *Params:
+		return ParamsEncodingType, nil
+	case *PubKey:
+		return PubKeyEncodingType, nil
+	case *Cred:
+		return CredEncodingType, nil
+	case *qr.RepresentationProof:
+		return RepresentationProofEncodingType, nil
+	case *CredRequest:
+		return CredRequestEncodingType, nil
+	case *ReceiverRecord:
+		return ReceiverRecordEncodingType, nil
+	case *CredManager:
+		return CredManagerEncodingType, nil
+	}
+
+	return "", fmt.Errorf("canonical encoding of %T is not supported", v)
+}
+
+// EncodeJSON returns the canonical, versioned JSON encoding of v (big integers are encoded
+// as decimal strings, see common.MarshalCanonicalJSON), which can be read by clients in
+// other languages. v is one of *Params, *PubKey, *Cred, *qr.RepresentationProof,
+// *CredRequest, *ReceiverRecord or *CredManager. Note that the encoding of CredManager
+// holds the secrets of the credential holder.
+func EncodeJSON(v interface{}) ([]byte, error) {
+	typ, err := encodingType(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.MarshalCanonicalJSON(typ, v)
+}
+
+// DecodeJSON decodes the value encoded by EncodeJSON into v, which needs to be of the same
+// type as the encoded value.
+func DecodeJSON(data []byte, v interface{}) error {
+	typ, err := encodingType(v)
+	if err != nil {
+		return err
+	}
+
+	return common.UnmarshalCanonicalJSON(data, typ, v)
+}
+
+// EncodeBinary returns the canonical encoding of v (see EncodeJSON) in the compact binary
+// form (see common.MarshalCanonicalBinary).
+func EncodeBinary(v interface{}) ([]byte, error) {
+	typ, err := encodingType(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.MarshalCanonicalBinary(typ, v)
+}
+
+// DecodeBinary decodes the value encoded by EncodeBinary into v, which needs to be of the
+// same type as the encoded value.
+func DecodeBinary(data []byte, v interface{}) error {
+	typ, err := encodingType(v)
+	if err != nil {
+		return err
+	}
+
+	return common.UnmarshalCanonicalBinary(data, typ, v)
+}
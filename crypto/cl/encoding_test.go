@@ -0,0 +1,126 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoding(t *testing.T) {
+	params := GetDefaultParamSizes()
+	attrCount := NewAttrCount(2, 1, 0)
+	org := newTestOrg(t, attrCount, 0)
+
+	rawCred := NewRawCred(attrCount)
+	_ = rawCred.AddStrAttr("Name", "Jack", true)
+	_ = rawCred.AddInt64Attr("Age", 25, true)
+	_ = rawCred.AddInt64Attr("Income", 1000, false)
+	credMgr, err := NewCredManager(params, org.Keys.Pub,
+		org.Keys.Pub.GenerateUserMasterSecret(), rawCred)
+	require.NoError(t, err)
+	nonce, err := org.GetCredIssueNonce()
+	require.NoError(t, err)
+	credReq, err := credMgr.GetCredRequest(nonce)
+	require.NoError(t, err)
+	res, err := org.IssueCred(credReq, nonce)
+	require.NoError(t, err)
+	require.NoError(t, credMgr.SetWitness(res.Cred, res.Witness))
+
+	// encode then decode gives back the same values, in both forms
+	for _, v := range []interface{}{params, res.Cred, res.AProof, credReq, res.Record} {
+		data, err := EncodeJSON(v)
+		require.NoError(t, err)
+		decoded := newEncodingValue(v)
+		require.NoError(t, DecodeJSON(data, decoded))
+		assert.Equal(t, v, decoded)
+
+		data, err = EncodeBinary(v)
+		require.NoError(t, err)
+		decoded = newEncodingValue(v)
+		require.NoError(t, DecodeBinary(data, decoded))
+		assert.Equal(t, v, decoded)
+	}
+
+	// the Pedersen trapdoor of the public key is not encoded
+	data, err := EncodeJSON(org.Keys.Pub)
+	require.NoError(t, err)
+	pubKey := new(PubKey)
+	require.NoError(t, DecodeJSON(data, pubKey))
+	assert.Equal(t, org.Keys.Pub.GetID(), pubKey.GetID())
+	assert.Equal(t, org.Keys.Pub.PedersenParams.Group, pubKey.PedersenParams.Group)
+	assert.Equal(t, org.Keys.Pub.Accumulator, pubKey.Accumulator)
+	assert.NoError(t, pubKey.VerifyProof(params))
+	data2, err := EncodeJSON(pubKey)
+	require.NoError(t, err)
+	assert.Equal(t, data, data2)
+
+	var c struct {
+		Type  string
+		Value map[string]interface{}
+	}
+	require.NoError(t, json.Unmarshal(data, &c))
+	assert.Equal(t, PubKeyEncodingType, c.Type)
+	assert.Equal(t, org.Keys.Pub.N.String(), c.Value["n"])
+
+	// the decoded credential manager proves the possession of the credential
+	for _, encode := range []func(interface{}) ([]byte, error){EncodeJSON, EncodeBinary} {
+		data, err := encode(credMgr)
+		require.NoError(t, err)
+		decoded := new(CredManager)
+		if data[0] == '{' {
+			err = DecodeJSON(data, decoded)
+		} else {
+			err = DecodeBinary(data, decoded)
+		}
+		require.NoError(t, err)
+		assert.Equal(t, credMgr.Nym, decoded.Nym)
+		assert.Equal(t, credMgr.Witness, decoded.Witness)
+		assert.Equal(t, rawCred.GetKnownVals(), decoded.RawCred.GetKnownVals())
+
+		context := []byte("encoding test")
+		p, err := decoded.BuildPresentation(res.Cred, context, []int{0}, []int{}, nil, nil)
+		require.NoError(t, err)
+		verified, err := VerifyPresentation(params, org.Keys.Pub, org.Accumulator, p, context)
+		require.NoError(t, err)
+		assert.True(t, verified, "presentation of the decoded credential manager not accepted")
+	}
+
+	// the records stored by earlier versions are in JSON
+	legacy, err := json.Marshal(res.Record)
+	require.NoError(t, err)
+	rec := new(ReceiverRecord)
+	require.NoError(t, rec.UnmarshalBinary(legacy))
+	assert.Equal(t, res.Record, rec)
+
+	data, err = EncodeBinary(res.Cred)
+	require.NoError(t, err)
+	assert.Error(t, DecodeBinary(data, new(CredRequest)),
+		"value of a different type should not be decoded")
+	_, err = EncodeJSON(org)
+	assert.Error(t, err, "encoding of an unsupported type should fail")
+}
+
+// newEncodingValue returns a pointer to a new zero value of the type v points to.
+func newEncodingValue(v interface{}) interface{} {
+	return reflect.New(reflect.TypeOf(v).Elem()).Interface()
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/encryption"
+)
+
+// Escrow states that a known attribute, which is not revealed to the verifier, needs to be
+// encrypted under the public key of an inspector (Camenisch-Shoup verifiable encryption).
+// The inspector can decrypt the attribute (for example to de-anonymize the holder under
+// a court order), while the verifier learns nothing about it. AttrIndex is the index of the
+// attribute among known attributes, Label describes the conditions under which the inspector
+// is allowed to decrypt the attribute (it is bound to the ciphertext).
+type Escrow struct {
+	AttrIndex int
+	PubKey    *encryption.CSPaillierPubKey
+	Label     []byte
+}
+
+func NewEscrow(attrIndex int, pubKey *encryption.CSPaillierPubKey, label []byte) *Escrow {
+	return &Escrow{
+		AttrIndex: attrIndex,
+		PubKey:    pubKey,
+		Label:     label,
+	}
+}
+
+// equals returns true if e requires the same attribute to be encrypted under the same key
+// and with the same label as escrow.
+func (e *Escrow) equals(escrow *Escrow) bool {
+	return e.AttrIndex == escrow.AttrIndex && e.PubKey != nil && escrow.PubKey != nil &&
+		e.PubKey.GetID() == escrow.PubKey.GetID() && bytes.Equal(e.Label, escrow.Label)
+}
+
+// EscrowProof holds the Camenisch-Shoup ciphertext (U, E, V) of the attribute and proves
+// that it encrypts the same value as used in the credential proof. For the randomness r of
+// the encryption and the attribute m it holds:
+//
+//	U = g^r, E = y1^r * h^m, V = abs((y2 * y3^hash(U, E, Label))^r)
+//
+// ProofRandomData holds g^(2*r1), y1^(2*r1) * h^(2*m1) and (y2 * y3^hash(U, E, Label))^(2*r1),
+// where m1 is the random value used for the attribute in the credential proof. ProofData is
+// the response for r (the response for m is taken from the credential proof).
+type EscrowProof struct {
+	Escrow          *Escrow
+	U               *big.Int
+	E               *big.Int
+	V               *big.Int
+	ProofRandomData []*big.Int
+	ProofData       *big.Int
+}
+
+// isComplete returns true if none of the values of the proof (and of the public key of
+// the inspector) is missing.
+func (p *EscrowProof) isComplete() bool {
+	if p.Escrow == nil || p.Escrow.PubKey == nil || p.U == nil || p.E == nil || p.V == nil ||
+		len(p.ProofRandomData) != 3 || p.ProofData == nil {
+		return false
+	}
+	k := p.Escrow.PubKey
+	values := append([]*big.Int{k.N, k.G, k.Y1, k.Y2, k.Y3}, p.ProofRandomData...)
+	for _, v := range values {
+		if v == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// challengeData returns all values of the proof that need to be included in
+// the computation of the challenge.
+func (p *EscrowProof) challengeData() []*big.Int {
+	k := p.Escrow.PubKey
+	l := []*big.Int{big.NewInt(int64(p.Escrow.AttrIndex)), k.N, k.G, k.Y1, k.Y2, k.Y3,
+		new(big.Int).SetBytes(p.Escrow.Label), p.U, p.E, p.V}
+
+	return append(l, p.ProofRandomData...)
+}
+
+// escrowHashBase returns y2 * y3^hash(u, e, label) mod n^2.
+func escrowHashBase(pubKey *encryption.CSPaillierPubKey, u, e *big.Int, label []byte) *big.Int {
+	n2 := new(big.Int).Mul(pubKey.N, pubKey.N)
+	h := common.Hash(u, e, new(big.Int).SetBytes(label))
+	t := new(big.Int).Exp(pubKey.Y3, h, n2)
+	t.Mul(t, pubKey.Y2)
+
+	return t.Mod(t, n2)
+}
+
+type escrowProver struct {
+	params  *Params
+	r       *big.Int
+	randomR *big.Int
+	proof   *EscrowProof
+}
+
+func newEscrowProver(params *Params, escrow *Escrow, attr *big.Int) (*escrowProver, error) {
+	k := escrow.PubKey
+	if k == nil || k.N == nil || k.G == nil || k.Y1 == nil || k.Y2 == nil || k.Y3 == nil {
+		return nil, fmt.Errorf("public key of the inspector is not complete")
+	}
+
+	csp := encryption.NewCSPaillierFromPubKey(k)
+	r := common.GetRandomInt(new(big.Int).Div(k.N, big.NewInt(4)))
+	u, e, v, err := csp.EncryptWithGivenR(attr, r, new(big.Int).SetBytes(escrow.Label))
+	if err != nil {
+		return nil, fmt.Errorf("error when encrypting attribute %d: %v", escrow.AttrIndex, err)
+	}
+
+	return &escrowProver{
+		params: params,
+		r:      r,
+		proof: &EscrowProof{
+			Escrow: escrow,
+			U:      u,
+			E:      e,
+			V:      v,
+		},
+	}, nil
+}
+
+// getProofRandomData returns the values which need to be included in the computation
+// of the challenge. Random value rM needs to be the same as the random value used for
+// the attribute in the credential proof.
+func (p *escrowProver) getProofRandomData(rM *big.Int) []*big.Int {
+	k := p.proof.Escrow.PubKey
+	n2 := new(big.Int).Mul(k.N, k.N)
+	// r is from [0, n/4), randomR needs to hide challenge * r
+	b := k.N.BitLen() + p.params.SecParam + p.params.HashBitLen
+	p.randomR = common.GetRandomIntAlsoNeg(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(b)),
+		nil))
+	twoR := new(big.Int).Mul(big.NewInt(2), p.randomR)
+	twoM := new(big.Int).Mul(big.NewInt(2), rM)
+
+	u1 := common.Exponentiate(k.G, twoR, n2)
+	h := new(big.Int).Add(k.N, big.NewInt(1))
+	e1 := new(big.Int).Mul(common.Exponentiate(k.Y1, twoR, n2), common.Exponentiate(h, twoM, n2))
+	e1.Mod(e1, n2)
+	v1 := common.Exponentiate(escrowHashBase(k, p.proof.U, p.proof.E, p.proof.Escrow.Label),
+		twoR, n2)
+	p.proof.ProofRandomData = []*big.Int{u1, e1, v1}
+
+	return p.proof.challengeData()
+}
+
+func (p *escrowProver) getProof(challenge *big.Int) *EscrowProof {
+	s := new(big.Int).Mul(challenge, p.r)
+	p.proof.ProofData = s.Add(s, p.randomR)
+
+	return p.proof
+}
+
+// verifyEscrowProof verifies the escrow proof given the challenge and the response for
+// the attribute from the credential proof.
+func verifyEscrowProof(p *EscrowProof, challenge, sM *big.Int) bool {
+	if !p.isComplete() {
+		return false
+	}
+	k := p.Escrow.PubKey
+	n2 := new(big.Int).Mul(k.N, k.N)
+	for _, x := range []*big.Int{p.U, p.E, p.V} {
+		if x.Sign() <= 0 || x.Cmp(n2) >= 0 {
+			return false
+		}
+	}
+	// V needs to be equal to abs(V)
+	if p.V.Cmp(new(big.Int).Rsh(n2, 1)) > 0 {
+		return false
+	}
+
+	twoC := new(big.Int).Mul(big.NewInt(2), challenge)
+	twoS := new(big.Int).Mul(big.NewInt(2), p.ProofData)
+	mul := func(x, y *big.Int) *big.Int {
+		z := new(big.Int).Mul(x, y)
+		return z.Mod(z, n2)
+	}
+
+	// g^(2*s) = u1 * U^(2*c)
+	left := common.Exponentiate(k.G, twoS, n2)
+	right := mul(p.ProofRandomData[0], common.Exponentiate(p.U, twoC, n2))
+	if left.Cmp(right) != 0 {
+		return false
+	}
+
+	// y1^(2*s) * h^(2*sM) = e1 * E^(2*c)
+	h := new(big.Int).Add(k.N, big.NewInt(1))
+	left = mul(common.Exponentiate(k.Y1, twoS, n2),
+		common.Exponentiate(h, new(big.Int).Mul(big.NewInt(2), sM), n2))
+	right = mul(p.ProofRandomData[1], common.Exponentiate(p.E, twoC, n2))
+	if left.Cmp(right) != 0 {
+		return false
+	}
+
+	// (y2 * y3^hash(U, E, Label))^(2*s) = v1 * V^(2*c)
+	left = common.Exponentiate(escrowHashBase(k, p.U, p.E, p.Escrow.Label), twoS, n2)
+	right = mul(p.ProofRandomData[2], common.Exponentiate(p.V, twoC, n2))
+
+	return left.Cmp(right) == 0
+}
+
+// EscrowRecord is stored by the verifier for each accepted escrow proof. It holds
+// the ciphertext of the attribute, which can be decrypted only by the inspector.
+type EscrowRecord struct {
+	// KeyID is the ID of the public key of the inspector
+	KeyID     string
+	AttrIndex int
+	Label     []byte
+	U         *big.Int
+	E         *big.Int
+	V         *big.Int
+}
+
+// NewEscrowRecord returns the record of the ciphertext from the (verified) escrow proof.
+func NewEscrowRecord(p *EscrowProof) *EscrowRecord {
+	return &EscrowRecord{
+		KeyID:     p.Escrow.PubKey.GetID(),
+		AttrIndex: p.Escrow.AttrIndex,
+		Label:     p.Escrow.Label,
+		U:         p.U,
+		E:         p.E,
+		V:         p.V,
+	}
+}
+
+// GetID returns the identifier of the record (hex encoded SHA-256 hash of the ciphertext).
+func (r *EscrowRecord) GetID() string {
+	h := sha256.Sum256(common.ConcatenateNumbers(r.U, r.E, r.V))
+	return hex.EncodeToString(h[:])
+}
+
+// Decrypt decrypts the escrowed attribute (its internal value) with the secret key
+// of the inspector.
+func (r *EscrowRecord) Decrypt(secKey *encryption.CSPaillierSecKey) (*big.Int, error) {
+	csp, err := encryption.NewCSPaillierFromSecKey(secKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return csp.Decrypt(r.U, r.E, r.V, new(big.Int).SetBytes(r.Label))
+}
+
+func (r *EscrowRecord) MarshalBinary() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *EscrowRecord) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, r)
+}
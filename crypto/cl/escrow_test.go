@@ -0,0 +1,166 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xlab-si/emmy/crypto/encryption"
+)
+
+func TestEscrow(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 0)
+
+	org := newTestOrg(t, attrCount, 0)
+
+	credMgr, res := issueTestCred(t, org, newTestRawCred(t, attrCount, "Jack"), nil)
+
+	inspector := encryption.NewCSPaillier(&encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	})
+	other := encryption.NewCSPaillier(&encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	})
+
+	records := NewMockRecordManager()
+	org.EscrowRecords = records
+
+	// the name is escrowed, while only the gender is revealed
+	revealed := []int{1}
+	escrow := NewEscrow(0, inspector.PubKey, []byte("court order"))
+	prove := func(userEscrow, verifierEscrow *Escrow,
+		modify func(*EscrowProof)) (*EscrowProof, bool, error) {
+		nonce, err := org.GetProveCredNonce()
+		require.NoError(t, err)
+		proof, err := credMgr.BuildProof(res.Cred, revealed, []int{},
+			&ProofOptions{Escrow: userEscrow}, nonce)
+		require.NoError(t, err)
+		if modify != nil {
+			modify(proof.EscrowProof)
+		}
+
+		verified, _, err := org.ProveCred(proof, &VerificationPolicy{Escrow: verifierEscrow}, nonce)
+		return proof.EscrowProof, verified, err
+	}
+
+	escrowProof, verified, err := prove(escrow, escrow, nil)
+	require.NoError(t, err)
+	assert.True(t, verified, "escrow proof not accepted")
+
+	// the inspector decrypts the stored attribute
+	rec, err := records.LoadEscrowRecord(NewEscrowRecord(escrowProof).GetID())
+	require.NoError(t, err)
+	assert.Equal(t, inspector.PubKey.GetID(), rec.KeyID)
+	name, err := rec.Decrypt(inspector.SecKey)
+	require.NoError(t, err)
+	assert.Equal(t, credMgr.Attrs.Known[0], name, "decrypted attribute is not correct")
+	_, err = rec.Decrypt(other.SecKey)
+	assert.Error(t, err, "attribute should not be decrypted with other key")
+
+	// ciphertext of a different value is not accepted
+	_, verified, _ = prove(escrow, escrow, func(p *EscrowProof) {
+		csp := encryption.NewCSPaillierFromPubKey(inspector.PubKey)
+		p.U, p.E, p.V, _ = csp.Encrypt(big.NewInt(42), new(big.Int).SetBytes(p.Escrow.Label))
+	})
+	assert.False(t, verified, "escrow proof for a different value should not be accepted")
+
+	// the attribute needs to be escrowed as required by the verifier
+	_, _, err = prove(nil, escrow, nil)
+	assert.Error(t, err, "missing escrow proof should not be accepted")
+	_, _, err = prove(NewEscrow(0, other.PubKey, escrow.Label), escrow, nil)
+	assert.Error(t, err, "escrow under other key should not be accepted")
+	_, _, err = prove(NewEscrow(2, inspector.PubKey, escrow.Label), escrow, nil)
+	assert.Error(t, err, "escrow of other attribute should not be accepted")
+	_, _, err = prove(NewEscrow(0, inspector.PubKey, []byte("anything")), escrow, nil)
+	assert.Error(t, err, "escrow with other label should not be accepted")
+
+	// revealed attribute cannot be escrowed
+	_, err = credMgr.BuildProof(res.Cred, revealed, []int{},
+		&ProofOptions{Escrow: NewEscrow(1, inspector.PubKey, escrow.Label)}, org.GenNonce())
+	assert.Error(t, err, "revealed attribute should not be escrowed")
+}
+
+func TestEscrowMultiCred(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 1)
+
+	org1 := newTestOrg(t, attrCount, 0)
+	org2 := newTestOrg(t, attrCount, 1)
+	org2.Nonces = org1.Nonces
+	records := NewMockRecordManager()
+	org1.EscrowRecords, org2.EscrowRecords = records, records
+
+	masterSecret := org1.Keys.Pub.GenerateUserMasterSecret()
+	credMgr1, res1 := issueTestCred(t, org1, newTestRawCred(t, attrCount, "Jack"), masterSecret)
+	credMgr2, res2 := issueTestCred(t, org2, newTestRawCred(t, attrCount, "Jack"), masterSecret)
+
+	inspector := encryption.NewCSPaillier(&encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	})
+	escrow := NewEscrow(0, inspector.PubKey, []byte("court order"))
+	policies := []*VerificationPolicy{{Escrow: escrow}, {Escrow: escrow}}
+
+	prove := func(userEscrow *Escrow) ([]*CredProof, bool, error) {
+		presentations := []*CredPresentation{
+			NewCredPresentation(credMgr1, res1.Cred, []int{1}, []int{}),
+			NewCredPresentation(credMgr2, res2.Cred, []int{}, []int{}),
+		}
+		for _, p := range presentations {
+			p.Escrow = userEscrow
+		}
+		nonce, err := org1.GetProveCredNonce()
+		require.NoError(t, err)
+		proofs, err := BuildMultiProof(presentations, nil, nonce)
+		require.NoError(t, err)
+
+		verified, _, err := ProveMultiCred([]*Org{org1, org2}, policies, nil, proofs, nonce)
+		return proofs, verified, err
+	}
+
+	proofs, verified, err := prove(escrow)
+	require.NoError(t, err)
+	assert.True(t, verified, "multi-credential escrow proofs not accepted")
+
+	// the attribute escrowed in each of the credentials is stored
+	for _, p := range proofs {
+		rec, err := records.LoadEscrowRecord(NewEscrowRecord(p.EscrowProof).GetID())
+		require.NoError(t, err)
+		name, err := rec.Decrypt(inspector.SecKey)
+		require.NoError(t, err)
+		assert.Equal(t, credMgr1.Attrs.Known[0], name, "decrypted attribute is not correct")
+	}
+
+	_, _, err = prove(nil)
+	assert.Error(t, err, "multi-credential proof without escrow proofs should not be accepted")
+
+	// the escrowed attributes need to be stored
+	org2.EscrowRecords = nil
+	_, _, err = prove(escrow)
+	assert.Error(t, err, "escrow proof should not be accepted when it cannot be stored")
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRawCred returns the credential which is used in most of the tests (attrCount needs
+// to allow five known and one committed attribute): Name, Gender, Graduated, DateMin and
+// DateMax are known attributes, Age is a committed attribute.
+func newTestRawCred(t *testing.T, attrCount *AttrCount, name string) *RawCred {
+	rawCred := NewRawCred(attrCount)
+	require.NoError(t, rawCred.AddStrAttr("Name", name, true))
+	require.NoError(t, rawCred.AddStrAttr("Gender", "M", true))
+	require.NoError(t, rawCred.AddStrAttr("Graduated", "true", true))
+	require.NoError(t, rawCred.AddInt64Attr("DateMin", 1500000000, true))
+	require.NoError(t, rawCred.AddInt64Attr("DateMax", 1600000000, true))
+	require.NoError(t, rawCred.AddInt64Attr("Age", 25, false))
+
+	return rawCred
+}
+
+// testKeys holds the keys of the organizations returned by newTestOrg - generating the
+// keys (together with the proof of their validity) is the slowest part of most tests.
+var testKeys = struct {
+	sync.Mutex
+	pairs map[string]*KeyPair
+}{pairs: make(map[string]*KeyPair)}
+
+// newTestOrg returns an organization with the default parameters and the keys for
+// attrCount, which are generated only once per package. The organization has its own
+// nonces and revocation accumulator, but the tests must not modify its keys. Tests
+// which need several organizations with different keys pass different values of i.
+func newTestOrg(t *testing.T, attrCount *AttrCount, i int) *Org {
+	testKeys.Lock()
+	defer testKeys.Unlock()
+
+	params := GetDefaultParamSizes()
+	id := fmt.Sprintf("%d-%d-%d-%d", attrCount.Known, attrCount.Committed, attrCount.Hidden, i)
+	keys, ok := testKeys.pairs[id]
+	if !ok {
+		var err error
+		keys, err = GenerateKeyPair(params, attrCount)
+		require.NoError(t, err)
+		testKeys.pairs[id] = keys
+	}
+	org, err := NewOrgFromParams(params, keys)
+	require.NoError(t, err)
+
+	return org
+}
+
+// issueTestCred issues the credential with attributes rawCred by org to the holder of
+// masterSecret (a new master secret is generated when it is nil). The witness of the
+// credential is already set in the returned credential manager.
+func issueTestCred(t *testing.T, org *Org, rawCred *RawCred,
+	masterSecret *big.Int) (*CredManager, *CredResult) {
+	if masterSecret == nil {
+		masterSecret = org.Keys.Pub.GenerateUserMasterSecret()
+	}
+	credMgr, err := NewCredManager(org.Params, org.Keys.Pub, masterSecret, rawCred)
+	require.NoError(t, err)
+	issueNonce, err := org.GetCredIssueNonce()
+	require.NoError(t, err)
+	credReq, err := credMgr.GetCredRequest(issueNonce)
+	require.NoError(t, err)
+	res, err := org.IssueCred(credReq, issueNonce)
+	require.NoError(t, err)
+	require.NoError(t, credMgr.SetWitness(res.Cred, res.Witness))
+
+	return credMgr, res
+}
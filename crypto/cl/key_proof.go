@@ -0,0 +1,339 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+// keyProofPrimeBound bounds the small primes which need to be absent from (P-1)/2 and
+// (Q-1)/2, where P and Q are the factors of the modulus of the public key.
+const keyProofPrimeBound = 1 << 10
+
+// smallPrimesProduct is the product of all odd primes smaller than keyProofPrimeBound.
+var smallPrimesProduct = getSmallPrimesProduct(keyProofPrimeBound)
+
+// PubKeyProof is a non-interactive proof that the public key is well-formed, so that the
+// issuer cannot use it to link the presentations of credentials. It consists of:
+//
+// - a proof that N = P * Q for distinct primes P, Q = 3 mod 4 such that gcd(N, phi(N)) = 1
+// and neither (P-1)/2 nor (Q-1)/2 has a prime factor smaller than 1024 (which holds when
+// P and Q are safe primes). For each challenge y_i (derived from the public key by hash),
+// NthRoots[i] is the (N * L)-th root of y_i where L is the product of small odd primes,
+// and FourthRoots[i] is the fourth root of (-1)^A[i] * W^B[i] * y_i, where W has Jacobi
+// symbol -1. When N is not of the described form, at least one of the roots does not
+// exist for at least half of the possible challenges.
+// The primality of (P-1)/2 and (Q-1)/2 is not proved, but QR_N does not have
+// elements of small order, which is the property the scheme relies on.
+//
+// - a proof that Z and all R values (RsKnown, RsCommitted, RsHidden in this order) lie in
+// the group generated by S. Proofs of discrete logarithms modulo N determine the values
+// only up to the sign, which could be used to mark the attributes. Thus the square roots
+// of S (SqrtS) and of the values (SqrtResidues) are given, and the knowledge of discrete
+// logarithms of the roots with respect to SqrtS is proved (DLogProofRandomData,
+// DLogChallenge and DLogProofData). The squares of the roots are then exact powers of S.
+//
+// The Pedersen parameters (used for the nym) need no proof, VerifyProof checks them
+// directly: P and Q are primes, Q divides P-1 and both G and H are elements of order Q.
+//
+// Not covered are N1, G and H (used for the commitments of the committed attributes)
+// and the public key of the accumulator. An issuer could choose G outside the group
+// generated by H (or the generators of the accumulator in the same way), in which case
+// the commitments sent in credential requests and proofs would not hide the committed
+// attributes and the non-revocation proofs would not hide the credentials.
+type PubKeyProof struct {
+	W                   *big.Int
+	NthRoots            []*big.Int
+	FourthRoots         []*big.Int
+	A                   []bool
+	B                   []bool
+	SqrtS               *big.Int
+	SqrtResidues        []*big.Int
+	DLogProofRandomData []*big.Int
+	DLogChallenge       *big.Int
+	DLogProofData       []*big.Int
+}
+
+// newPubKeyProof proves that the public key pubKey with modulus group.N is well-formed.
+// The values exps are the discrete logarithms of pubKey.residues() with respect to pubKey.S.
+func newPubKeyProof(group *qr.RSASpecial, params *Params, pubKey *PubKey,
+	exps []*big.Int) (*PubKeyProof, error) {
+	residues := pubKey.residues()
+	if len(exps) != len(residues) {
+		return nil, fmt.Errorf("the number of exponents does not match the public key")
+	}
+
+	proof := &PubKeyProof{
+		NthRoots:    make([]*big.Int, params.SecParam),
+		FourthRoots: make([]*big.Int, params.SecParam),
+		A:           make([]bool, params.SecParam),
+		B:           make([]bool, params.SecParam),
+	}
+
+	for {
+		proof.W = common.GetRandomZnInvertibleElement(group.N)
+		if big.Jacobi(proof.W, group.N) == -1 {
+			break
+		}
+	}
+
+	one := big.NewInt(1)
+	pMin1 := new(big.Int).Sub(group.P, one)
+	qMin1 := new(big.Int).Sub(group.Q, one)
+	phi := new(big.Int).Mul(pMin1, qMin1)
+	e := new(big.Int).Mul(group.N, smallPrimesProduct)
+	d := new(big.Int).ModInverse(e, phi)
+	if d == nil {
+		return nil, fmt.Errorf("modulus is not a product of safe primes")
+	}
+
+	context := pubKey.GetContext()
+	minusOne := new(big.Int).Sub(group.N, one)
+	for i := range proof.NthRoots {
+		y := keyProofChallenge(group.N, params.SecParam, context, proof.W, i)
+		proof.NthRoots[i] = group.Exp(y, d)
+
+		// exactly one of y, -y, W*y, -W*y is a quadratic residue modulo both P and Q
+		found := false
+		for _, a := range []bool{false, true} {
+			for _, b := range []bool{false, true} {
+				v := new(big.Int).Set(y)
+				if a {
+					v = group.Mul(v, minusOne)
+				}
+				if b {
+					v = group.Mul(v, proof.W)
+				}
+				if !found && big.Jacobi(v, group.P) == 1 && big.Jacobi(v, group.Q) == 1 {
+					proof.FourthRoots[i] = fourthRoot(v, group.P, group.Q)
+					proof.A[i], proof.B[i] = a, b
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("modulus is not a product of primes congruent to 3 mod 4")
+		}
+	}
+
+	// the square roots which are quadratic residues are powers of the root of S with
+	// the same exponents as the values of the key
+	proof.SqrtS = sqrtQR(pubKey.S, group.P, group.Q)
+	proof.SqrtResidues = make([]*big.Int, len(residues))
+	for i := range residues {
+		proof.SqrtResidues[i] = group.Exp(proof.SqrtS, exps[i])
+	}
+
+	// the random values need to hide the product of the challenge and the exponent
+	boundary := []int{group.N.BitLen() + params.HashBitLen + params.SecParam}
+	provers := make([]*qr.RepresentationProver, len(residues))
+	proof.DLogProofRandomData = make([]*big.Int, len(residues))
+	for i, r := range proof.SqrtResidues {
+		provers[i] = qr.NewRepresentationProver(group, params.SecParam,
+			[]*big.Int{exps[i]}, []*big.Int{proof.SqrtS}, r)
+		t, err := provers[i].GetProofRandomDataGivenBoundaries(boundary, false)
+		if err != nil {
+			return nil, err
+		}
+		proof.DLogProofRandomData[i] = t
+	}
+
+	proof.DLogChallenge = proof.getDLogChallenge(context)
+	proof.DLogProofData = make([]*big.Int, len(residues))
+	for i, p := range provers {
+		proof.DLogProofData[i] = p.GetProofData(proof.DLogChallenge)[0]
+	}
+
+	return proof, nil
+}
+
+// VerifyProof checks that the public key is well-formed (see PubKeyProof).
+func (k *PubKey) VerifyProof(params *Params) error {
+	proof := k.Proof
+	if proof == nil {
+		return fmt.Errorf("proof of the public key is missing")
+	}
+	n := k.N
+	if n.Bit(0) == 0 || n.ProbablyPrime(20) {
+		return fmt.Errorf("modulus is not a product of two odd primes")
+	}
+	if proof.W == nil || big.Jacobi(proof.W, n) != -1 {
+		return fmt.Errorf("invalid W in the proof of the modulus")
+	}
+	if len(proof.NthRoots) != params.SecParam || len(proof.FourthRoots) != params.SecParam ||
+		len(proof.A) != params.SecParam || len(proof.B) != params.SecParam {
+		return fmt.Errorf("invalid length of the proof of the modulus")
+	}
+
+	group := qr.NewRSApecialPublic(n)
+	context := k.GetContext()
+	minusOne := new(big.Int).Sub(n, big.NewInt(1))
+	for i := range proof.NthRoots {
+		if proof.NthRoots[i] == nil || proof.FourthRoots[i] == nil {
+			return fmt.Errorf("invalid proof of the modulus")
+		}
+		y := keyProofChallenge(n, params.SecParam, context, proof.W, i)
+		z := group.Exp(group.Exp(proof.NthRoots[i], smallPrimesProduct), n)
+		if z.Cmp(y) != 0 {
+			return fmt.Errorf("modulus shares factors with its order or the order has small factors")
+		}
+
+		v := new(big.Int).Set(y)
+		if proof.A[i] {
+			v = group.Mul(v, minusOne)
+		}
+		if proof.B[i] {
+			v = group.Mul(v, proof.W)
+		}
+		if group.Exp(proof.FourthRoots[i], big.NewInt(4)).Cmp(v) != 0 {
+			return fmt.Errorf("modulus is not a product of two primes congruent to 3 mod 4")
+		}
+	}
+
+	residues := k.residues()
+	if len(proof.SqrtResidues) != len(residues) ||
+		len(proof.DLogProofRandomData) != len(residues) ||
+		len(proof.DLogProofData) != len(residues) || proof.DLogChallenge == nil {
+		return fmt.Errorf("invalid length of the proof of discrete logarithms")
+	}
+	two := big.NewInt(2)
+	if proof.SqrtS == nil || group.Exp(proof.SqrtS, two).Cmp(k.S) != 0 {
+		return fmt.Errorf("invalid square root of S")
+	}
+	for i, r := range residues {
+		if proof.SqrtResidues[i] == nil || proof.DLogProofRandomData[i] == nil ||
+			proof.DLogProofData[i] == nil {
+			return fmt.Errorf("invalid proof of discrete logarithms")
+		}
+		if group.Exp(proof.SqrtResidues[i], two).Cmp(r) != 0 {
+			return fmt.Errorf("invalid square root of value %d of the public key", i)
+		}
+	}
+	challenge := proof.getDLogChallenge(context)
+	if challenge.Cmp(proof.DLogChallenge) != 0 {
+		return fmt.Errorf("invalid challenge in the proof of discrete logarithms")
+	}
+	for i, r := range proof.SqrtResidues {
+		verifier := qr.NewRepresentationVerifier(group, params.SecParam)
+		verifier.SetProofRandomData(proof.DLogProofRandomData[i], []*big.Int{proof.SqrtS}, r)
+		verifier.SetChallenge(challenge)
+		if !verifier.Verify([]*big.Int{proof.DLogProofData[i]}) {
+			return fmt.Errorf("value %d of the public key is not in the group generated by S", i)
+		}
+	}
+
+	return k.verifyPedersenParams()
+}
+
+// verifyPedersenParams checks that the Pedersen parameters of the public key define
+// a group of prime order Q with generators G and H.
+func (k *PubKey) verifyPedersenParams() error {
+	p := k.PedersenParams
+	if p == nil || p.Group == nil || p.Group.P == nil || p.Group.Q == nil ||
+		p.Group.G == nil || p.H == nil {
+		return fmt.Errorf("Pedersen parameters are missing")
+	}
+	g := p.Group
+	if !g.P.ProbablyPrime(20) || !g.Q.ProbablyPrime(20) {
+		return fmt.Errorf("modulus or order of the Pedersen group is not prime")
+	}
+	pMin1 := new(big.Int).Sub(g.P, big.NewInt(1))
+	if new(big.Int).Mod(pMin1, g.Q).Sign() != 0 {
+		return fmt.Errorf("order of the Pedersen group does not divide P-1")
+	}
+	for _, x := range []*big.Int{g.G, p.H} {
+		if x.Cmp(big.NewInt(1)) <= 0 || x.Cmp(g.P) >= 0 || !g.IsElementInGroup(x) {
+			return fmt.Errorf("invalid generator of the Pedersen group")
+		}
+	}
+
+	return nil
+}
+
+// getDLogChallenge returns the Fiat-Shamir challenge of the proof of discrete logarithms.
+func (p *PubKeyProof) getDLogChallenge(context *big.Int) *big.Int {
+	numbers := []*big.Int{context, p.SqrtS}
+	numbers = append(numbers, p.SqrtResidues...)
+	numbers = append(numbers, p.DLogProofRandomData...)
+
+	return common.Hash(numbers...)
+}
+
+// residues returns Z, RsKnown, RsCommitted and RsHidden (in this order).
+func (k *PubKey) residues() []*big.Int {
+	residues := []*big.Int{k.Z}
+	residues = append(residues, k.RsKnown...)
+	residues = append(residues, k.RsCommitted...)
+	return append(residues, k.RsHidden...)
+}
+
+// keyProofChallenge derives the i-th challenge of the proof of the modulus n from the
+// context of the public key and w. The hash is extended to exceed the bit length of n
+// by secParam bits, so that the challenge is almost uniformly distributed modulo n.
+func keyProofChallenge(n *big.Int, secParam int, context, w *big.Int, i int) *big.Int {
+	var b []byte
+	for j := 0; len(b)*8 < n.BitLen()+secParam; j++ {
+		b = append(b, common.HashIntoBytes(context, w, big.NewInt(int64(i)),
+			big.NewInt(int64(j)))...)
+	}
+
+	return new(big.Int).Mod(new(big.Int).SetBytes(b), n)
+}
+
+// fourthRoot returns a fourth root of v modulo P * Q, where v is a quadratic residue modulo
+// primes P, Q = 3 mod 4.
+func fourthRoot(v, P, Q *big.Int) *big.Int {
+	return sqrtQR(sqrtQR(v, P, Q), P, Q)
+}
+
+// sqrtQR returns the square root of v modulo P * Q which is a quadratic residue, where v is
+// a quadratic residue modulo primes P, Q = 3 mod 4. Modulo such a prime, v^((P+1)/4) is
+// the square root of v which is a quadratic residue.
+func sqrtQR(v, P, Q *big.Int) *big.Int {
+	root := func(p *big.Int) *big.Int {
+		e := new(big.Int).Add(p, big.NewInt(1))
+		e.Rsh(e, 2)
+		return new(big.Int).Exp(v, e, p)
+	}
+	xp, xq := root(P), root(Q)
+
+	// combine the roots by the Chinese remainder theorem
+	x := new(big.Int).Sub(xq, xp)
+	x.Mul(x, new(big.Int).ModInverse(P, Q))
+	x.Mod(x, Q)
+	x.Mul(x, P)
+
+	return x.Add(x, xp)
+}
+
+// getSmallPrimesProduct returns the product of all odd primes smaller than bound.
+func getSmallPrimesProduct(bound int64) *big.Int {
+	product := big.NewInt(1)
+	for i := int64(3); i < bound; i += 2 {
+		p := big.NewInt(i)
+		if p.ProbablyPrime(20) {
+			product.Mul(product, p)
+		}
+	}
+
+	return product
+}
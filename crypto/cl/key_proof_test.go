@@ -0,0 +1,97 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xlab-si/emmy/crypto/common"
+)
+
+func TestPubKeyProof(t *testing.T) {
+	params := GetDefaultParamSizes()
+	org, err := NewOrg(params, NewAttrCount(2, 1, 1))
+	require.NoError(t, err)
+	pubKey := org.Keys.Pub
+	require.NoError(t, pubKey.VerifyProof(params))
+
+	// the proof is not a part of the key context
+	id := pubKey.GetID()
+	proof := pubKey.Proof
+	pubKey.Proof = nil
+	assert.Equal(t, id, pubKey.GetID())
+	assert.Error(t, pubKey.VerifyProof(params), "key without the proof should not be accepted")
+	rawCred := NewRawCred(NewAttrCount(2, 1, 0))
+	_, err = NewCredManager(params, pubKey, pubKey.GenerateUserMasterSecret(), rawCred)
+	assert.Error(t, err, "credential manager should not accept the key without the proof")
+	pubKey.Proof = proof
+
+	// a value which is not a power of S, for example -Z, is detected
+	Z := pubKey.Z
+	pubKey.Z = new(big.Int).Sub(pubKey.N, Z)
+	assert.Error(t, pubKey.VerifyProof(params), "value outside of the group should be detected")
+	pubKey.Z = Z
+
+	// the square roots need to match the values of the key
+	proof.SqrtS, proof.SqrtResidues[0] = proof.SqrtResidues[0], proof.SqrtS
+	assert.Error(t, pubKey.VerifyProof(params), "invalid square roots should be detected")
+	proof.SqrtS, proof.SqrtResidues[0] = proof.SqrtResidues[0], proof.SqrtS
+
+	// so is a modulus which is a product of three primes
+	N := pubKey.N
+	pubKey.N = new(big.Int).Mul(N, common.GetGermainPrime(64))
+	assert.Error(t, pubKey.VerifyProof(params), "invalid modulus should be detected")
+	pubKey.N = N
+
+	// the roots need to match the challenges
+	root := proof.NthRoots[0]
+	proof.NthRoots[0] = new(big.Int).Add(root, big.NewInt(1))
+	assert.Error(t, pubKey.VerifyProof(params), "invalid root should be detected")
+	proof.NthRoots[0] = root
+	proof.A[0] = !proof.A[0]
+	assert.Error(t, pubKey.VerifyProof(params), "invalid fourth root should be detected")
+	proof.A[0] = !proof.A[0]
+
+	// the Pedersen parameters are checked directly
+	pp := pubKey.PedersenParams
+	H := pp.H
+	pp.H = big.NewInt(1)
+	assert.Error(t, pubKey.verifyPedersenParams(), "trivial generator should be detected")
+	pp.H = new(big.Int).Sub(pp.Group.P, big.NewInt(1)) // element of order 2
+	assert.Error(t, pubKey.verifyPedersenParams(), "element outside of the group should be detected")
+	pp.H = H
+	Q := pp.Group.Q
+	pp.Group.Q = new(big.Int).Add(Q, big.NewInt(2))
+	assert.Error(t, pubKey.verifyPedersenParams(), "invalid group order should be detected")
+	pp.Group.Q = Q
+
+	require.NoError(t, pubKey.VerifyProof(params))
+}
+
+func TestFourthRoot(t *testing.T) {
+	P, Q := big.NewInt(23), big.NewInt(47) // safe primes congruent to 3 mod 4
+	N := new(big.Int).Mul(P, Q)
+	for _, x := range []int64{2, 5, 100, 1000} {
+		v := new(big.Int).Exp(big.NewInt(x), big.NewInt(2), N)
+		root := fourthRoot(v, P, Q)
+		assert.Equal(t, v, new(big.Int).Exp(root, big.NewInt(4), N))
+	}
+}
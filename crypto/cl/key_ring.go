@@ -0,0 +1,170 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// KeyVersion is the organization with one version of its keys together with the period
+// in which the keys are valid. Zero NotBefore (NotAfter) means that the period is not
+// bounded from below (above).
+type KeyVersion struct {
+	Org       *Org
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// IsValid returns true if the keys are valid at time t.
+func (v *KeyVersion) IsValid(t time.Time) bool {
+	if !v.NotBefore.IsZero() && t.Before(v.NotBefore) {
+		return false
+	}
+	if !v.NotAfter.IsZero() && t.After(v.NotAfter) {
+		return false
+	}
+
+	return true
+}
+
+// KeyRing holds all versions of the keys of an organization. New credentials are issued
+// under the active keys (see GetActiveOrg), while credential proofs are accepted for
+// the credentials issued under any version of the keys which has not been retired yet.
+// This way the keys can be rotated without invalidating the credentials issued under
+// the previous keys immediately.
+type KeyRing struct {
+	versions []*KeyVersion
+}
+
+func NewKeyRing() *KeyRing {
+	return &KeyRing{}
+}
+
+// Add adds the organization with a new version of the keys, valid between notBefore
+// and notAfter, to the key ring.
+func (r *KeyRing) Add(org *Org, notBefore, notAfter time.Time) error {
+	if !notBefore.IsZero() && !notAfter.IsZero() && notAfter.Before(notBefore) {
+		return fmt.Errorf("keys expire before they become valid")
+	}
+	keyID := org.Keys.Pub.GetID()
+	if _, err := r.Get(keyID); err == nil {
+		return fmt.Errorf("key %s is already in the key ring", keyID)
+	}
+
+	r.versions = append(r.versions, &KeyVersion{
+		Org:       org,
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+	})
+
+	return nil
+}
+
+// GetVersions returns all versions of the keys in the order they were added.
+func (r *KeyRing) GetVersions() []*KeyVersion {
+	return r.versions
+}
+
+// Get returns the version of the keys with the given ID (see PubKey.GetID).
+func (r *KeyRing) Get(keyID string) (*KeyVersion, error) {
+	for _, v := range r.versions {
+		if v.Org.Keys.Pub.GetID() == keyID {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("key %s is not known", keyID)
+}
+
+// GetValidOrg returns the organization with the keys with the given ID if the keys
+// are valid at time t.
+func (r *KeyRing) GetValidOrg(keyID string, t time.Time) (*Org, error) {
+	v, err := r.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !v.IsValid(t) {
+		return nil, fmt.Errorf("key %s is not valid", keyID)
+	}
+
+	return v.Org, nil
+}
+
+// GetActiveOrg returns the organization with the keys under which the credentials are
+// issued at time t. These are the keys which are valid at time t and became valid last.
+// When there are several such keys, the last added is returned.
+func (r *KeyRing) GetActiveOrg(t time.Time) (*Org, error) {
+	var active *KeyVersion
+	for _, v := range r.versions {
+		if !v.IsValid(t) {
+			continue
+		}
+		if active == nil || !v.NotBefore.Before(active.NotBefore) {
+			active = v
+		}
+	}
+	if active == nil {
+		return nil, fmt.Errorf("there is no valid key")
+	}
+
+	return active.Org, nil
+}
+
+// GetRecordVersion returns the version of the keys under which the credential described
+// by the receiver record rec was issued.
+func (r *KeyRing) GetRecordVersion(rec *ReceiverRecord) (*KeyVersion, error) {
+	for _, v := range r.versions {
+		if rec.Context != nil && v.Org.Keys.Pub.GetContext().Cmp(rec.Context) == 0 {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("key of the credential is not known")
+}
+
+// GetProveCredNonce generates a nonce for a credential proof. The nonce is stored for
+// all versions of the keys, as the keys used for the verification are known only
+// after the proof is received.
+func (r *KeyRing) GetProveCredNonce() (*big.Int, error) {
+	return r.genStoredNonce(NonceProve)
+}
+
+// GetCredIssueNonce generates a nonce for a credential update request (see Org.UpdateCred).
+// The nonce is stored for all versions of the keys, as the credential is updated under
+// the keys it was issued under, which are known only from its receiver record.
+func (r *KeyRing) GetCredIssueNonce() (*big.Int, error) {
+	return r.genStoredNonce(NonceIssue)
+}
+
+// genStoredNonce generates a nonce for the given purpose and stores it for all versions
+// of the keys.
+func (r *KeyRing) genStoredNonce(purpose NoncePurpose) (*big.Int, error) {
+	if len(r.versions) == 0 {
+		return nil, fmt.Errorf("key ring is empty")
+	}
+	nonce := r.versions[0].Org.GenNonce()
+	for _, v := range r.versions {
+		if err := v.Org.Nonces.StoreNonce(purpose, nonce, NonceTTL); err != nil {
+			return nil, fmt.Errorf("error when storing nonce: %v", err)
+		}
+	}
+
+	return nonce, nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRing(t *testing.T) {
+	attrCount := NewAttrCount(2, 1, 1)
+	oldOrg := newTestOrg(t, attrCount, 0)
+	newOrg := newTestOrg(t, attrCount, 1)
+
+	now := time.Now()
+	rotation := now.AddDate(0, 0, -1)
+	retirement := now.AddDate(0, 1, 0)
+
+	keyRing := NewKeyRing()
+	_, err := keyRing.GetActiveOrg(now)
+	assert.Error(t, err, "empty key ring should not have an active key")
+
+	err = keyRing.Add(oldOrg, time.Time{}, retirement)
+	require.NoError(t, err)
+	err = keyRing.Add(newOrg, rotation, time.Time{})
+	require.NoError(t, err)
+	err = keyRing.Add(newOrg, rotation, time.Time{})
+	assert.Error(t, err, "the same key should not be added twice")
+	err = keyRing.Add(oldOrg, retirement, rotation)
+	assert.Error(t, err, "key which expires before it becomes valid should not be added")
+
+	// the key which became valid last is active
+	org, err := keyRing.GetActiveOrg(now)
+	require.NoError(t, err)
+	assert.Equal(t, newOrg, org)
+	org, err = keyRing.GetActiveOrg(rotation.AddDate(0, 0, -1))
+	require.NoError(t, err)
+	assert.Equal(t, oldOrg, org)
+
+	// the old key is accepted until it is retired
+	org, err = keyRing.GetValidOrg(oldOrg.Keys.Pub.GetID(), now)
+	require.NoError(t, err)
+	assert.Equal(t, oldOrg, org)
+	_, err = keyRing.GetValidOrg(oldOrg.Keys.Pub.GetID(), retirement.Add(time.Second))
+	assert.Error(t, err, "retired key should not be valid")
+	_, err = keyRing.GetValidOrg(newOrg.Keys.Pub.GetID(), rotation.Add(-time.Second))
+	assert.Error(t, err, "key should not be valid before it becomes valid")
+	_, err = keyRing.GetValidOrg("unknown", now)
+	assert.Error(t, err, "unknown key should not be valid")
+
+	// the credential issued under the old key is verified with the old key
+	rawCred := NewRawCred(attrCount)
+	_ = rawCred.AddStrAttr("Name", "Jack", true)
+	_ = rawCred.AddStrAttr("Gender", "M", true)
+	_ = rawCred.AddInt64Attr("Age", 25, false)
+	credMgr, res := issueTestCred(t, oldOrg, rawCred, nil)
+
+	v, err := keyRing.GetRecordVersion(res.Record)
+	require.NoError(t, err)
+	assert.Equal(t, oldOrg, v.Org)
+
+	nonce, err := keyRing.GetProveCredNonce()
+	require.NoError(t, err)
+	revealed := []int{0}
+	proof, err := credMgr.BuildProof(res.Cred, revealed, []int{}, nil, nonce)
+	require.NoError(t, err)
+
+	org, err = keyRing.GetValidOrg(credMgr.PubKey.GetID(), now)
+	require.NoError(t, err)
+	verified, _, err := org.ProveCred(proof, nil, nonce)
+	require.NoError(t, err)
+	assert.True(t, verified, "credential issued under the old key not accepted")
+}
@@ -18,6 +18,9 @@
 package cl
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"math/big"
 
 	"github.com/pkg/errors"
@@ -37,14 +40,16 @@ type KeyPair struct {
 type SecKey struct {
 	RsaPrimes                  *qr.RSASpecialPrimes
 	AttributesSpecialRSAPrimes *qr.RSASpecialPrimes
+	AccumulatorPrimes          *qr.RSASpecialPrimes // for revocation of credentials
 }
 
-// NewSecKey accepts group g and commitment receiver cr, and returns
-// new secret key for the CL scheme.
-func NewSecKey(g *qr.RSASpecial, cr *df.Receiver) *SecKey {
+// NewSecKey accepts group g, commitment receiver cr and revocation accumulator
+// group accGroup, and returns new secret key for the CL scheme.
+func NewSecKey(g *qr.RSASpecial, cr *df.Receiver, accGroup *qr.RSASpecial) *SecKey {
 	return &SecKey{
 		RsaPrimes:                  g.GetPrimes(),
 		AttributesSpecialRSAPrimes: cr.QRSpecialRSA.GetPrimes(),
+		AccumulatorPrimes:          accGroup.GetPrimes(),
 	}
 }
 
@@ -61,14 +66,20 @@ type PubKey struct {
 	N1 *big.Int
 	G  *big.Int
 	H  *big.Int
+	// Accumulator is used for revocation of credentials
+	Accumulator *AccumulatorPubKey
+	// Proof proves that the public key is well-formed (see VerifyProof), it is not part
+	// of the key context
+	Proof *PubKeyProof
 }
 
-// NewPubKey accepts group g, parameters p and commitment receiver recv,
-// and returns a public key for the CL scheme.
+// NewPubKey accepts group g, parameters p, commitment receiver recv and revocation
+// accumulator group accGroup, and returns a public key for the CL scheme together with
+// the proof that it is well-formed.
 func NewPubKey(g *qr.RSASpecial, p *Params,
-	attrs *AttrCount, recv *df.Receiver) (*PubKey,
+	attrs *AttrCount, recv *df.Receiver, accGroup *qr.RSASpecial) (*PubKey,
 	error) {
-	S, Z, RsKnown, RsCommitted, RsHidden, err := generateQuadraticResidues(
+	S, Z, RsKnown, RsCommitted, RsHidden, exps, err := generateQuadraticResidues(
 		g, attrs.Known, attrs.Committed, attrs.Hidden)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating quadratic residues")
@@ -79,7 +90,12 @@ func NewPubKey(g *qr.RSASpecial, p *Params,
 		return nil, errors.Wrap(err, "error creating Pedersen receiver")
 	}
 
-	return &PubKey{
+	accPubKey, err := NewAccumulatorPubKey(accGroup)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating accumulator public key")
+	}
+
+	pk := &PubKey{
 		N:              g.N,
 		S:              S,
 		Z:              Z,
@@ -90,7 +106,13 @@ func NewPubKey(g *qr.RSASpecial, p *Params,
 		N1:             recv.QRSpecialRSA.N,
 		G:              recv.G,
 		H:              recv.H,
-	}, nil
+		Accumulator:    accPubKey,
+	}
+	if pk.Proof, err = newPubKeyProof(g, p, pk, exps); err != nil {
+		return nil, errors.Wrap(err, "error creating proof of the public key")
+	}
+
+	return pk, nil
 }
 
 // GenerateUserMasterSecret generates a secret key that needs to be encoded into every user's credential as a
@@ -100,18 +122,106 @@ func (k *PubKey) GenerateUserMasterSecret() *big.Int {
 }
 
 // GetContext concatenates public parameters and returns a corresponding number.
+// All values of the key are included (except the proof), so that the context (and
+// the ID) changes whenever any of them changes.
 func (k *PubKey) GetContext() *big.Int {
 	numbers := []*big.Int{k.N, k.S, k.Z}
 	numbers = append(numbers, k.RsKnown...)
 	numbers = append(numbers, k.RsCommitted...)
 	numbers = append(numbers, k.RsHidden...)
+	if p := k.PedersenParams; p != nil && p.Group != nil {
+		numbers = append(numbers, p.Group.P, p.Group.G, p.Group.Q, p.H)
+	}
+	numbers = append(numbers, k.N1, k.G, k.H)
+	if a := k.Accumulator; a != nil {
+		numbers = append(numbers, a.N, a.G, a.H, a.V0)
+	}
 	concatenated := common.ConcatenateNumbers(numbers...)
 	return new(big.Int).SetBytes(concatenated)
 }
 
+// GetID returns the identifier of the public key (hex encoded SHA-256 hash of the context).
+func (k *PubKey) GetID() string {
+	h := sha256.Sum256(k.GetContext().Bytes())
+	return hex.EncodeToString(h[:])
+}
+
+const (
+	pubKeyFileType = "cl-public-key"
+	secKeyFileType = "cl-secret-key"
+)
+
+// pubKeyFile is the content of the public key file - the public key is stored together
+// with the parameters it was generated for.
+type pubKeyFile struct {
+	Params *Params
+	PubKey *PubKey
+}
+
+// WriteKeyPair writes the public key (together with params) and the secret key to new files
+// in the format described by common.KeyFile. The secret key file is readable only by its owner.
+func WriteKeyPair(pubKeyPath, secKeyPath string, params *Params, keys *KeyPair) error {
+	keyID := keys.Pub.GetID()
+	if err := common.WriteKeyFile(pubKeyPath, pubKeyFileType, keyID,
+		&pubKeyFile{Params: params, PubKey: keys.Pub}, false); err != nil {
+		return errors.Wrap(err, "error writing public key")
+	}
+	if err := common.WriteKeyFile(secKeyPath, secKeyFileType, keyID, keys.Sec, true); err != nil {
+		return errors.Wrap(err, "error writing secret key")
+	}
+
+	return nil
+}
+
+// WriteSecKey writes the secret key which matches pubKey to a new file (readable only by its
+// owner), for example the secret key of the coordinator returned by SplitSecKey.
+func WriteSecKey(path string, pubKey *PubKey, secKey *SecKey) error {
+	if err := common.WriteKeyFile(path, secKeyFileType, pubKey.GetID(), secKey, true); err != nil {
+		return errors.Wrap(err, "error writing secret key")
+	}
+
+	return nil
+}
+
+// ReadPubKey reads the public key and the parameters it was generated for from the file
+// written by WriteKeyPair.
+func ReadPubKey(path string) (*Params, *PubKey, error) {
+	var f pubKeyFile
+	keyID, err := common.ReadKeyFile(path, pubKeyFileType, &f)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f.Params == nil || f.PubKey == nil {
+		return nil, nil, fmt.Errorf("public key file %s is not complete", path)
+	}
+	if f.PubKey.GetID() != keyID {
+		return nil, nil, fmt.Errorf("public key in %s does not match its ID", path)
+	}
+
+	return f.Params, f.PubKey, nil
+}
+
+// ReadSecKey reads the secret key which matches pubKey from the file written by WriteKeyPair.
+func ReadSecKey(path string, pubKey *PubKey) (*SecKey, error) {
+	secKey := new(SecKey)
+	keyID, err := common.ReadKeyFile(path, secKeyFileType, secKey)
+	if err != nil {
+		return nil, err
+	}
+	if keyID != pubKey.GetID() {
+		return nil, fmt.Errorf("secret key in %s does not match the public key", path)
+	}
+
+	return secKey, nil
+}
+
 // GenerateKeyPair takes and constructs a keypair containing public and
 // secret key for the CL scheme.
 func GenerateKeyPair(p *Params, attrs *AttrCount) (*KeyPair, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
 	g, err := qr.NewRSASpecial(int(p.NLength) / 2)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating RSASpecial group")
@@ -123,9 +233,15 @@ func GenerateKeyPair(p *Params, attrs *AttrCount) (*KeyPair, error) {
 		return nil, errors.Wrap(err, "error creating DF commitment receiver")
 	}
 
-	sk := NewSecKey(g, commRecv)
+	// group for the revocation accumulator:
+	accGroup, err := qr.NewRSASpecial(int(p.NLength) / 2)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating accumulator group")
+	}
+
+	sk := NewSecKey(g, commRecv, accGroup)
 
-	pk, err := NewPubKey(g, p, attrs, commRecv)
+	pk, err := NewPubKey(g, p, attrs, commRecv, accGroup)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPairFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clkeys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	params := GetDefaultParamSizes()
+	org, err := NewOrg(params, NewAttrCount(2, 1, 1))
+	require.NoError(t, err)
+
+	pubKeyPath := filepath.Join(dir, "pub.json")
+	secKeyPath := filepath.Join(dir, "sec.json")
+	err = WriteKeyPair(pubKeyPath, secKeyPath, params, org.Keys)
+	require.NoError(t, err)
+
+	loaded, err := LoadOrg(pubKeyPath, secKeyPath)
+	require.NoError(t, err)
+	assert.Equal(t, org.Keys.Pub.GetID(), loaded.Keys.Pub.GetID())
+	assert.Equal(t, params, loaded.Params)
+	assert.Equal(t, org.Keys.Sec, loaded.Keys.Sec)
+
+	// only the public key is needed to verify credential proofs
+	loaded, err = LoadOrg(pubKeyPath, "")
+	require.NoError(t, err)
+	assert.Nil(t, loaded.Keys.Sec)
+
+	// the secret key of a different organization is not accepted
+	other, err := NewOrg(params, NewAttrCount(2, 1, 1))
+	require.NoError(t, err)
+	otherPubKeyPath := filepath.Join(dir, "pub2.json")
+	otherSecKeyPath := filepath.Join(dir, "sec2.json")
+	err = WriteKeyPair(otherPubKeyPath, otherSecKeyPath, params, other.Keys)
+	require.NoError(t, err)
+	_, err = LoadOrg(pubKeyPath, otherSecKeyPath)
+	assert.Error(t, err, "secret key of a different organization should not be loaded")
+}
+
+func TestPubKeyID(t *testing.T) {
+	params := GetDefaultParamSizes()
+	org, err := NewOrg(params, NewAttrCount(2, 1, 1))
+	require.NoError(t, err)
+	pubKey := org.Keys.Pub
+	id := pubKey.GetID()
+
+	// every value of the public key is a part of its ID
+	one := big.NewInt(1)
+	for name, val := range map[string]*big.Int{
+		"Z":                    pubKey.Z,
+		"RsHidden":             pubKey.RsHidden[0],
+		"PedersenParams.H":     pubKey.PedersenParams.H,
+		"PedersenParams.Group": pubKey.PedersenParams.Group.G,
+		"N1":                   pubKey.N1,
+		"G":                    pubKey.G,
+		"H":                    pubKey.H,
+		"Accumulator.N":        pubKey.Accumulator.N,
+		"Accumulator.V0":       pubKey.Accumulator.V0,
+	} {
+		val.Add(val, one)
+		assert.NotEqual(t, id, pubKey.GetID(), "changed %s should change the ID", name)
+		val.Sub(val, one)
+	}
+	assert.Equal(t, id, pubKey.GetID())
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+// CredProof is a proof of the possession of a credential (RandCred is the randomized
+// credential, only RandCred.A is sent to the verifier) together with the revealed
+// attributes and the accompanying proofs.
+type CredProof struct {
+	RandCred                          *Cred
+	Proof                             *qr.RepresentationProof
+	NonRevProof                       *NonRevocationProof
+	PredicateProofs                   []*PredicateProof
+	SetMembershipProofs               []*SetMembershipProof
+	DomainPseudonymProof              *DomainPseudonymProof
+	EscrowProof                       *EscrowProof
+	CommitmentEqualityProofs          []*CommitmentEqualityProof
+	RevealedKnownAttrsIndices         []int
+	RevealedCommitmentsOfAttrsIndices []int
+	RevealedKnownAttrs                []*big.Int
+	RevealedCommitmentsOfAttrs        []*big.Int
+}
+
+// CredPresentation specifies a credential which is to be proved in a multi-credential
+// proof and which of its attributes are to be revealed. Predicates and SetMemberships
+// can be set to prove properties of unrevealed attributes. When Scope is set, the proof
+// contains the domain pseudonym for the scope, when Escrow is set, the attribute is
+// escrowed for the inspector, and for CommitmentEqualities it is proved that the
+// commitments hide the attributes (see ProofOptions).
+type CredPresentation struct {
+	CredManager                       *CredManager
+	Cred                              *Cred
+	RevealedKnownAttrsIndices         []int
+	RevealedCommitmentsOfAttrsIndices []int
+	Predicates                        []*Predicate
+	SetMemberships                    []*SetMembership
+	Scope                             []byte
+	Escrow                            *Escrow
+	CommitmentEqualities              []*CommitmentEquality
+}
+
+func NewCredPresentation(credManager *CredManager, cred *Cred, revealedKnownAttrsIndices,
+	revealedCommitmentsOfAttrsIndices []int) *CredPresentation {
+	return &CredPresentation{
+		CredManager:                       credManager,
+		Cred:                              cred,
+		RevealedKnownAttrsIndices:         revealedKnownAttrsIndices,
+		RevealedCommitmentsOfAttrsIndices: revealedCommitmentsOfAttrsIndices,
+	}
+}
+
+// BuildMultiProof builds proofs for several credentials (possibly issued by different
+// organizations) which share the challenge. All credentials need to have the same master
+// secret encoded as the first hidden attribute. The same random value is used for the master
+// secret in all proofs, so the verifier can check that the responses for the master secret
+// are the same - meaning that all credentials belong to the same holder. In the same way,
+// attrEqualities (attributes are named as in RawCred of the credential managers) prove that
+// the credentials contain the same values of the attributes.
+func BuildMultiProof(presentations []*CredPresentation, attrEqualities []*AttrEquality,
+	nonceOrg *big.Int) ([]*CredProof, error) {
+	if len(presentations) == 0 {
+		return nil, fmt.Errorf("no credentials to prove")
+	}
+
+	params := presentations[0].CredManager.Params
+	b := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(
+		params.AttrBitLen+params.SecParam+params.HashBitLen)), nil)
+	masterSecretRandom := common.GetRandomIntAlsoNeg(b)
+	attrRandoms, err := getAttrEqualityRandoms(presentations, attrEqualities)
+	if err != nil {
+		return nil, err
+	}
+
+	provers := make([]*credProver, len(presentations))
+	pubKeys := make([]*PubKey, len(presentations))
+	proofRandomData := make([]*big.Int, len(presentations))
+	additionalProofRandomData := make([][]*big.Int, len(presentations))
+	for i, p := range presentations {
+		prover, err := p.CredManager.newCredProver(p.Cred, p.RevealedKnownAttrsIndices,
+			p.RevealedCommitmentsOfAttrsIndices, &ProofOptions{
+				Predicates:           p.Predicates,
+				SetMemberships:       p.SetMemberships,
+				Scope:                p.Scope,
+				Escrow:               p.Escrow,
+				CommitmentEqualities: p.CommitmentEqualities,
+			}, attrRandoms[i], masterSecretRandom)
+		if err != nil {
+			return nil, err
+		}
+		provers[i] = prover
+		pubKeys[i] = p.CredManager.PubKey
+		proofRandomData[i] = prover.proofRandomData
+		additionalProofRandomData[i] = prover.additionalProofRandomData
+	}
+
+	challenge := getMultiProofChallenge(pubKeys, proofRandomData, additionalProofRandomData,
+		nonceOrg)
+
+	proofs := make([]*CredProof, len(provers))
+	for i, prover := range provers {
+		proof, err := prover.getProof(challenge)
+		if err != nil {
+			return nil, err
+		}
+		proofs[i] = proof
+	}
+
+	return proofs, nil
+}
+
+// getMultiProofChallenge returns the challenge which is shared by the proofs of several credentials.
+func getMultiProofChallenge(pubKeys []*PubKey, proofRandomData []*big.Int,
+	additionalProofRandomData [][]*big.Int, nonceOrg *big.Int) *big.Int {
+	l := []*big.Int{nonceOrg}
+	for i, pubKey := range pubKeys {
+		l = append(l, pubKey.GetContext(), proofRandomData[i])
+		l = append(l, additionalProofRandomData[i]...)
+	}
+
+	return common.Hash(l...)
+}
+
+// ProveMultiCred verifies proofs of several credentials built by BuildMultiProof. Parameter
+// orgs contains the organizations which issued the credentials (proofs[i] is verified using
+// orgs[i], only public keys are needed). When policies is not nil, proofs[i] needs to satisfy
+// policies[i] (unless it is nil), including the domain pseudonym for policies[i].Scope and
+// the escrow of policies[i].Escrow and the commitment equality proofs for
+// policies[i].CommitmentEqualities as in Org.ProveCred - the pseudonyms are returned
+// (pseudonyms[i] is nil when no scope is set for proofs[i]) and the escrowed attributes
+// are stored in EscrowRecords of orgs[i]. Besides checking each of the proofs, it checks that all
+// the credentials contain the same master secret and that the attributes in attrEqualities
+// are equal. The attributes are named as in the credential structures which the policies
+// are bound to, so policies need to be given for the credentials in attrEqualities.
+// The proofs need to be built for nonceOrg, obtained from GetProveCredNonce of orgs[0] (or of
+// an organization which shares Nonces with it). The nonce can be used only once.
+func ProveMultiCred(orgs []*Org, policies []*VerificationPolicy, attrEqualities []*AttrEquality,
+	proofs []*CredProof, nonceOrg *big.Int) (bool, []*big.Int, error) {
+	if len(proofs) == 0 || len(orgs) != len(proofs) {
+		return false, nil, fmt.Errorf("the number of organizations and proofs does not match")
+	}
+	if policies != nil && len(policies) != len(proofs) {
+		return false, nil, fmt.Errorf("the number of policies and proofs does not match")
+	}
+	if err := orgs[0].useNonce(NonceProve, nonceOrg); err != nil {
+		return false, nil, err
+	}
+	for i, p := range proofs {
+		if err := checkCredProofStructure(orgs[i].Keys.Pub, p); err != nil {
+			return false, nil, err
+		}
+	}
+
+	pubKeys := make([]*PubKey, len(proofs))
+	proofRandomData := make([]*big.Int, len(proofs))
+	additionalProofRandomData := make([][]*big.Int, len(proofs))
+	accValues := make([]*big.Int, len(proofs))
+	pseudonyms := make([]*big.Int, len(proofs))
+	var masterSecretResponse *big.Int
+	for i, p := range proofs {
+		o := orgs[i]
+		if len(o.Keys.Pub.RsHidden) == 0 {
+			return false, nil, fmt.Errorf("master secret is not encoded in credentials of organization %d", i)
+		}
+		pseudonym, err := o.checkProofPolicy(p, getPolicy(policies, i))
+		if err != nil {
+			return false, nil, err
+		}
+		pseudonyms[i] = pseudonym
+		data, accValue, err := o.getAdditionalProofRandomData(p)
+		if err != nil {
+			return false, nil, err
+		}
+
+		pos := masterSecretPosition(o.Keys.Pub, p)
+		if pos >= len(p.Proof.ProofData) {
+			return false, nil, fmt.Errorf("credential proof data is not complete")
+		}
+		if masterSecretResponse == nil {
+			masterSecretResponse = p.Proof.ProofData[pos]
+		} else if masterSecretResponse.Cmp(p.Proof.ProofData[pos]) != 0 {
+			return false, nil, fmt.Errorf("credentials do not contain the same master secret")
+		}
+
+		pubKeys[i] = o.Keys.Pub
+		proofRandomData[i] = p.Proof.ProofRandomData
+		additionalProofRandomData[i] = data
+		accValues[i] = accValue
+	}
+	if err := verifyAttrEqualities(orgs, policies, proofs, attrEqualities); err != nil {
+		return false, nil, err
+	}
+
+	challenge := getMultiProofChallenge(pubKeys, proofRandomData, additionalProofRandomData,
+		nonceOrg)
+	for i, p := range proofs {
+		if p.Proof.Challenge.Cmp(challenge) != 0 {
+			return false, nil, fmt.Errorf("challenge is not correct")
+		}
+		verified, err := orgs[i].verifyCredProof(p, accValues[i])
+		if err != nil || !verified {
+			return false, nil, err
+		}
+	}
+
+	for i, p := range proofs {
+		if err := orgs[i].storeEscrowRecord(p, getPolicy(policies, i)); err != nil {
+			return false, nil, err
+		}
+	}
+
+	return true, pseudonyms, nil
+}
+
+// getPolicy returns the i-th policy or nil when no policies are given.
+func getPolicy(policies []*VerificationPolicy, i int) *VerificationPolicy {
+	if policies == nil {
+		return nil
+	}
+
+	return policies[i]
+}
+
+// masterSecretPosition returns the position of the master secret (the first hidden attribute) in
+// the credential proof - it follows unrevealed known attributes and unrevealed commitments
+// of attributes.
+func masterSecretPosition(pubKey *PubKey, p *CredProof) int {
+	pos := 0
+	for i := 0; i < len(pubKey.RsKnown); i++ {
+		if !common.Contains(p.RevealedKnownAttrsIndices, i) {
+			pos++
+		}
+	}
+	for i := 0; i < len(pubKey.RsCommitted); i++ {
+		if !common.Contains(p.RevealedCommitmentsOfAttrsIndices, i) {
+			pos++
+		}
+	}
+
+	return pos
+}
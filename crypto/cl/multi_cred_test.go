@@ -0,0 +1,260 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xlab-si/emmy/crypto/pedersen"
+)
+
+func TestMultiCredProof(t *testing.T) {
+	params := GetDefaultParamSizes()
+	attrCount := NewAttrCount(5, 1, 1)
+
+	org1 := newTestOrg(t, attrCount, 0)
+	org2 := newTestOrg(t, attrCount, 1)
+	// the organizations share the nonces, so that the nonce can be issued by any of them
+	org2.Nonces = org1.Nonces
+
+	issue := func(org *Org, masterSecret *big.Int, name string) (*CredManager, *Cred) {
+		credMgr, res := issueTestCred(t, org, newTestRawCred(t, attrCount, name), masterSecret)
+		return credMgr, res.Cred
+	}
+
+	masterSecret := org1.Keys.Pub.GenerateUserMasterSecret()
+	credMgr1, cred1 := issue(org1, masterSecret, "Jack")
+	credMgr2, cred2 := issue(org2, masterSecret, "John")
+
+	orgs := []*Org{org1, org2}
+	p1 := NewCredPresentation(credMgr1, cred1, []int{0}, []int{})
+	p2 := NewCredPresentation(credMgr2, cred2, []int{0, 1}, []int{})
+	p2.Predicates = []*Predicate{NewLesserPredicate(params, 3, EncodeInt64(1562643001))}
+
+	attrs := credAttrs(credMgr1.RawCred)
+	policy1, err := NewVerificationPolicy(attrs, []string{"Name"}, nil)
+	require.NoError(t, err)
+	max := int64(1562643000)
+	policy2, err := NewVerificationPolicy(attrs, []string{"Name", "Gender"},
+		&PolicyCondition{Attr: "DateMin", Max: &max})
+	require.NoError(t, err)
+	policies := []*VerificationPolicy{policy1, policy2}
+
+	nonce, err := org1.GetProveCredNonce()
+	require.NoError(t, err)
+	proofs, err := BuildMultiProof([]*CredPresentation{p1, p2}, nil, nonce)
+	require.NoError(t, err)
+	verified, _, err := ProveMultiCred(orgs, policies, nil, proofs, nonce)
+	require.NoError(t, err)
+	assert.True(t, verified, "multi-credential proof not accepted")
+
+	// the nonce can be used only once
+	_, _, err = ProveMultiCred(orgs, policies, nil, proofs, nonce)
+	assert.Error(t, err, "multi-credential proof should not be accepted twice")
+	// the nonce is stored again to check the other conditions
+	reuseNonce := func() {
+		require.NoError(t, org1.Nonces.StoreNonce(NonceProve, nonce, NonceTTL))
+	}
+
+	// each proof needs to satisfy the policy for its organization
+	reuseNonce()
+	_, _, err = ProveMultiCred(orgs, []*VerificationPolicy{policy2, policy1}, nil, proofs, nonce)
+	assert.Error(t, err, "multi-credential proof not satisfying the policies should not be accepted")
+
+	// the proof is bound to the nonce
+	otherNonce, err := org1.GetProveCredNonce()
+	require.NoError(t, err)
+	_, _, err = ProveMultiCred(orgs, nil, nil, proofs, otherNonce)
+	assert.Error(t, err, "multi-credential proof with a different nonce should not be accepted")
+
+	// proofs cannot be verified against wrong organizations
+	reuseNonce()
+	verified, _, _ = ProveMultiCred([]*Org{org2, org1}, nil, nil, proofs, nonce)
+	assert.False(t, verified, "multi-credential proof with swapped organizations should not be accepted")
+
+	// when the policies require a domain pseudonym, it is proved for each credential
+	scope := []byte("e-voting")
+	scoped := func(p *CredPresentation) *CredPresentation {
+		sp := *p
+		sp.Scope = scope
+		return &sp
+	}
+	scopedPolicy1, scopedPolicy2 := *policy1, *policy2
+	scopedPolicy1.Scope, scopedPolicy2.Scope = scope, scope
+	scopedPolicies := []*VerificationPolicy{&scopedPolicy1, &scopedPolicy2}
+	_, _, err = ProveMultiCred(orgs, scopedPolicies, nil, proofs, otherNonce)
+	assert.Error(t, err, "multi-credential proof without domain pseudonyms should not be accepted")
+	nonce, err = org1.GetProveCredNonce()
+	require.NoError(t, err)
+	proofs, err = BuildMultiProof([]*CredPresentation{scoped(p1), scoped(p2)}, nil, nonce)
+	require.NoError(t, err)
+	verified, pseudonyms, err := ProveMultiCred(orgs, scopedPolicies, nil, proofs, nonce)
+	require.NoError(t, err)
+	assert.True(t, verified, "multi-credential proof with domain pseudonyms not accepted")
+	require.Len(t, pseudonyms, 2)
+	assert.Equal(t, proofs[0].DomainPseudonymProof.Pseudonym, pseudonyms[0])
+	assert.Equal(t, proofs[1].DomainPseudonymProof.Pseudonym, pseudonyms[1])
+
+	// when the policies require commitment equality proofs, they need to be proved for
+	// the commitments of the policies (Gender of the first credential is committed)
+	pedersenParams, err := pedersen.GenerateParams(256)
+	require.NoError(t, err)
+	committer := pedersen.NewCommitter(pedersenParams)
+	c, err := committer.GetCommitMsg(credMgr1.Attrs.Known[1])
+	require.NoError(t, err)
+	_, r := committer.GetDecommitMsg()
+	equalityPolicy1 := *policy1
+	equalityPolicy1.CommitmentEqualities = []*CommitmentEquality{
+		NewCommitmentEquality(1, pedersenParams, c),
+	}
+	equalityPolicies := []*VerificationPolicy{&equalityPolicy1, policy2}
+	nonce, err = org1.GetProveCredNonce()
+	require.NoError(t, err)
+	proofs, err = BuildMultiProof([]*CredPresentation{p1, p2}, nil, nonce)
+	require.NoError(t, err)
+	_, _, err = ProveMultiCred(orgs, equalityPolicies, nil, proofs, nonce)
+	assert.Error(t, err, "multi-credential proof without commitment equality proof should not be accepted")
+	equality := NewCommitmentEquality(1, pedersenParams, c)
+	equality.SetRandomness(r)
+	equalityP1 := *p1
+	equalityP1.CommitmentEqualities = []*CommitmentEquality{equality}
+	nonce, err = org1.GetProveCredNonce()
+	require.NoError(t, err)
+	proofs, err = BuildMultiProof([]*CredPresentation{&equalityP1, p2}, nil, nonce)
+	require.NoError(t, err)
+	verified, _, err = ProveMultiCred(orgs, equalityPolicies, nil, proofs, nonce)
+	require.NoError(t, err)
+	assert.True(t, verified, "multi-credential proof with commitment equality proof not accepted")
+
+	// credentials with different master secrets cannot be proved together
+	credMgr3, cred3 := issue(org2, org2.Keys.Pub.GenerateUserMasterSecret(), "Jill")
+	p3 := NewCredPresentation(credMgr3, cred3, []int{0}, []int{})
+	nonce, err = org1.GetProveCredNonce()
+	require.NoError(t, err)
+	proofs, err = BuildMultiProof([]*CredPresentation{p1, p3}, nil, nonce)
+	require.NoError(t, err)
+	verified, _, err = ProveMultiCred(orgs, nil, nil, proofs, nonce)
+	assert.Error(t, err, "credentials with different master secrets should not be accepted")
+	assert.False(t, verified, "credentials with different master secrets should not be accepted")
+
+	// a credential without the master secret cannot be part of a multi-credential proof
+	org4 := newTestOrg(t, NewAttrCount(5, 1, 0), 0)
+	credMgr4, cred4 := issue(org4, masterSecret, "Jack")
+	p4 := NewCredPresentation(credMgr4, cred4, []int{0}, []int{})
+	_, err = BuildMultiProof([]*CredPresentation{p1, p4}, nil, org1.GenNonce())
+	assert.Error(t, err, "credential without master secret should not be proved")
+}
+
+func TestMultiCredAttrEquality(t *testing.T) {
+	clinicAttrCount := NewAttrCount(2, 0, 1)
+	passportAttrCount := NewAttrCount(3, 0, 1)
+
+	clinic := newTestOrg(t, clinicAttrCount, 0)
+	passportIssuer := newTestOrg(t, passportAttrCount, 1)
+	passportIssuer.Nonces = clinic.Nonces
+
+	issue := func(org *Org, rawCred *RawCred, masterSecret *big.Int) (*CredManager, *Cred) {
+		credMgr, res := issueTestCred(t, org, rawCred, masterSecret)
+		return credMgr, res.Cred
+	}
+	clinicCred := func(name string) *RawCred {
+		rawCred := NewRawCred(clinicAttrCount)
+		_ = rawCred.AddInt64Attr("PatientID", 1234, true)
+		_ = rawCred.AddStrAttr("Name", name, true)
+		return rawCred
+	}
+	passportCred := func(name string) *RawCred {
+		rawCred := NewRawCred(passportAttrCount)
+		_ = rawCred.AddStrAttr("FullName", name, true)
+		_ = rawCred.AddStrAttr("Nationality", "SI", true)
+		_ = rawCred.AddInt64Attr("Number", 1234, true)
+		return rawCred
+	}
+
+	masterSecret := clinic.Keys.Pub.GenerateUserMasterSecret()
+	clinicMgr, cred1 := issue(clinic, clinicCred("Jack"), masterSecret)
+	passportMgr, cred2 := issue(passportIssuer, passportCred("Jack"), masterSecret)
+	otherPassportMgr, cred3 := issue(passportIssuer, passportCred("John"), masterSecret)
+
+	// the verifier knows the structure of the credentials through the policies
+	clinicPolicy, err := NewVerificationPolicy(credAttrs(clinicCred("")), nil, nil)
+	require.NoError(t, err)
+	passportPolicy, err := NewVerificationPolicy(credAttrs(passportCred("")), []string{"Nationality"},
+		nil)
+	require.NoError(t, err)
+	orgs := []*Org{clinic, passportIssuer}
+	policies := []*VerificationPolicy{clinicPolicy, passportPolicy}
+
+	prove := func(passportMgr *CredManager, passportCred *Cred,
+		userEqualities, verifierEqualities []*AttrEquality) (bool, error) {
+		presentations := []*CredPresentation{
+			NewCredPresentation(clinicMgr, cred1, []int{}, []int{}),
+			NewCredPresentation(passportMgr, passportCred, []int{1}, []int{}),
+		}
+		nonce, err := clinic.GetProveCredNonce()
+		require.NoError(t, err)
+		proofs, err := BuildMultiProof(presentations, userEqualities, nonce)
+		require.NoError(t, err)
+
+		verified, _, err := ProveMultiCred(orgs, policies, verifierEqualities, proofs, nonce)
+		return verified, err
+	}
+
+	names := []*AttrEquality{NewAttrEquality(0, "Name", 1, "FullName")}
+	verified, err := prove(passportMgr, cred2, names, names)
+	require.NoError(t, err)
+	assert.True(t, verified, "equal attributes not accepted")
+
+	// equalities sharing an attribute are proved together
+	numbers := []*AttrEquality{
+		NewAttrEquality(0, "PatientID", 1, "Number"),
+		NewAttrEquality(1, "Number", 0, "PatientID"),
+		NewAttrEquality(0, "Name", 1, "FullName"),
+	}
+	verified, err = prove(passportMgr, cred2, numbers, numbers)
+	require.NoError(t, err)
+	assert.True(t, verified, "equal attributes not accepted")
+
+	verified, err = prove(otherPassportMgr, cred3, names, names)
+	assert.Error(t, err, "different attributes should not be accepted")
+	assert.False(t, verified, "different attributes should not be accepted")
+
+	// the verifier needs to check the equality which was proved
+	_, err = prove(passportMgr, cred2, nil, names)
+	assert.Error(t, err, "proof without the equality should not be accepted")
+	_, err = prove(passportMgr, cred2, names,
+		[]*AttrEquality{NewAttrEquality(0, "PatientID", 1, "Number")})
+	assert.Error(t, err, "proof of other equality should not be accepted")
+
+	// only unrevealed known attributes of the same type can be compared
+	for _, e := range []*AttrEquality{
+		NewAttrEquality(0, "Name", 1, "Nationality"),
+		NewAttrEquality(0, "Name", 1, "Number"),
+		NewAttrEquality(0, "Name", 2, "FullName"),
+		NewAttrEquality(0, "Name", 1, "Address"),
+	} {
+		_, err = BuildMultiProof([]*CredPresentation{
+			NewCredPresentation(clinicMgr, cred1, []int{}, []int{}),
+			NewCredPresentation(passportMgr, cred2, []int{1}, []int{}),
+		}, []*AttrEquality{e}, clinic.GenNonce())
+		assert.Error(t, err, "proof of equality %v should not be built", e)
+	}
+}
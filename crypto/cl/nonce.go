@@ -0,0 +1,136 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"container/heap"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// NonceTTL is the time in which the nonce issued by the organization needs to be used.
+const NonceTTL = 5 * time.Minute
+
+// NoncePurpose denotes the protocol in which the nonce issued by the organization needs
+// to be used, so that a nonce issued for one protocol cannot be used in another.
+type NoncePurpose string
+
+const (
+	// NonceIssue is the purpose of the nonces for credential requests (see GetCredIssueNonce).
+	NonceIssue NoncePurpose = "issue"
+	// NonceProve is the purpose of the nonces for credential proofs (see GetProveCredNonce).
+	NonceProve NoncePurpose = "prove"
+)
+
+// NonceStore keeps the nonces issued by the organization (see GetCredIssueNonce and
+// GetProveCredNonce) until they are used or expire. Each nonce can be used only once and
+// only for the purpose for which it was issued, so that a credential request or a credential
+// proof cannot be replayed. The nonces are random for each protocol execution, so a nonce
+// together with its purpose identifies the execution in which it can be used.
+// The store can be shared by several organizations and by concurrent protocol
+// executions.
+type NonceStore interface {
+	// StoreNonce stores the nonce for the given purpose which expires after the given
+	// time, returning error in case the nonce was not successfully stored.
+	StoreNonce(NoncePurpose, *big.Int, time.Duration) error
+
+	// UseNonce removes the nonce for the given purpose from the store. It returns false
+	// if the nonce is not in the store (it was never issued for the purpose, it has
+	// already been used or it has expired).
+	UseNonce(NoncePurpose, *big.Int) (bool, error)
+}
+
+// nonceID returns the ID of the nonce issued for the purpose.
+func nonceID(purpose NoncePurpose, nonce *big.Int) string {
+	return string(purpose) + ":" + nonce.String()
+}
+
+// nonceExpiry is an entry of the queue of nonces ordered by their expiry.
+type nonceExpiry struct {
+	id     string
+	expiry time.Time
+}
+
+// nonceQueue is a min-heap of nonces by their expiry (see container/heap).
+type nonceQueue []nonceExpiry
+
+func (q nonceQueue) Len() int            { return len(q) }
+func (q nonceQueue) Less(i, j int) bool  { return q[i].expiry.Before(q[j].expiry) }
+func (q nonceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nonceQueue) Push(x interface{}) { *q = append(*q, x.(nonceExpiry)) }
+func (q *nonceQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
+
+// MemNonceStore keeps the nonces in memory. Expired nonces are removed when a new
+// nonce is stored - only the nonces which have expired are visited, as they are
+// taken from a queue ordered by expiry, so storing a nonce does not depend on the
+// number of stored nonces (apart from the logarithmic cost of the queue).
+type MemNonceStore struct {
+	nonces map[string]time.Time
+	queue  nonceQueue
+	mutex  sync.Mutex
+}
+
+func NewMemNonceStore() *MemNonceStore {
+	return &MemNonceStore{
+		nonces: make(map[string]time.Time),
+	}
+}
+
+func (s *MemNonceStore) StoreNonce(purpose NoncePurpose, nonce *big.Int,
+	ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	for len(s.queue) > 0 && now.After(s.queue[0].expiry) {
+		e := heap.Pop(&s.queue).(nonceExpiry)
+		// the nonce may have been used (and stored again with a different expiry)
+		if expiry, ok := s.nonces[e.id]; ok && expiry.Equal(e.expiry) {
+			delete(s.nonces, e.id)
+		}
+	}
+
+	id, expiry := nonceID(purpose, nonce), now.Add(ttl)
+	s.nonces[id] = expiry
+	heap.Push(&s.queue, nonceExpiry{id: id, expiry: expiry})
+
+	return nil
+}
+
+func (s *MemNonceStore) UseNonce(purpose NoncePurpose, nonce *big.Int) (bool, error) {
+	if nonce == nil {
+		return false, nil
+	}
+
+	id := nonceID(purpose, nonce)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	expiry, ok := s.nonces[id]
+	if !ok {
+		return false, nil
+	}
+	delete(s.nonces, id)
+
+	return !time.Now().After(expiry), nil
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemNonceStore(t *testing.T) {
+	store := NewMemNonceStore()
+	nonce := big.NewInt(42)
+
+	ok, err := store.UseNonce(NonceProve, nonce)
+	require.NoError(t, err)
+	assert.False(t, ok, "nonce which was not stored should not be usable")
+
+	require.NoError(t, store.StoreNonce(NonceProve, nonce, time.Minute))
+	ok, err = store.UseNonce(NonceIssue, nonce)
+	require.NoError(t, err)
+	assert.False(t, ok, "nonce should not be usable for another purpose")
+	ok, err = store.UseNonce(NonceProve, nonce)
+	require.NoError(t, err)
+	assert.True(t, ok, "stored nonce should be usable")
+	ok, err = store.UseNonce(NonceProve, nonce)
+	require.NoError(t, err)
+	assert.False(t, ok, "nonce should be usable only once")
+
+	require.NoError(t, store.StoreNonce(NonceProve, nonce, time.Millisecond))
+	time.Sleep(10 * time.Millisecond)
+	ok, err = store.UseNonce(NonceProve, nonce)
+	require.NoError(t, err)
+	assert.False(t, ok, "expired nonce should not be usable")
+
+	// expired nonces are removed when new nonces are stored, the others are kept
+	for i := 0; i < 10; i++ {
+		require.NoError(t, store.StoreNonce(NonceIssue, big.NewInt(int64(i)), time.Millisecond))
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, store.StoreNonce(NonceIssue, nonce, time.Minute))
+	assert.Len(t, store.nonces, 1)
+	ok, err = store.UseNonce(NonceIssue, nonce)
+	require.NoError(t, err)
+	assert.True(t, ok, "nonce which has not expired should be usable")
+}
+
+func TestNoncePurpose(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 0)
+	org := newTestOrg(t, attrCount, 0)
+
+	// a nonce issued for the credential request cannot be used for a credential proof
+	credMgr, res := issueTestCred(t, org, newTestRawCred(t, attrCount, "Jack"), nil)
+	issueNonce, err := org.GetCredIssueNonce()
+	require.NoError(t, err)
+	proof, err := credMgr.BuildProof(res.Cred, []int{0}, []int{}, nil, issueNonce)
+	require.NoError(t, err)
+	_, _, err = org.ProveCred(proof, nil, issueNonce)
+	assert.Error(t, err, "nonce for credential request should not be usable for a proof")
+
+	// and a nonce issued for a credential proof cannot be used for a credential request
+	proveNonce, err := org.GetProveCredNonce()
+	require.NoError(t, err)
+	credReq, err := credMgr.GetCredRequest(proveNonce)
+	require.NoError(t, err)
+	_, err = org.IssueCred(credReq, proveNonce)
+	assert.Error(t, err, "nonce for credential proof should not be usable for a request")
+}
+
+// TestOrgConcurrent checks that one organization can issue and verify credentials
+// in concurrent protocol executions.
+func TestOrgConcurrent(t *testing.T) {
+	params := GetDefaultParamSizes()
+	attrCount := NewAttrCount(2, 1, 0)
+
+	org := newTestOrg(t, attrCount, 0)
+
+	// the nonces are obtained before any of them is used, so that the executions overlap
+	n := 4
+	issueNonces := make([]*big.Int, n)
+	proveNonces := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		var err error
+		issueNonces[i], err = org.GetCredIssueNonce()
+		require.NoError(t, err)
+		proveNonces[i], err = org.GetProveCredNonce()
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			rawCred := NewRawCred(attrCount)
+			_ = rawCred.AddStrAttr("Name", fmt.Sprintf("User%d", i), true)
+			_ = rawCred.AddStrAttr("Gender", "M", true)
+			_ = rawCred.AddInt64Attr("Age", int64(20+i), false)
+			credMgr, err := NewCredManager(params, org.Keys.Pub,
+				org.Keys.Pub.GenerateUserMasterSecret(), rawCred)
+			if !assert.NoError(t, err) {
+				return
+			}
+			credReq, err := credMgr.GetCredRequest(issueNonces[i])
+			if !assert.NoError(t, err) {
+				return
+			}
+			res, err := org.IssueCred(credReq, issueNonces[i])
+			if !assert.NoError(t, err) {
+				return
+			}
+			if !assert.NoError(t, credMgr.SetWitness(res.Cred, res.Witness)) {
+				return
+			}
+
+			revealed := []int{0}
+			proof, err := credMgr.BuildProof(res.Cred, revealed, []int{}, nil, proveNonces[i])
+			if !assert.NoError(t, err) {
+				return
+			}
+			verified, _, err := org.ProveCred(proof, nil, proveNonces[i])
+			assert.NoError(t, err)
+			assert.True(t, verified, "credential proof in concurrent execution %d failed", i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -18,6 +18,7 @@
 package cl
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -26,7 +27,6 @@ import (
 	"encoding/gob"
 	"os"
 
-	"github.com/xlab-si/emmy/config"
 	"github.com/xlab-si/emmy/crypto/common"
 	"github.com/xlab-si/emmy/crypto/df"
 	"github.com/xlab-si/emmy/crypto/pedersen"
@@ -34,20 +34,37 @@ import (
 	"github.com/xlab-si/emmy/crypto/schnorr"
 )
 
+// Org holds no state of the protocol executions (the nonces are kept in Nonces), so
+// one instance can be shared by concurrent issuances and proofs of credentials.
 type Org struct {
-	Params             *Params
-	Group              *qr.RSASpecial     // in this group attributes will be used as exponents (basis is PubKey.Rs...)
-	pedersenReceiver   *pedersen.Receiver // used for nyms (nym is Pedersen commitment)
-	nym                *big.Int
-	nymVerifier        *schnorr.Verifier
-	U                  *big.Int
-	UVerifier          *qr.RepresentationVerifier
-	Keys               *KeyPair
-	commitmentsOfAttrs []*big.Int
-	knownAttrs         []*big.Int
-	attrsVerifiers     []*df.OpeningVerifier // user proves the knowledge of commitment opening (committedAttrs)
-	credIssueNonceOrg  *big.Int
-	proveCredNonceOrg  *big.Int
+	Params           *Params
+	Group            *qr.RSASpecial     // in this group attributes will be used as exponents (basis is PubKey.Rs...)
+	pedersenReceiver *pedersen.Receiver // used for nyms (nym is Pedersen commitment)
+	Keys             *KeyPair
+	// Nonces keeps the issued nonces until they are used or expire. By default the nonces
+	// are kept in memory, a store shared by several organizations (or several instances
+	// of the organization) can be set instead.
+	Nonces NonceStore
+	// Accumulator contains all non-revoked credentials. It is nil when the keys
+	// do not support revocation.
+	Accumulator *Accumulator
+	// EscrowRecords stores the ciphertexts of the attributes escrowed in credential proofs
+	// (needed only when the organization requires identity escrow).
+	EscrowRecords EscrowRecordManager
+	// AccumulatorRecords stores the updates of the accumulator, so that the revocations
+	// are not lost when the organization is loaded again (see LoadAccumulator). When it is
+	// nil, the accumulator is kept only in memory.
+	AccumulatorRecords AccumulatorRecordManager
+	// Signer computes the signatures of the issued credentials when the secret key of the
+	// issuer is split among several parties (see ThresholdSigner). When it is nil, the
+	// organization signs the credentials with its own secret key.
+	Signer CredSigner
+}
+
+// CredSigner computes A = Q^(1/e) of a new credential together with the proof that
+// A has been computed correctly (bound to the nonce of the user).
+type CredSigner interface {
+	SignCred(Q, e, nonceUser *big.Int) (*big.Int, *qr.RepresentationProof, error)
 }
 
 func NewOrg(params *Params, attrCount *AttrCount) (*Org, error) {
@@ -61,9 +78,13 @@ func NewOrg(params *Params, attrCount *AttrCount) (*Org, error) {
 
 // FIXME
 func NewOrgFromParams(params *Params, keys *KeyPair) (*Org, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
 	var group *qr.RSASpecial
 	var err error
-	if keys.Sec != nil {
+	if keys.Sec != nil && keys.Sec.RsaPrimes != nil {
 		group, err = qr.NewRSASpecialFromParams(keys.Sec.RsaPrimes)
 		if err != nil {
 			return nil, fmt.Errorf("error when creating RSASpecial group: %s", err)
@@ -71,36 +92,52 @@ func NewOrgFromParams(params *Params, keys *KeyPair) (*Org, error) {
 	} else {
 		// ProveCL requires only Pub key which means some organization can check the validity of
 		// credential only using public key of the organization that issued a credential.
+		// The secret key of the coordinator of threshold issuance does not contain RsaPrimes.
 		group = qr.NewRSApecialPublic(keys.Pub.N)
 	}
 
 	pedersenReceiver := pedersen.NewReceiverFromParams(keys.Pub.PedersenParams)
 
+	var acc *Accumulator
+	if keys.Pub.Accumulator != nil {
+		var accPrimes *qr.RSASpecialPrimes
+		if keys.Sec != nil {
+			accPrimes = keys.Sec.AccumulatorPrimes
+		}
+		acc, err = NewAccumulator(keys.Pub.Accumulator, accPrimes)
+		if err != nil {
+			return nil, fmt.Errorf("error when creating revocation accumulator: %s", err)
+		}
+	}
+
 	return &Org{
 		Params:           params,
 		Keys:             keys,
 		Group:            group,
 		pedersenReceiver: pedersenReceiver,
+		Nonces:           NewMemNonceStore(),
+		Accumulator:      acc,
 	}, nil
 }
 
-// FIXME
+// LoadOrg loads the organization from the key files written by WriteKeyPair. When
+// secKeyPath is empty, only the public key is loaded - such organization can only
+// verify credential proofs.
 func LoadOrg(pubKeyPath, secKeyPath string) (*Org, error) {
-	pubKey := new(PubKey)
-	if err := ReadGob(pubKeyPath, pubKey); err != nil {
-		return nil, err
-	}
-	secKey := new(SecKey)
-	if err := ReadGob(secKeyPath, secKey); err != nil {
+	params, pubKey, err := ReadPubKey(pubKeyPath)
+	if err != nil {
 		return nil, err
 	}
-
 	keys := &KeyPair{
-		Sec: secKey,
 		Pub: pubKey,
 	}
+	if secKeyPath != "" {
+		keys.Sec, err = ReadSecKey(secKeyPath, pubKey)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	params := GetDefaultParamSizes()
 	org, err := NewOrgFromParams(params, keys)
 	if err != nil {
 		return nil, fmt.Errorf("error when loading CL org: %v", err)
@@ -116,6 +153,32 @@ func (o *Org) GenNonce() *big.Int {
 	return common.GetRandomInt(b)
 }
 
+// genStoredNonce generates a nonce and stores it to Nonces, so that it can be used
+// (once) for the given purpose within NonceTTL.
+func (o *Org) genStoredNonce(purpose NoncePurpose) (*big.Int, error) {
+	nonce := o.GenNonce()
+	if err := o.Nonces.StoreNonce(purpose, nonce, NonceTTL); err != nil {
+		return nil, fmt.Errorf("error when storing nonce: %v", err)
+	}
+
+	return nonce, nil
+}
+
+// useNonce removes the nonce from Nonces. It fails if the nonce was not issued by
+// the organization for the given purpose, if it has already been used or if it has
+// expired.
+func (o *Org) useNonce(purpose NoncePurpose, nonce *big.Int) error {
+	ok, err := o.Nonces.UseNonce(purpose, nonce)
+	if err != nil {
+		return fmt.Errorf("error when checking nonce: %v", err)
+	}
+	if !ok {
+		return fmt.Errorf("nonce is not valid, it has already been used or it has expired")
+	}
+
+	return nil
+}
+
 func (o *Org) genCredRandoms() (*big.Int, *big.Int) {
 	exp := big.NewInt(int64(o.Params.EBitLen - 1))
 	b := new(big.Int).Exp(big.NewInt(2), exp, nil)
@@ -128,10 +191,9 @@ func (o *Org) genCredRandoms() (*big.Int, *big.Int) {
 		}
 	}
 
-	vr, _ := rand.Prime(rand.Reader, int(o.Params.VBitLen)-1)
-	exp = big.NewInt(int64(o.Params.VBitLen - 1))
-	b = new(big.Int).Exp(big.NewInt(2), exp, nil)
-	v11 := new(big.Int).Add(vr, b)
+	// unlike e, v11 only needs to be a random number of length VBitLen (generating
+	// a prime of this length was the slowest part of the issuance)
+	v11 := common.GetRandomIntOfLength(o.Params.VBitLen)
 
 	return e, v11
 }
@@ -147,221 +209,589 @@ func (o *Org) genAProof(nonceUser, context, eInv, Q, A *big.Int) *qr.Representat
 	return qr.NewRepresentationProof(proofRandomData, challenge, proofData)
 }
 
+// signCred computes A = Q^(1/e) and the proof for it, either with Signer or with the secret key.
+func (o *Org) signCred(Q, e, nonceUser *big.Int) (*big.Int, *qr.RepresentationProof, error) {
+	if o.Signer != nil {
+		A, AProof, err := o.Signer.SignCred(Q, e, nonceUser)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error when signing credential: %v", err)
+		}
+		return A, AProof, nil
+	}
+	if o.Group.P1 == nil {
+		return nil, nil, fmt.Errorf("the organization cannot sign credentials without secret key")
+	}
+
+	phiN := new(big.Int).Mul(o.Group.P1, o.Group.Q1)
+	eInv := new(big.Int).ModInverse(e, phiN)
+	A := o.Group.Exp(Q, eInv)
+
+	context := o.Keys.Pub.GetContext()
+	return A, o.genAProof(nonceUser, context, eInv, Q, A), nil
+}
+
 type CredResult struct {
 	Cred   *Cred
 	AProof *qr.RepresentationProof
 	Record *ReceiverRecord
+	// Witness is nil when the organization does not support revocation
+	Witness *Witness
 }
 
-func (o *Org) IssueCred(cr *CredRequest) (*CredResult, error) {
-	o.nymVerifier = schnorr.NewVerifier(o.pedersenReceiver.Params.Group)
-	o.UVerifier = qr.NewRepresentationVerifier(o.Group, int(o.Params.SecParam))
+// IssueCred issues the credential for the credential request cr, which needs to be
+// computed for nonceOrg (obtained from GetCredIssueNonce). The nonce can be used only once.
+func (o *Org) IssueCred(cr *CredRequest, nonceOrg *big.Int) (*CredResult, error) {
+	if err := o.useNonce(NonceIssue, nonceOrg); err != nil {
+		return nil, err
+	}
 
-	o.nym = cr.Nym
-	o.knownAttrs = cr.KnownAttrs
-	err := o.setUpAttrVerifiers(cr.CommitmentsOfAttrs)
+	attrsVerifiers, err := o.getAttrsVerifiers(cr.CommitmentsOfAttrs)
 	if err != nil {
 		return nil, err
 	}
-	o.U = cr.U
 
-	if verified := o.verifyCredRequest(cr); !verified {
-		return nil, fmt.Errorf("credential request not valid")
+	if err := o.verifyCredRequest(cr, nonceOrg, attrsVerifiers); err != nil {
+		return nil, fmt.Errorf("credential request not valid: %s", err)
 	}
 
 	e, v11 := o.genCredRandoms()
 
 	// denom = U * S^v11 * R_1^attr_1 * ... * R_j^attr_j where only attributes from knownAttrs and committedAttrs
 	acc := big.NewInt(1)
-	for ind := 0; ind < len(o.knownAttrs); ind++ {
-		t1 := o.Group.Exp(o.Keys.Pub.RsKnown[ind], o.knownAttrs[ind])
+	for ind := 0; ind < len(cr.KnownAttrs); ind++ {
+		t1 := o.Group.Exp(o.Keys.Pub.RsKnown[ind], cr.KnownAttrs[ind])
 		acc = o.Group.Mul(acc, t1)
 	}
 
-	for ind := 0; ind < len(o.commitmentsOfAttrs); ind++ {
-		t1 := o.Group.Exp(o.Keys.Pub.RsCommitted[ind], o.commitmentsOfAttrs[ind])
+	for ind := 0; ind < len(cr.CommitmentsOfAttrs); ind++ {
+		t1 := o.Group.Exp(o.Keys.Pub.RsCommitted[ind], cr.CommitmentsOfAttrs[ind])
 		acc = o.Group.Mul(acc, t1)
 	}
 
 	t := o.Group.Exp(o.Keys.Pub.S, v11) // s^v11
-	denom := o.Group.Mul(t, o.U)        // U * s^v11
+	denom := o.Group.Mul(t, cr.U)       // U * s^v11
 	denom = o.Group.Mul(denom, acc)     // U * s^v11 * acc
 	denomInv := o.Group.Inv(denom)
 	Q := o.Group.Mul(o.Keys.Pub.Z, denomInv)
 
-	phiN := new(big.Int).Mul(o.Group.P1, o.Group.Q1)
-	eInv := new(big.Int).ModInverse(e, phiN)
-	A := o.Group.Exp(Q, eInv)
-
+	A, AProof, err := o.signCred(Q, e, cr.Nonce) // nonceUser!
+	if err != nil {
+		return nil, err
+	}
 	context := o.Keys.Pub.GetContext()
-	AProof := o.genAProof(cr.Nonce, context, eInv, Q, A) // nonceUser!
+
+	witness, err := o.getWitness(e)
+	if err != nil {
+		return nil, err
+	}
 
 	res := &CredResult{
-		Cred:   NewCred(A, e, v11),
-		AProof: AProof,
-		Record: NewReceiverRecord(o.knownAttrs, o.commitmentsOfAttrs, Q, v11, context),
+		Cred:    NewCred(A, e, v11),
+		AProof:  AProof,
+		Record:  NewReceiverRecord(cr.KnownAttrs, cr.CommitmentsOfAttrs, Q, v11, e, context),
+		Witness: witness,
 	}
 
 	return res, nil
 }
 
-func (o *Org) UpdateCred(nym *big.Int, rec *ReceiverRecord, nonceUser *big.Int, newKnownAttrs []*big.Int) (*CredResult, error) {
-	if o.knownAttrs == nil { // for example when Org is instantiated and there is no call to IssueCred
-		o.knownAttrs = newKnownAttrs
-		o.setUpAttrVerifiers(rec.CommitmentsOfAttrs)
-		o.nymVerifier = schnorr.NewVerifier(o.pedersenReceiver.Params.Group) // pubKey.Params.Group
-		o.UVerifier = qr.NewRepresentationVerifier(o.Group,
-			int(o.Params.SecParam))
+// UpdateCred issues a new credential with the known attributes from the update request ur
+// to the holder of the credential described by the receiver record rec. The request needs
+// to be computed for nonceOrg (obtained from GetCredIssueNonce), which can be used only once,
+// and needs to prove the knowledge of the opening of ur.Nym - rec must be the record stored
+// for this nym. When ur contains commitments of the committed attributes, they replace the
+// commitments in rec - their proofs need to prove the knowledge of their openings (see
+// CredManager.GetCredUpdateRequest). The values of the provisioned known attributes
+// (rec.ProvisionedAttrs) need to remain unchanged. The old credential is revoked.
+func (o *Org) UpdateCred(ur *CredUpdateRequest, rec *ReceiverRecord, nonceOrg *big.Int) (
+	*CredResult, error) {
+	if err := o.useNonce(NonceIssue, nonceOrg); err != nil {
+		return nil, err
+	}
+	// the holder of a revoked credential must not obtain a new (non-revoked) credential
+	if o.Accumulator != nil && rec.E != nil && o.Accumulator.IsRevoked(rec.E) {
+		return nil, fmt.Errorf("credential has been revoked")
+	}
+	if ur.Nym == nil || ur.Nonce == nil || containsNil(ur.NewKnownAttrs...) ||
+		containsNil(ur.CommitmentsOfAttrs...) {
+		return nil, fmt.Errorf("credential update request is not complete")
+	}
+	newKnownAttrs := ur.NewKnownAttrs
+	if len(newKnownAttrs) != len(o.Keys.Pub.RsKnown) || len(rec.KnownAttrs) != len(o.Keys.Pub.RsKnown) {
+		return nil, fmt.Errorf("the number of known attributes does not match the public key")
+	}
+	for _, ind := range rec.ProvisionedAttrs {
+		if ind < 0 || ind >= len(newKnownAttrs) {
+			return nil, fmt.Errorf("invalid index of provisioned attribute: %d", ind)
+		}
+		if newKnownAttrs[ind].Cmp(rec.KnownAttrs[ind]) != 0 {
+			return nil, fmt.Errorf("provisioned attribute %d cannot be changed", ind)
+		}
+	}
+	if err := o.verifyCredUpdateRequest(ur, nonceOrg); err != nil {
+		return nil, err
+	}
+	commitmentsOfAttrs := rec.CommitmentsOfAttrs
+	if ur.CommitmentsOfAttrs != nil {
+		commitmentsOfAttrs = ur.CommitmentsOfAttrs
+	}
+	if len(commitmentsOfAttrs) != len(o.Keys.Pub.RsCommitted) ||
+		len(rec.CommitmentsOfAttrs) != len(o.Keys.Pub.RsCommitted) {
+		return nil, fmt.Errorf("the number of commitments of attributes does not match the public key")
 	}
 
 	e, v11 := o.genCredRandoms()
 	v11Diff := new(big.Int).Sub(v11, rec.V11)
 
 	acc := big.NewInt(1)
-	for ind := 0; ind < len(o.knownAttrs); ind++ {
+	for ind := 0; ind < len(newKnownAttrs); ind++ {
 		t1 := o.Group.Exp(o.Keys.Pub.RsKnown[ind],
 			new(big.Int).Sub(newKnownAttrs[ind], rec.KnownAttrs[ind]))
 		acc = o.Group.Mul(acc, t1)
 	}
+	for ind := 0; ind < len(commitmentsOfAttrs); ind++ {
+		t1 := o.Group.Exp(o.Keys.Pub.RsCommitted[ind],
+			new(big.Int).Sub(commitmentsOfAttrs[ind], rec.CommitmentsOfAttrs[ind]))
+		acc = o.Group.Mul(acc, t1)
+	}
 	t := o.Group.Exp(o.Keys.Pub.S, v11Diff)
 	denom := o.Group.Mul(acc, t)
 	denomInv := o.Group.Inv(denom)
 	newQ := o.Group.Mul(rec.Q, denomInv)
 
-	phiN := new(big.Int).Mul(o.Group.P1, o.Group.Q1)
-	eInv := new(big.Int).ModInverse(e, phiN)
-	newA := o.Group.Exp(newQ, eInv)
-
+	newA, AProof, err := o.signCred(newQ, e, ur.Nonce)
+	if err != nil {
+		return nil, err
+	}
 	context := o.Keys.Pub.GetContext()
-	AProof := o.genAProof(nonceUser, context, eInv, newQ, newA)
-	// currently commitmentsOfAttrs cannot be updated
+
+	// the credential with old attribute values must not be usable anymore
+	if o.Accumulator != nil && rec.E != nil {
+		if _, err := o.revoke(rec.E); err != nil {
+			return nil, fmt.Errorf("error when revoking the old credential: %s", err)
+		}
+	}
+
+	witness, err := o.getWitness(e)
+	if err != nil {
+		return nil, err
+	}
 
 	res := &CredResult{
-		Cred:   NewCred(newA, e, v11),
-		AProof: AProof,
-		Record: NewReceiverRecord(newKnownAttrs, rec.CommitmentsOfAttrs, newQ, v11, context),
+		Cred:    NewCred(newA, e, v11),
+		AProof:  AProof,
+		Record:  NewReceiverRecord(newKnownAttrs, commitmentsOfAttrs, newQ, v11, e, context),
+		Witness: witness,
 	}
+	res.Record.ProvisionedAttrs = rec.ProvisionedAttrs
 
 	return res, nil
 }
 
-func (o *Org) GetProveCredNonce() *big.Int {
-	nonce := o.GenNonce()
-	o.proveCredNonceOrg = nonce
+// getWitness returns a witness for e or nil if revocation is not supported.
+func (o *Org) getWitness(e *big.Int) (*Witness, error) {
+	if o.Accumulator == nil {
+		return nil, nil
+	}
+
+	witness, err := o.Accumulator.GetWitness(e)
+	if err != nil {
+		return nil, fmt.Errorf("error when computing accumulator witness: %s", err)
+	}
+
+	return witness, nil
+}
+
+// RevokeCred revokes the credential described by the receiver record rec. The returned
+// update needs to be applied by the holders of all other credentials before they
+// can prove the possession of a (non-revoked) credential again.
+func (o *Org) RevokeCred(rec *ReceiverRecord) (*AccumulatorUpdate, error) {
+	if o.Accumulator == nil {
+		return nil, fmt.Errorf("revocation is not supported by the organization")
+	}
+	if rec.E == nil {
+		return nil, fmt.Errorf("receiver record does not contain e")
+	}
 
-	return nonce
+	return o.revoke(rec.E)
 }
 
-// ProveCred proves the possession of a valid credential and reveals only the attributes the user desires
-// to reveal. Which knownAttrs and commitmentsOfAttrs are to be revealed are given by revealedKnownAttrsIndices and
-// revealedCommitmentsOfAttrsIndices parameters. Parameters knownAttrs and commitmentsOfAttrs must contain only
-// known attributes and commitments of attributes (of attributes for which only commitment is known) which are
-// to be revealed to the organization.
-func (o *Org) ProveCred(A *big.Int, proof *qr.RepresentationProof,
-	revealedKnownAttrsIndices, revealedCommitmentsOfAttrsIndices []int,
-	revealedKnownAttrs, revealedCommitmentsOfAttrs []*big.Int) (bool, error) {
+// revoke removes e from the accumulator, storing the update in AccumulatorRecords
+// (when it is set) before the accumulator is changed.
+func (o *Org) revoke(e *big.Int) (*AccumulatorUpdate, error) {
+	if o.AccumulatorRecords == nil {
+		return o.Accumulator.Revoke(e)
+	}
+
+	return o.Accumulator.revoke(e, func(u *AccumulatorUpdate) error {
+		return o.AccumulatorRecords.StoreAccumulatorUpdate(o.Keys.Pub.GetID(), u)
+	})
+}
 
-	structure, err := config.LoadCredentialStructure()
+// LoadAccumulator restores the state of the accumulator (its value and the revoked
+// credentials) from the updates stored in AccumulatorRecords. It needs to be called
+// before the organization issues or verifies credentials.
+func (o *Org) LoadAccumulator() error {
+	if o.Accumulator == nil || o.AccumulatorRecords == nil {
+		return nil
+	}
+	updates, err := o.AccumulatorRecords.LoadAccumulatorUpdates(o.Keys.Pub.GetID())
 	if err != nil {
-		return false, err
+		return fmt.Errorf("error when loading accumulator updates: %v", err)
+	}
+	epoch, _ := o.Accumulator.GetValue()
+	if epoch > len(updates) {
+		return fmt.Errorf("accumulator is in epoch %d, but only %d updates are stored",
+			epoch, len(updates))
 	}
 
-	attrs, _, err := ParseAttrs(structure)
+	return o.Accumulator.ApplyUpdates(updates[epoch:])
+}
+
+// GetProveCredNonce generates a nonce for a credential proof (see ProveCred).
+func (o *Org) GetProveCredNonce() (*big.Int, error) {
+	return o.genStoredNonce(NonceProve)
+}
+
+// ProveCred verifies the proof of the possession of a valid credential, which reveals only the
+// attributes the user desires to reveal (p.RevealedKnownAttrs and p.RevealedCommitmentsOfAttrs,
+// given by p.RevealedKnownAttrsIndices and p.RevealedCommitmentsOfAttrsIndices).
+// When the organization supports revocation, p.NonRevProof needs to prove that the credential has
+// not been revoked (with respect to the current accumulator value). Conditions on attributes which
+// are not revealed can be satisfied by predicate and set membership proofs.
+// When policy is not nil, the proof needs to satisfy it (see VerificationPolicy.Check) as well as
+// the requirements of the verifier set in the policy:
+// When policy.Scope is not nil, p.DomainPseudonymProof needs to prove that its pseudonym for the
+// scope is derived from the master secret in the credential. The pseudonym is returned, so that
+// the organization can recognize the credential when it is shown again for the same scope.
+// When policy.Escrow is not nil, p.EscrowProof needs to prove that the attribute is encrypted as
+// required - the ciphertext is then stored in EscrowRecords, so that the inspector can decrypt
+// it later.
+// p.CommitmentEqualityProofs need to prove (in the same order) that the commitments of
+// policy.CommitmentEqualities hide the corresponding unrevealed known attributes.
+// The proof needs to be computed for nonceOrg (obtained from GetProveCredNonce), which can be
+// used only once.
+func (o *Org) ProveCred(p *CredProof, policy *VerificationPolicy, nonceOrg *big.Int) (bool,
+	*big.Int, error) {
+	if err := o.useNonce(NonceProve, nonceOrg); err != nil {
+		return false, nil, err
+	}
+	if err := checkCredProofStructure(o.Keys.Pub, p); err != nil {
+		return false, nil, err
+	}
+
+	pseudonym, err := o.checkProofPolicy(p, policy)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 
-	knownAttrs := make([]CredAttr, 0)
-	revealedIndices := make([]int, 0) // indices of known attributes with all attributes taken into account
-	count := 0
-	for _, a := range attrs { // attrs are ordered by index, so knownAttrs will be too
-		if a.IsKnown() {
-			knownAttrs = append(knownAttrs, a)
-			revealedIndices = append(revealedIndices, count)
-		}
-		count++
+	accValue, err := o.checkChallenge(p, nonceOrg)
+	if err != nil {
+		return false, nil, err
+	}
+
+	verified, err := o.verifyCredProof(p, accValue)
+	if err != nil || !verified {
+		return false, nil, err
+	}
+
+	if err := o.storeEscrowRecord(p, policy); err != nil {
+		return false, nil, err
+	}
+
+	return true, pseudonym, nil
+}
+
+// checkProofPolicy checks that the credential proof contains the domain pseudonym, the
+// escrowed attribute and the commitment equality proofs required by policy (which can be
+// nil) and that it satisfies the policy. The domain pseudonym is returned (nil when the
+// policy sets no scope). The escrowed attribute is stored by storeEscrowRecord after the
+// proof is verified.
+func (o *Org) checkProofPolicy(p *CredProof, policy *VerificationPolicy) (*big.Int, error) {
+	var scope []byte
+	var escrow *Escrow
+	var commitmentEqualities []*CommitmentEquality
+	if policy != nil {
+		scope, escrow, commitmentEqualities = policy.Scope, policy.Escrow, policy.CommitmentEqualities
 	}
 
-	conditions, intValues, strValues, err := config.LoadConditions()
+	pseudonym, err := checkDomainPseudonym(p, scope)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	// TODO: check values in a separate component
-	count = 0
-	for _, ind := range revealedKnownAttrsIndices {
-		a := knownAttrs[ind]
-		val, err := a.FromInternalValue(revealedKnownAttrs[count])
-		if err != nil {
-			return false, err
+	if err := o.checkEscrow(p, escrow); err != nil {
+		return nil, err
+	}
+
+	if len(p.CommitmentEqualityProofs) != len(commitmentEqualities) {
+		return nil, fmt.Errorf("the number of commitment equality proofs does not match")
+	}
+	for i, e := range commitmentEqualities {
+		ep := p.CommitmentEqualityProofs[i]
+		if ep == nil || ep.Equality == nil || !ep.Equality.equals(e) {
+			return nil, fmt.Errorf("commitment equality proof %d is for a different "+
+				"commitment or attribute", i)
+		}
+	}
+
+	if policy != nil {
+		if err := policy.Check(p); err != nil {
+			return nil, err
 		}
-		count++
+	}
+
+	return pseudonym, nil
+}
+
+// storeEscrowRecord stores the attribute escrowed in the verified credential proof when
+// policy (which can be nil) requires the escrow.
+func (o *Org) storeEscrowRecord(p *CredProof, policy *VerificationPolicy) error {
+	if policy == nil || policy.Escrow == nil {
+		return nil
+	}
+	if err := o.EscrowRecords.StoreEscrowRecord(NewEscrowRecord(p.EscrowProof)); err != nil {
+		return fmt.Errorf("error when storing escrowed attribute: %v", err)
+	}
 
-		indexAll := revealedIndices[ind]
-		cond := conditions[indexAll]
+	return nil
+}
 
-		switch val.(type) {
-		case int:
-			valInt := val.(int)
-			accVal := intValues[indexAll]
+// checkDomainPseudonym checks that the credential proof contains the domain pseudonym
+// for the scope (when it is not nil) and returns the pseudonym.
+func checkDomainPseudonym(p *CredProof, scope []byte) (*big.Int, error) {
+	if scope == nil {
+		return nil, nil
+	}
+	if p.DomainPseudonymProof == nil {
+		return nil, fmt.Errorf("domain pseudonym proof is missing")
+	}
+	if !bytes.Equal(p.DomainPseudonymProof.Scope, scope) {
+		return nil, fmt.Errorf("domain pseudonym is for a different scope")
+	}
 
-			if cond == "greater" && accVal < valInt {
-				return false, fmt.Errorf("attribute value for %s not acceptable", a.GetName())
-			} else if cond == "lesser" && accVal > valInt {
-				return false, fmt.Errorf("attribute value for %s not acceptable", a.GetName())
-			} else if cond == "equal" && valInt != accVal {
-				return false, fmt.Errorf("attribute value for %s not acceptable", a.GetName())
-			}
-		case string:
-			accVal := strValues[indexAll]
-			if cond == "equal" && accVal != val {
-				return false, fmt.Errorf("attribute value for %s not acceptable", a.GetName())
-			}
+	return p.DomainPseudonymProof.Pseudonym, nil
+}
+
+// checkEscrow checks that the credential proof escrows the attribute as required by
+// escrow (when it is not nil) and that the escrowed attribute can be stored.
+func (o *Org) checkEscrow(p *CredProof, escrow *Escrow) error {
+	if escrow == nil {
+		return nil
+	}
+	if p.EscrowProof == nil {
+		return fmt.Errorf("escrow proof is missing")
+	}
+	if p.EscrowProof.Escrow == nil || !p.EscrowProof.Escrow.equals(escrow) {
+		return fmt.Errorf("attribute is not escrowed as required")
+	}
+	if o.EscrowRecords == nil {
+		return fmt.Errorf("escrowed attributes cannot be stored")
+	}
+
+	return nil
+}
+
+// checkChallenge checks that the challenge of the credential proof is computed from
+// the proof random data (including the data of the accompanying proofs) and nonceOrg.
+// When the organization supports revocation, the current accumulator value is returned.
+func (o *Org) checkChallenge(p *CredProof, nonceOrg *big.Int) (*big.Int, error) {
+	additionalProofRandomData, accValue, err := o.getAdditionalProofRandomData(p)
+	if err != nil {
+		return nil, err
+	}
+
+	context := o.Keys.Pub.GetContext()
+	l := []*big.Int{context, p.Proof.ProofRandomData, nonceOrg}
+	l = append(l, additionalProofRandomData...)
+
+	c := common.Hash(l...) // TODO: function for GetChallenge
+	if p.Proof.Challenge.Cmp(c) != 0 {
+		return nil, fmt.Errorf("challenge is not correct")
+	}
+
+	return accValue, nil
+}
+
+// getAdditionalProofRandomData returns the data of the non-revocation, predicate, set
+// membership, domain pseudonym, escrow and commitment equality proofs which is included in
+// the computation of the challenge. When the organization supports revocation, the current
+// accumulator value is returned as well.
+func (o *Org) getAdditionalProofRandomData(p *CredProof) ([]*big.Int, *big.Int, error) {
+	l := []*big.Int{}
+
+	var accValue *big.Int
+	if o.Accumulator != nil {
+		if p.NonRevProof == nil {
+			return nil, nil, fmt.Errorf("non-revocation proof is missing")
+		}
+		if len(p.Proof.ProofData) < 2 {
+			return nil, nil, fmt.Errorf("credential proof data is not complete")
+		}
+		var epoch int
+		epoch, accValue = o.Accumulator.GetValue()
+		if p.NonRevProof.Epoch != epoch {
+			return nil, nil, fmt.Errorf("non-revocation proof is for accumulator epoch %d, "+
+				"current epoch is %d", p.NonRevProof.Epoch, epoch)
+		}
+		l = append(l, p.NonRevProof.Commitments...)
+		l = append(l, p.NonRevProof.ProofRandomData...)
+	}
+	for _, pp := range p.PredicateProofs {
+		if err := o.checkUnrevealedKnownAttr(p, "predicate proof", pp.Predicate.AttrIndex); err != nil {
+			return nil, nil, err
+		}
+		l = append(l, pp.challengeData()...)
+	}
+	for _, sp := range p.SetMembershipProofs {
+		err := o.checkUnrevealedKnownAttr(p, "set membership proof", sp.SetMembership.AttrIndex)
+		if err != nil {
+			return nil, nil, err
 		}
+		l = append(l, sp.challengeData()...)
 	}
+	if p.DomainPseudonymProof != nil {
+		if len(o.Keys.Pub.RsHidden) == 0 {
+			return nil, nil, fmt.Errorf("master secret is not encoded in the credential")
+		}
+		l = append(l, p.DomainPseudonymProof.challengeData()...)
+	}
+	if p.EscrowProof != nil {
+		if !p.EscrowProof.isComplete() {
+			return nil, nil, fmt.Errorf("escrow proof is not complete")
+		}
+		err := o.checkUnrevealedKnownAttr(p, "escrow proof", p.EscrowProof.Escrow.AttrIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		l = append(l, p.EscrowProof.challengeData()...)
+	}
+	for _, ep := range p.CommitmentEqualityProofs {
+		if ep == nil || !ep.isComplete() {
+			return nil, nil, fmt.Errorf("commitment equality proof is not complete")
+		}
+		err := o.checkUnrevealedKnownAttr(p, "commitment equality proof", ep.Equality.AttrIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		l = append(l, ep.challengeData()...)
+	}
+
+	return l, accValue, nil
+}
+
+// checkUnrevealedKnownAttr checks that the proof accompanying the credential proof p (named
+// by name) refers to the attribute ind which is a known attribute not revealed in p.
+func (o *Org) checkUnrevealedKnownAttr(p *CredProof, name string, ind int) error {
+	if ind < 0 || ind >= len(o.Keys.Pub.RsKnown) || common.Contains(p.RevealedKnownAttrsIndices, ind) {
+		return fmt.Errorf("%s refers to attribute %d which is not an unrevealed known attribute",
+			name, ind)
+	}
+
+	return nil
+}
 
+// verifyCredProof verifies the credential proof and all the accompanying proofs. The challenge
+// needs to be checked beforehand.
+func (o *Org) verifyCredProof(p *CredProof, accValue *big.Int) (bool, error) {
+	proof := p.Proof
 	ver := qr.NewRepresentationVerifier(o.Group, int(o.Params.SecParam))
 	bases := []*big.Int{}
 	for i := 0; i < len(o.Keys.Pub.RsKnown); i++ {
-		if !common.Contains(revealedKnownAttrsIndices, i) {
+		if !common.Contains(p.RevealedKnownAttrsIndices, i) {
 			bases = append(bases, o.Keys.Pub.RsKnown[i])
 		}
 	}
 	for i := 0; i < len(o.Keys.Pub.RsCommitted); i++ {
-		if !common.Contains(revealedCommitmentsOfAttrsIndices, i) {
+		if !common.Contains(p.RevealedCommitmentsOfAttrsIndices, i) {
 			bases = append(bases, o.Keys.Pub.RsCommitted[i])
 		}
 	}
 	bases = append(bases, o.Keys.Pub.RsHidden...)
-	bases = append(bases, A)
+	bases = append(bases, p.RandCred.A)
 	bases = append(bases, o.Keys.Pub.S)
 
 	denom := big.NewInt(1)
-	for i := 0; i < len(revealedKnownAttrs); i++ {
-		rInd := revealedKnownAttrsIndices[i]
-		t1 := o.Group.Exp(o.Keys.Pub.RsKnown[rInd], revealedKnownAttrs[i])
+	for i := 0; i < len(p.RevealedKnownAttrs); i++ {
+		rInd := p.RevealedKnownAttrsIndices[i]
+		t1 := o.Group.Exp(o.Keys.Pub.RsKnown[rInd], p.RevealedKnownAttrs[i])
 		denom = o.Group.Mul(denom, t1)
 	}
 
-	for i := 0; i < len(revealedCommitmentsOfAttrs); i++ {
-		rInd := revealedCommitmentsOfAttrsIndices[i]
-		t1 := o.Group.Exp(o.Keys.Pub.RsCommitted[rInd], revealedCommitmentsOfAttrs[i])
+	for i := 0; i < len(p.RevealedCommitmentsOfAttrs); i++ {
+		rInd := p.RevealedCommitmentsOfAttrsIndices[i]
+		t1 := o.Group.Exp(o.Keys.Pub.RsCommitted[rInd], p.RevealedCommitmentsOfAttrs[i])
 		denom = o.Group.Mul(denom, t1)
 	}
 	denomInv := o.Group.Inv(denom)
 	y := o.Group.Mul(o.Keys.Pub.Z, denomInv)
 	ver.SetProofRandomData(proof.ProofRandomData, bases, y)
+	ver.SetChallenge(proof.Challenge)
 
-	context := o.Keys.Pub.GetContext()
-	l := []*big.Int{context, proof.ProofRandomData, o.proveCredNonceOrg}
-	//l = append(l, ...) // TODO: add other values
+	if o.Accumulator != nil {
+		// response for e is the second to last in the credential proof
+		sE := proof.ProofData[len(proof.ProofData)-2]
+		if !verifyNonRevocationProof(o.Keys.Pub.Accumulator, accValue, p.NonRevProof,
+			proof.Challenge, sE) {
+			return false, nil
+		}
+	}
 
-	c := common.Hash(l...) // TODO: function for GetChallenge
-	if proof.Challenge.Cmp(c) != 0 {
-		return false, fmt.Errorf("challenge is not correct")
+	for _, pp := range p.PredicateProofs {
+		// response for the attribute is taken from the credential proof
+		pos := unrevealedPosition(p.RevealedKnownAttrsIndices, pp.Predicate.AttrIndex)
+		if pos >= len(proof.ProofData) {
+			return false, fmt.Errorf("credential proof data is not complete")
+		}
+		verified, err := verifyPredicateProof(o.Params, o.Keys.Pub, pp, proof.Challenge,
+			proof.ProofData[pos])
+		if err != nil || !verified {
+			return false, err
+		}
 	}
 
-	ver.SetChallenge(proof.Challenge)
+	for _, sp := range p.SetMembershipProofs {
+		pos := unrevealedPosition(p.RevealedKnownAttrsIndices, sp.SetMembership.AttrIndex)
+		if pos >= len(proof.ProofData) {
+			return false, fmt.Errorf("credential proof data is not complete")
+		}
+		verified, err := verifySetMembershipProof(o.Params, o.Keys.Pub, sp, proof.Challenge,
+			proof.ProofData[pos])
+		if err != nil || !verified {
+			return false, err
+		}
+	}
+
+	if p.DomainPseudonymProof != nil {
+		pos := masterSecretPosition(o.Keys.Pub, p)
+		if pos >= len(proof.ProofData) {
+			return false, fmt.Errorf("credential proof data is not complete")
+		}
+		if !verifyDomainPseudonymProof(o.Keys.Pub, p.DomainPseudonymProof, proof.Challenge,
+			proof.ProofData[pos]) {
+			return false, nil
+		}
+	}
+
+	if p.EscrowProof != nil {
+		pos := unrevealedPosition(p.RevealedKnownAttrsIndices, p.EscrowProof.Escrow.AttrIndex)
+		if pos >= len(proof.ProofData) {
+			return false, fmt.Errorf("credential proof data is not complete")
+		}
+		if !verifyEscrowProof(p.EscrowProof, proof.Challenge, proof.ProofData[pos]) {
+			return false, nil
+		}
+	}
+
+	for _, ep := range p.CommitmentEqualityProofs {
+		pos := unrevealedPosition(p.RevealedKnownAttrsIndices, ep.Equality.AttrIndex)
+		if pos >= len(proof.ProofData) {
+			return false, fmt.Errorf("credential proof data is not complete")
+		}
+		if !verifyCommitmentEqualityProof(ep, proof.Challenge, proof.ProofData[pos]) {
+			return false, nil
+		}
+	}
 
 	return ver.Verify(proof.ProofData), nil
 }
@@ -382,76 +812,116 @@ func NewCred(A, e, v11 *big.Int) *Cred {
 	}
 }
 
+// generateQuadraticResidues returns a generator S of the group and the quadratic residues
+// Z, RsKnown, RsCommitted and RsHidden, which are random powers of S. The exponents are
+// returned too (in the same order as the residues), as they are needed for the proof that
+// the public key is well-formed.
 func generateQuadraticResidues(group *qr.RSASpecial, knownAttrsNum, committedAttrsNum,
 	hiddenAttrsNum int) (*big.Int, *big.Int, []*big.Int,
-	[]*big.Int, []*big.Int, error) {
+	[]*big.Int, []*big.Int, []*big.Int, error) {
 	S, err := group.GetRandomGenerator()
 	if err != nil {
-		return nil, nil, nil, nil, nil, fmt.Errorf("error when searching for RSASpecial generator: %s", err)
-	}
-	Z := group.Exp(S, common.GetRandomInt(group.Order))
-
-	RsKnown := make([]*big.Int, knownAttrsNum)
-	for i, _ := range RsKnown {
-		RsKnown[i] = group.Exp(S, common.GetRandomInt(group.Order))
+		return nil, nil, nil, nil, nil, nil,
+			fmt.Errorf("error when searching for RSASpecial generator: %s", err)
 	}
 
-	RsCommitted := make([]*big.Int, committedAttrsNum)
-	for i, _ := range RsCommitted {
-		RsCommitted[i] = group.Exp(S, common.GetRandomInt(group.Order))
+	var exps []*big.Int
+	genResidues := func(n int) []*big.Int {
+		residues := make([]*big.Int, n)
+		for i := range residues {
+			x := common.GetRandomInt(group.Order)
+			exps = append(exps, x)
+			residues[i] = group.Exp(S, x)
+		}
+		return residues
 	}
 
-	RsHidden := make([]*big.Int, hiddenAttrsNum)
-	for i, _ := range RsHidden {
-		RsHidden[i] = group.Exp(S, common.GetRandomInt(group.Order))
-	}
+	Z := genResidues(1)[0]
+	RsKnown := genResidues(knownAttrsNum)
+	RsCommitted := genResidues(committedAttrsNum)
+	RsHidden := genResidues(hiddenAttrsNum)
 
-	return S, Z, RsKnown, RsCommitted, RsHidden, nil
+	return S, Z, RsKnown, RsCommitted, RsHidden, exps, nil
 }
 
-func (o *Org) GetCredIssueNonce() *big.Int {
-	nonce := o.GenNonce()
-	o.credIssueNonceOrg = nonce
-
-	return nonce
+// GetCredIssueNonce generates a nonce for a credential request (see IssueCred).
+func (o *Org) GetCredIssueNonce() (*big.Int, error) {
+	return o.genStoredNonce(NonceIssue)
 }
 
-func (o *Org) verifyCredRequest(cr *CredRequest) bool {
-	return o.verifyNym(cr.NymProof) &&
-		o.verifyU(cr.UProof) &&
-		o.verifyCommitmentsOfAttrs(cr.CommitmentsOfAttrs, cr.CommitmentsOfAttrsProofs) &&
-		o.verifyChallenge(cr.UProof.Challenge) &&
-		o.verifyUProofDataLengths(cr.UProof.ProofData)
+// verifyCredRequest returns an error if the credential request is not complete (the
+// values in it are checked before they are used) or any of its proofs is not valid.
+func (o *Org) verifyCredRequest(cr *CredRequest, nonceOrg *big.Int,
+	attrsVerifiers []*df.OpeningVerifier) error {
+	if containsNil(cr.KnownAttrs...) || containsNil(cr.CommitmentsOfAttrs...) {
+		return fmt.Errorf("credential request is not complete")
+	}
+	if len(cr.KnownAttrs) != len(o.Keys.Pub.RsKnown) ||
+		len(cr.CommitmentsOfAttrs) != len(o.Keys.Pub.RsCommitted) {
+		return fmt.Errorf("the number of attributes does not match the public key")
+	}
+	if err := o.verifyNym(cr.Nym, cr.NymProof); err != nil {
+		return err
+	}
+	if err := o.verifyU(cr.U, cr.UProof); err != nil {
+		return err
+	}
+	if !o.verifyCommitmentsOfAttrs(attrsVerifiers, cr.CommitmentsOfAttrsProofs) {
+		return fmt.Errorf("proof of commitment opening not valid")
+	}
+	if !o.verifyChallenge(cr, nonceOrg) {
+		return fmt.Errorf("challenge is not correct")
+	}
+
+	return o.verifyUProofDataLengths(cr.UProof.ProofData)
 }
 
-func (o *Org) verifyNym(proof *schnorr.Proof) bool {
+func (o *Org) verifyNym(nym *big.Int, proof *schnorr.Proof) error {
 	bases := []*big.Int{
 		o.pedersenReceiver.Params.Group.G,
 		o.pedersenReceiver.Params.H,
 	}
-	o.nymVerifier.SetProofRandomData(proof.ProofRandomData, bases, o.nym)
-	o.nymVerifier.SetChallenge(proof.Challenge)
+	if nym == nil || proof == nil || proof.ProofRandomData == nil || proof.Challenge == nil ||
+		len(proof.ProofData) != len(bases) || containsNil(proof.ProofData...) {
+		return fmt.Errorf("proof of nym opening is not complete")
+	}
+	verifier := schnorr.NewVerifier(o.pedersenReceiver.Params.Group)
+	verifier.SetProofRandomData(proof.ProofRandomData, bases, nym)
+	verifier.SetChallenge(proof.Challenge)
+	if !verifier.Verify(proof.ProofData) {
+		return fmt.Errorf("proof of nym opening not valid")
+	}
 
-	return o.nymVerifier.Verify(proof.ProofData)
+	return nil
 }
 
-func (o *Org) verifyU(UProof *qr.RepresentationProof) bool {
-	// bases are [R_1, ..., R_L, S]
-	bases := append(o.Keys.Pub.RsHidden, o.Keys.Pub.S)
-	o.UVerifier.SetProofRandomData(UProof.ProofRandomData, bases, o.U)
-	o.UVerifier.SetChallenge(UProof.Challenge)
+func (o *Org) verifyU(U *big.Int, UProof *qr.RepresentationProof) error {
+	// bases are [R_1, ..., R_L, S] (copied, as the keys are shared by concurrent requests)
+	bases := append(append([]*big.Int{}, o.Keys.Pub.RsHidden...), o.Keys.Pub.S)
+	if U == nil || UProof == nil || UProof.ProofRandomData == nil || UProof.Challenge == nil ||
+		len(UProof.ProofData) != len(bases) || containsNil(UProof.ProofData...) {
+		return fmt.Errorf("proof of U is not complete")
+	}
+	verifier := qr.NewRepresentationVerifier(o.Group, int(o.Params.SecParam))
+	verifier.SetProofRandomData(UProof.ProofRandomData, bases, U)
+	verifier.SetChallenge(UProof.Challenge)
+	if !verifier.Verify(UProof.ProofData) {
+		return fmt.Errorf("proof of U not valid")
+	}
 
-	return o.UVerifier.Verify(UProof.ProofData)
+	return nil
 }
 
-func (o *Org) setUpAttrVerifiers(commitmentsOfAttrs []*big.Int) error {
+// getAttrsVerifiers returns the verifiers of the proofs of the knowledge of the openings
+// of the commitments of attributes.
+func (o *Org) getAttrsVerifiers(commitmentsOfAttrs []*big.Int) ([]*df.OpeningVerifier, error) {
 	attrsVerifiers := make([]*df.OpeningVerifier, len(commitmentsOfAttrs))
 	for i, attr := range commitmentsOfAttrs {
 		receiver, err := df.NewReceiverFromParams(
 			o.Keys.Sec.AttributesSpecialRSAPrimes, o.Keys.Pub.G, o.Keys.Pub.H,
 			int(o.Params.SecParam))
 		if err != nil {
-			return err
+			return nil, err
 		}
 		receiver.SetCommitment(attr)
 
@@ -460,17 +930,21 @@ func (o *Org) setUpAttrVerifiers(commitmentsOfAttrs []*big.Int) error {
 		attrsVerifiers[i] = verifier
 	}
 
-	o.attrsVerifiers = attrsVerifiers
-	o.commitmentsOfAttrs = commitmentsOfAttrs
-
-	return nil
+	return attrsVerifiers, nil
 }
 
-// commitments ... commitmentsOfAttrs
+// attrsVerifiers ... verifiers of commitmentsOfAttrs (see getAttrsVerifiers)
 // proofs ... commitmentsOfAttrsProofs
-func (o *Org) verifyCommitmentsOfAttrs(commitmentsOfAttrs []*big.Int, proofs []*df.OpeningProof) bool {
-	//o.setUpAttrVerifiers(commitmentsOfAttrs)
-	for i, v := range o.attrsVerifiers {
+func (o *Org) verifyCommitmentsOfAttrs(attrsVerifiers []*df.OpeningVerifier,
+	proofs []*df.OpeningProof) bool {
+	if len(proofs) != len(attrsVerifiers) {
+		return false
+	}
+	for i, v := range attrsVerifiers {
+		p := proofs[i]
+		if p == nil || containsNil(p.ProofRandomData, p.Challenge, p.ProofData1, p.ProofData2) {
+			return false
+		}
 		v.SetProofRandomData(proofs[i].ProofRandomData)
 		v.SetChallenge(proofs[i].Challenge)
 		if !v.Verify(proofs[i].ProofData1, proofs[i].ProofData2) {
@@ -481,15 +955,65 @@ func (o *Org) verifyCommitmentsOfAttrs(commitmentsOfAttrs []*big.Int, proofs []*
 	return true
 }
 
-func (o *Org) verifyChallenge(challenge *big.Int) bool {
+// verifyCredUpdateRequest verifies the proof of the knowledge of the nym opening and the
+// proofs of the knowledge of the openings of the commitments of attributes sent when updating
+// the credential.
+func (o *Org) verifyCredUpdateRequest(ur *CredUpdateRequest, nonceOrg *big.Int) error {
+	if ur.NymProof == nil || ur.NymProof.ProofRandomData == nil || ur.NymProof.Challenge == nil {
+		return fmt.Errorf("proof of nym opening is not complete")
+	}
+	if len(ur.CommitmentsOfAttrsProofs) != len(ur.CommitmentsOfAttrs) {
+		return fmt.Errorf("the number of commitments of attributes and proofs does not match")
+	}
+	proofRandomData := make([]*big.Int, len(ur.CommitmentsOfAttrsProofs))
+	for i, p := range ur.CommitmentsOfAttrsProofs {
+		if p == nil || p.ProofRandomData == nil || p.Challenge == nil {
+			return fmt.Errorf("proof of commitment opening is not complete")
+		}
+		proofRandomData[i] = p.ProofRandomData
+	}
+
+	challenge := getCredUpdateChallenge(o.Keys.Pub, ur.Nym, nonceOrg, ur.Nonce,
+		ur.NymProof.ProofRandomData, ur.CommitmentsOfAttrs, proofRandomData)
+	if ur.NymProof.Challenge.Cmp(challenge) != 0 {
+		return fmt.Errorf("challenge is not correct")
+	}
+	for _, p := range ur.CommitmentsOfAttrsProofs {
+		if p.Challenge.Cmp(challenge) != 0 {
+			return fmt.Errorf("challenge is not correct")
+		}
+	}
+
+	if err := o.verifyNym(ur.Nym, ur.NymProof); err != nil {
+		return err
+	}
+	if len(ur.CommitmentsOfAttrs) == 0 {
+		return nil
+	}
+	attrsVerifiers, err := o.getAttrsVerifiers(ur.CommitmentsOfAttrs)
+	if err != nil {
+		return err
+	}
+	if !o.verifyCommitmentsOfAttrs(attrsVerifiers, ur.CommitmentsOfAttrsProofs) {
+		return fmt.Errorf("proof of commitment opening not valid")
+	}
+
+	return nil
+}
+
+func (o *Org) verifyChallenge(cr *CredRequest, nonceOrg *big.Int) bool {
 	context := o.Keys.Pub.GetContext()
-	l := []*big.Int{context, o.U, o.nym, o.credIssueNonceOrg}
-	l = append(l, o.commitmentsOfAttrs...)
+	l := []*big.Int{context, cr.U, cr.Nym, nonceOrg}
+	l = append(l, cr.CommitmentsOfAttrs...)
 	c := common.Hash(l...)
-	return c.Cmp(challenge) == 0
+	return c.Cmp(cr.UProof.Challenge) == 0
 }
 
-func (o *Org) verifyUProofDataLengths(UProofData []*big.Int) bool {
+func (o *Org) verifyUProofDataLengths(UProofData []*big.Int) error {
+	if len(UProofData) != len(o.Keys.Pub.RsHidden)+1 || containsNil(UProofData...) {
+		return fmt.Errorf("proof of U is not complete")
+	}
+
 	// boundary for m_tilde
 	b_m := o.Params.AttrBitLen + o.Params.SecParam + o.Params.HashBitLen + 2
 	// boundary for v1_tilde
@@ -503,14 +1027,14 @@ func (o *Org) verifyUProofDataLengths(UProofData []*big.Int) bool {
 
 	for ind := 0; ind < len(o.Keys.Pub.RsHidden); ind++ {
 		if UProofData[ind].Cmp(b1) > 0 {
-			return false
+			return fmt.Errorf("proof data of U is too long")
 		}
 	}
 	if UProofData[len(o.Keys.Pub.RsHidden)].Cmp(b2) > 0 {
-		return false
+		return fmt.Errorf("proof data of U is too long")
 	}
 
-	return true
+	return nil
 }
 
 type ReceiverRecord struct {
@@ -518,33 +1042,44 @@ type ReceiverRecord struct {
 	CommitmentsOfAttrs []*big.Int
 	Q                  *big.Int
 	V11                *big.Int
+	E                  *big.Int // needed for the revocation of the credential
 	Context            *big.Int
+	// indices of the known attributes with the values provisioned for the holder,
+	// which cannot be changed by UpdateCred
+	ProvisionedAttrs []int
 }
 
 // Returns ReceiverRecord which contains user data needed when updating the credential for this user.
-func NewReceiverRecord(knownAttrs, commitmentsOfAttrs []*big.Int, Q, v11, context *big.Int) *ReceiverRecord {
+func NewReceiverRecord(knownAttrs, commitmentsOfAttrs []*big.Int, Q, v11, e, context *big.Int) *ReceiverRecord {
 	return &ReceiverRecord{
 		KnownAttrs:         knownAttrs,
 		CommitmentsOfAttrs: commitmentsOfAttrs,
 		Q:                  Q,
 		V11:                v11,
+		E:                  e,
 		Context:            context,
 	}
 }
 
+// MarshalBinary encodes the record in the canonical binary form (see EncodeBinary).
 func (r *ReceiverRecord) MarshalBinary() ([]byte, error) {
-	return json.Marshal(r)
+	return EncodeBinary(r)
 }
 
+// UnmarshalBinary decodes the record encoded by MarshalBinary. Records stored in JSON by
+// earlier versions are decoded as well.
 func (r *ReceiverRecord) UnmarshalBinary(data []byte) error {
-	if err := json.Unmarshal(data, &r); err != nil {
-		return err
+	if len(data) > 0 && data[0] == '{' {
+		return json.Unmarshal(data, r)
 	}
 
-	return nil
+	return DecodeBinary(data, r)
 }
 
-// TODO: where should we put WriteGob and ReadGob?
+// WriteGob writes object to a new file in gob encoding.
+//
+// Deprecated: gob encoding can be read only by Go and breaks when struct fields change,
+// use EncodeJSON or EncodeBinary instead.
 func WriteGob(filePath string, object interface{}) error {
 	file, err := os.Create(filePath)
 	if err == nil {
@@ -556,6 +1091,9 @@ func WriteGob(filePath string, object interface{}) error {
 	return err
 }
 
+// ReadGob reads object from the file written by WriteGob.
+//
+// Deprecated: use DecodeJSON or DecodeBinary instead.
 func ReadGob(filePath string, object interface{}) error {
 	file, err := os.Open(filePath)
 	if err == nil {
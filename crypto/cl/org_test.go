@@ -0,0 +1,181 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveCredMalformedProof(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 1)
+
+	org := newTestOrg(t, attrCount, 0)
+	org2 := newTestOrg(t, attrCount, 1)
+	org2.Nonces = org.Nonces
+
+	rawCred := newTestRawCred(t, attrCount, "Jack")
+	masterSecret := org.Keys.Pub.GenerateUserMasterSecret()
+	credMgr, res := issueTestCred(t, org, rawCred, masterSecret)
+	credMgr2, res2 := issueTestCred(t, org2, newTestRawCred(t, attrCount, "John"), masterSecret)
+
+	build := func() (*CredProof, *big.Int) {
+		nonce, err := org.GetProveCredNonce()
+		require.NoError(t, err)
+		proof, err := credMgr.BuildProof(res.Cred, []int{0}, []int{0}, &ProofOptions{
+			Predicates: []*Predicate{
+				NewRangePredicate(3, EncodeInt64(0), EncodeInt64(1562643000)),
+			},
+			SetMemberships: []*SetMembership{
+				NewSetMembership(2, []*big.Int{new(big.Int).SetBytes([]byte("true"))}),
+			},
+		}, nonce)
+		require.NoError(t, err)
+
+		return proof, nonce
+	}
+
+	proof, nonce := build()
+	verified, _, err := org.ProveCred(proof, nil, nonce)
+	require.NoError(t, err)
+	assert.True(t, verified, "credential proof not accepted")
+
+	malformed := map[string]func(p *CredProof){
+		"revealed index out of range": func(p *CredProof) {
+			p.RevealedKnownAttrsIndices[0] = 5
+		},
+		"negative revealed index": func(p *CredProof) {
+			p.RevealedKnownAttrsIndices[0] = -1
+		},
+		"duplicate revealed index": func(p *CredProof) {
+			p.RevealedKnownAttrsIndices = []int{0, 0}
+			p.RevealedKnownAttrs = append(p.RevealedKnownAttrs, p.RevealedKnownAttrs[0])
+		},
+		"committed index out of range": func(p *CredProof) {
+			p.RevealedCommitmentsOfAttrsIndices[0] = 1
+		},
+		"fewer revealed values than indices": func(p *CredProof) {
+			p.RevealedKnownAttrs = nil
+		},
+		"more revealed values than indices": func(p *CredProof) {
+			p.RevealedCommitmentsOfAttrs = append(p.RevealedCommitmentsOfAttrs, big.NewInt(1))
+		},
+		"nil revealed value": func(p *CredProof) {
+			p.RevealedKnownAttrs[0] = nil
+		},
+		"truncated proof data": func(p *CredProof) {
+			p.Proof.ProofData = p.Proof.ProofData[:2]
+		},
+		"missing proof": func(p *CredProof) {
+			p.Proof = nil
+		},
+		"predicate index out of range": func(p *CredProof) {
+			p.PredicateProofs[0].Predicate.AttrIndex = 7
+		},
+		"predicate on revealed attribute": func(p *CredProof) {
+			p.PredicateProofs[0].Predicate.AttrIndex = 0
+		},
+		"incomplete predicate proof": func(p *CredProof) {
+			p.PredicateProofs[0].ProofData = nil
+		},
+		"set membership index out of range": func(p *CredProof) {
+			p.SetMembershipProofs[0].SetMembership.AttrIndex = -3
+		},
+		"incomplete set membership proof": func(p *CredProof) {
+			p.SetMembershipProofs[0].OrProofData[0] = nil
+		},
+	}
+	for name, mutate := range malformed {
+		proof, nonce := build()
+		mutate(proof)
+		assert.NotPanics(t, func() {
+			_, _, err = org.ProveCred(proof, nil, nonce)
+		}, name)
+		assert.Error(t, err, name)
+	}
+
+	_, _, err = org.ProveCred(nil, nil, org.GenNonce())
+	assert.Error(t, err, "missing credential proof should not be accepted")
+
+	// malformed proofs are rejected also in multi-credential proofs
+	nonce, err = org.GetProveCredNonce()
+	require.NoError(t, err)
+	proofs, err := BuildMultiProof([]*CredPresentation{
+		NewCredPresentation(credMgr, res.Cred, []int{0}, []int{}),
+		NewCredPresentation(credMgr2, res2.Cred, []int{0}, []int{}),
+	}, nil, nonce)
+	require.NoError(t, err)
+	proofs[1].RevealedKnownAttrsIndices[0] = 6
+	assert.NotPanics(t, func() {
+		_, _, err = ProveMultiCred([]*Org{org, org2}, nil, nil, proofs, nonce)
+	})
+	assert.Error(t, err, "malformed multi-credential proof should not be accepted")
+}
+
+func TestIssueCredMalformedRequest(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 1)
+	org := newTestOrg(t, attrCount, 0)
+	credMgr, err := NewCredManager(org.Params, org.Keys.Pub,
+		org.Keys.Pub.GenerateUserMasterSecret(), newTestRawCred(t, attrCount, "Jack"))
+	require.NoError(t, err)
+
+	build := func() (*CredRequest, *big.Int) {
+		nonce, err := org.GetCredIssueNonce()
+		require.NoError(t, err)
+		credReq, err := credMgr.GetCredRequest(nonce)
+		require.NoError(t, err)
+
+		return credReq, nonce
+	}
+
+	malformed := map[string]func(r *CredRequest){
+		"missing nym":           func(r *CredRequest) { r.Nym = nil },
+		"missing nym proof":     func(r *CredRequest) { r.NymProof = nil },
+		"truncated nym proof":   func(r *CredRequest) { r.NymProof.ProofData = r.NymProof.ProofData[:1] },
+		"nil nym proof data":    func(r *CredRequest) { r.NymProof.ProofData[1] = nil },
+		"missing U":             func(r *CredRequest) { r.U = nil },
+		"missing U proof":       func(r *CredRequest) { r.UProof = nil },
+		"truncated U proof":     func(r *CredRequest) { r.UProof.ProofData = r.UProof.ProofData[:1] },
+		"nil U proof challenge": func(r *CredRequest) { r.UProof.Challenge = nil },
+		// the known attributes are shared with credMgr, thus they are not modified in place
+		"too many known attrs": func(r *CredRequest) {
+			r.KnownAttrs = append(append([]*big.Int{}, r.KnownAttrs...), big.NewInt(1))
+		},
+		"nil known attr": func(r *CredRequest) {
+			r.KnownAttrs = append([]*big.Int{nil}, r.KnownAttrs[1:]...)
+		},
+		"missing commitment":      func(r *CredRequest) { r.CommitmentsOfAttrs = nil },
+		"nil commitment proof":    func(r *CredRequest) { r.CommitmentsOfAttrsProofs[0] = nil },
+		"incomplete commit proof": func(r *CredRequest) { r.CommitmentsOfAttrsProofs[0].ProofData2 = nil },
+	}
+	for name, mutate := range malformed {
+		credReq, nonce := build()
+		mutate(credReq)
+		assert.NotPanics(t, func() {
+			_, err = org.IssueCred(credReq, nonce)
+		}, name)
+		assert.Error(t, err, name)
+	}
+
+	credReq, nonce := build()
+	_, err = org.IssueCred(credReq, nonce)
+	assert.NoError(t, err)
+}
@@ -17,6 +17,13 @@
 
 package cl
 
+import (
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
 // Params presents parameters that organization (which is issuing credentials) needs to set.
 type Params struct {
 	// There are only a few possibilities for RhoBitLen. 256 implies that the modulus
@@ -35,20 +42,143 @@ type Params struct {
 	ChallengeSpace    int // bit length of challenges for DF commitment proofs
 }
 
-// TODO: add method to load params from file or blockchain or wherever they will be stored.
+// Names of parameter profiles (see GetParamProfile).
+const (
+	// TestProfile uses a small RSA modulus to make tests fast, it must not be used
+	// in production.
+	TestProfile = "test"
+	// Profile2048 uses a 2048-bit RSA modulus.
+	Profile2048 = "2048"
+	// Profile3072 uses a 3072-bit RSA modulus and a larger statistical security parameter.
+	Profile3072 = "3072"
+)
+
+var paramProfiles = map[string]Params{
+	TestProfile: {
+		RhoBitLen:      256,
+		NLength:        256,
+		AttrBitLen:     256,
+		HashBitLen:     512,
+		SecParam:       80,
+		EBitLen:        853,
+		E1BitLen:       120,
+		VBitLen:        2724,
+		ChallengeSpace: 80,
+	},
+	Profile2048: {
+		RhoBitLen:      256,
+		NLength:        2048,
+		AttrBitLen:     256,
+		HashBitLen:     512,
+		SecParam:       80,
+		EBitLen:        853,
+		E1BitLen:       120,
+		VBitLen:        2980,
+		ChallengeSpace: 80,
+	},
+	Profile3072: {
+		RhoBitLen:      256,
+		NLength:        3072,
+		AttrBitLen:     256,
+		HashBitLen:     512,
+		SecParam:       128,
+		EBitLen:        901,
+		E1BitLen:       120,
+		VBitLen:        4100,
+		ChallengeSpace: 128,
+	},
+}
+
+// GetParamProfile returns the parameters of the profile with the given name (see TestProfile
+// and others). The numbers of attributes are not set.
+func GetParamProfile(name string) (*Params, error) {
+	p, ok := paramProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown CL parameter profile %s", name)
+	}
+
+	return &p, nil
+}
+
+// GetDefaultParamSizes returns the parameters of the test profile with the
+// default numbers of attributes.
 func GetDefaultParamSizes() *Params {
-	return &Params{
-		RhoBitLen:         256,
-		NLength:           256, // should be at least 2048 when not testing
-		KnownAttrsNum:     5,
-		CommittedAttrsNum: 1,
-		HiddenAttrsNum:    0,
-		AttrBitLen:        256,
-		HashBitLen:        512,
-		SecParam:          80,
-		EBitLen:           597,
-		E1BitLen:          120,
-		VBitLen:           2724,
-		ChallengeSpace:    80,
+	p, _ := GetParamProfile(TestProfile)
+	p.KnownAttrsNum = 5
+	p.CommittedAttrsNum = 1
+
+	return p
+}
+
+// ReadParams reads parameters in JSON format from the file at path and validates them.
+func ReadParams(path string) (*Params, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Params)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("error when reading CL parameters: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Validate checks that the bit lengths are consistent with each other as required by the
+// CL scheme (l_phi denotes SecParam, l_H HashBitLen, l_m AttrBitLen, l_n NLength):
+//   - l_e > l_phi + l_H + max(l_m + 4, l_e' + 2)
+//   - l_e' < l_e - l_phi - l_H - 3
+//   - l_v > l_n + l_phi + l_H + max(l_m + l_phi + 3, l_phi + 2)
+//
+// Besides, challenges are SHA-512 hashes (l_H needs to be at least 512) and the master secret
+// (an element of the commitment group) is encoded as an attribute (l_m needs to be at least
+// RhoBitLen).
+func (p *Params) Validate() error {
+	if p.RhoBitLen <= 0 || p.NLength <= 0 || p.AttrBitLen <= 0 || p.HashBitLen <= 0 ||
+		p.SecParam <= 0 || p.EBitLen <= 0 || p.E1BitLen <= 0 || p.VBitLen <= 0 ||
+		p.ChallengeSpace <= 0 {
+		return fmt.Errorf("CL parameters need to be positive")
+	}
+	if p.KnownAttrsNum < 0 || p.CommittedAttrsNum < 0 || p.HiddenAttrsNum < 0 {
+		return fmt.Errorf("numbers of attributes cannot be negative")
+	}
+	if p.NLength%2 != 0 {
+		return fmt.Errorf("NLength needs to be even, RSA modulus is a product of two primes of " +
+			"the same length")
+	}
+	if p.HashBitLen < 8*sha512.Size {
+		return fmt.Errorf("HashBitLen needs to be at least %d (challenges are SHA-512 hashes)",
+			8*sha512.Size)
+	}
+	if p.AttrBitLen < p.RhoBitLen {
+		return fmt.Errorf("AttrBitLen needs to be at least RhoBitLen (%d), the master secret "+
+			"is encoded as an attribute", p.RhoBitLen)
+	}
+
+	minE := p.SecParam + p.HashBitLen + maxInt(p.AttrBitLen+4, p.E1BitLen+2) + 1
+	if p.EBitLen < minE {
+		return fmt.Errorf("EBitLen needs to be at least %d", minE)
 	}
+	if p.E1BitLen >= p.EBitLen-p.SecParam-p.HashBitLen-3 {
+		return fmt.Errorf("E1BitLen needs to be smaller than %d",
+			p.EBitLen-p.SecParam-p.HashBitLen-3)
+	}
+	minV := p.NLength + p.SecParam + p.HashBitLen +
+		maxInt(p.AttrBitLen+p.SecParam+3, p.SecParam+2) + 1
+	if p.VBitLen < minV {
+		return fmt.Errorf("VBitLen needs to be at least %d", minV)
+	}
+
+	return nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
 }
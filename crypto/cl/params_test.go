@@ -0,0 +1,88 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamProfiles(t *testing.T) {
+	for _, name := range []string{TestProfile, Profile2048, Profile3072} {
+		p, err := GetParamProfile(name)
+		assert.NoError(t, err)
+		assert.NoError(t, p.Validate(), "profile %s should be valid", name)
+	}
+
+	_, err := GetParamProfile("1024")
+	assert.Error(t, err)
+
+	// profiles must not be modified through the returned parameters
+	p, _ := GetParamProfile(TestProfile)
+	p.EBitLen = 1
+	p, _ = GetParamProfile(TestProfile)
+	assert.NoError(t, p.Validate())
+}
+
+func TestParamsValidate(t *testing.T) {
+	tests := map[string]func(p *Params){
+		"EBitLen too small":   func(p *Params) { p.EBitLen = 597 },
+		"E1BitLen too large":  func(p *Params) { p.E1BitLen = 300 },
+		"VBitLen too small":   func(p *Params) { p.VBitLen = p.NLength },
+		"odd NLength":         func(p *Params) { p.NLength = 255 },
+		"short hash":          func(p *Params) { p.HashBitLen = 256 },
+		"short attributes":    func(p *Params) { p.AttrBitLen = 128 },
+		"zero SecParam":       func(p *Params) { p.SecParam = 0 },
+		"negative attributes": func(p *Params) { p.KnownAttrsNum = -1 },
+	}
+	for name, modify := range tests {
+		p := GetDefaultParamSizes()
+		modify(p)
+		assert.Error(t, p.Validate(), name)
+	}
+
+	p := GetDefaultParamSizes()
+	p.EBitLen = 597
+	_, err := NewCredManager(p, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestReadParams(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clparams")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := GetDefaultParamSizes()
+	data, _ := json.Marshal(p)
+	path := filepath.Join(dir, "params.json")
+	assert.NoError(t, ioutil.WriteFile(path, data, 0600))
+	read, err := ReadParams(path)
+	assert.NoError(t, err)
+	assert.Equal(t, p, read)
+
+	p.VBitLen = 1000
+	data, _ = json.Marshal(p)
+	assert.NoError(t, ioutil.WriteFile(path, data, 0600))
+	_, err = ReadParams(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,357 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"gopkg.in/yaml.v2"
+)
+
+// VerificationPolicy describes which credential proofs are accepted by the verifier: which
+// attributes need to be revealed and which condition the attributes need to satisfy.
+// A condition on an attribute which is not revealed can be satisfied by a predicate
+// (range) proof or a set membership proof.
+//
+// Policies can be given in YAML or JSON format, for example:
+//
+//	revealed: [Name]
+//	condition:
+//	  or:
+//	    - and:
+//	        - attr: DateMin
+//	          max: 1562643000
+//	        - attr: DateMax
+//	          min: 1562643000
+//	    - attr: Graduated
+//	      in: ["true", "yes"]
+//
+// Before a policy can be checked, it needs to be bound to the structure of the credentials
+// (see NewVerificationPolicy and ParseVerificationPolicy).
+type VerificationPolicy struct {
+	// Revealed contains the names of the attributes which need to be revealed
+	Revealed  []string         `json:"revealed,omitempty" yaml:"revealed,omitempty"`
+	Condition *PolicyCondition `json:"condition,omitempty" yaml:"condition,omitempty"`
+
+	// Scope, Escrow and CommitmentEqualities are the requirements of the verifier which are
+	// set by the verifier itself (they are not part of the policy format, see Org.ProveCred).
+	// A policy which only sets them does not need to be bound to the credential structure.
+	Scope                []byte                `json:"-" yaml:"-"`
+	Escrow               *Escrow               `json:"-" yaml:"-"`
+	CommitmentEqualities []*CommitmentEquality `json:"-" yaml:"-"`
+
+	// knownAttrs are known attributes of the credentials in the order of their indices
+	knownAttrs   []CredAttr
+	knownIndices map[string]int
+}
+
+// PolicyCondition is either a combination of conditions (all conditions in And or at least
+// one condition in Or need to be satisfied) or a condition on the attribute Attr. The value
+// of the attribute needs to be in [Min, Max] (Min and Max are optional, dates are given as
+// Unix time) or it needs to be one of the values in In.
+type PolicyCondition struct {
+	And  []*PolicyCondition `json:"and,omitempty" yaml:"and,omitempty"`
+	Or   []*PolicyCondition `json:"or,omitempty" yaml:"or,omitempty"`
+	Attr string             `json:"attr,omitempty" yaml:"attr,omitempty"`
+	Min  *int64             `json:"min,omitempty" yaml:"min,omitempty"`
+	Max  *int64             `json:"max,omitempty" yaml:"max,omitempty"`
+	In   []string           `json:"in,omitempty" yaml:"in,omitempty"`
+}
+
+// NewVerificationPolicy returns the policy for credentials with attributes attrs (ordered
+// by index, as returned by ParseAttrs).
+func NewVerificationPolicy(attrs []CredAttr, revealed []string,
+	condition *PolicyCondition) (*VerificationPolicy, error) {
+	p := &VerificationPolicy{
+		Revealed:  revealed,
+		Condition: condition,
+	}
+	if err := p.bind(attrs); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// ParseVerificationPolicy parses the policy given in YAML or JSON format for credentials
+// with attributes attrs (ordered by index, as returned by ParseAttrs).
+func ParseVerificationPolicy(attrs []CredAttr, data []byte) (*VerificationPolicy, error) {
+	var p VerificationPolicy
+	if err := yaml.UnmarshalStrict(data, &p); err != nil {
+		return nil, fmt.Errorf("error when parsing verification policy: %v", err)
+	}
+	if err := p.bind(attrs); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// bind checks that the policy is valid for credentials with attributes attrs and
+// prepares it for checking the proofs.
+func (p *VerificationPolicy) bind(attrs []CredAttr) error {
+	p.knownAttrs = make([]CredAttr, 0)
+	p.knownIndices = make(map[string]int)
+	for _, a := range attrs {
+		if a != nil && a.IsKnown() {
+			p.knownIndices[a.GetName()] = len(p.knownAttrs)
+			p.knownAttrs = append(p.knownAttrs, a)
+		}
+	}
+
+	for _, name := range p.Revealed {
+		if _, ok := p.knownIndices[name]; !ok {
+			return fmt.Errorf("attribute %s cannot be revealed", name)
+		}
+	}
+	if p.Condition != nil {
+		return p.Condition.validate(p)
+	}
+
+	return nil
+}
+
+func (c *PolicyCondition) validate(p *VerificationPolicy) error {
+	if c == nil {
+		return fmt.Errorf("policy condition is empty")
+	}
+
+	isAttrCond := c.Attr != "" || c.Min != nil || c.Max != nil || c.In != nil
+	switch {
+	case c.And != nil && c.Or == nil && !isAttrCond:
+		return validateConditions(p, c.And)
+	case c.Or != nil && c.And == nil && !isAttrCond:
+		return validateConditions(p, c.Or)
+	case c.And != nil || c.Or != nil:
+		return fmt.Errorf("policy condition needs to be a combination or a condition on an attribute")
+	}
+
+	ind, ok := p.knownIndices[c.Attr]
+	if !ok {
+		return fmt.Errorf("conditions can be set only on known attributes, not on %s", c.Attr)
+	}
+	isRange := c.Min != nil || c.Max != nil
+	if isRange == (c.In != nil) {
+		return fmt.Errorf("condition on %s needs to be either a range or a set", c.Attr)
+	}
+	if isRange {
+		switch p.knownAttrs[ind].(type) {
+		case *Int64Attr, *DateAttr:
+		default:
+			return fmt.Errorf("range condition on %s which is not a number or a date", c.Attr)
+		}
+		if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+			return fmt.Errorf("range condition on %s is empty", c.Attr)
+		}
+	} else if _, ok := p.knownAttrs[ind].(*DateAttr); ok {
+		return fmt.Errorf("set condition on date %s", c.Attr)
+	}
+
+	return nil
+}
+
+func validateConditions(p *VerificationPolicy, conditions []*PolicyCondition) error {
+	if len(conditions) == 0 {
+		return fmt.Errorf("combination of policy conditions is empty")
+	}
+	for _, c := range conditions {
+		if err := c.validate(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Check checks that the credential proof satisfies the policy: all required attributes are
+// revealed and the condition is satisfied by the values of the revealed attributes and
+// by the predicate and set membership proofs of the attributes which are not revealed.
+// Note that the proofs themselves are not verified.
+func (p *VerificationPolicy) Check(proof *CredProof) error {
+	if p.knownIndices == nil {
+		if len(p.Revealed) > 0 || p.Condition != nil {
+			return fmt.Errorf("verification policy is not bound to the credential structure")
+		}
+		return nil
+	}
+	if err := p.checkIndices(proof); err != nil {
+		return err
+	}
+
+	for _, name := range p.Revealed {
+		if !common.Contains(proof.RevealedKnownAttrsIndices, p.knownIndices[name]) {
+			return fmt.Errorf("attribute %s needs to be revealed", name)
+		}
+	}
+
+	if p.Condition != nil {
+		ok, err := p.Condition.isSatisfied(p, proof)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("credential proof does not satisfy the verification policy")
+		}
+	}
+
+	return nil
+}
+
+// checkIndices checks that the revealed attributes, predicates, set memberships and escrow
+// refer to the known attributes and that the predicates, set memberships and escrow refer
+// to the attributes which are not revealed.
+func (p *VerificationPolicy) checkIndices(proof *CredProof) error {
+	for _, ind := range proof.RevealedKnownAttrsIndices {
+		if ind < 0 || ind >= len(p.knownAttrs) {
+			return fmt.Errorf("revealed attribute %d is not known", ind)
+		}
+	}
+	for _, pp := range proof.PredicateProofs {
+		ind := pp.Predicate.AttrIndex
+		if ind < 0 || ind >= len(p.knownAttrs) {
+			return fmt.Errorf("predicate refers to unknown attribute %d", ind)
+		}
+		if common.Contains(proof.RevealedKnownAttrsIndices, ind) {
+			return fmt.Errorf("predicate refers to revealed attribute %d", ind)
+		}
+	}
+	for _, sp := range proof.SetMembershipProofs {
+		ind := sp.SetMembership.AttrIndex
+		if ind < 0 || ind >= len(p.knownAttrs) {
+			return fmt.Errorf("set membership refers to unknown attribute %d", ind)
+		}
+		if common.Contains(proof.RevealedKnownAttrsIndices, ind) {
+			return fmt.Errorf("set membership refers to revealed attribute %d", ind)
+		}
+	}
+	if proof.EscrowProof != nil && proof.EscrowProof.Escrow != nil {
+		ind := proof.EscrowProof.Escrow.AttrIndex
+		if ind < 0 || ind >= len(p.knownAttrs) {
+			return fmt.Errorf("escrow refers to unknown attribute %d", ind)
+		}
+		if common.Contains(proof.RevealedKnownAttrsIndices, ind) {
+			return fmt.Errorf("escrow refers to revealed attribute %d", ind)
+		}
+	}
+
+	return nil
+}
+
+func (c *PolicyCondition) isSatisfied(p *VerificationPolicy, proof *CredProof) (bool, error) {
+	switch {
+	case c.And != nil:
+		for _, cond := range c.And {
+			ok, err := cond.isSatisfied(p, proof)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case c.Or != nil:
+		for _, cond := range c.Or {
+			ok, err := cond.isSatisfied(p, proof)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	ind := p.knownIndices[c.Attr]
+	a := p.knownAttrs[ind]
+	for i, revealedInd := range proof.RevealedKnownAttrsIndices {
+		if revealedInd == ind {
+			return c.accepts(a, proof.RevealedKnownAttrs[i])
+		}
+	}
+
+	// the attribute is not revealed
+	for _, pp := range proof.PredicateProofs {
+		if pp.Predicate.AttrIndex == ind && c.impliedByRange(pp.Predicate.Min, pp.Predicate.Max) {
+			return true, nil
+		}
+	}
+	for _, sp := range proof.SetMembershipProofs {
+		if sp.SetMembership.AttrIndex != ind {
+			continue
+		}
+		ok := true
+		for _, v := range sp.SetMembership.Values {
+			if accepted, err := c.accepts(a, v); err != nil || !accepted {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// accepts returns true if the attribute a with internal value val satisfies the condition.
+func (c *PolicyCondition) accepts(a CredAttr, val *big.Int) (bool, error) {
+	v, err := a.FromInternalValue(val)
+	if err != nil {
+		return false, err
+	}
+
+	if c.In != nil {
+		s := fmt.Sprint(v)
+		for _, in := range c.In {
+			if _, ok := a.(*HashedStrAttr); ok {
+				in = fmt.Sprintf("%x", sha256.Sum256([]byte(in)))
+			}
+			if in == s {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	var x int64
+	switch v := v.(type) {
+	case int:
+		x = int64(v)
+	case time.Time: // dates are compared as Unix time
+		x = v.Unix()
+	default:
+		return false, nil
+	}
+
+	return (c.Min == nil || *c.Min <= x) && (c.Max == nil || x <= *c.Max), nil
+}
+
+// impliedByRange returns true if all values in [min, max] (internal values) satisfy
+// the range condition.
+func (c *PolicyCondition) impliedByRange(min, max *big.Int) bool {
+	if c.In != nil {
+		return false
+	}
+
+	return (c.Min == nil || EncodeInt64(*c.Min).Cmp(min) <= 0) &&
+		(c.Max == nil || max.Cmp(EncodeInt64(*c.Max)) <= 0)
+}
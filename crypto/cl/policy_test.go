@@ -0,0 +1,154 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// credAttrs returns the attributes of the credential ordered by index.
+func credAttrs(c *RawCred) []CredAttr {
+	attrs := make([]CredAttr, len(c.GetAttrs()))
+	for i, a := range c.GetAttrs() {
+		attrs[i] = a
+	}
+
+	return attrs
+}
+
+func TestVerificationPolicy(t *testing.T) {
+	rawCred := NewRawCred(NewAttrCount(5, 1, 0))
+	_ = rawCred.AddStrAttr("Name", "Jack", true)
+	_ = rawCred.AddStrAttr("Gender", "M", true)
+	_ = rawCred.AddEmptyAttr(NewEmptyHashedStrAttr("Address", true))
+	_ = rawCred.AddEmptyAttr(NewEmptyDateAttr("DateMin", true))
+	_ = rawCred.AddInt64Attr("Age", 25, true)
+	_ = rawCred.AddInt64Attr("Income", 1000, false)
+	attrs := credAttrs(rawCred)
+
+	data := []byte(`
+revealed: [Name]
+condition:
+  or:
+    - and:
+        - attr: DateMin
+          max: 1562643000
+        - attr: Age
+          min: 18
+    - attr: Address
+      in: ["Main street 1"]
+`)
+	policy, err := ParseVerificationPolicy(attrs, data)
+	require.NoError(t, err)
+
+	// the policy in JSON format (as sent to the clients) is parsed as well
+	jsonData, err := json.Marshal(policy)
+	require.NoError(t, err)
+	jsonPolicy, err := ParseVerificationPolicy(attrs, jsonData)
+	require.NoError(t, err)
+	assert.Equal(t, policy, jsonPolicy)
+
+	strVal := func(s string) *big.Int {
+		return new(big.Int).SetBytes([]byte(s))
+	}
+	address, _ := NewHashedStrAttr("Address", "Main street 1", true)
+	proof := func(revealed []int, values []*big.Int, predicates []*Predicate,
+		sets []*SetMembership) *CredProof {
+		p := &CredProof{
+			RevealedKnownAttrsIndices: revealed,
+			RevealedKnownAttrs:        values,
+		}
+		for _, pr := range predicates {
+			p.PredicateProofs = append(p.PredicateProofs, &PredicateProof{Predicate: pr})
+		}
+		for _, s := range sets {
+			p.SetMembershipProofs = append(p.SetMembershipProofs, &SetMembershipProof{SetMembership: s})
+		}
+		return p
+	}
+
+	// revealed values satisfying the first alternative
+	err = policy.Check(proof([]int{0, 3, 4},
+		[]*big.Int{strVal("Jack"), EncodeInt64(1500000000), EncodeInt64(20)}, nil, nil))
+	assert.NoError(t, err)
+
+	// predicates satisfying the first alternative
+	err = policy.Check(proof([]int{0}, []*big.Int{strVal("Jack")}, []*Predicate{
+		NewRangePredicate(3, EncodeInt64(0), EncodeInt64(1562643000)),
+		NewRangePredicate(4, EncodeInt64(18), EncodeInt64(200)),
+	}, nil))
+	assert.NoError(t, err)
+
+	// revealed (hashed) value or set membership satisfying the second alternative
+	err = policy.Check(proof([]int{0, 2}, []*big.Int{strVal("Jack"), address.InternalValue()},
+		nil, nil))
+	assert.NoError(t, err)
+	err = policy.Check(proof([]int{0}, []*big.Int{strVal("Jack")}, nil,
+		[]*SetMembership{NewSetMembership(2, []*big.Int{address.InternalValue()})}))
+	assert.NoError(t, err)
+
+	// required attribute is not revealed
+	err = policy.Check(proof([]int{2}, []*big.Int{address.InternalValue()}, nil, nil))
+	assert.Error(t, err, "proof without required revealed attribute should not be accepted")
+
+	// revealed value does not satisfy the condition
+	err = policy.Check(proof([]int{0, 3, 4},
+		[]*big.Int{strVal("Jack"), EncodeInt64(1500000000), EncodeInt64(17)}, nil, nil))
+	assert.Error(t, err, "proof with unacceptable value should not be accepted")
+
+	// predicate weaker than the condition
+	err = policy.Check(proof([]int{0}, []*big.Int{strVal("Jack")}, []*Predicate{
+		NewRangePredicate(3, EncodeInt64(0), EncodeInt64(1562643000)),
+		NewRangePredicate(4, EncodeInt64(17), EncodeInt64(200)),
+	}, nil))
+	assert.Error(t, err, "proof with weaker predicate should not be accepted")
+
+	// set with unacceptable values
+	err = policy.Check(proof([]int{0}, []*big.Int{strVal("Jack")}, nil,
+		[]*SetMembership{NewSetMembership(2, []*big.Int{address.InternalValue(), strVal("x")})}))
+	assert.Error(t, err, "proof with unacceptable set should not be accepted")
+
+	// a policy received in JSON needs to be bound to the credential structure
+	var unbound VerificationPolicy
+	require.NoError(t, json.Unmarshal(jsonData, &unbound))
+	err = unbound.Check(proof([]int{0}, []*big.Int{strVal("Jack")}, nil, nil))
+	assert.Error(t, err, "unbound policy should not be checked")
+
+	// invalid policies
+	invalid := []string{
+		`revealed: [Income]`,
+		`revealed: [Unknown]`,
+		`condition: {attr: Name, min: 1}`,
+		`condition: {attr: Age, min: 1, in: ["1"]}`,
+		`condition: {attr: Age, min: 2, max: 1}`,
+		`condition: {attr: DateMin, in: ["1"]}`,
+		`condition: {and: [], attr: Age}`,
+		`condition: {or: []}`,
+		`condition: {attr: Income, max: 1}`,
+		`condition: {attr: Age, maximum: 1}`,
+	}
+	for _, p := range invalid {
+		_, err := ParseVerificationPolicy(attrs, []byte(p))
+		assert.Error(t, err, "invalid policy %s should not be parsed", p)
+	}
+}
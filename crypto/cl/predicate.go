@@ -0,0 +1,233 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/df"
+)
+
+// Predicate states that a known attribute, which is not revealed to the verifier,
+// lies in the interval [Min, Max]. AttrIndex is the index of the attribute among
+// known attributes. Min and Max are internal values (integers and dates need to be
+// encoded by EncodeInt64).
+//
+// The attribute is committed in a DF commitment (using PubKey.N1, PubKey.G, PubKey.H),
+// the commitment is proved to hide the same value as used in the credential proof and
+// a DF range proof is used to prove that the committed value is in [Min, Max].
+type Predicate struct {
+	AttrIndex int
+	Min       *big.Int
+	Max       *big.Int
+}
+
+// NewRangePredicate returns a predicate min <= attr <= max.
+func NewRangePredicate(attrIndex int, min, max *big.Int) *Predicate {
+	return &Predicate{
+		AttrIndex: attrIndex,
+		Min:       min,
+		Max:       max,
+	}
+}
+
+// NewGreaterPredicate returns a predicate attr > val. Params are needed to
+// determine the upper bound for attribute values.
+func NewGreaterPredicate(params *Params, attrIndex int, val *big.Int) *Predicate {
+	min := new(big.Int).Add(val, big.NewInt(1))
+	return NewRangePredicate(attrIndex, min, maxAttrValue(params))
+}
+
+// NewLesserPredicate returns a predicate attr < val. Params are needed to
+// determine the lower bound for attribute values.
+func NewLesserPredicate(params *Params, attrIndex int, val *big.Int) *Predicate {
+	max := new(big.Int).Sub(val, big.NewInt(1))
+	return NewRangePredicate(attrIndex, new(big.Int).Neg(maxAttrValue(params)), max)
+}
+
+// maxAttrValue returns the largest absolute value an attribute can have.
+func maxAttrValue(params *Params) *big.Int {
+	b := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(params.AttrBitLen)), nil)
+	return b.Sub(b, big.NewInt(1))
+}
+
+// PredicateProof proves that the attribute satisfies Predicate.
+type PredicateProof struct {
+	Predicate *Predicate
+	// Commitment is a commitment to the attribute
+	Commitment *big.Int
+	// ProofRandomData and ProofData prove that Commitment hides the same value as is
+	// used in the credential proof (the response for the attribute is taken
+	// from the credential proof)
+	ProofRandomData *big.Int
+	ProofData       *big.Int
+	// the fields below are for the range proof
+	SmallCommitments1     []*big.Int
+	BigCommitments1       []*big.Int
+	SmallCommitments2     []*big.Int
+	BigCommitments2       []*big.Int
+	RangeProofRandomData1 []*big.Int
+	RangeProofRandomData2 []*big.Int
+	RangeProofData1       []*big.Int
+	RangeProofData2       []*big.Int
+}
+
+// challengeData returns all values of the proof that need to be included in
+// the computation of the challenge.
+func (p *PredicateProof) challengeData() []*big.Int {
+	l := []*big.Int{big.NewInt(int64(p.Predicate.AttrIndex)), p.Predicate.Min, p.Predicate.Max,
+		p.Commitment, p.ProofRandomData}
+	for _, s := range [][]*big.Int{p.SmallCommitments1, p.BigCommitments1,
+		p.SmallCommitments2, p.BigCommitments2,
+		p.RangeProofRandomData1, p.RangeProofRandomData2} {
+		l = append(l, s...)
+	}
+
+	return l
+}
+
+// isComplete checks that the proof (which might come from an untrusted source) contains
+// all the values.
+func (p *PredicateProof) isComplete() bool {
+	if p.Predicate == nil {
+		return false
+	}
+	l := append(p.challengeData(), p.ProofData)
+	l = append(l, p.RangeProofData1...)
+	l = append(l, p.RangeProofData2...)
+
+	return !containsNil(l...)
+}
+
+type predicateProver struct {
+	*attrCommitter
+	rangeProver *df.RangeProver
+	proof       *PredicateProof
+}
+
+func newPredicateProver(params *Params, pubKey *PubKey, predicate *Predicate,
+	attr *big.Int) (*predicateProver, error) {
+	if attr.Cmp(predicate.Min) < 0 || attr.Cmp(predicate.Max) > 0 {
+		return nil, fmt.Errorf("attribute %d does not satisfy the predicate", predicate.AttrIndex)
+	}
+
+	ac, commitment, err := newAttrCommitter(params, pubKey, attr)
+	if err != nil {
+		return nil, err
+	}
+
+	rangeProver, err := df.NewRangeProver(ac.committer, attr, predicate.Min, predicate.Max,
+		int(params.ChallengeSpace))
+	if err != nil {
+		return nil, fmt.Errorf("error when creating range prover: %s", err)
+	}
+
+	sc1, bc1, sc2, bc2 := rangeProver.GetVerifierInitializationData()
+
+	return &predicateProver{
+		attrCommitter: ac,
+		rangeProver:   rangeProver,
+		proof: &PredicateProof{
+			Predicate:         predicate,
+			Commitment:        commitment,
+			SmallCommitments1: sc1,
+			BigCommitments1:   bc1,
+			SmallCommitments2: sc2,
+			BigCommitments2:   bc2,
+		},
+	}, nil
+}
+
+// getProofRandomData returns the values which need to be included in the computation
+// of the challenge. Random value rM needs to be the same as the random value used for
+// the attribute in the credential proof.
+func (p *predicateProver) getProofRandomData(rM *big.Int) []*big.Int {
+	p.proof.ProofRandomData = p.attrCommitter.getProofRandomData(rM)
+	p.proof.RangeProofRandomData1, p.proof.RangeProofRandomData2 = p.rangeProver.GetProofRandomData()
+
+	return p.proof.challengeData()
+}
+
+func (p *predicateProver) getProof(challenge *big.Int) (*PredicateProof, error) {
+	p.proof.ProofData = p.attrCommitter.getProofData(challenge)
+
+	challenges1, challenges2 := getRangeProofChallenges(p.params, challenge,
+		len(p.proof.SmallCommitments1), len(p.proof.SmallCommitments2))
+	proofData1, proofData2, err := p.rangeProver.GetProofData(challenges1, challenges2)
+	if err != nil {
+		return nil, err
+	}
+	p.proof.RangeProofData1 = proofData1
+	p.proof.RangeProofData2 = proofData2
+
+	return p.proof, nil
+}
+
+// getRangeProofChallenges derives challenges for the range proof (which consists of
+// several sigma protocols) from the challenge of the credential proof.
+func getRangeProofChallenges(params *Params, challenge *big.Int, n1, n2 int) ([]*big.Int,
+	[]*big.Int) {
+	b := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(params.ChallengeSpace)), nil)
+	challenges := make([]*big.Int, n1+n2)
+	for i, _ := range challenges {
+		c := common.Hash(challenge, big.NewInt(int64(i)))
+		challenges[i] = c.Mod(c, b)
+	}
+
+	return challenges[:n1], challenges[n1:]
+}
+
+// verifyPredicateProof verifies the predicate proof. Parameter sM is the response for
+// the attribute from the credential proof.
+func verifyPredicateProof(params *Params, pubKey *PubKey, proof *PredicateProof,
+	challenge, sM *big.Int) (bool, error) {
+	// range proof consists of two positive proofs, each of them of four square proofs
+	for _, s := range [][]*big.Int{proof.SmallCommitments1, proof.BigCommitments1,
+		proof.SmallCommitments2, proof.BigCommitments2} {
+		if len(s) != 4 {
+			return false, fmt.Errorf("range proof is not complete")
+		}
+	}
+
+	if !verifyAttrCommitment(pubKey, proof.Commitment, proof.ProofRandomData, proof.ProofData,
+		challenge, sM) {
+		return false, nil
+	}
+
+	receiver := df.NewReceiverFromPublicParams(pubKey.N1, pubKey.G, pubKey.H,
+		int(params.SecParam))
+	receiver.SetCommitment(proof.Commitment)
+	verifier, err := df.NewRangeVerifier(receiver, proof.Predicate.Min, proof.Predicate.Max,
+		proof.SmallCommitments1, proof.BigCommitments1,
+		proof.SmallCommitments2, proof.BigCommitments2, int(params.ChallengeSpace))
+	if err != nil {
+		return false, err
+	}
+
+	if err := verifier.SetProofRandomData(proof.RangeProofRandomData1,
+		proof.RangeProofRandomData2); err != nil {
+		return false, err
+	}
+	challenges1, challenges2 := getRangeProofChallenges(params, challenge,
+		len(proof.SmallCommitments1), len(proof.SmallCommitments2))
+	verifier.SetChallenges(challenges1, challenges2)
+
+	return verifier.Verify(proof.RangeProofData1, proof.RangeProofData2)
+}
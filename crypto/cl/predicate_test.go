@@ -0,0 +1,114 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPredicateProof(t *testing.T) {
+	params := GetDefaultParamSizes()
+	attrCount := NewAttrCount(5, 1, 0)
+
+	org := newTestOrg(t, attrCount, 0)
+
+	rawCred := newTestRawCred(t, attrCount, "Jack")
+	credMgr, res := issueTestCred(t, org, rawCred, nil)
+
+	// DateMin <= 1562643000, DateMax >= 1562643000
+	policy, err := ParseVerificationPolicy(credAttrs(rawCred), []byte(`
+condition:
+  and:
+    - attr: DateMin
+      max: 1562643000
+    - attr: DateMax
+      min: 1562643000
+`))
+	require.NoError(t, err)
+
+	revealed := []int{0}
+	build := func(predicates []*Predicate) (*CredProof, func() (bool, error)) {
+		nonce, err := org.GetProveCredNonce()
+		require.NoError(t, err)
+		proof, err := credMgr.BuildProof(res.Cred, revealed, []int{},
+			&ProofOptions{Predicates: predicates}, nonce)
+		require.NoError(t, err)
+
+		return proof, func() (bool, error) {
+			verified, _, err := org.ProveCred(proof, policy, nonce)
+			return verified, err
+		}
+	}
+
+	_, prove := build([]*Predicate{
+		NewLesserPredicate(params, 3, EncodeInt64(1562643001)),
+		NewGreaterPredicate(params, 4, EncodeInt64(1562642999)),
+	})
+	verified, err := prove()
+	require.NoError(t, err)
+	assert.True(t, verified, "predicate proofs not accepted")
+
+	// the predicate holds even when the difference to the bound is zero
+	_, prove = build([]*Predicate{
+		NewRangePredicate(3, EncodeInt64(1500000000), EncodeInt64(1500000000)),
+		NewRangePredicate(4, EncodeInt64(1600000000), EncodeInt64(1600000000)),
+	})
+	verified, err = prove()
+	require.NoError(t, err)
+	assert.True(t, verified, "predicate proof with tight bounds not accepted")
+
+	// a proof for an attribute which does not satisfy the predicate cannot be built
+	_, err = credMgr.BuildProof(res.Cred, revealed, []int{}, &ProofOptions{
+		Predicates: []*Predicate{NewGreaterPredicate(params, 3, EncodeInt64(1500000000))},
+	}, org.GenNonce())
+	assert.Error(t, err, "proof for unsatisfied predicate should not be built")
+
+	// predicates cannot be proved for revealed attributes
+	_, err = credMgr.BuildProof(res.Cred, revealed, []int{}, &ProofOptions{
+		Predicates: []*Predicate{NewRangePredicate(0, big.NewInt(0), maxAttrValue(params))},
+	}, org.GenNonce())
+	assert.Error(t, err, "proof for revealed attribute should not be built")
+
+	// the predicate needs to imply the condition from the policy
+	_, prove = build([]*Predicate{
+		NewRangePredicate(3, EncodeInt64(0), EncodeInt64(1600000000)),
+		NewRangePredicate(4, EncodeInt64(1590000000), EncodeInt64(1610000000)),
+	})
+	_, err = prove()
+	assert.Error(t, err, "predicate weaker than the condition should not be accepted")
+
+	// all conditions of the policy need to be proved
+	_, prove = build([]*Predicate{
+		NewRangePredicate(3, EncodeInt64(0), EncodeInt64(1562643000)),
+	})
+	_, err = prove()
+	assert.Error(t, err, "proof not satisfying the policy should not be accepted")
+
+	// a proof cannot be reused for a different predicate
+	proof, prove := build([]*Predicate{
+		NewRangePredicate(3, EncodeInt64(0), EncodeInt64(1562643000)),
+		NewRangePredicate(4, EncodeInt64(1590000000), EncodeInt64(1610000000)),
+	})
+	proof.PredicateProofs[1].Predicate.Min = EncodeInt64(1600000000)
+	verified, _ = prove()
+	assert.False(t, verified, "modified predicate should not be accepted")
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Presentation is a non-interactive proof of the possession of a credential. It is bound
+// to the context (a string or a nonce chosen by the verifier) instead of a nonce obtained
+// from the organization, thus it can be built without a connection to the verifier
+// (for example to be transferred via QR code or NFC) and verified later by anyone who
+// holds the public key of the issuer (see VerifyPresentation).
+type Presentation struct {
+	// IssuerKeyID identifies the public key of the issuer (see PubKey.GetID)
+	IssuerKeyID string
+	Context     []byte
+	// Proof contains only A of the randomized credential
+	Proof *CredProof
+}
+
+func (p *Presentation) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *Presentation) UnmarshalBinary(data []byte) error {
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// presentationNonce returns the value which is used in place of the organization's nonce
+// in the computation of the challenge.
+func presentationNonce(context []byte) *big.Int {
+	h := sha256.Sum256(context)
+	return new(big.Int).SetBytes(h[:])
+}
+
+// BuildPresentation builds a presentation of the credential bound to the given context.
+// The parameters are the same as for BuildProof (only predicates and set memberships can
+// accompany a presentation).
+func (m *CredManager) BuildPresentation(cred *Cred, context []byte, revealedKnownAttrsIndices,
+	revealedCommitmentsOfAttrsIndices []int, predicates []*Predicate,
+	setMemberships []*SetMembership) (*Presentation, error) {
+	prover, err := m.newCredProver(cred, revealedKnownAttrsIndices, revealedCommitmentsOfAttrsIndices,
+		&ProofOptions{Predicates: predicates, SetMemberships: setMemberships}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := m.GetProofChallenge(prover.proofRandomData, presentationNonce(context),
+		prover.additionalProofRandomData...)
+	proof, err := prover.getProof(challenge)
+	if err != nil {
+		return nil, err
+	}
+	// e and v of the randomized credential must not be revealed
+	proof.RandCred = &Cred{A: proof.RandCred.A}
+
+	return &Presentation{
+		IssuerKeyID: m.PubKey.GetID(),
+		Context:     context,
+		Proof:       proof,
+	}, nil
+}
+
+// VerifyPresentation verifies that the presentation was built for the given context by
+// the holder of a valid credential issued under pubKey. When pubKey supports revocation,
+// acc needs to hold the current state of the issuer's accumulator (see Accumulator.ApplyUpdates),
+// otherwise it can be nil.
+//
+// Conditions on the revealed attributes (and the bounds of predicates and the sets of set
+// membership proofs) are not checked - the verifier needs to check them in p.Proof.
+func VerifyPresentation(params *Params, pubKey *PubKey, acc *Accumulator, p *Presentation,
+	context []byte) (bool, error) {
+	if p.IssuerKeyID != pubKey.GetID() {
+		return false, fmt.Errorf("presentation is for a different issuer key")
+	}
+	if !bytes.Equal(p.Context, context) {
+		return false, fmt.Errorf("presentation is for a different context")
+	}
+	if pubKey.Accumulator != nil && acc == nil {
+		return false, fmt.Errorf("accumulator is needed to verify the non-revocation proof")
+	}
+	if err := checkCredProofStructure(pubKey, p.Proof); err != nil {
+		return false, err
+	}
+
+	o, err := NewOrgFromParams(params, &KeyPair{Pub: pubKey})
+	if err != nil {
+		return false, err
+	}
+	o.Accumulator = acc
+
+	accValue, err := o.checkChallenge(p.Proof, presentationNonce(context))
+	if err != nil {
+		return false, err
+	}
+
+	return o.verifyCredProof(p.Proof, accValue)
+}
+
+// checkCredProofStructure checks that the credential proof (which might come from an
+// untrusted source) is complete and consistent with the public key.
+func checkCredProofStructure(pubKey *PubKey, p *CredProof) error {
+	if p == nil || p.Proof == nil || p.RandCred == nil || p.RandCred.A == nil ||
+		p.Proof.ProofRandomData == nil || p.Proof.Challenge == nil {
+		return fmt.Errorf("credential proof is not complete")
+	}
+	if len(p.RevealedKnownAttrs) != len(p.RevealedKnownAttrsIndices) ||
+		len(p.RevealedCommitmentsOfAttrs) != len(p.RevealedCommitmentsOfAttrsIndices) {
+		return fmt.Errorf("revealed attributes do not match their indices")
+	}
+	if containsNil(p.RevealedKnownAttrs...) || containsNil(p.RevealedCommitmentsOfAttrs...) {
+		return fmt.Errorf("revealed attributes are not complete")
+	}
+	if np := p.NonRevProof; np != nil && (containsNil(np.Commitments...) ||
+		containsNil(np.ProofRandomData...) || containsNil(np.ProofData...)) {
+		return fmt.Errorf("non-revocation proof is not complete")
+	}
+	for _, pp := range p.PredicateProofs {
+		if pp == nil || !pp.isComplete() {
+			return fmt.Errorf("predicate proof is not complete")
+		}
+	}
+	for _, sp := range p.SetMembershipProofs {
+		if sp == nil || !sp.isComplete() {
+			return fmt.Errorf("set membership proof is not complete")
+		}
+	}
+	if dp := p.DomainPseudonymProof; dp != nil && (dp.Pseudonym == nil || dp.ProofRandomData == nil) {
+		return fmt.Errorf("domain pseudonym proof is not complete")
+	}
+	if err := checkIndices(p.RevealedKnownAttrsIndices, len(pubKey.RsKnown)); err != nil {
+		return err
+	}
+	if err := checkIndices(p.RevealedCommitmentsOfAttrsIndices, len(pubKey.RsCommitted)); err != nil {
+		return err
+	}
+
+	// one response for each unrevealed and hidden attribute, e and v
+	n := len(pubKey.RsKnown) - len(p.RevealedKnownAttrsIndices) +
+		len(pubKey.RsCommitted) - len(p.RevealedCommitmentsOfAttrsIndices) +
+		len(pubKey.RsHidden) + 2
+	if len(p.Proof.ProofData) != n {
+		return fmt.Errorf("credential proof data is not complete")
+	}
+	for _, d := range p.Proof.ProofData {
+		if d == nil {
+			return fmt.Errorf("credential proof data is not complete")
+		}
+	}
+
+	return nil
+}
+
+// containsNil returns true if any of the values is nil.
+func containsNil(values ...*big.Int) bool {
+	for _, v := range values {
+		if v == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkIndices checks that indices are distinct and smaller than n.
+func checkIndices(indices []int, n int) error {
+	seen := make(map[int]bool)
+	for _, ind := range indices {
+		if ind < 0 || ind >= n || seen[ind] {
+			return fmt.Errorf("invalid index of revealed attribute: %d", ind)
+		}
+		seen[ind] = true
+	}
+
+	return nil
+}
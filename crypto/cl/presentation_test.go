@@ -0,0 +1,102 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresentation(t *testing.T) {
+	params := GetDefaultParamSizes()
+	attrCount := NewAttrCount(5, 1, 0)
+
+	org := newTestOrg(t, attrCount, 0)
+
+	issue := func() (*CredManager, *Cred) {
+		credMgr, res := issueTestCred(t, org, newTestRawCred(t, attrCount, "Jack"), nil)
+		return credMgr, res.Cred
+	}
+	credMgr, cred := issue()
+
+	// the verifier has only the public key and a copy of the public accumulator
+	pubKey := org.Keys.Pub
+	acc, err := NewAccumulator(pubKey.Accumulator, nil)
+	require.NoError(t, err)
+
+	context := []byte("verifier 1, session 42")
+	p, err := credMgr.BuildPresentation(cred, context, []int{0}, []int{},
+		[]*Predicate{NewLesserPredicate(params, 3, EncodeInt64(1562643000))}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, p.Proof.RandCred.E, "e of the randomized credential should not be in the token")
+
+	// the presentation is transferred as a token
+	token, err := p.MarshalBinary()
+	require.NoError(t, err)
+	p = new(Presentation)
+	err = p.UnmarshalBinary(token)
+	require.NoError(t, err)
+
+	verified, err := VerifyPresentation(params, pubKey, acc, p, context)
+	require.NoError(t, err)
+	assert.True(t, verified, "presentation not accepted")
+	name, _ := new(StrAttr).FromInternalValue(p.Proof.RevealedKnownAttrs[0])
+	assert.Equal(t, "Jack", name)
+
+	_, err = VerifyPresentation(params, pubKey, acc, p, []byte("verifier 2"))
+	assert.Error(t, err, "presentation for a different context should not be accepted")
+
+	_, err = VerifyPresentation(params, pubKey, nil, p, context)
+	assert.Error(t, err, "presentation should not be verified without the accumulator")
+
+	p.Proof.RevealedKnownAttrs[0] = new(big.Int).SetBytes([]byte("John"))
+	verified, _ = VerifyPresentation(params, pubKey, acc, p, context)
+	assert.False(t, verified, "presentation with modified attribute should not be accepted")
+
+	// after a revocation of some other credential, the verifier applies the update and
+	// old presentations are not accepted anymore
+	otherMgr, otherCred := issue()
+	update, err := org.Accumulator.Revoke(otherCred.E)
+	require.NoError(t, err)
+	err = acc.ApplyUpdates([]*AccumulatorUpdate{update})
+	require.NoError(t, err)
+
+	p, err = credMgr.BuildPresentation(cred, context, []int{0}, []int{}, nil, nil)
+	require.NoError(t, err)
+	_, err = VerifyPresentation(params, pubKey, acc, p, context)
+	assert.Error(t, err, "presentation for an old accumulator epoch should not be accepted")
+
+	err = credMgr.UpdateWitness(cred, []*AccumulatorUpdate{update})
+	require.NoError(t, err)
+	p, err = credMgr.BuildPresentation(cred, context, []int{0}, []int{}, nil, nil)
+	require.NoError(t, err)
+	verified, err = VerifyPresentation(params, pubKey, acc, p, context)
+	require.NoError(t, err)
+	assert.True(t, verified, "presentation with updated witness not accepted")
+
+	// the revoked credential cannot be presented anymore
+	err = otherMgr.UpdateWitness(otherCred, []*AccumulatorUpdate{update})
+	assert.Error(t, err, "witness of a revoked credential should not be updatable")
+
+	// forged accumulator updates are rejected
+	err = acc.ApplyUpdates([]*AccumulatorUpdate{NewAccumulatorUpdate(2, big.NewInt(3), big.NewInt(5))})
+	assert.Error(t, err, "forged accumulator update should not be applied")
+}
@@ -18,8 +18,11 @@
 package cl
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
+	"time"
 )
 
 // RawCred represents a credential to be used by application that
@@ -30,6 +33,7 @@ type RawCred struct {
 	attrCount            *AttrCount
 	attrKnownIndices     map[string]int // positions of known attributes amongst known attributes
 	attrCommittedIndices map[string]int // positions of commited attributes amongst committed attributes
+	attrHiddenIndices    map[string]int // positions of hidden attributes amongst hidden attributes
 }
 
 func NewRawCred(c *AttrCount) *RawCred {
@@ -39,6 +43,7 @@ func NewRawCred(c *AttrCount) *RawCred {
 		attrCount:            c,
 		attrKnownIndices:     make(map[string]int),
 		attrCommittedIndices: make(map[string]int),
+		attrHiddenIndices:    make(map[string]int),
 	}
 }
 
@@ -63,8 +68,20 @@ func (c *RawCred) GetAttr(name string) (CredAttr, error) {
 	return c.attrs[i], nil
 }
 
+// AddEmptyAttr adds an attribute of any type (see NewEmptyAttr), its value is to be
+// set by CredAttr.UpdateValue.
+func (c *RawCred) AddEmptyAttr(a CredAttr) error {
+	if err := c.validateAttr(a.GetName(), a.IsKnown(), a.IsHidden()); err != nil {
+		return err
+	}
+	i := len(c.attrs)
+	c.insertAttr(i, a)
+
+	return nil
+}
+
 func (c *RawCred) AddEmptyStrAttr(name string, known bool) error {
-	if err := c.validateAttr(name, known); err != nil {
+	if err := c.validateAttr(name, known, false); err != nil {
 		return err
 	}
 	i := len(c.attrs)
@@ -93,7 +110,7 @@ func (c *RawCred) AddInt64Attr(name string, val int64, known bool) error {
 }
 
 func (c *RawCred) AddEmptyInt64Attr(name string, known bool) error {
-	if err := c.validateAttr(name, known); err != nil {
+	if err := c.validateAttr(name, known, false); err != nil {
 		return err
 	}
 	i := len(c.attrs)
@@ -102,6 +119,50 @@ func (c *RawCred) AddEmptyInt64Attr(name string, known bool) error {
 	return nil
 }
 
+// AddEmptyHiddenStrAttr adds a hidden attribute - its value is set by the credential
+// receiver and is never sent to the issuer.
+func (c *RawCred) AddEmptyHiddenStrAttr(name string) error {
+	if err := c.validateAttr(name, false, true); err != nil {
+		return err
+	}
+	i := len(c.attrs)
+	empty := NewEmptyHiddenStrAttr(name)
+	c.insertAttr(i, empty)
+
+	return nil
+}
+
+func (c *RawCred) AddHiddenStrAttr(name, val string) error {
+	if err := c.AddEmptyHiddenStrAttr(name); err != nil {
+		return err
+	}
+
+	a, _ := c.GetAttr(name)
+	return a.UpdateValue(val)
+}
+
+// AddEmptyHiddenInt64Attr adds a hidden attribute - its value is set by the credential
+// receiver and is never sent to the issuer.
+func (c *RawCred) AddEmptyHiddenInt64Attr(name string) error {
+	if err := c.validateAttr(name, false, true); err != nil {
+		return err
+	}
+	i := len(c.attrs)
+	empty := NewEmptyHiddenInt64Attr(name)
+	c.insertAttr(i, empty)
+
+	return nil
+}
+
+func (c *RawCred) AddHiddenInt64Attr(name string, val int64) error {
+	if err := c.AddEmptyHiddenInt64Attr(name); err != nil {
+		return err
+	}
+
+	a, _ := c.GetAttr(name)
+	return a.UpdateValue(val)
+}
+
 // GetKnownVals returns *big.Int values of known attributes.
 // The returned elements are ordered by attribute's index.
 func (c *RawCred) GetKnownVals() []*big.Int {
@@ -123,7 +184,21 @@ func (c *RawCred) GetCommittedVals() []*big.Int {
 	for i := 0; i < len(c.attrs); i++ { // avoid range to have attributes in
 		// proper order
 		attr := c.attrs[i]
-		if !attr.IsKnown() {
+		if !attr.IsKnown() && !attr.IsHidden() {
+			values = append(values, attr.InternalValue())
+		}
+	}
+
+	return values
+}
+
+// GetHiddenVals returns *big.Int values of hidden attributes.
+// The returned elements are ordered by attribute's index.
+func (c *RawCred) GetHiddenVals() []*big.Int {
+	var values []*big.Int
+	for i := 0; i < len(c.attrs); i++ { // avoid range to have attributes in proper order
+		attr := c.attrs[i]
+		if attr.IsHidden() {
 			values = append(values, attr.InternalValue())
 		}
 	}
@@ -142,6 +217,8 @@ func (c *RawCred) GetAttrInternalIndex(attrName string) (int, error) {
 	}
 	if a.IsKnown() {
 		return c.attrKnownIndices[attrName], nil
+	} else if a.IsHidden() {
+		return c.attrHiddenIndices[attrName], nil
 	} else {
 		return c.attrCommittedIndices[attrName], nil
 	}
@@ -152,17 +229,23 @@ func (c *RawCred) insertAttr(i int, a CredAttr) {
 	c.attrs[i] = a
 	if a.IsKnown() {
 		c.attrKnownIndices[a.GetName()] = len(c.attrKnownIndices)
+	} else if a.IsHidden() {
+		c.attrHiddenIndices[a.GetName()] = len(c.attrHiddenIndices)
 	} else {
 		c.attrCommittedIndices[a.GetName()] = len(c.attrCommittedIndices)
 	}
 }
 
-func (c *RawCred) validateAttr(name string, known bool) error {
+func (c *RawCred) validateAttr(name string, known, hidden bool) error {
 	if known && len(c.GetKnownVals()) >= c.attrCount.Known {
 		return fmt.Errorf("known attributes exhausted")
 	}
 
-	if !known && len(c.GetCommittedVals()) >= c.attrCount.Committed {
+	if hidden && len(c.GetHiddenVals()) >= c.attrCount.Hidden {
+		return fmt.Errorf("hidden attributes exhausted")
+	}
+
+	if !known && !hidden && len(c.GetCommittedVals()) >= c.attrCount.Committed {
 		return fmt.Errorf("committed attributes exhausted")
 	}
 
@@ -186,3 +269,126 @@ func (c *RawCred) hasAttr(name string) bool {
 
 	return false
 }
+
+// attrJSON is the JSON encoding of an attribute of RawCred. Value is the string
+// representation of the attribute value as accepted by UpdateValueFromString, it is
+// omitted when the value is not set.
+type attrJSON struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Known  bool     `json:"known"`
+	Hidden bool     `json:"hidden"`
+	Values []string `json:"values,omitempty"`
+	Value  *string  `json:"value,omitempty"`
+}
+
+type rawCredJSON struct {
+	AttrCount *AttrCount  `json:"attr_count"`
+	Attrs     []*attrJSON `json:"attrs"`
+}
+
+// MarshalJSON encodes the raw credential - attributes are encoded in the order of their
+// indices, together with their types and values.
+func (c *RawCred) MarshalJSON() ([]byte, error) {
+	r, err := c.toJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(r)
+}
+
+// MarshalCanonical returns the value the raw credential is encoded as in the canonical
+// encoding (see EncodeJSON) - the same as in MarshalJSON.
+func (c *RawCred) MarshalCanonical() (interface{}, error) {
+	return c.toJSON()
+}
+
+func (c *RawCred) toJSON() (*rawCredJSON, error) {
+	attrs := make([]*attrJSON, len(c.attrs))
+	for i := 0; i < len(c.attrs); i++ {
+		a := c.attrs[i]
+		attr := &attrJSON{
+			Name:   a.GetName(),
+			Known:  a.IsKnown(),
+			Hidden: a.IsHidden(),
+		}
+		var val string
+		switch t := a.(type) {
+		case *Int64Attr:
+			attr.Type = Int64AttrType
+			val = strconv.FormatInt(t.val, 10)
+		case *StrAttr:
+			attr.Type = StrAttrType
+			val = t.val
+		case *HashedStrAttr:
+			attr.Type = HashedStrAttrType
+			val = t.val
+		case *DateAttr:
+			attr.Type = DateAttrType
+			val = t.val.Format(time.RFC3339)
+		case *BoolAttr:
+			attr.Type = BoolAttrType
+			val = strconv.FormatBool(t.val)
+		case *EnumAttr:
+			attr.Type = EnumAttrType
+			attr.Values = t.Values
+			val = t.val
+		default:
+			return nil, fmt.Errorf("attribute %s of unsupported type %T", a.GetName(), a)
+		}
+		if a.HasVal() {
+			attr.Value = &val
+		}
+		attrs[i] = attr
+	}
+
+	return &rawCredJSON{
+		AttrCount: c.attrCount,
+		Attrs:     attrs,
+	}, nil
+}
+
+// UnmarshalJSON decodes the raw credential encoded by MarshalJSON.
+func (c *RawCred) UnmarshalJSON(data []byte) error {
+	var r rawCredJSON
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+
+	return c.fromJSON(&r)
+}
+
+// UnmarshalCanonical decodes the raw credential from the canonical encoding.
+func (c *RawCred) UnmarshalCanonical(decode func(v interface{}) error) error {
+	var r rawCredJSON
+	if err := decode(&r); err != nil {
+		return err
+	}
+
+	return c.fromJSON(&r)
+}
+
+func (c *RawCred) fromJSON(r *rawCredJSON) error {
+	if r.AttrCount == nil {
+		return fmt.Errorf("raw credential misses the attribute count")
+	}
+
+	*c = *NewRawCred(r.AttrCount)
+	for _, attr := range r.Attrs {
+		a, err := NewEmptyAttr(attr.Name, attr.Type, attr.Known, attr.Hidden, attr.Values...)
+		if err != nil {
+			return err
+		}
+		if attr.Value != nil {
+			if err := UpdateValueFromString(a, *attr.Value); err != nil {
+				return err
+			}
+		}
+		if err := c.AddEmptyAttr(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -18,9 +18,12 @@
 package cl
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRawCred_EmptyAttributeName(t *testing.T) {
@@ -44,6 +47,30 @@ func TestRawCred_ExceedCommittedAttrsCount(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestRawCred_ExceedHiddenAttrsCount(t *testing.T) {
+	nAttrs := NewAttrCount(1, 1, 0)
+	rc := NewRawCred(nAttrs)
+	err := rc.AddHiddenInt64Attr("a", 0)
+	assert.Error(t, err)
+}
+
+func TestRawCred_AddHiddenAttr(t *testing.T) {
+	c := NewRawCred(NewAttrCount(1, 1, 1))
+	_ = c.AddInt64Attr("Age", 122, true)
+	err := c.AddHiddenStrAttr("DeviceKey", "secret")
+	assert.NoError(t, err)
+	_ = c.AddInt64Attr("Income", 10, false)
+
+	a, _ := c.GetAttr("DeviceKey")
+	assert.True(t, a.IsHidden())
+	assert.False(t, a.IsKnown())
+	assert.Len(t, c.GetHiddenVals(), 1)
+	assert.Len(t, c.GetCommittedVals(), 1)
+	ind, err := c.GetAttrInternalIndex("DeviceKey")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ind)
+}
+
 func TestRawCred_AddInt64Attr(t *testing.T) {
 	c := NewRawCred(NewAttrCount(1, 0, 0))
 	err := c.AddInt64Attr("Age", 122, true)
@@ -51,6 +78,39 @@ func TestRawCred_AddInt64Attr(t *testing.T) {
 	assert.Len(t, c.GetAttrs(), 1)
 }
 
+func TestRawCred_JSON(t *testing.T) {
+	c := NewRawCred(NewAttrCount(3, 1, 1))
+	_ = c.AddStrAttr("Name", "Jack", true)
+	gender, _ := NewEmptyAttr("Gender", EnumAttrType, true, false, "M", "F")
+	_ = c.AddEmptyAttr(gender)
+	_ = gender.UpdateValue("F")
+	birth, _ := NewEmptyAttr("BirthDate", DateAttrType, true, false)
+	_ = c.AddEmptyAttr(birth)
+	_ = c.AddInt64Attr("Age", -5, false)
+	_ = c.AddEmptyHiddenStrAttr("DeviceKey")
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	var c1 RawCred
+	require.NoError(t, json.Unmarshal(data, &c1))
+
+	assert.Equal(t, c.GetKnownVals(), c1.GetKnownVals())
+	assert.Equal(t, c.GetCommittedVals(), c1.GetCommittedVals())
+	a, err := c1.GetAttr("Gender")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"M", "F"}, a.(*EnumAttr).Values)
+	assert.Equal(t, "F", a.GetValue())
+	// attributes without values stay empty
+	a, err = c1.GetAttr("BirthDate")
+	require.NoError(t, err)
+	assert.False(t, a.HasVal())
+	assert.True(t, a.GetValue().(time.Time).IsZero())
+	a, err = c1.GetAttr("DeviceKey")
+	require.NoError(t, err)
+	assert.True(t, a.IsHidden())
+	assert.False(t, a.HasVal())
+}
+
 /*
  func TestRawCred_AddStringAttribute(t *testing.T) {
 	 rc := NewRawCred()
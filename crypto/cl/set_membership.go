@@ -0,0 +1,223 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+// SetMembership states that a known attribute, which is not revealed to the verifier,
+// is one of Values (internal values of attributes, see CredAttr.InternalValue).
+// AttrIndex is the index of the attribute among known attributes.
+//
+// The attribute is committed in a DF commitment C = G^m * H^r, the commitment is proved
+// to hide the same value as used in the credential proof and an OR-composition of proofs
+// of knowledge of log_H(C / G^v) (one for each v in Values) is used to prove that
+// the committed value is one of the values.
+type SetMembership struct {
+	AttrIndex int
+	Values    []*big.Int
+}
+
+func NewSetMembership(attrIndex int, values []*big.Int) *SetMembership {
+	return &SetMembership{
+		AttrIndex: attrIndex,
+		Values:    values,
+	}
+}
+
+// SetMembershipProof proves that the attribute satisfies SetMembership.
+type SetMembershipProof struct {
+	SetMembership *SetMembership
+	// Commitment is a commitment to the attribute
+	Commitment *big.Int
+	// ProofRandomData and ProofData prove that Commitment hides the same value as is
+	// used in the credential proof
+	ProofRandomData *big.Int
+	ProofData       *big.Int
+	// the fields below are for the OR proof, each of them holds one value for each
+	// of SetMembership.Values
+	OrProofRandomData []*big.Int
+	OrChallenges      []*big.Int
+	OrProofData       []*big.Int
+}
+
+// challengeData returns all values of the proof that need to be included in
+// the computation of the challenge.
+func (p *SetMembershipProof) challengeData() []*big.Int {
+	l := []*big.Int{big.NewInt(int64(p.SetMembership.AttrIndex))}
+	l = append(l, p.SetMembership.Values...)
+	l = append(l, p.Commitment, p.ProofRandomData)
+
+	return append(l, p.OrProofRandomData...)
+}
+
+// isComplete checks that the proof (which might come from an untrusted source) contains
+// all the values.
+func (p *SetMembershipProof) isComplete() bool {
+	if p.SetMembership == nil {
+		return false
+	}
+	l := append(p.challengeData(), p.ProofData)
+	l = append(l, p.OrChallenges...)
+	l = append(l, p.OrProofData...)
+
+	return !containsNil(l...)
+}
+
+type setMembershipProver struct {
+	*attrCommitter
+	proof *SetMembershipProof
+	// index of the attribute value in SetMembership.Values
+	index   int
+	randomK *big.Int
+}
+
+func newSetMembershipProver(params *Params, pubKey *PubKey, set *SetMembership,
+	attr *big.Int) (*setMembershipProver, error) {
+	index := -1
+	for i, v := range set.Values {
+		if v.Cmp(attr) == 0 {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, fmt.Errorf("attribute %d is not in the set", set.AttrIndex)
+	}
+
+	ac, commitment, err := newAttrCommitter(params, pubKey, attr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &setMembershipProver{
+		attrCommitter: ac,
+		proof: &SetMembershipProof{
+			SetMembership: set,
+			Commitment:    commitment,
+		},
+		index: index,
+	}, nil
+}
+
+// getProofRandomData returns the values which need to be included in the computation
+// of the challenge. Random value rM needs to be the same as the random value used for
+// the attribute in the credential proof.
+func (p *setMembershipProver) getProofRandomData(rM *big.Int) []*big.Int {
+	p.proof.ProofRandomData = p.attrCommitter.getProofRandomData(rM)
+
+	group := p.committer.QRSpecialRSA
+	b := p.orProofDataBound()
+	challengeBound := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(p.params.ChallengeSpace)), nil)
+
+	n := len(p.proof.SetMembership.Values)
+	p.proof.OrProofRandomData = make([]*big.Int, n)
+	p.proof.OrChallenges = make([]*big.Int, n)
+	p.proof.OrProofData = make([]*big.Int, n)
+	for i, v := range p.proof.SetMembership.Values {
+		if i == p.index {
+			p.randomK = common.GetRandomInt(b)
+			p.proof.OrProofRandomData[i] = group.Exp(p.committer.H, p.randomK)
+			continue
+		}
+		// simulate the proof for the values which are not committed:
+		// H^z = t * D^c, where D = Commitment / G^v
+		d := orProofBase(group, p.committer.G, p.proof.Commitment, v)
+		p.proof.OrChallenges[i] = common.GetRandomInt(challengeBound)
+		p.proof.OrProofData[i] = common.GetRandomInt(b)
+		dc := group.Exp(d, new(big.Int).Neg(p.proof.OrChallenges[i]))
+		p.proof.OrProofRandomData[i] = group.Mul(group.Exp(p.committer.H, p.proof.OrProofData[i]), dc)
+	}
+
+	return p.proof.challengeData()
+}
+
+func (p *setMembershipProver) getProof(challenge *big.Int) *SetMembershipProof {
+	p.proof.ProofData = p.attrCommitter.getProofData(challenge)
+
+	// challenge for the real proof is such that all challenges sum up to the OR challenge
+	challengeBound := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(p.params.ChallengeSpace)), nil)
+	c := new(big.Int).Mod(challenge, challengeBound)
+	for i, ci := range p.proof.OrChallenges {
+		if i != p.index {
+			c.Sub(c, ci)
+		}
+	}
+	c.Mod(c, challengeBound)
+	p.proof.OrChallenges[p.index] = c
+
+	_, r := p.committer.GetDecommitMsg()
+	z := new(big.Int).Mul(c, r)
+	p.proof.OrProofData[p.index] = z.Add(z, p.randomK)
+
+	return p.proof
+}
+
+// orProofDataBound returns the bound for the random values of the OR proof - it needs
+// to hide challenge * r, where r (the commitment randomness) is from [0, 2^(B+k)).
+func (p *setMembershipProver) orProofDataBound() *big.Int {
+	exp := p.committer.B + p.committer.K + int(p.params.ChallengeSpace+p.params.SecParam)
+	return new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(exp)), nil)
+}
+
+// orProofBase returns commitment / G^v.
+func orProofBase(group *qr.RSASpecial, g, commitment, v *big.Int) *big.Int {
+	return group.Mul(commitment, group.Exp(g, new(big.Int).Neg(v)))
+}
+
+// verifySetMembershipProof verifies the set membership proof. Parameter sM is the
+// response for the attribute from the credential proof.
+func verifySetMembershipProof(params *Params, pubKey *PubKey, proof *SetMembershipProof,
+	challenge, sM *big.Int) (bool, error) {
+	n := len(proof.SetMembership.Values)
+	if n == 0 || len(proof.OrProofRandomData) != n || len(proof.OrChallenges) != n ||
+		len(proof.OrProofData) != n {
+		return false, fmt.Errorf("set membership proof is not complete")
+	}
+
+	if !verifyAttrCommitment(pubKey, proof.Commitment, proof.ProofRandomData, proof.ProofData,
+		challenge, sM) {
+		return false, nil
+	}
+
+	group := qr.NewRSApecialPublic(pubKey.N1)
+	challengeBound := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(params.ChallengeSpace)), nil)
+	sum := big.NewInt(0)
+	for i, v := range proof.SetMembership.Values {
+		c := proof.OrChallenges[i]
+		if c.Sign() < 0 || c.Cmp(challengeBound) >= 0 {
+			return false, nil
+		}
+		// H^z = t * D^c
+		d := orProofBase(group, pubKey.G, proof.Commitment, v)
+		left := group.Exp(pubKey.H, proof.OrProofData[i])
+		right := group.Mul(proof.OrProofRandomData[i], group.Exp(d, c))
+		if left.Cmp(right) != 0 {
+			return false, nil
+		}
+		sum.Add(sum, c)
+	}
+	sum.Mod(sum, challengeBound)
+
+	return sum.Cmp(new(big.Int).Mod(challenge, challengeBound)) == 0, nil
+}
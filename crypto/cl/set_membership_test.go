@@ -0,0 +1,98 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetMembershipProof(t *testing.T) {
+	attrCount := NewAttrCount(5, 1, 0)
+
+	org := newTestOrg(t, attrCount, 0)
+
+	rawCred := newTestRawCred(t, attrCount, "Jack")
+	graduated, _ := rawCred.GetAttr("Graduated")
+	require.NoError(t, graduated.UpdateValue("yes"))
+	credMgr, res := issueTestCred(t, org, rawCred, nil)
+
+	strVal := func(s string) *big.Int {
+		return new(big.Int).SetBytes([]byte(s))
+	}
+
+	policy, err := NewVerificationPolicy(credAttrs(rawCred), []string{"Name"},
+		&PolicyCondition{Attr: "Graduated", In: []string{"true", "yes"}})
+	require.NoError(t, err)
+
+	revealed := []int{0}
+	build := func(sets []*SetMembership) ([]*SetMembershipProof, func() (bool, error)) {
+		nonce, err := org.GetProveCredNonce()
+		require.NoError(t, err)
+		proof, err := credMgr.BuildProof(res.Cred, revealed, []int{},
+			&ProofOptions{SetMemberships: sets}, nonce)
+		require.NoError(t, err)
+
+		return proof.SetMembershipProofs, func() (bool, error) {
+			verified, _, err := org.ProveCred(proof, policy, nonce)
+			return verified, err
+		}
+	}
+
+	// Graduated is in {"true", "yes"}
+	_, prove := build([]*SetMembership{
+		NewSetMembership(2, []*big.Int{strVal("true"), strVal("yes")}),
+	})
+	verified, err := prove()
+	require.NoError(t, err)
+	assert.True(t, verified, "set membership proof not accepted")
+
+	// attributes without a condition can be proved to be in a set too
+	_, prove = build([]*SetMembership{
+		NewSetMembership(1, []*big.Int{strVal("M"), strVal("F"), strVal("X")}),
+		NewSetMembership(2, []*big.Int{strVal("yes")}),
+	})
+	verified, err = prove()
+	require.NoError(t, err)
+	assert.True(t, verified, "set membership proofs not accepted")
+
+	// a proof for an attribute which is not in the set cannot be built
+	_, err = credMgr.BuildProof(res.Cred, revealed, []int{}, &ProofOptions{
+		SetMemberships: []*SetMembership{NewSetMembership(2, []*big.Int{strVal("true")})},
+	}, org.GenNonce())
+	assert.Error(t, err, "proof for attribute not in the set should not be built")
+
+	// the set needs to contain only acceptable values
+	_, prove = build([]*SetMembership{
+		NewSetMembership(2, []*big.Int{strVal("no"), strVal("yes")}),
+	})
+	_, err = prove()
+	assert.Error(t, err, "set with unacceptable values should not be accepted")
+
+	// a proof cannot be reused for a different set
+	setMembershipProofs, prove := build([]*SetMembership{
+		NewSetMembership(1, []*big.Int{strVal("M"), strVal("F")}),
+		NewSetMembership(2, []*big.Int{strVal("yes")}),
+	})
+	setMembershipProofs[0].SetMembership.Values[0] = strVal("F")
+	verified, _ = prove()
+	assert.False(t, verified, "modified set should not be accepted")
+}
@@ -0,0 +1,730 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package cl
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xlab-si/emmy/crypto/common"
+	"github.com/xlab-si/emmy/crypto/encryption"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+// Threshold issuance splits the secret key of the issuer (the order m = P1*Q1 of QR_N) among n
+// parties, any t of which can jointly compute A = Q^(1/e) of a new credential. The organization
+// which answers the clients (the coordinator) keeps the rest of the secret key (needed for
+// commitments of attributes and for the revocation accumulator), but cannot issue credentials
+// on its own.
+//
+// The order of QR_N needs to stay secret, so the shares are Shamir's shares over the integers
+// (not modulo a known prime as in secretsharing.Dealer): share_i = f(i) where
+// f(x) = m + a_1*x + ... + a_(t-1)*x^(t-1). Any t parties can compute additive shares of
+// Delta*m (Delta = n!) from their shares, as the Lagrange coefficients multiplied by Delta
+// are integers.
+//
+// For each credential, the participating parties compute additive shares of d = e^(-1) mod m
+// (R. Gennaro, D. Catalano, S. Halevi: Computing Inverses over a Shared Secret Modulus):
+//  1. Each party j computes its additive share phi_j of Delta*m, chooses random lambda_j
+//     and R_j and sends its Paillier public key and the encryption of phi_j to all parties.
+//  2. Party i sends to party j the encryption (under j's Paillier key) of lambda_i*phi_j + r_ij
+//     (r_ij is random). The parties thus obtain additive shares s_j of lambda*Delta*m
+//     (lambda = lambda_1 + ... + lambda_t).
+//  3. Each party sends gamma_j = s_j + R_j*e to the coordinator which computes
+//     gamma = lambda*Delta*m + R*e and a, b such that a*gamma + b*e = 1. Then d = a*R + b.
+//  4. Each party sends A_j = Q^(a*R_j) and Q^rho_j (random rho_j), the coordinator
+//     computes A = Q^b * A_1 * ... * A_t and the challenge c for the proof that A = Q^d.
+//  5. Each party sends z_j = rho_j + c*a*R_j, the coordinator computes z = c*b + z_1 + ... + z_t.
+//
+// The parties are assumed to follow the protocol - a misbehaving party can make the issuance
+// fail (the coordinator checks the credential and the proof), but the issuance cannot succeed
+// with a credential which would not be valid.
+
+const thresholdKeyShareFileType = "cl-threshold-key-share"
+
+// Rounds of the threshold issuance protocol (see ThresholdParty.Round).
+const (
+	thresholdRoundStart = iota + 1
+	thresholdRoundMtA
+	thresholdRoundGamma
+	thresholdRoundSign
+	thresholdRoundProof
+)
+
+// ThresholdKeyShare is the share of the issuer's secret key held by one of the parties.
+type ThresholdKeyShare struct {
+	Index     int      // index of the party (from 1 to Parties)
+	Threshold int      // number of parties needed to issue a credential
+	Parties   int      // number of all parties
+	Share     *big.Int // f(Index)
+}
+
+// ThresholdMessage is a message exchanged in the threshold issuance protocol. Index 0
+// denotes the coordinator (To is 0 also for the messages which the coordinator forwards
+// to all parties).
+type ThresholdMessage struct {
+	From   int
+	To     int
+	Values []*big.Int
+}
+
+func NewThresholdMessage(from, to int, values ...*big.Int) *ThresholdMessage {
+	return &ThresholdMessage{
+		From:   from,
+		To:     to,
+		Values: values,
+	}
+}
+
+// SplitSecKey splits the secret key of the issuer (keys.Sec.RsaPrimes) into shares for the given
+// number of parties, any threshold of which are needed to issue a credential. The returned
+// secret key of the coordinator contains all the other parts of keys.Sec.
+func SplitSecKey(params *Params, keys *KeyPair, threshold, parties int) ([]*ThresholdKeyShare,
+	*SecKey, error) {
+	if keys.Sec == nil || keys.Sec.RsaPrimes == nil {
+		return nil, nil, fmt.Errorf("secret key is needed to split it")
+	}
+	if threshold < 2 {
+		return nil, nil, fmt.Errorf("the threshold should be at least 2")
+	}
+	if threshold > parties {
+		return nil, nil, fmt.Errorf("the threshold should not be bigger than the number of parties")
+	}
+
+	m := new(big.Int).Mul(keys.Sec.RsaPrimes.P1, keys.Sec.RsaPrimes.Q1)
+	delta := factorial(parties)
+	coeffBound := new(big.Int).Lsh(big.NewInt(1),
+		uint(params.NLength+2*delta.BitLen()+params.SecParam))
+	coeffs := make([]*big.Int, threshold)
+	coeffs[0] = m
+	for k := 1; k < threshold; k++ {
+		coeffs[k] = common.GetRandomInt(coeffBound)
+	}
+
+	shares := make([]*ThresholdKeyShare, parties)
+	for i := 1; i <= parties; i++ {
+		// f(i) by Horner's rule
+		x := big.NewInt(int64(i))
+		f := new(big.Int)
+		for k := threshold - 1; k >= 0; k-- {
+			f.Mul(f, x)
+			f.Add(f, coeffs[k])
+		}
+		shares[i-1] = &ThresholdKeyShare{
+			Index:     i,
+			Threshold: threshold,
+			Parties:   parties,
+			Share:     f,
+		}
+	}
+
+	coordinatorKey := &SecKey{
+		AttributesSpecialRSAPrimes: keys.Sec.AttributesSpecialRSAPrimes,
+		AccumulatorPrimes:          keys.Sec.AccumulatorPrimes,
+	}
+
+	return shares, coordinatorKey, nil
+}
+
+// WriteThresholdKeyShare writes the share of the secret key which matches pubKey to a new
+// file (readable only by its owner) in the format described by common.KeyFile.
+func WriteThresholdKeyShare(path string, pubKey *PubKey, share *ThresholdKeyShare) error {
+	if err := common.WriteKeyFile(path, thresholdKeyShareFileType, pubKey.GetID(), share,
+		true); err != nil {
+		return errors.Wrap(err, "error writing secret key share")
+	}
+
+	return nil
+}
+
+// ReadThresholdKeyShare reads the share of the secret key which matches pubKey from the file
+// written by WriteThresholdKeyShare.
+func ReadThresholdKeyShare(path string, pubKey *PubKey) (*ThresholdKeyShare, error) {
+	share := new(ThresholdKeyShare)
+	keyID, err := common.ReadKeyFile(path, thresholdKeyShareFileType, share)
+	if err != nil {
+		return nil, err
+	}
+	if keyID != pubKey.GetID() {
+		return nil, fmt.Errorf("secret key share in %s does not match the public key", path)
+	}
+	if share.Share == nil || share.Index < 1 || share.Index > share.Parties ||
+		share.Threshold < 2 || share.Threshold > share.Parties {
+		return nil, fmt.Errorf("secret key share in %s is not valid", path)
+	}
+
+	return share, nil
+}
+
+// thresholdBounds holds the bit lengths of the values used in the threshold issuance.
+type thresholdBounds struct {
+	phi      int // additive shares of Delta*m
+	lambda   int // lambda_j
+	mask     int // r_ij
+	r        int // R_j
+	rho      int // rho_j
+	paillier int // minimal bit length of the Paillier modulus
+}
+
+func newThresholdBounds(params *Params, parties int) *thresholdBounds {
+	delta := factorial(parties)
+	partiesBitLen := big.NewInt(int64(parties)).BitLen()
+	// |f(i)| < t * n^(t-1) * 2^(NLength+2*|Delta|+SecParam) and the Lagrange coefficients
+	// multiplied by Delta are smaller than Delta * n^t
+	phi := params.NLength + params.SecParam + 3*delta.BitLen() + 2*parties*partiesBitLen + 2
+	lambda := params.EBitLen + params.SecParam
+	mask := phi + lambda + params.SecParam
+	r := phi + lambda + partiesBitLen + params.SecParam
+
+	return &thresholdBounds{
+		phi:      phi,
+		lambda:   lambda,
+		mask:     mask,
+		r:        r,
+		rho:      params.EBitLen + r + partiesBitLen + params.HashBitLen + params.SecParam,
+		paillier: mask + partiesBitLen + 4,
+	}
+}
+
+// thresholdSession is the state of one execution of the protocol at a party.
+type thresholdSession struct {
+	sync.Mutex
+	round        int
+	created      time.Time
+	participants []int
+	e            *big.Int
+	Q            *big.Int
+	phi          *big.Int
+	lambda       *big.Int
+	r            *big.Int
+	s            *big.Int
+	a            *big.Int
+	rho          *big.Int
+}
+
+// ThresholdParty holds a share of the issuer's secret key and takes part in the threshold
+// issuance of credentials (see Round). One instance can take part in concurrent issuances.
+type ThresholdParty struct {
+	Params   *Params
+	PubKey   *PubKey
+	Share    *ThresholdKeyShare
+	group    *qr.RSASpecial
+	bounds   *thresholdBounds
+	paillier *encryption.Paillier
+	sessions map[string]*thresholdSession
+	mutex    sync.Mutex
+}
+
+// NewThresholdParty creates the party holding share. It generates the Paillier key
+// used by the party in all issuances.
+func NewThresholdParty(params *Params, pubKey *PubKey, share *ThresholdKeyShare) (*ThresholdParty,
+	error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	bounds := newThresholdBounds(params, share.Parties)
+
+	return &ThresholdParty{
+		Params:   params,
+		PubKey:   pubKey,
+		Share:    share,
+		group:    qr.NewRSApecialPublic(pubKey.N),
+		bounds:   bounds,
+		paillier: encryption.NewPaillier(bounds.paillier/2 + 1),
+		sessions: make(map[string]*thresholdSession),
+	}, nil
+}
+
+// Round executes the round of the protocol execution sessionID (the sessions are chosen
+// by the coordinator) for the messages in and returns the messages which the coordinator
+// needs to collect (or forward to the other parties). The execution is aborted when
+// a round fails.
+func (p *ThresholdParty) Round(sessionID string, round int, in []*ThresholdMessage) (
+	[]*ThresholdMessage, error) {
+	if round == thresholdRoundStart {
+		return p.start(sessionID, in)
+	}
+
+	p.mutex.Lock()
+	s, ok := p.sessions[sessionID]
+	p.mutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("threshold issuance %s does not exist", sessionID)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if round != s.round+1 {
+		p.deleteSession(sessionID)
+		return nil, fmt.Errorf("round %d of threshold issuance is not expected", round)
+	}
+	s.round = round
+
+	var out []*ThresholdMessage
+	var err error
+	switch round {
+	case thresholdRoundMtA:
+		out, err = p.mta(s, in)
+	case thresholdRoundGamma:
+		out, err = p.gamma(s, in)
+	case thresholdRoundSign:
+		out, err = p.sign(s, in)
+	case thresholdRoundProof:
+		out, err = p.prove(s, in)
+		p.deleteSession(sessionID)
+	default:
+		err = fmt.Errorf("round %d of threshold issuance does not exist", round)
+	}
+	if err != nil {
+		p.deleteSession(sessionID)
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (p *ThresholdParty) deleteSession(sessionID string) {
+	p.mutex.Lock()
+	delete(p.sessions, sessionID)
+	p.mutex.Unlock()
+}
+
+// start expects e, Q and the indices of the participating parties from the coordinator.
+func (p *ThresholdParty) start(sessionID string, in []*ThresholdMessage) ([]*ThresholdMessage,
+	error) {
+	if len(in) != 1 || in[0].From != 0 || len(in[0].Values) != 2+p.Share.Threshold {
+		return nil, fmt.Errorf("threshold issuance start message is not valid")
+	}
+	e, Q := in[0].Values[0], in[0].Values[1]
+	if e.BitLen() != p.Params.EBitLen || !e.ProbablyPrime(20) {
+		return nil, fmt.Errorf("e is not a prime of the required length")
+	}
+	if Q.Sign() <= 0 || Q.Cmp(p.PubKey.N) >= 0 {
+		return nil, fmt.Errorf("Q is not in the group")
+	}
+	participants, err := p.getParticipants(in[0].Values[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	phi := lagrangeCoeff(participants, p.Share.Index, p.Share.Parties)
+	phi.Mul(phi, p.Share.Share)
+	// Paillier plaintexts are from Z_n, negative values are represented by n - |x|
+	encPhi, err := p.paillier.Encrypt(new(big.Int).Mod(phi, p.paillier.GetPubKey().GetN()))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &thresholdSession{
+		round:        thresholdRoundStart,
+		created:      time.Now(),
+		participants: participants,
+		e:            e,
+		Q:            Q,
+		phi:          phi,
+		lambda:       common.GetRandomInt(bitBound(p.bounds.lambda)),
+		r:            common.GetRandomInt(bitBound(p.bounds.r)),
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	// drop the executions which were abandoned by the coordinator
+	for id, old := range p.sessions {
+		if time.Since(old.created) > NonceTTL {
+			delete(p.sessions, id)
+		}
+	}
+	if _, ok := p.sessions[sessionID]; ok {
+		return nil, fmt.Errorf("threshold issuance %s already exists", sessionID)
+	}
+	p.sessions[sessionID] = s
+
+	pk := p.paillier.GetPubKey()
+	return []*ThresholdMessage{
+		NewThresholdMessage(p.Share.Index, 0, pk.GetN(), pk.GetG(), encPhi),
+	}, nil
+}
+
+func (p *ThresholdParty) getParticipants(values []*big.Int) ([]int, error) {
+	participants := make([]int, len(values))
+	seen := make(map[int]bool)
+	for i, v := range values {
+		if !v.IsInt64() || v.Int64() < 1 || v.Int64() > int64(p.Share.Parties) || seen[int(v.Int64())] {
+			return nil, fmt.Errorf("participants of threshold issuance are not valid")
+		}
+		participants[i] = int(v.Int64())
+		seen[participants[i]] = true
+	}
+	if !seen[p.Share.Index] {
+		return nil, fmt.Errorf("party %d is not among the participants", p.Share.Index)
+	}
+	sort.Ints(participants)
+
+	return participants, nil
+}
+
+// getMessages checks that in contains exactly one message with valuesNum values from each
+// of the other participants and returns them by the sender.
+func (p *ThresholdParty) getMessages(s *thresholdSession, in []*ThresholdMessage, to,
+	valuesNum int) (map[int]*ThresholdMessage, error) {
+	msgs := make(map[int]*ThresholdMessage)
+	for _, m := range in {
+		if m.From == p.Share.Index || m.To != to || len(m.Values) != valuesNum ||
+			!common.Contains(s.participants, m.From) || msgs[m.From] != nil {
+			return nil, fmt.Errorf("threshold issuance message is not valid")
+		}
+		msgs[m.From] = m
+	}
+	if len(msgs) != len(s.participants)-1 {
+		return nil, fmt.Errorf("threshold issuance messages are missing")
+	}
+
+	return msgs, nil
+}
+
+// mta expects the Paillier public keys and encryptions of phi_j of the other participants.
+func (p *ThresholdParty) mta(s *thresholdSession, in []*ThresholdMessage) ([]*ThresholdMessage,
+	error) {
+	msgs, err := p.getMessages(s, in, 0, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	s.s = new(big.Int).Mul(s.lambda, s.phi)
+	var out []*ThresholdMessage
+	for _, j := range s.participants {
+		m, ok := msgs[j]
+		if !ok {
+			continue
+		}
+		n, g, encPhi := m.Values[0], m.Values[1], m.Values[2]
+		if n.BitLen() < p.bounds.paillier || encPhi.Cmp(new(big.Int).Mul(n, n)) >= 0 {
+			return nil, fmt.Errorf("Paillier key of party %d is not valid", j)
+		}
+		pub := encryption.NewPubPaillier(encryption.NewPaillierPubKey(n, g))
+		// Enc(lambda_i*phi_j + r_ij), r_ij is subtracted from the own share
+		mask := common.GetRandomInt(bitBound(p.bounds.mask))
+		encMask, err := pub.Encrypt(mask)
+		if err != nil {
+			return nil, err
+		}
+		c := pub.Add(pub.MulConst(encPhi, s.lambda), encMask)
+		s.s.Sub(s.s, mask)
+		out = append(out, NewThresholdMessage(p.Share.Index, j, c))
+	}
+
+	return out, nil
+}
+
+// gamma expects the encryptions of lambda_j*phi_i + r_ji from the other participants.
+func (p *ThresholdParty) gamma(s *thresholdSession, in []*ThresholdMessage) ([]*ThresholdMessage,
+	error) {
+	msgs, err := p.getMessages(s, in, p.Share.Index, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	n := p.paillier.GetPubKey().GetN()
+	halfN := new(big.Int).Rsh(n, 1)
+	for _, m := range msgs {
+		c := new(big.Int).Set(m.Values[0]) // Decrypt modifies its argument
+		alpha, err := p.paillier.Decrypt(c)
+		if err != nil {
+			return nil, err
+		}
+		if alpha.Cmp(halfN) > 0 {
+			alpha.Sub(alpha, n)
+		}
+		s.s.Add(s.s, alpha)
+	}
+
+	gamma := new(big.Int).Mul(s.r, s.e)
+	gamma.Add(gamma, s.s)
+
+	return []*ThresholdMessage{NewThresholdMessage(p.Share.Index, 0, gamma)}, nil
+}
+
+// sign expects a (such that a*gamma + b*e = 1) from the coordinator.
+func (p *ThresholdParty) sign(s *thresholdSession, in []*ThresholdMessage) ([]*ThresholdMessage,
+	error) {
+	if len(in) != 1 || in[0].From != 0 || len(in[0].Values) != 1 ||
+		in[0].Values[0].BitLen() > p.Params.EBitLen {
+		return nil, fmt.Errorf("threshold issuance sign message is not valid")
+	}
+	s.a = in[0].Values[0]
+
+	aR := new(big.Int).Mul(s.a, s.r)
+	s.rho = common.GetRandomInt(bitBound(p.bounds.rho))
+
+	return []*ThresholdMessage{
+		NewThresholdMessage(p.Share.Index, 0, p.group.Exp(s.Q, aR), p.group.Exp(s.Q, s.rho)),
+	}, nil
+}
+
+// prove expects the challenge for the proof that A = Q^d from the coordinator.
+func (p *ThresholdParty) prove(s *thresholdSession, in []*ThresholdMessage) ([]*ThresholdMessage,
+	error) {
+	if len(in) != 1 || in[0].From != 0 || len(in[0].Values) != 1 ||
+		in[0].Values[0].Sign() < 0 || in[0].Values[0].BitLen() > p.Params.HashBitLen {
+		return nil, fmt.Errorf("threshold issuance challenge is not valid")
+	}
+	c := in[0].Values[0]
+
+	z := new(big.Int).Mul(c, s.a)
+	z.Mul(z, s.r)
+	z.Add(z, s.rho)
+
+	return []*ThresholdMessage{NewThresholdMessage(p.Share.Index, 0, z)}, nil
+}
+
+// ThresholdPartyConn is the coordinator's connection to a party holding a share of the
+// issuer's secret key (see ThresholdParty.Round).
+type ThresholdPartyConn interface {
+	Round(sessionID string, round int, in []*ThresholdMessage) ([]*ThresholdMessage, error)
+}
+
+// ThresholdSigner is the CredSigner of the coordinator - it computes A = Q^(1/e) together
+// with the parties holding the shares of the secret key.
+type ThresholdSigner struct {
+	Params    *Params
+	PubKey    *PubKey
+	Threshold int
+	// Parties maps the indices of the shares to the connections to the parties holding them.
+	Parties map[int]ThresholdPartyConn
+	group   *qr.RSASpecial
+}
+
+func NewThresholdSigner(params *Params, pubKey *PubKey, threshold int,
+	parties map[int]ThresholdPartyConn) (*ThresholdSigner, error) {
+	if threshold < 2 || threshold > len(parties) {
+		return nil, fmt.Errorf("threshold %d is not valid for %d parties", threshold, len(parties))
+	}
+
+	return &ThresholdSigner{
+		Params:    params,
+		PubKey:    pubKey,
+		Threshold: threshold,
+		Parties:   parties,
+		group:     qr.NewRSApecialPublic(pubKey.N),
+	}, nil
+}
+
+// SignCred computes A = Q^(1/e) and the proof that A has been computed correctly. When
+// a party fails, the issuance is repeated without it (as long as enough parties remain).
+func (s *ThresholdSigner) SignCred(Q, e, nonceUser *big.Int) (*big.Int, *qr.RepresentationProof,
+	error) {
+	var candidates []int
+	for i := range s.Parties {
+		candidates = append(candidates, i)
+	}
+	sort.Ints(candidates)
+
+	var lastErr error
+	for len(candidates) >= s.Threshold {
+		A, proof, failed, err := s.sign(candidates[:s.Threshold], Q, e, nonceUser)
+		if err == nil {
+			return A, proof, nil
+		}
+		if failed == 0 {
+			return nil, nil, err
+		}
+		lastErr = err
+		for k, i := range candidates {
+			if i == failed {
+				candidates = append(candidates[:k], candidates[k+1:]...)
+				break
+			}
+		}
+	}
+
+	return nil, nil, fmt.Errorf("not enough parties for threshold issuance: %v", lastErr)
+}
+
+// sign executes the protocol with the participants. When it fails because of a party,
+// the index of the party is returned.
+func (s *ThresholdSigner) sign(participants []int, Q, e, nonceUser *big.Int) (*big.Int,
+	*qr.RepresentationProof, int, error) {
+	sessionID := common.GetRandomInt(bitBound(128)).Text(16)
+
+	start := []*big.Int{e, Q}
+	for _, i := range participants {
+		start = append(start, big.NewInt(int64(i)))
+	}
+	toAll := func(values ...*big.Int) map[int][]*ThresholdMessage {
+		in := make(map[int][]*ThresholdMessage)
+		for _, i := range participants {
+			in[i] = []*ThresholdMessage{NewThresholdMessage(0, 0, values...)}
+		}
+		return in
+	}
+
+	// round 1: the messages from each party are forwarded to all the other parties
+	out, failed, err := s.runRound(sessionID, thresholdRoundStart, toAll(start...), 1, 3)
+	if err != nil {
+		return nil, nil, failed, err
+	}
+	in := make(map[int][]*ThresholdMessage)
+	for _, i := range participants {
+		for _, j := range participants {
+			if i != j {
+				in[i] = append(in[i], out[j]...)
+			}
+		}
+	}
+
+	// round 2: the messages are routed to their recipients
+	out, failed, err = s.runRound(sessionID, thresholdRoundMtA, in, len(participants)-1, 1)
+	if err != nil {
+		return nil, nil, failed, err
+	}
+	in = make(map[int][]*ThresholdMessage)
+	for i, msgs := range out {
+		for _, m := range msgs {
+			if m.To == i || !common.Contains(participants, m.To) {
+				return nil, nil, i, fmt.Errorf("party %d sent a message to a wrong recipient", i)
+			}
+			in[m.To] = append(in[m.To], m)
+		}
+	}
+	for _, i := range participants {
+		if len(in[i]) != len(participants)-1 {
+			return nil, nil, 0, fmt.Errorf("threshold issuance failed: messages for party %d are missing", i)
+		}
+	}
+
+	// round 3: gamma = lambda*Delta*m + R*e
+	out, failed, err = s.runRound(sessionID, thresholdRoundGamma, in, 1, 1)
+	if err != nil {
+		return nil, nil, failed, err
+	}
+	gamma := big.NewInt(0)
+	for _, msgs := range out {
+		gamma.Add(gamma, msgs[0].Values[0])
+	}
+	if gamma.Sign() <= 0 {
+		return nil, nil, 0, fmt.Errorf("threshold issuance failed: gamma is not valid")
+	}
+	a, b := new(big.Int), new(big.Int)
+	if new(big.Int).GCD(a, b, gamma, e).Cmp(big.NewInt(1)) != 0 {
+		return nil, nil, 0, fmt.Errorf("threshold issuance failed: gamma and e are not coprime")
+	}
+
+	// round 4: A = Q^b * A_1 * ... * A_t
+	out, failed, err = s.runRound(sessionID, thresholdRoundSign, toAll(a), 1, 2)
+	if err != nil {
+		return nil, nil, failed, err
+	}
+	A := s.group.Exp(Q, b)
+	t := big.NewInt(1)
+	for _, msgs := range out {
+		A = s.group.Mul(A, msgs[0].Values[0])
+		t = s.group.Mul(t, msgs[0].Values[1])
+	}
+	if s.group.Exp(A, e).Cmp(Q) != 0 {
+		return nil, nil, 0, fmt.Errorf("threshold issuance failed: A is not valid")
+	}
+
+	// round 5: the proof that A = Q^d
+	context := s.PubKey.GetContext()
+	challenge := common.Hash(context, Q, A, t, nonceUser)
+	out, failed, err = s.runRound(sessionID, thresholdRoundProof, toAll(challenge), 1, 1)
+	if err != nil {
+		return nil, nil, failed, err
+	}
+	z := new(big.Int).Mul(challenge, b)
+	for _, msgs := range out {
+		z.Add(z, msgs[0].Values[0])
+	}
+
+	verifier := qr.NewRepresentationVerifier(s.group, s.Params.SecParam)
+	verifier.SetProofRandomData(t, []*big.Int{Q}, A)
+	verifier.SetChallenge(challenge)
+	if !verifier.Verify([]*big.Int{z}) {
+		return nil, nil, 0, fmt.Errorf("threshold issuance failed: proof for A is not valid")
+	}
+
+	return A, qr.NewRepresentationProof(t, challenge, []*big.Int{z}), 0, nil
+}
+
+// runRound executes the round at all parties in parallel (in[i] are the messages for party i)
+// and checks that each party returned msgsNum messages with valuesNum values.
+func (s *ThresholdSigner) runRound(sessionID string, round int, in map[int][]*ThresholdMessage,
+	msgsNum, valuesNum int) (map[int][]*ThresholdMessage, int, error) {
+	type result struct {
+		party int
+		msgs  []*ThresholdMessage
+		err   error
+	}
+	results := make(chan result, len(in))
+	for i, msgs := range in {
+		go func(i int, msgs []*ThresholdMessage) {
+			out, err := s.Parties[i].Round(sessionID, round, msgs)
+			results <- result{i, out, err}
+		}(i, msgs)
+	}
+
+	out := make(map[int][]*ThresholdMessage)
+	failed := 0
+	var err error
+	for range in {
+		r := <-results
+		if r.err == nil && len(r.msgs) != msgsNum {
+			r.err = fmt.Errorf("wrong number of messages")
+		}
+		for _, m := range r.msgs {
+			if r.err == nil && (m.From != r.party || len(m.Values) != valuesNum) {
+				r.err = fmt.Errorf("message is not valid")
+			}
+		}
+		if r.err != nil && failed == 0 {
+			failed = r.party
+			err = fmt.Errorf("party %d failed in round %d of threshold issuance: %v", r.party,
+				round, r.err)
+		}
+		out[r.party] = r.msgs
+	}
+
+	return out, failed, err
+}
+
+// lagrangeCoeff returns Delta times the Lagrange coefficient of the party with index j in the set
+// of participants (Delta = n!), which is an integer.
+func lagrangeCoeff(participants []int, j, parties int) *big.Int {
+	num := factorial(parties)
+	den := big.NewInt(1)
+	for _, k := range participants {
+		if k != j {
+			num.Mul(num, big.NewInt(int64(k)))
+			den.Mul(den, big.NewInt(int64(k-j)))
+		}
+	}
+
+	return num.Quo(num, den)
+}
+
+func factorial(n int) *big.Int {
+	return new(big.Int).MulRange(1, int64(n))
+}
+
+// bitBound returns 2^bitLen.
+func bitBound(bitLen int) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(bitLen))
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package cl
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingParty is a party which is not available.
+type failingParty struct{}
+
+func (p *failingParty) Round(sessionID string, round int, in []*ThresholdMessage) (
+	[]*ThresholdMessage, error) {
+	return nil, fmt.Errorf("party not available")
+}
+
+func TestThresholdIssuance(t *testing.T) {
+	params := GetDefaultParamSizes()
+	keys, err := GenerateKeyPair(params, NewAttrCount(1, 0, 1))
+	require.NoError(t, err)
+
+	shares, coordinatorKey, err := SplitSecKey(params, keys, 2, 3)
+	require.NoError(t, err)
+	assert.Nil(t, coordinatorKey.RsaPrimes, "coordinator should not get the secret key")
+
+	parties := make(map[int]ThresholdPartyConn)
+	for _, share := range shares {
+		party, err := NewThresholdParty(params, keys.Pub, share)
+		require.NoError(t, err)
+		parties[share.Index] = party
+	}
+
+	org, err := NewOrgFromParams(params, &KeyPair{Pub: keys.Pub, Sec: coordinatorKey})
+	require.NoError(t, err)
+
+	issue := func(credMgr *CredManager) (*CredResult, error) {
+		nonce, err := org.GetCredIssueNonce()
+		require.NoError(t, err)
+		credReq, err := credMgr.GetCredRequest(nonce)
+		require.NoError(t, err)
+		return org.IssueCred(credReq, nonce)
+	}
+
+	rawCred := NewRawCred(NewAttrCount(1, 0, 0))
+	_ = rawCred.AddStrAttr("Name", "Jack", true)
+	credMgr, err := NewCredManager(params, keys.Pub, keys.Pub.GenerateUserMasterSecret(), rawCred)
+	require.NoError(t, err)
+
+	_, err = issue(credMgr)
+	assert.Error(t, err, "coordinator should not issue credentials without the parties")
+
+	// the first party is not available, the other two can issue the credential
+	org.Signer, err = NewThresholdSigner(params, keys.Pub, 2, map[int]ThresholdPartyConn{
+		1: &failingParty{},
+		2: parties[2],
+		3: parties[3],
+	})
+	require.NoError(t, err)
+	res, err := issue(credMgr)
+	require.NoError(t, err)
+	verified, err := credMgr.Verify(res.Cred, res.AProof)
+	assert.NoError(t, err)
+	assert.True(t, verified, "credential issued by parties 2 and 3 is not valid")
+
+	// any two parties can update the credential
+	org.Signer, err = NewThresholdSigner(params, keys.Pub, 2, map[int]ThresholdPartyConn{
+		1: parties[1],
+		3: parties[3],
+	})
+	require.NoError(t, err)
+	a, _ := rawCred.GetAttr("Name")
+	_ = a.UpdateValue("John")
+	require.NoError(t, credMgr.Update(rawCred))
+	updateNonce, err := org.GetCredIssueNonce()
+	require.NoError(t, err)
+	updateReq, err := credMgr.GetCredUpdateRequest(updateNonce)
+	require.NoError(t, err)
+	res1, err := org.UpdateCred(updateReq, res.Record, updateNonce)
+	require.NoError(t, err)
+	verified, err = credMgr.Verify(res1.Cred, res1.AProof)
+	assert.NoError(t, err)
+	assert.True(t, verified, "credential updated by parties 1 and 3 is not valid")
+
+	// a single party cannot issue credentials
+	org.Signer, err = NewThresholdSigner(params, keys.Pub, 2, map[int]ThresholdPartyConn{
+		1: &failingParty{},
+		2: parties[2],
+	})
+	require.NoError(t, err)
+	_, err = issue(credMgr)
+	assert.Error(t, err, "credential should not be issued by a single party")
+
+	_, err = parties[2].Round("unknown", thresholdRoundGamma, nil)
+	assert.Error(t, err, "round of unknown issuance should fail")
+}
+
+func TestLagrangeCoeff(t *testing.T) {
+	// f(x) = 7 + 3x + 5x^2, Delta*f(0) from any three of five shares
+	f := func(x int64) *big.Int { return big.NewInt(7 + 3*x + 5*x*x) }
+	participants := []int{1, 3, 5}
+	sum := new(big.Int)
+	for _, j := range participants {
+		sum.Add(sum, new(big.Int).Mul(lagrangeCoeff(participants, j, 5), f(int64(j))))
+	}
+	assert.Equal(t, new(big.Int).Mul(factorial(5), big.NewInt(7)), sum)
+}
@@ -0,0 +1,662 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CanonicalVersion is the version of the canonical encoding written by
+// MarshalCanonicalJSON and MarshalCanonicalBinary.
+const CanonicalVersion = 1
+
+// The canonical encoding does not depend on Go specific formats (such as gob), so that it
+// can be read by clients written in other languages. A value is encoded as a tree:
+//
+//   - a struct is encoded as a map from the names of its exported fields to their values -
+//     a name is taken from the json tag of the field or is the field name in snake case
+//     (for example V11 is encoded as v11 and RsKnown as rs_known),
+//   - *big.Int, integers, booleans, strings and []byte are encoded as leaves,
+//   - slices and arrays are encoded as lists, maps with string keys as maps,
+//   - nil pointers and nil slices are encoded as null.
+//
+// Unknown map entries are ignored when decoding and missing ones leave the fields with
+// zero values, so that fields can be added without breaking the encoding. Types which hold
+// their state in unexported fields implement CanonicalMarshaler and CanonicalUnmarshaler.
+//
+// In the JSON form, the tree is wrapped in an object which holds the version of the
+// encoding and the type of the value:
+//
+//	{
+//	  "version": 1,
+//	  "type": "cl-credential",
+//	  "value": {"a": "1234...", "e": "5678...", "v11": "9012..."}
+//	}
+//
+// Big integers are encoded as strings with their decimal representation (JSON numbers
+// cannot hold them in many languages), []byte as base64 strings. Map keys are sorted.
+//
+// The binary form starts with the version (uvarint) and the type (uvarint length followed by
+// the bytes of the string), followed by the value. Each node of the tree starts with a tag:
+//
+//	0 null, 1 false, 2 true
+//	3 integer: zig-zag varint
+//	4 non-negative big integer, 5 negative big integer: uvarint length and big-endian
+//	  bytes of the absolute value, without leading zeros
+//	6 string, 7 bytes: uvarint length and the bytes
+//	8 list: uvarint number of elements and the elements
+//	9 map: uvarint number of entries and the entries sorted by key - a key is encoded as
+//	  uvarint length and the bytes of the key, followed by the value
+const (
+	tagNull byte = iota
+	tagFalse
+	tagTrue
+	tagInt
+	tagBigInt
+	tagNegBigInt
+	tagString
+	tagBytes
+	tagList
+	tagMap
+)
+
+// maxCanonicalDepth limits nesting of the decoded values.
+const maxCanonicalDepth = 64
+
+// CanonicalMarshaler is implemented by types which are encoded as another value in the
+// canonical encoding, for example because they hold their state in unexported fields.
+type CanonicalMarshaler interface {
+	MarshalCanonical() (interface{}, error)
+}
+
+// CanonicalUnmarshaler is implemented by types which are decoded from another value in the
+// canonical encoding. The type calls decode with a pointer to the value returned by its
+// MarshalCanonical and restores itself from it.
+type CanonicalUnmarshaler interface {
+	UnmarshalCanonical(decode func(v interface{}) error) error
+}
+
+type canonicalJSON struct {
+	Version int             `json:"version"`
+	Type    string          `json:"type"`
+	Value   json.RawMessage `json:"value"`
+}
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// MarshalCanonicalJSON returns the canonical encoding of v (of the given type) in the
+// JSON form.
+func MarshalCanonicalJSON(typ string, v interface{}) ([]byte, error) {
+	t, err := toCanonicalTree(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(toJSONTree(t))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&canonicalJSON{
+		Version: CanonicalVersion,
+		Type:    typ,
+		Value:   value,
+	})
+}
+
+// UnmarshalCanonicalJSON decodes the value of the given type encoded by MarshalCanonicalJSON
+// into v, which needs to be a non-nil pointer.
+func UnmarshalCanonicalJSON(data []byte, typ string, v interface{}) error {
+	var c canonicalJSON
+	if err := json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+	if err := checkCanonicalHeader(c.Version, c.Type, typ); err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(c.Value))
+	d.UseNumber()
+	var t interface{}
+	if err := d.Decode(&t); err != nil {
+		return err
+	}
+
+	return fromCanonicalTreeInto(t, v)
+}
+
+// MarshalCanonicalBinary returns the canonical encoding of v (of the given type) in the
+// binary form.
+func MarshalCanonicalBinary(typ string, v interface{}) ([]byte, error) {
+	t, err := toCanonicalTree(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeUvarint(&buf, CanonicalVersion)
+	writeBinaryString(&buf, typ)
+	writeBinaryTree(&buf, t)
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalCanonicalBinary decodes the value of the given type encoded by
+// MarshalCanonicalBinary into v, which needs to be a non-nil pointer.
+func UnmarshalCanonicalBinary(data []byte, typ string, v interface{}) error {
+	r := bytes.NewReader(data)
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("error when reading version: %v", err)
+	}
+	dataType, err := readBinaryString(r)
+	if err != nil {
+		return fmt.Errorf("error when reading type: %v", err)
+	}
+	if err := checkCanonicalHeader(int(version), dataType, typ); err != nil {
+		return err
+	}
+
+	t, err := readBinaryTree(r, 0)
+	if err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("%d bytes left after the encoded value", r.Len())
+	}
+
+	return fromCanonicalTreeInto(t, v)
+}
+
+func checkCanonicalHeader(version int, dataType, typ string) error {
+	if version != CanonicalVersion {
+		return fmt.Errorf("unsupported version %d of the encoding", version)
+	}
+	if dataType != typ {
+		return fmt.Errorf("encoded value is %s, expected %s", dataType, typ)
+	}
+
+	return nil
+}
+
+func fromCanonicalTreeInto(t interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("value to decode into needs to be a non-nil pointer, not %T", v)
+	}
+	if u, ok := v.(CanonicalUnmarshaler); ok {
+		return u.UnmarshalCanonical(func(x interface{}) error {
+			return fromCanonicalTreeInto(t, x)
+		})
+	}
+
+	return fromCanonicalTree(t, rv.Elem())
+}
+
+// canonicalName returns the name of the struct field in the canonical encoding.
+func canonicalName(f reflect.StructField) string {
+	if tag := f.Tag.Get("json"); tag != "" {
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name
+		}
+	}
+
+	r := []rune(f.Name)
+	var name []rune
+	for i, c := range r {
+		if unicode.IsUpper(c) {
+			if i > 0 && (!unicode.IsUpper(r[i-1]) ||
+				i+1 < len(r) && unicode.IsLower(r[i+1])) {
+				name = append(name, '_')
+			}
+			c = unicode.ToLower(c)
+		}
+		name = append(name, c)
+	}
+
+	return string(name)
+}
+
+// toCanonicalTree converts v into a tree of nil, bool, int64, *big.Int, string, []byte,
+// []interface{} and map[string]interface{} values.
+func toCanonicalTree(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	if v.Type() == bigIntType {
+		return new(big.Int).Set(v.Interface().(*big.Int)), nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if m, ok := v.Interface().(CanonicalMarshaler); ok {
+			x, err := m.MarshalCanonical()
+			if err != nil {
+				return nil, err
+			}
+			return toCanonicalTree(reflect.ValueOf(x))
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return toCanonicalTree(v.Elem())
+	case reflect.Struct:
+		m := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+				continue
+			}
+			val, err := toCanonicalTree(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			m[canonicalName(f)] = val
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return b, nil
+		}
+		l := make([]interface{}, v.Len())
+		for i := range l {
+			val, err := toCanonicalTree(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			l[i] = val
+		}
+		return l, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("canonical encoding of %s is not supported", v.Type())
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			val, err := toCanonicalTree(v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+			m[k.String()] = val
+		}
+		return m, nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > 1<<63-1 {
+			return nil, fmt.Errorf("integer %d is too large", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	case reflect.String:
+		return v.String(), nil
+	}
+
+	return nil, fmt.Errorf("canonical encoding of %s is not supported", v.Type())
+}
+
+// fromCanonicalTree sets v from the tree t, which was either decoded from the binary form
+// or from JSON (with json.Number numbers).
+func fromCanonicalTree(t interface{}, v reflect.Value) error {
+	if t == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Type() == bigIntType {
+		switch x := t.(type) {
+		case *big.Int:
+			v.Set(reflect.ValueOf(x))
+		case string:
+			i, ok := new(big.Int).SetString(x, 10)
+			if !ok {
+				return fmt.Errorf("%q is not a decimal integer", x)
+			}
+			v.Set(reflect.ValueOf(i))
+		default:
+			return fmt.Errorf("cannot decode %T into big integer", t)
+		}
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		if u, ok := v.Interface().(CanonicalUnmarshaler); ok {
+			return u.UnmarshalCanonical(func(x interface{}) error {
+				return fromCanonicalTreeInto(t, x)
+			})
+		}
+		return fromCanonicalTree(t, v.Elem())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		m, ok := t.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot decode %T into %s", t, v.Type())
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+				continue
+			}
+			if val, ok := m[canonicalName(f)]; ok {
+				if err := fromCanonicalTree(val, v.Field(i)); err != nil {
+					return fmt.Errorf("%s: %v", canonicalName(f), err)
+				}
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			var b []byte
+			switch x := t.(type) {
+			case []byte:
+				b = x
+			case string:
+				var err error
+				if b, err = base64.StdEncoding.DecodeString(x); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("cannot decode %T into %s", t, v.Type())
+			}
+			if v.Kind() == reflect.Array {
+				if len(b) != v.Len() {
+					return fmt.Errorf("expected %d bytes, got %d", v.Len(), len(b))
+				}
+			} else {
+				v.Set(reflect.MakeSlice(v.Type(), len(b), len(b)))
+			}
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+		l, ok := t.([]interface{})
+		if !ok {
+			return fmt.Errorf("cannot decode %T into %s", t, v.Type())
+		}
+		if v.Kind() == reflect.Array {
+			if len(l) != v.Len() {
+				return fmt.Errorf("expected %d elements, got %d", v.Len(), len(l))
+			}
+		} else {
+			v.Set(reflect.MakeSlice(v.Type(), len(l), len(l)))
+		}
+		for i, el := range l {
+			if err := fromCanonicalTree(el, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		m, ok := t.(map[string]interface{})
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot decode %T into %s", t, v.Type())
+		}
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(m)))
+		for k, el := range m {
+			val := reflect.New(v.Type().Elem()).Elem()
+			if err := fromCanonicalTree(el, val); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), val)
+		}
+		return nil
+	case reflect.Bool:
+		b, ok := t.(bool)
+		if !ok {
+			return fmt.Errorf("cannot decode %T into %s", t, v.Type())
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var i int64
+		switch x := t.(type) {
+		case int64:
+			i = x
+		case json.Number:
+			var err error
+			if i, err = x.Int64(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("cannot decode %T into %s", t, v.Type())
+		}
+		if v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64 {
+			if i < 0 || v.OverflowUint(uint64(i)) {
+				return fmt.Errorf("integer %d overflows %s", i, v.Type())
+			}
+			v.SetUint(uint64(i))
+			return nil
+		}
+		if v.OverflowInt(i) {
+			return fmt.Errorf("integer %d overflows %s", i, v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.String:
+		s, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("cannot decode %T into %s", t, v.Type())
+		}
+		v.SetString(s)
+		return nil
+	}
+
+	return fmt.Errorf("canonical encoding of %s is not supported", v.Type())
+}
+
+// toJSONTree replaces big integers in the tree with their decimal representation.
+func toJSONTree(t interface{}) interface{} {
+	switch x := t.(type) {
+	case *big.Int:
+		return x.String()
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, el := range x {
+			l[i] = toJSONTree(el)
+		}
+		return l
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, el := range x {
+			m[k] = toJSONTree(el)
+		}
+		return m
+	}
+
+	return t
+}
+
+func writeUvarint(buf *bytes.Buffer, x uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], x)])
+}
+
+func writeBinaryString(buf *bytes.Buffer, s string) {
+	writeUvarint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+func writeBinaryTree(buf *bytes.Buffer, t interface{}) {
+	switch x := t.(type) {
+	case nil:
+		buf.WriteByte(tagNull)
+	case bool:
+		if x {
+			buf.WriteByte(tagTrue)
+		} else {
+			buf.WriteByte(tagFalse)
+		}
+	case int64:
+		buf.WriteByte(tagInt)
+		var b [binary.MaxVarintLen64]byte
+		buf.Write(b[:binary.PutVarint(b[:], x)])
+	case *big.Int:
+		if x.Sign() < 0 {
+			buf.WriteByte(tagNegBigInt)
+		} else {
+			buf.WriteByte(tagBigInt)
+		}
+		b := x.Bytes()
+		writeUvarint(buf, uint64(len(b)))
+		buf.Write(b)
+	case string:
+		buf.WriteByte(tagString)
+		writeBinaryString(buf, x)
+	case []byte:
+		buf.WriteByte(tagBytes)
+		writeUvarint(buf, uint64(len(x)))
+		buf.Write(x)
+	case []interface{}:
+		buf.WriteByte(tagList)
+		writeUvarint(buf, uint64(len(x)))
+		for _, el := range x {
+			writeBinaryTree(buf, el)
+		}
+	case map[string]interface{}:
+		buf.WriteByte(tagMap)
+		writeUvarint(buf, uint64(len(x)))
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeBinaryString(buf, k)
+			writeBinaryTree(buf, x[k])
+		}
+	}
+}
+
+// readLen reads a length and checks that r holds at least that many bytes (each element
+// of a list or a map takes at least one byte).
+func readLen(r *bytes.Reader) (int, error) {
+	l, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if l > uint64(r.Len()) {
+		return 0, fmt.Errorf("length %d exceeds the encoded data", l)
+	}
+
+	return int(l), nil
+}
+
+func readBinaryBytes(r *bytes.Reader) ([]byte, error) {
+	l, err := readLen(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, l)
+	r.Read(b)
+
+	return b, nil
+}
+
+func readBinaryString(r *bytes.Reader) (string, error) {
+	b, err := readBinaryBytes(r)
+	return string(b), err
+}
+
+func readBinaryTree(r *bytes.Reader, depth int) (interface{}, error) {
+	if depth > maxCanonicalDepth {
+		return nil, fmt.Errorf("encoded value is nested too deeply")
+	}
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case tagNull:
+		return nil, nil
+	case tagFalse:
+		return false, nil
+	case tagTrue:
+		return true, nil
+	case tagInt:
+		return binary.ReadVarint(r)
+	case tagBigInt, tagNegBigInt:
+		b, err := readBinaryBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > 0 && b[0] == 0 {
+			return nil, fmt.Errorf("big integer is not in canonical form")
+		}
+		x := new(big.Int).SetBytes(b)
+		if tag == tagNegBigInt {
+			if x.Sign() == 0 {
+				return nil, fmt.Errorf("big integer is not in canonical form")
+			}
+			x.Neg(x)
+		}
+		return x, nil
+	case tagString:
+		return readBinaryString(r)
+	case tagBytes:
+		return readBinaryBytes(r)
+	case tagList:
+		l, err := readLen(r)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, l)
+		for i := range list {
+			if list[i], err = readBinaryTree(r, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case tagMap:
+		l, err := readLen(r)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, l)
+		for i := 0; i < l; i++ {
+			k, err := readBinaryString(r)
+			if err != nil {
+				return nil, err
+			}
+			if m[k], err = readBinaryTree(r, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("unknown tag %d", tag)
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type canonicalInner struct {
+	X *big.Int
+}
+
+type canonicalTest struct {
+	N          *big.Int
+	Neg        *big.Int
+	Zero       *big.Int
+	Missing    *big.Int
+	Ints       []*big.Int
+	NoInts     []*big.Int
+	EmptyInts  []*big.Int
+	Flags      []bool
+	Count      int
+	Small      int32
+	Name       string `json:"name_tag,omitempty"`
+	Data       []byte
+	Inner      *canonicalInner
+	Inners     []*canonicalInner
+	Attrs      map[string]string
+	unexported int
+}
+
+func TestCanonicalEncoding(t *testing.T) {
+	n, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+	v := &canonicalTest{
+		N:         n,
+		Neg:       big.NewInt(-257),
+		Zero:      big.NewInt(0),
+		Ints:      []*big.Int{big.NewInt(1), big.NewInt(2)},
+		EmptyInts: []*big.Int{},
+		Flags:     []bool{true, false},
+		Count:     -42,
+		Small:     7,
+		Name:      "test",
+		Data:      []byte{0, 1, 2},
+		Inner:     &canonicalInner{X: big.NewInt(3)},
+		Inners:    []*canonicalInner{{X: big.NewInt(4)}, nil},
+		Attrs:     map[string]string{"b": "2", "a": "1"},
+	}
+
+	data, err := MarshalCanonicalJSON("test", v)
+	require.NoError(t, err)
+	var decoded canonicalTest
+	require.NoError(t, UnmarshalCanonicalJSON(data, "test", &decoded))
+	assert.Equal(t, v, &decoded)
+
+	// big integers are encoded as decimal strings, field names in snake case
+	var c struct {
+		Version int
+		Type    string
+		Value   map[string]interface{}
+	}
+	require.NoError(t, json.Unmarshal(data, &c))
+	assert.Equal(t, CanonicalVersion, c.Version)
+	assert.Equal(t, "test", c.Type)
+	assert.Equal(t, n.String(), c.Value["n"])
+	assert.Equal(t, "-257", c.Value["neg"])
+	assert.Equal(t, "test", c.Value["name_tag"])
+	assert.NotContains(t, c.Value, "unexported")
+
+	data2, err := MarshalCanonicalJSON("test", v)
+	require.NoError(t, err)
+	assert.Equal(t, data, data2, "encoding is not deterministic")
+
+	bin, err := MarshalCanonicalBinary("test", v)
+	require.NoError(t, err)
+	decoded = canonicalTest{}
+	require.NoError(t, UnmarshalCanonicalBinary(bin, "test", &decoded))
+	assert.Equal(t, v, &decoded)
+	assert.True(t, len(bin) < len(data), "binary form should be more compact")
+
+	bin2, err := MarshalCanonicalBinary("test", v)
+	require.NoError(t, err)
+	assert.Equal(t, bin, bin2, "encoding is not deterministic")
+
+	assert.Error(t, UnmarshalCanonicalJSON(data, "other", &decoded),
+		"value of a different type should not be decoded")
+	assert.Error(t, UnmarshalCanonicalBinary(bin, "other", &decoded),
+		"value of a different type should not be decoded")
+	assert.Error(t, UnmarshalCanonicalBinary(bin[:len(bin)-1], "test", &decoded),
+		"truncated value should not be decoded")
+	assert.Error(t, UnmarshalCanonicalBinary(append(bin, 0), "test", &decoded),
+		"value with trailing data should not be decoded")
+	assert.Error(t, UnmarshalCanonicalBinary(bin, "test", decoded),
+		"value should not be decoded into a non-pointer")
+}
+
+func TestCanonicalName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"N":                          "n",
+		"V11":                        "v11",
+		"RsKnown":                    "rs_known",
+		"AProof":                     "a_proof",
+		"AttributesSpecialRSAPrimes": "attributes_special_rsa_primes",
+		"DLogProofRandomData":        "d_log_proof_random_data",
+	} {
+		f := reflect.StructField{Name: name}
+		assert.Equal(t, expected, canonicalName(f))
+	}
+}
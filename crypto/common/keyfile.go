@@ -0,0 +1,106 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// KeyFileVersion is the version of the key file format written by WriteKeyFile.
+const KeyFileVersion = 1
+
+// KeyFile is the on-disk format of keys (as generated by "emmy keygen"). It is
+// a JSON object:
+//
+//	{
+//	  "version": 1,
+//	  "type": "cl-public-key",
+//	  "key_id": "<hex encoded identifier of the public key>",
+//	  "key": { ... }
+//	}
+//
+// Type tells which scheme the key belongs to and whether it is public or secret. A secret
+// key file holds the ID of the matching public key, so that the keys cannot be mixed up.
+// Key holds the fields of the key, big integers are encoded as JSON numbers.
+type KeyFile struct {
+	Version int             `json:"version"`
+	Type    string          `json:"type"`
+	KeyID   string          `json:"key_id"`
+	Key     json.RawMessage `json:"key"`
+}
+
+// WriteKeyFile writes key of the given type and ID to a new file at path. An existing file
+// is never overwritten. When secret is true, the file is readable only by its owner.
+func WriteKeyFile(path, keyType, keyID string, key interface{}, secret bool) error {
+	k, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(&KeyFile{
+		Version: KeyFileVersion,
+		Type:    keyType,
+		KeyID:   keyID,
+		Key:     k,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var perm os.FileMode = 0644
+	if secret {
+		perm = 0600
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReadKeyFile reads the key of the given type from the file at path into key and
+// returns the ID of the key.
+func ReadKeyFile(path, keyType string, key interface{}) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var f KeyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return "", fmt.Errorf("error when reading key file %s: %v", path, err)
+	}
+	if f.Version != KeyFileVersion {
+		return "", fmt.Errorf("key file %s has unsupported version %d", path, f.Version)
+	}
+	if f.Type != keyType {
+		return "", fmt.Errorf("key file %s holds %s, expected %s", path, f.Type, keyType)
+	}
+	if err := json.Unmarshal(f.Key, key); err != nil {
+		return "", fmt.Errorf("error when reading key file %s: %v", path, err)
+	}
+
+	return f.KeyID, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package common
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	type key struct {
+		X *big.Int
+	}
+	path := filepath.Join(dir, "key.json")
+	err = WriteKeyFile(path, "test-secret-key", "id", &key{X: big.NewInt(42)}, true)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "secret key file is readable by others")
+
+	var k key
+	keyID, err := ReadKeyFile(path, "test-secret-key", &k)
+	require.NoError(t, err)
+	assert.Equal(t, "id", keyID)
+	assert.Equal(t, big.NewInt(42), k.X)
+
+	_, err = ReadKeyFile(path, "test-public-key", &k)
+	assert.Error(t, err, "key of a different type should not be read")
+
+	err = WriteKeyFile(path, "test-secret-key", "id", &key{X: big.NewInt(1)}, true)
+	assert.Error(t, err, "existing key file should not be overwritten")
+}
@@ -159,6 +159,29 @@ func NewReceiverFromParams(specialRSAPrimes *qr.RSASpecialPrimes, g, h *big.Int,
 	}, nil
 }
 
+// NewReceiverFromPublicParams returns an instance of a receiver which knows only the
+// public parameters of the group (N, but not its factorization). Such a receiver can be used
+// for verifying the associated proofs.
+func NewReceiverFromPublicParams(n, g, h *big.Int, k int) *Receiver {
+	return &Receiver{df: df{
+		QRSpecialRSA: qr.NewRSApecialPublic(n),
+		G:            g,
+		H:            h,
+		K:            k},
+	}
+}
+
+// newReceiverWithBases returns a receiver in the same group as receiver r, but with
+// bases g and h.
+func newReceiverWithBases(r *Receiver, g, h *big.Int) *Receiver {
+	return &Receiver{df: df{
+		QRSpecialRSA: r.QRSpecialRSA,
+		G:            g,
+		H:            h,
+		K:            r.K},
+	}
+}
+
 // When receiver receives a commitment, it stores the value using SetCommitment method.
 func (r *Receiver) SetCommitment(c *big.Int) {
 	r.Commitment = c
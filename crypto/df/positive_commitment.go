@@ -41,10 +41,14 @@ func NewPositiveProver(committer *Committer,
 	// c2 = g^(x2^2) * h^r2, c3 = g^(x3^2) * h^r3 and where r = r0 + r1 + r2 + r3.
 	// We then prove that c0, c1, c2, c3 contains squares and verifier checks that c = c0*c1*c2*c3.
 
-	roots, err := lipmaaDecomposition(x)
+	// All four roots are used (also those which are zero), because the verifier expects
+	// exactly four square proofs - otherwise the number of non-zero roots would be revealed
+	// and x = 0 could not be proved at all.
+	w, err := lipmaaDecompose(x)
 	if err != nil {
 		return nil, fmt.Errorf("error when doing Lipmaa decomposition")
 	}
+	roots := w[:]
 	nRoots := len(roots)
 
 	// find r0, r1, r2, r3 such that r0 + r1 + r2 + r3 = r
@@ -171,12 +175,7 @@ func NewPositiveVerifier(receiver *Receiver,
 
 	receivers := make([]*Receiver, nRoots)
 	for i, comm := range bigCommitments {
-		receiver, err := NewReceiverFromParams(
-			receiver.QRSpecialRSA.GetPrimes(),
-			receiver.G, receiver.H, receiver.K)
-		if err != nil {
-			return nil, fmt.Errorf("error when calling NewReceiverFromParams")
-		}
+		receiver := newReceiverWithBases(receiver, receiver.G, receiver.H)
 		receiver.SetCommitment(comm)
 		receivers[i] = receiver
 	}
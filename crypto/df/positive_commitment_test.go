@@ -38,35 +38,39 @@ func TestDFCommitmentPositive(t *testing.T) {
 	committer := NewCommitter(receiver.QRSpecialRSA.N,
 		receiver.G, receiver.H, T, receiver.K)
 
-	x := common.GetRandomInt(committer.QRSpecialRSA.N)
-	c, err := committer.GetCommitMsg(x)
-	if err != nil {
-		t.Errorf("error in computing commit msg: %v", err)
-	}
-	receiver.SetCommitment(c)
-	_, r := committer.GetDecommitMsg()
+	// besides a random number, test also numbers with less than four non-zero roots
+	xs := []*big.Int{common.GetRandomInt(committer.QRSpecialRSA.N), big.NewInt(0),
+		big.NewInt(1), big.NewInt(4)}
+	for _, x := range xs {
+		c, err := committer.GetCommitMsg(x)
+		if err != nil {
+			t.Errorf("error in computing commit msg: %v", err)
+		}
+		receiver.SetCommitment(c)
+		_, r := committer.GetDecommitMsg()
 
-	challengeSpaceSize := 80
-	prover, err := NewPositiveProver(committer, x, r,
-		challengeSpaceSize)
-	if err != nil {
-		t.Errorf("error in instantiating PositiveProver: %v", err)
-	}
+		challengeSpaceSize := 80
+		prover, err := NewPositiveProver(committer, x, r,
+			challengeSpaceSize)
+		if err != nil {
+			t.Errorf("error in instantiating PositiveProver: %v", err)
+		}
 
-	smallCommitments, bigCommitments := prover.GetVerifierInitializationData()
-	verifier, err := NewPositiveVerifier(receiver, receiver.Commitment,
-		smallCommitments, bigCommitments, challengeSpaceSize)
-	if err != nil {
-		t.Errorf("error in instantiating PositiveVerifier: %v", err)
-	}
+		smallCommitments, bigCommitments := prover.GetVerifierInitializationData()
+		verifier, err := NewPositiveVerifier(receiver, receiver.Commitment,
+			smallCommitments, bigCommitments, challengeSpaceSize)
+		if err != nil {
+			t.Errorf("error in instantiating PositiveVerifier: %v", err)
+		}
 
-	proofRandomData := prover.GetProofRandomData()
-	challenges := verifier.GetChallenges()
-	err = verifier.SetProofRandomData(proofRandomData)
-	if err != nil {
-		t.Errorf("error when calling SetProofRandomData: %v", err)
+		proofRandomData := prover.GetProofRandomData()
+		challenges := verifier.GetChallenges()
+		err = verifier.SetProofRandomData(proofRandomData)
+		if err != nil {
+			t.Errorf("error when calling SetProofRandomData: %v", err)
+		}
+		proofData := prover.GetProofData(challenges)
+		proved := verifier.Verify(proofData)
+		assert.Equal(t, true, proved, "DamgardFujisaki positive proof failed.")
 	}
-	proofData := prover.GetProofData(challenges)
-	proved := verifier.Verify(proofData)
-	assert.Equal(t, true, proved, "DamgardFujisaki positive proof failed.")
 }
@@ -79,18 +79,10 @@ type SquareVerifier struct {
 func NewSquareVerifier(receiver *Receiver,
 	c1 *big.Int, challengeSpaceSize int) (*SquareVerifier, error) {
 
-	receiver1, err := NewReceiverFromParams(receiver.QRSpecialRSA.GetPrimes(),
-		receiver.G, receiver.H, receiver.K)
-	if err != nil {
-		return nil, fmt.Errorf("error when calling NewReceiverFromParams")
-	}
+	receiver1 := newReceiverWithBases(receiver, receiver.G, receiver.H)
 	receiver1.SetCommitment(c1)
 
-	receiver2, err := NewReceiverFromParams(receiver.QRSpecialRSA.GetPrimes(),
-		c1, receiver.H, receiver.K)
-	if err != nil {
-		return nil, fmt.Errorf("error when calling NewReceiverFromParams")
-	}
+	receiver2 := newReceiverWithBases(receiver, c1, receiver.H)
 	receiver2.SetCommitment(receiver.Commitment)
 
 	verifier := NewEqualityVerifier(receiver1, receiver2, challengeSpaceSize)
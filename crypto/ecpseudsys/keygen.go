@@ -18,6 +18,10 @@
 package ecpseudsys
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
 	"github.com/xlab-si/emmy/crypto/common"
 	"github.com/xlab-si/emmy/crypto/ec"
 	"github.com/xlab-si/emmy/crypto/pseudsys"
@@ -41,3 +45,61 @@ func GenerateKeyPair(group *ec.Group) (*pseudsys.SecKey, *PubKey) {
 
 	return pseudsys.NewSecKey(s1, s2), NewPubKey(h1, h2)
 }
+
+const (
+	pubKeyFileType = "ecpseudonymsys-public-key"
+	secKeyFileType = "ecpseudonymsys-secret-key"
+)
+
+// GetID returns the identifier of the public key (hex encoded SHA-256 hash of
+// the coordinates of H1 and H2).
+func (k *PubKey) GetID() string {
+	h := sha256.Sum256(common.ConcatenateNumbers(k.H1.X, k.H1.Y, k.H2.X, k.H2.Y))
+	return hex.EncodeToString(h[:])
+}
+
+// WriteKeyPair writes the public and the secret key to new files in the format described
+// by common.KeyFile. The secret key file is readable only by its owner.
+func WriteKeyPair(pubKeyPath, secKeyPath string, secKey *pseudsys.SecKey, pubKey *PubKey) error {
+	keyID := pubKey.GetID()
+	if err := common.WriteKeyFile(pubKeyPath, pubKeyFileType, keyID, pubKey, false); err != nil {
+		return fmt.Errorf("error writing public key: %v", err)
+	}
+	if err := common.WriteKeyFile(secKeyPath, secKeyFileType, keyID, secKey, true); err != nil {
+		return fmt.Errorf("error writing secret key: %v", err)
+	}
+
+	return nil
+}
+
+// ReadPubKey reads the public key from the file written by WriteKeyPair.
+func ReadPubKey(path string) (*PubKey, error) {
+	pubKey := new(PubKey)
+	keyID, err := common.ReadKeyFile(path, pubKeyFileType, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if pubKey.H1 == nil || pubKey.H2 == nil || pubKey.H1.X == nil || pubKey.H1.Y == nil ||
+		pubKey.H2.X == nil || pubKey.H2.Y == nil {
+		return nil, fmt.Errorf("public key file %s is not complete", path)
+	}
+	if pubKey.GetID() != keyID {
+		return nil, fmt.Errorf("public key in %s does not match its ID", path)
+	}
+
+	return pubKey, nil
+}
+
+// ReadSecKey reads the secret key which matches pubKey from the file written by WriteKeyPair.
+func ReadSecKey(path string, pubKey *PubKey) (*pseudsys.SecKey, error) {
+	secKey := new(pseudsys.SecKey)
+	keyID, err := common.ReadKeyFile(path, secKeyFileType, secKey)
+	if err != nil {
+		return nil, err
+	}
+	if keyID != pubKey.GetID() {
+		return nil, fmt.Errorf("secret key in %s does not match the public key", path)
+	}
+
+	return secKey, nil
+}
@@ -18,6 +18,8 @@
 package encryption
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"math/big"
@@ -141,14 +143,22 @@ func NewCSPaillierFromPubKey(pubKey *CSPaillierPubKey) *CSPaillier {
 
 // Returns (u, e, v).
 func (csp *CSPaillier) Encrypt(m, label *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	b := new(big.Int).Div(csp.PubKey.N, big.NewInt(4))
+	r := common.GetRandomInt(b)
+
+	return csp.EncryptWithGivenR(m, r, label)
+}
+
+// EncryptWithGivenR is the same as Encrypt, but the randomness r (which needs to be
+// from [0, n/4)) is given. It serves as a helper function for proofs about the
+// ciphertext which need to know r.
+func (csp *CSPaillier) EncryptWithGivenR(m, r, label *big.Int) (*big.Int, *big.Int, *big.Int,
+	error) {
 	if m.Cmp(csp.PubKey.N) >= 0 {
 		err := fmt.Errorf("msg is too big")
 		return nil, nil, nil, err
 	}
 
-	b := new(big.Int).Div(csp.PubKey.N, big.NewInt(4))
-	r := common.GetRandomInt(b)
-
 	n2 := new(big.Int).Mul(csp.PubKey.N, csp.PubKey.N)
 	// u = g^r
 	u := new(big.Int).Exp(csp.PubKey.G, r, n2)
@@ -581,3 +591,68 @@ func NewVerifiableEncGroup(primes *qr.RSASpecialPrimes) (*VerifiableEncGroup, er
 	}
 	return &group, nil
 }
+
+const (
+	csPaillierPubKeyFileType = "cspaillier-public-key"
+	csPaillierSecKeyFileType = "cspaillier-secret-key"
+)
+
+// GetID returns the identifier of the public key (hex encoded SHA-256 hash of N, G, Y1,
+// Y2 and Y3).
+func (k *CSPaillierPubKey) GetID() string {
+	h := sha256.Sum256(common.ConcatenateNumbers(k.N, k.G, k.Y1, k.Y2, k.Y3))
+	return hex.EncodeToString(h[:])
+}
+
+// WriteCSPaillierKeyPair writes the public and the secret key to new files in the format
+// described by common.KeyFile. The secret key file is readable only by its owner.
+func WriteCSPaillierKeyPair(pubKeyPath, secKeyPath string, secKey *CSPaillierSecKey,
+	pubKey *CSPaillierPubKey) error {
+	keyID := pubKey.GetID()
+	if err := common.WriteKeyFile(pubKeyPath, csPaillierPubKeyFileType, keyID, pubKey,
+		false); err != nil {
+		return fmt.Errorf("error writing public key: %v", err)
+	}
+	if err := common.WriteKeyFile(secKeyPath, csPaillierSecKeyFileType, keyID, secKey,
+		true); err != nil {
+		return fmt.Errorf("error writing secret key: %v", err)
+	}
+
+	return nil
+}
+
+// ReadCSPaillierPubKey reads the public key from the file written by WriteCSPaillierKeyPair.
+func ReadCSPaillierPubKey(path string) (*CSPaillierPubKey, error) {
+	pubKey := new(CSPaillierPubKey)
+	keyID, err := common.ReadKeyFile(path, csPaillierPubKeyFileType, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if pubKey.N == nil || pubKey.G == nil || pubKey.Y1 == nil || pubKey.Y2 == nil ||
+		pubKey.Y3 == nil {
+		return nil, fmt.Errorf("public key file %s is not complete", path)
+	}
+	if keyID != pubKey.GetID() {
+		return nil, fmt.Errorf("public key in %s does not match its ID", path)
+	}
+
+	return pubKey, nil
+}
+
+// ReadCSPaillierSecKey reads the secret key which matches pubKey from the file written
+// by WriteCSPaillierKeyPair.
+func ReadCSPaillierSecKey(path string, pubKey *CSPaillierPubKey) (*CSPaillierSecKey, error) {
+	secKey := new(CSPaillierSecKey)
+	keyID, err := common.ReadKeyFile(path, csPaillierSecKeyFileType, secKey)
+	if err != nil {
+		return nil, err
+	}
+	if keyID != pubKey.GetID() {
+		return nil, fmt.Errorf("secret key in %s does not match the public key", path)
+	}
+	if secKey.N == nil || secKey.X1 == nil || secKey.X2 == nil || secKey.X3 == nil {
+		return nil, fmt.Errorf("secret key file %s is not complete", path)
+	}
+
+	return secKey, nil
+}
@@ -53,6 +53,24 @@ func NewPubPaillier(pubKey *PaillierPubKey) *Paillier {
 	}
 }
 
+// NewPaillierPubKey returns the public key with modulus n and generator g (as returned
+// by GetN and GetG of the key owner's public key).
+func NewPaillierPubKey(n, g *big.Int) *PaillierPubKey {
+	return &PaillierPubKey{
+		n:  n,
+		n2: new(big.Int).Mul(n, n),
+		g:  g,
+	}
+}
+
+func (pubKey *PaillierPubKey) GetN() *big.Int {
+	return pubKey.n
+}
+
+func (pubKey *PaillierPubKey) GetG() *big.Int {
+	return pubKey.g
+}
+
 func (paillier *Paillier) Encrypt(m *big.Int) (*big.Int, error) {
 	if m.Cmp(paillier.pubKey.n) >= 0 {
 		err := fmt.Errorf("msg is too big")
@@ -93,6 +111,17 @@ func (paillier *Paillier) Decrypt(c *big.Int) (*big.Int, error) {
 	return p, nil
 }
 
+// Add returns the encryption of the sum of the messages encrypted in c1 and c2.
+func (paillier *Paillier) Add(c1, c2 *big.Int) *big.Int {
+	c := new(big.Int).Mul(c1, c2)
+	return c.Mod(c, paillier.pubKey.n2)
+}
+
+// MulConst returns the encryption of the message encrypted in c multiplied by k (k >= 0).
+func (paillier *Paillier) MulConst(c, k *big.Int) *big.Int {
+	return new(big.Int).Exp(c, k, paillier.pubKey.n2)
+}
+
 func (paillier *Paillier) GetPubKey() *PaillierPubKey {
 	return paillier.pubKey
 }
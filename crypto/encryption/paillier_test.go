@@ -36,3 +36,22 @@ func TestPaillier(t *testing.T) {
 
 	assert.Equal(t, m, p, "Paillier encryption/decryption does not work correctly")
 }
+
+func TestPaillierHomomorphic(t *testing.T) {
+	paillier := NewPaillier(512)
+	pubKey := paillier.GetPubKey()
+	pubPaillier := NewPubPaillier(NewPaillierPubKey(pubKey.GetN(), pubKey.GetG()))
+
+	m1 := common.GetRandomInt(big.NewInt(123412341234123))
+	m2 := common.GetRandomInt(big.NewInt(123412341234123))
+	k := common.GetRandomInt(big.NewInt(123412341234123))
+	c1, _ := pubPaillier.Encrypt(m1)
+	c2, _ := pubPaillier.Encrypt(m2)
+
+	c := pubPaillier.Add(pubPaillier.MulConst(c1, k), c2)
+	p, _ := paillier.Decrypt(c)
+
+	expected := new(big.Int).Mul(m1, k)
+	expected.Add(expected, m2)
+	assert.Equal(t, expected, p, "Paillier homomorphic operations do not work correctly")
+}
@@ -94,6 +94,24 @@ func (c *Committer) GetCommitMsg(val *big.Int) (*big.Int, error) {
 	return comm, nil
 }
 
+// GetCommitMsgWithGivenR outputs c = g^x * h^r for the given r, it can be used to
+// restore the committer of an existing commitment.
+func (c *Committer) GetCommitMsgWithGivenR(val, r *big.Int) (*big.Int, error) {
+	if val.Cmp(c.Params.Group.Q) == 1 || val.Cmp(big.NewInt(0)) == -1 {
+		err := fmt.Errorf("committed value needs to be in Z_q (order of a base point)")
+		return nil, err
+	}
+
+	c.r = r
+	c.committedValue = val
+	t1 := c.Params.Group.Exp(c.Params.Group.G, val)
+	t2 := c.Params.Group.Exp(c.Params.H, r)
+	comm := c.Params.Group.Mul(t1, t2)
+	c.Commitment = comm
+
+	return comm, nil
+}
+
 // It returns values x and r (commitment was c = g^x * g^r).
 func (c *Committer) GetDecommitMsg() (*big.Int, *big.Int) {
 	val := c.committedValue
@@ -45,3 +45,27 @@ func TestPedersen(t *testing.T) {
 
 	assert.Equal(t, true, success, "Pedersen commitment failed.")
 }
+
+func TestPedersenGivenR(t *testing.T) {
+	receiver, err := NewReceiver(256)
+	if err != nil {
+		t.Errorf("Error in NewReceiver: %v", err)
+	}
+
+	committer := NewCommitter(receiver.Params)
+	a := common.GetRandomInt(committer.Params.Group.Q)
+	c, err := committer.GetCommitMsg(a)
+	if err != nil {
+		t.Errorf("Error in GetCommitMsg: %v", err)
+	}
+
+	// a committer restored from the committed value and r produces the same commitment
+	_, r := committer.GetDecommitMsg()
+	restored := NewCommitter(receiver.Params)
+	c1, err := restored.GetCommitMsgWithGivenR(a, r)
+	if err != nil {
+		t.Errorf("Error in GetCommitMsgWithGivenR: %v", err)
+	}
+
+	assert.Equal(t, c, c1, "Pedersen commitment with given r does not match")
+}
@@ -51,6 +51,53 @@ func NewCACert(blindedA, blindedB, r, s *big.Int) *CACert {
 	}
 }
 
+const (
+	caPubKeyFileType = "pseudonymsys-ca-public-key"
+	caSecKeyFileType = "pseudonymsys-ca-secret-key"
+)
+
+// caSecKey is the content of the CA secret key file.
+type caSecKey struct {
+	D *big.Int
+}
+
+// GenerateCAKeyPair generates the ECDSA (P-256) key of the CA. It returns the secret d
+// and the public key, where H1 and H2 are the coordinates of the public point.
+func GenerateCAKeyPair() (*big.Int, *PubKey, error) {
+	k, err := ecdsa.GenerateKey(ec.GetCurve(ec.P256), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return k.D, NewPubKey(k.X, k.Y), nil
+}
+
+// WriteCAKeyPair writes the CA public key and the secret d to new files in the format
+// described by common.KeyFile. The secret key file is readable only by its owner.
+func WriteCAKeyPair(pubKeyPath, secKeyPath string, d *big.Int, pubKey *PubKey) error {
+	return writeKeyPair(pubKeyPath, secKeyPath, caPubKeyFileType, caSecKeyFileType,
+		pubKey.GetID(), pubKey, &caSecKey{D: d})
+}
+
+// ReadCAPubKey reads the CA public key from the file written by WriteCAKeyPair.
+func ReadCAPubKey(path string) (*PubKey, error) {
+	return readPubKey(path, caPubKeyFileType)
+}
+
+// ReadCASecKey reads the CA secret which matches pubKey from the file written
+// by WriteCAKeyPair.
+func ReadCASecKey(path string, pubKey *PubKey) (*big.Int, error) {
+	var k caSecKey
+	if err := readSecKey(path, caSecKeyFileType, pubKey.GetID(), &k); err != nil {
+		return nil, err
+	}
+	if k.D == nil {
+		return nil, fmt.Errorf("secret key file %s is not complete", path)
+	}
+
+	return k.D, nil
+}
+
 func NewCA(group *schnorr.Group, d *big.Int, caPubKey *PubKey) *CA {
 	c := ec.GetCurve(ec.P256)
 	pubKey := ecdsa.PublicKey{Curve: c, X: caPubKey.H1, Y: caPubKey.H2}
@@ -18,6 +18,9 @@
 package pseudsys
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"math/big"
 
 	"github.com/xlab-si/emmy/crypto/common"
@@ -50,3 +53,76 @@ func GenerateKeyPair(group *schnorr.Group) (*SecKey, *PubKey) {
 
 	return NewSecKey(s1, s2), NewPubKey(h1, h2)
 }
+
+const (
+	pubKeyFileType = "pseudonymsys-public-key"
+	secKeyFileType = "pseudonymsys-secret-key"
+)
+
+// GetID returns the identifier of the public key (hex encoded SHA-256 hash of H1 and H2).
+func (k *PubKey) GetID() string {
+	h := sha256.Sum256(common.ConcatenateNumbers(k.H1, k.H2))
+	return hex.EncodeToString(h[:])
+}
+
+// WriteKeyPair writes the public and the secret key to new files in the format described
+// by common.KeyFile. The secret key file is readable only by its owner.
+func WriteKeyPair(pubKeyPath, secKeyPath string, secKey *SecKey, pubKey *PubKey) error {
+	return writeKeyPair(pubKeyPath, secKeyPath, pubKeyFileType, secKeyFileType, pubKey.GetID(),
+		pubKey, secKey)
+}
+
+// ReadPubKey reads the public key from the file written by WriteKeyPair.
+func ReadPubKey(path string) (*PubKey, error) {
+	return readPubKey(path, pubKeyFileType)
+}
+
+// ReadSecKey reads the secret key which matches pubKey from the file written by WriteKeyPair.
+func ReadSecKey(path string, pubKey *PubKey) (*SecKey, error) {
+	secKey := new(SecKey)
+	if err := readSecKey(path, secKeyFileType, pubKey.GetID(), secKey); err != nil {
+		return nil, err
+	}
+
+	return secKey, nil
+}
+
+func writeKeyPair(pubKeyPath, secKeyPath, pubKeyType, secKeyType, keyID string,
+	pubKey, secKey interface{}) error {
+	if err := common.WriteKeyFile(pubKeyPath, pubKeyType, keyID, pubKey, false); err != nil {
+		return fmt.Errorf("error writing public key: %v", err)
+	}
+	if err := common.WriteKeyFile(secKeyPath, secKeyType, keyID, secKey, true); err != nil {
+		return fmt.Errorf("error writing secret key: %v", err)
+	}
+
+	return nil
+}
+
+func readPubKey(path, keyType string) (*PubKey, error) {
+	pubKey := new(PubKey)
+	keyID, err := common.ReadKeyFile(path, keyType, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if pubKey.H1 == nil || pubKey.H2 == nil {
+		return nil, fmt.Errorf("public key file %s is not complete", path)
+	}
+	if pubKey.GetID() != keyID {
+		return nil, fmt.Errorf("public key in %s does not match its ID", path)
+	}
+
+	return pubKey, nil
+}
+
+func readSecKey(path, keyType, pubKeyID string, secKey interface{}) error {
+	keyID, err := common.ReadKeyFile(path, keyType, secKey)
+	if err != nil {
+		return err
+	}
+	if keyID != pubKeyID {
+		return fmt.Errorf("secret key in %s does not match the public key", path)
+	}
+
+	return nil
+}
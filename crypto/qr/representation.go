@@ -104,6 +104,30 @@ func (p *RepresentationProver) GetProofRandomDataGivenBoundaries(boundariesBitLe
 	return t, nil
 }
 
+// GetProofRandomDataGivenRandomValues returns t = g_1^r_1 * ... * g_k^r_k where g_i are bases and
+// r_i are the given random values. This is useful when some of the random values need to be
+// shared with another proof (for example when proving that two proofs contain the same secret).
+func (p *RepresentationProver) GetProofRandomDataGivenRandomValues(randomVals []*big.Int) (*big.Int,
+	error) {
+	if len(randomVals) != len(p.bases) {
+		return nil, fmt.Errorf("the length of randomVals should be the same as the number of bases")
+	}
+	t := big.NewInt(1)
+	for i, r := range randomVals {
+		f := p.group.Exp(p.bases[i], r)
+		t = p.group.Mul(t, f)
+	}
+	p.randomVals = randomVals
+	return t, nil
+}
+
+// GetRandomValues returns random values r_i which were used in the computation of the
+// proof random data. This is useful when the same secret appears in another proof and the
+// two proofs need to be linked (by using the same random value for that secret).
+func (p *RepresentationProver) GetRandomValues() []*big.Int {
+	return p.randomVals
+}
+
 func (p *RepresentationProver) GetProofData(challenge *big.Int) []*big.Int {
 	// z_i = r_i + challenge * secrets[i] (in Z, not modulo)
 	var proofData = make([]*big.Int, len(p.bases))
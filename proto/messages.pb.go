@@ -16,6 +16,10 @@ It has these top-level messages:
 	Attribute
 	IntAttribute
 	StringAttribute
+	DateAttribute
+	BoolAttribute
+	EnumAttribute
+	HashedStringAttribute
 	CredAttribute
 	CredStructure
 	Status
@@ -45,11 +49,33 @@ It has these top-level messages:
 	CSPaillierSecretKey
 	CSPaillierPubKey
 	SessionKey
+	SessionAttr
+	SessionCred
+	SessionInfo
 	RegKey
+	CLOrg
 	CLCredReq
 	CLCredential
 	UpdateCLCredential
 	ProveCLCredential
+	CLProofRequest
+	CLAttrEquality
+	CLCredProof
+	ProveCLCredentials
+	CLWitness
+	CLNonRevocationProof
+	CLPredicateProof
+	CLSetMembershipProof
+	CLDomainPseudonymProof
+	CLEscrow
+	CLEscrowProof
+	CLRevokeCredential
+	CLAccumulatorUpdate
+	CLWitnessUpdatesRequest
+	CLWitnessUpdates
+	CLThresholdMessage
+	CLThresholdRound
+	CLThresholdMessages
 */
 package proto
 
@@ -95,6 +121,9 @@ type Message struct {
 	//	*Message_UpdateClCredential
 	//	*Message_ProveClCredential
 	//	*Message_RegKey
+	//	*Message_ProveClCredentials
+	//	*Message_ClProofRequest
+	//	*Message_ClOrg
 	Content  isMessage_Content `protobuf_oneof:"content"`
 	ClientId int32             `protobuf:"varint,28,opt,name=clientId" json:"clientId,omitempty"`
 }
@@ -177,6 +206,15 @@ type Message_ProveClCredential struct {
 type Message_RegKey struct {
 	RegKey *RegKey `protobuf:"bytes,35,opt,name=RegKey,oneof"`
 }
+type Message_ProveClCredentials struct {
+	ProveClCredentials *ProveCLCredentials `protobuf:"bytes,36,opt,name=prove_cl_credentials,json=proveClCredentials,oneof"`
+}
+type Message_ClProofRequest struct {
+	ClProofRequest *CLProofRequest `protobuf:"bytes,37,opt,name=cl_proof_request,json=clProofRequest,oneof"`
+}
+type Message_ClOrg struct {
+	ClOrg *CLOrg `protobuf:"bytes,38,opt,name=cl_org,json=clOrg,oneof"`
+}
 
 func (*Message_Bigint) isMessage_Content()                               {}
 func (*Message_EcGroupElement) isMessage_Content()                       {}
@@ -201,6 +239,9 @@ func (*Message_CLCredential) isMessage_Content()                         {}
 func (*Message_UpdateClCredential) isMessage_Content()                   {}
 func (*Message_ProveClCredential) isMessage_Content()                    {}
 func (*Message_RegKey) isMessage_Content()                               {}
+func (*Message_ProveClCredentials) isMessage_Content()                   {}
+func (*Message_ClProofRequest) isMessage_Content()                       {}
+func (*Message_ClOrg) isMessage_Content()                                {}
 
 func (m *Message) GetContent() isMessage_Content {
 	if m != nil {
@@ -370,6 +411,27 @@ func (m *Message) GetRegKey() *RegKey {
 	return nil
 }
 
+func (m *Message) GetProveClCredentials() *ProveCLCredentials {
+	if x, ok := m.GetContent().(*Message_ProveClCredentials); ok {
+		return x.ProveClCredentials
+	}
+	return nil
+}
+
+func (m *Message) GetClProofRequest() *CLProofRequest {
+	if x, ok := m.GetContent().(*Message_ClProofRequest); ok {
+		return x.ClProofRequest
+	}
+	return nil
+}
+
+func (m *Message) GetClOrg() *CLOrg {
+	if x, ok := m.GetContent().(*Message_ClOrg); ok {
+		return x.ClOrg
+	}
+	return nil
+}
+
 func (m *Message) GetClientId() int32 {
 	if m != nil {
 		return m.ClientId
@@ -403,6 +465,9 @@ func (*Message) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffer) err
 		(*Message_UpdateClCredential)(nil),
 		(*Message_ProveClCredential)(nil),
 		(*Message_RegKey)(nil),
+		(*Message_ProveClCredentials)(nil),
+		(*Message_ClProofRequest)(nil),
+		(*Message_ClOrg)(nil),
 	}
 }
 
@@ -525,6 +590,21 @@ func _Message_OneofMarshaler(msg proto1.Message, b *proto1.Buffer) error {
 		if err := b.EncodeMessage(x.RegKey); err != nil {
 			return err
 		}
+	case *Message_ProveClCredentials:
+		b.EncodeVarint(36<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.ProveClCredentials); err != nil {
+			return err
+		}
+	case *Message_ClProofRequest:
+		b.EncodeVarint(37<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.ClProofRequest); err != nil {
+			return err
+		}
+	case *Message_ClOrg:
+		b.EncodeVarint(38<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.ClOrg); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Message.Content has unexpected type %T", x)
@@ -719,6 +799,30 @@ func _Message_OneofUnmarshaler(msg proto1.Message, tag, wire int, b *proto1.Buff
 		err := b.DecodeMessage(msg)
 		m.Content = &Message_RegKey{msg}
 		return true, err
+	case 36: // content.prove_cl_credentials
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(ProveCLCredentials)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_ProveClCredentials{msg}
+		return true, err
+	case 37: // content.cl_proof_request
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(CLProofRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_ClProofRequest{msg}
+		return true, err
+	case 38: // content.cl_org
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(CLOrg)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_ClOrg{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -843,6 +947,21 @@ func _Message_OneofSizer(msg proto1.Message) (n int) {
 		n += proto1.SizeVarint(35<<3 | proto1.WireBytes)
 		n += proto1.SizeVarint(uint64(s))
 		n += s
+	case *Message_ProveClCredentials:
+		s := proto1.Size(x.ProveClCredentials)
+		n += proto1.SizeVarint(36<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *Message_ClProofRequest:
+		s := proto1.Size(x.ClProofRequest)
+		n += proto1.SizeVarint(37<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *Message_ClOrg:
+		s := proto1.Size(x.ClOrg)
+		n += proto1.SizeVarint(38<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -885,6 +1004,8 @@ func (m *ServiceInfo) GetProvider() string {
 type AcceptableCred struct {
 	OrgName       string   `protobuf:"bytes,1,opt,name=orgName" json:"orgName,omitempty"`
 	RevealedAttrs []string `protobuf:"bytes,2,rep,name=revealedAttrs" json:"revealedAttrs,omitempty"`
+	// policy is the verification policy in JSON format (see cl.VerificationPolicy)
+	Policy string `protobuf:"bytes,3,opt,name=policy" json:"policy,omitempty"`
 }
 
 func (m *AcceptableCred) Reset()                    { *m = AcceptableCred{} }
@@ -906,6 +1027,13 @@ func (m *AcceptableCred) GetRevealedAttrs() []string {
 	return nil
 }
 
+func (m *AcceptableCred) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
 type AcceptableCreds struct {
 	Creds []*AcceptableCred `protobuf:"bytes,1,rep,name=creds" json:"creds,omitempty"`
 }
@@ -923,9 +1051,10 @@ func (m *AcceptableCreds) GetCreds() []*AcceptableCred {
 }
 
 type Attribute struct {
-	Index int32  `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
-	Name  string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
-	Known bool   `protobuf:"varint,4,opt,name=known" json:"known,omitempty"`
+	Index  int32  `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Known  bool   `protobuf:"varint,4,opt,name=known" json:"known,omitempty"`
+	Hidden bool   `protobuf:"varint,5,opt,name=hidden" json:"hidden,omitempty"`
 }
 
 func (m *Attribute) Reset()                    { *m = Attribute{} }
@@ -954,6 +1083,13 @@ func (m *Attribute) GetKnown() bool {
 	return false
 }
 
+func (m *Attribute) GetHidden() bool {
+	if m != nil {
+		return m.Hidden
+	}
+	return false
+}
+
 type IntAttribute struct {
 	Attr *Attribute `protobuf:"bytes,1,opt,name=attr" json:"attr,omitempty"`
 }
@@ -986,17 +1122,93 @@ func (m *StringAttribute) GetAttr() *Attribute {
 	return nil
 }
 
+type DateAttribute struct {
+	Attr *Attribute `protobuf:"bytes,1,opt,name=attr" json:"attr,omitempty"`
+}
+
+func (m *DateAttribute) Reset()                    { *m = DateAttribute{} }
+func (m *DateAttribute) String() string            { return proto1.CompactTextString(m) }
+func (*DateAttribute) ProtoMessage()               {}
+func (*DateAttribute) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *DateAttribute) GetAttr() *Attribute {
+	if m != nil {
+		return m.Attr
+	}
+	return nil
+}
+
+type BoolAttribute struct {
+	Attr *Attribute `protobuf:"bytes,1,opt,name=attr" json:"attr,omitempty"`
+}
+
+func (m *BoolAttribute) Reset()                    { *m = BoolAttribute{} }
+func (m *BoolAttribute) String() string            { return proto1.CompactTextString(m) }
+func (*BoolAttribute) ProtoMessage()               {}
+func (*BoolAttribute) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *BoolAttribute) GetAttr() *Attribute {
+	if m != nil {
+		return m.Attr
+	}
+	return nil
+}
+
+type EnumAttribute struct {
+	Attr   *Attribute `protobuf:"bytes,1,opt,name=attr" json:"attr,omitempty"`
+	Values []string   `protobuf:"bytes,2,rep,name=values" json:"values,omitempty"`
+}
+
+func (m *EnumAttribute) Reset()                    { *m = EnumAttribute{} }
+func (m *EnumAttribute) String() string            { return proto1.CompactTextString(m) }
+func (*EnumAttribute) ProtoMessage()               {}
+func (*EnumAttribute) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *EnumAttribute) GetAttr() *Attribute {
+	if m != nil {
+		return m.Attr
+	}
+	return nil
+}
+
+func (m *EnumAttribute) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type HashedStringAttribute struct {
+	Attr *Attribute `protobuf:"bytes,1,opt,name=attr" json:"attr,omitempty"`
+}
+
+func (m *HashedStringAttribute) Reset()                    { *m = HashedStringAttribute{} }
+func (m *HashedStringAttribute) String() string            { return proto1.CompactTextString(m) }
+func (*HashedStringAttribute) ProtoMessage()               {}
+func (*HashedStringAttribute) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *HashedStringAttribute) GetAttr() *Attribute {
+	if m != nil {
+		return m.Attr
+	}
+	return nil
+}
+
 type CredAttribute struct {
 	// Types that are valid to be assigned to Type:
 	//	*CredAttribute_StringAttr
 	//	*CredAttribute_IntAttr
+	//	*CredAttribute_DateAttr
+	//	*CredAttribute_BoolAttr
+	//	*CredAttribute_EnumAttr
+	//	*CredAttribute_HashedStringAttr
 	Type isCredAttribute_Type `protobuf_oneof:"type"`
 }
 
 func (m *CredAttribute) Reset()                    { *m = CredAttribute{} }
 func (m *CredAttribute) String() string            { return proto1.CompactTextString(m) }
 func (*CredAttribute) ProtoMessage()               {}
-func (*CredAttribute) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*CredAttribute) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 type isCredAttribute_Type interface {
 	isCredAttribute_Type()
@@ -1008,9 +1220,25 @@ type CredAttribute_StringAttr struct {
 type CredAttribute_IntAttr struct {
 	IntAttr *IntAttribute `protobuf:"bytes,2,opt,name=intAttr,oneof"`
 }
+type CredAttribute_DateAttr struct {
+	DateAttr *DateAttribute `protobuf:"bytes,3,opt,name=dateAttr,oneof"`
+}
+type CredAttribute_BoolAttr struct {
+	BoolAttr *BoolAttribute `protobuf:"bytes,4,opt,name=boolAttr,oneof"`
+}
+type CredAttribute_EnumAttr struct {
+	EnumAttr *EnumAttribute `protobuf:"bytes,5,opt,name=enumAttr,oneof"`
+}
+type CredAttribute_HashedStringAttr struct {
+	HashedStringAttr *HashedStringAttribute `protobuf:"bytes,6,opt,name=hashedStringAttr,oneof"`
+}
 
-func (*CredAttribute_StringAttr) isCredAttribute_Type() {}
-func (*CredAttribute_IntAttr) isCredAttribute_Type()    {}
+func (*CredAttribute_StringAttr) isCredAttribute_Type()       {}
+func (*CredAttribute_IntAttr) isCredAttribute_Type()          {}
+func (*CredAttribute_DateAttr) isCredAttribute_Type()         {}
+func (*CredAttribute_BoolAttr) isCredAttribute_Type()         {}
+func (*CredAttribute_EnumAttr) isCredAttribute_Type()         {}
+func (*CredAttribute_HashedStringAttr) isCredAttribute_Type() {}
 
 func (m *CredAttribute) GetType() isCredAttribute_Type {
 	if m != nil {
@@ -1033,11 +1261,43 @@ func (m *CredAttribute) GetIntAttr() *IntAttribute {
 	return nil
 }
 
+func (m *CredAttribute) GetDateAttr() *DateAttribute {
+	if x, ok := m.GetType().(*CredAttribute_DateAttr); ok {
+		return x.DateAttr
+	}
+	return nil
+}
+
+func (m *CredAttribute) GetBoolAttr() *BoolAttribute {
+	if x, ok := m.GetType().(*CredAttribute_BoolAttr); ok {
+		return x.BoolAttr
+	}
+	return nil
+}
+
+func (m *CredAttribute) GetEnumAttr() *EnumAttribute {
+	if x, ok := m.GetType().(*CredAttribute_EnumAttr); ok {
+		return x.EnumAttr
+	}
+	return nil
+}
+
+func (m *CredAttribute) GetHashedStringAttr() *HashedStringAttribute {
+	if x, ok := m.GetType().(*CredAttribute_HashedStringAttr); ok {
+		return x.HashedStringAttr
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*CredAttribute) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffer) error, func(msg proto1.Message, tag, wire int, b *proto1.Buffer) (bool, error), func(msg proto1.Message) (n int), []interface{}) {
 	return _CredAttribute_OneofMarshaler, _CredAttribute_OneofUnmarshaler, _CredAttribute_OneofSizer, []interface{}{
 		(*CredAttribute_StringAttr)(nil),
 		(*CredAttribute_IntAttr)(nil),
+		(*CredAttribute_DateAttr)(nil),
+		(*CredAttribute_BoolAttr)(nil),
+		(*CredAttribute_EnumAttr)(nil),
+		(*CredAttribute_HashedStringAttr)(nil),
 	}
 }
 
@@ -1055,6 +1315,26 @@ func _CredAttribute_OneofMarshaler(msg proto1.Message, b *proto1.Buffer) error {
 		if err := b.EncodeMessage(x.IntAttr); err != nil {
 			return err
 		}
+	case *CredAttribute_DateAttr:
+		b.EncodeVarint(3<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.DateAttr); err != nil {
+			return err
+		}
+	case *CredAttribute_BoolAttr:
+		b.EncodeVarint(4<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.BoolAttr); err != nil {
+			return err
+		}
+	case *CredAttribute_EnumAttr:
+		b.EncodeVarint(5<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.EnumAttr); err != nil {
+			return err
+		}
+	case *CredAttribute_HashedStringAttr:
+		b.EncodeVarint(6<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.HashedStringAttr); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("CredAttribute.Type has unexpected type %T", x)
@@ -1081,6 +1361,38 @@ func _CredAttribute_OneofUnmarshaler(msg proto1.Message, tag, wire int, b *proto
 		err := b.DecodeMessage(msg)
 		m.Type = &CredAttribute_IntAttr{msg}
 		return true, err
+	case 3: // type.dateAttr
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(DateAttribute)
+		err := b.DecodeMessage(msg)
+		m.Type = &CredAttribute_DateAttr{msg}
+		return true, err
+	case 4: // type.boolAttr
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(BoolAttribute)
+		err := b.DecodeMessage(msg)
+		m.Type = &CredAttribute_BoolAttr{msg}
+		return true, err
+	case 5: // type.enumAttr
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(EnumAttribute)
+		err := b.DecodeMessage(msg)
+		m.Type = &CredAttribute_EnumAttr{msg}
+		return true, err
+	case 6: // type.hashedStringAttr
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(HashedStringAttribute)
+		err := b.DecodeMessage(msg)
+		m.Type = &CredAttribute_HashedStringAttr{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -1100,6 +1412,26 @@ func _CredAttribute_OneofSizer(msg proto1.Message) (n int) {
 		n += proto1.SizeVarint(2<<3 | proto1.WireBytes)
 		n += proto1.SizeVarint(uint64(s))
 		n += s
+	case *CredAttribute_DateAttr:
+		s := proto1.Size(x.DateAttr)
+		n += proto1.SizeVarint(3<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *CredAttribute_BoolAttr:
+		s := proto1.Size(x.BoolAttr)
+		n += proto1.SizeVarint(4<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *CredAttribute_EnumAttr:
+		s := proto1.Size(x.EnumAttr)
+		n += proto1.SizeVarint(5<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *CredAttribute_HashedStringAttr:
+		s := proto1.Size(x.HashedStringAttr)
+		n += proto1.SizeVarint(6<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1112,12 +1444,14 @@ type CredStructure struct {
 	NCommitted int32            `protobuf:"varint,2,opt,name=nCommitted" json:"nCommitted,omitempty"`
 	NHidden    int32            `protobuf:"varint,3,opt,name=nHidden" json:"nHidden,omitempty"`
 	Attributes []*CredAttribute `protobuf:"bytes,4,rep,name=attributes" json:"attributes,omitempty"`
+	// keyId is the ID of the key under which the credentials are issued
+	KeyId string `protobuf:"bytes,5,opt,name=keyId" json:"keyId,omitempty"`
 }
 
 func (m *CredStructure) Reset()                    { *m = CredStructure{} }
 func (m *CredStructure) String() string            { return proto1.CompactTextString(m) }
 func (*CredStructure) ProtoMessage()               {}
-func (*CredStructure) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*CredStructure) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *CredStructure) GetNKnown() int32 {
 	if m != nil {
@@ -1147,6 +1481,13 @@ func (m *CredStructure) GetAttributes() []*CredAttribute {
 	return nil
 }
 
+func (m *CredStructure) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
 type Status struct {
 	Success bool `protobuf:"varint,1,opt,name=Success" json:"Success,omitempty"`
 }
@@ -1154,7 +1495,7 @@ type Status struct {
 func (m *Status) Reset()                    { *m = Status{} }
 func (m *Status) String() string            { return proto1.CompactTextString(m) }
 func (*Status) ProtoMessage()               {}
-func (*Status) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*Status) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *Status) GetSuccess() bool {
 	if m != nil {
@@ -1170,7 +1511,7 @@ type BigInt struct {
 func (m *BigInt) Reset()                    { *m = BigInt{} }
 func (m *BigInt) String() string            { return proto1.CompactTextString(m) }
 func (*BigInt) ProtoMessage()               {}
-func (*BigInt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*BigInt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *BigInt) GetX1() []byte {
 	if m != nil {
@@ -1187,7 +1528,7 @@ type DoubleBigInt struct {
 func (m *DoubleBigInt) Reset()                    { *m = DoubleBigInt{} }
 func (m *DoubleBigInt) String() string            { return proto1.CompactTextString(m) }
 func (*DoubleBigInt) ProtoMessage()               {}
-func (*DoubleBigInt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*DoubleBigInt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *DoubleBigInt) GetX1() []byte {
 	if m != nil {
@@ -1210,7 +1551,7 @@ type PedersenFirst struct {
 func (m *PedersenFirst) Reset()                    { *m = PedersenFirst{} }
 func (m *PedersenFirst) String() string            { return proto1.CompactTextString(m) }
 func (*PedersenFirst) ProtoMessage()               {}
-func (*PedersenFirst) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*PedersenFirst) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *PedersenFirst) GetH() []byte {
 	if m != nil {
@@ -1227,7 +1568,7 @@ type PedersenDecommitment struct {
 func (m *PedersenDecommitment) Reset()                    { *m = PedersenDecommitment{} }
 func (m *PedersenDecommitment) String() string            { return proto1.CompactTextString(m) }
 func (*PedersenDecommitment) ProtoMessage()               {}
-func (*PedersenDecommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*PedersenDecommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *PedersenDecommitment) GetX() []byte {
 	if m != nil {
@@ -1251,7 +1592,7 @@ type ECGroupElement struct {
 func (m *ECGroupElement) Reset()                    { *m = ECGroupElement{} }
 func (m *ECGroupElement) String() string            { return proto1.CompactTextString(m) }
 func (*ECGroupElement) ProtoMessage()               {}
-func (*ECGroupElement) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*ECGroupElement) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *ECGroupElement) GetX() []byte {
 	if m != nil {
@@ -1275,7 +1616,7 @@ type Pair struct {
 func (m *Pair) Reset()                    { *m = Pair{} }
 func (m *Pair) String() string            { return proto1.CompactTextString(m) }
 func (*Pair) ProtoMessage()               {}
-func (*Pair) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*Pair) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *Pair) GetA() []byte {
 	if m != nil {
@@ -1300,7 +1641,7 @@ type SchnorrProofRandomData struct {
 func (m *SchnorrProofRandomData) Reset()                    { *m = SchnorrProofRandomData{} }
 func (m *SchnorrProofRandomData) String() string            { return proto1.CompactTextString(m) }
 func (*SchnorrProofRandomData) ProtoMessage()               {}
-func (*SchnorrProofRandomData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*SchnorrProofRandomData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *SchnorrProofRandomData) GetX() []byte {
 	if m != nil {
@@ -1330,7 +1671,7 @@ type SchnorrProofData struct {
 func (m *SchnorrProofData) Reset()                    { *m = SchnorrProofData{} }
 func (m *SchnorrProofData) String() string            { return proto1.CompactTextString(m) }
 func (*SchnorrProofData) ProtoMessage()               {}
-func (*SchnorrProofData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*SchnorrProofData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *SchnorrProofData) GetZ() []byte {
 	if m != nil {
@@ -1353,7 +1694,7 @@ type FiatShamir struct {
 func (m *FiatShamir) Reset()                    { *m = FiatShamir{} }
 func (m *FiatShamir) String() string            { return proto1.CompactTextString(m) }
 func (*FiatShamir) ProtoMessage()               {}
-func (*FiatShamir) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*FiatShamir) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *FiatShamir) GetProofRandomData() []byte {
 	if m != nil {
@@ -1386,7 +1727,7 @@ type FiatShamirAlsoNeg struct {
 func (m *FiatShamirAlsoNeg) Reset()                    { *m = FiatShamirAlsoNeg{} }
 func (m *FiatShamirAlsoNeg) String() string            { return proto1.CompactTextString(m) }
 func (*FiatShamirAlsoNeg) ProtoMessage()               {}
-func (*FiatShamirAlsoNeg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*FiatShamirAlsoNeg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *FiatShamirAlsoNeg) GetProofRandomData() []byte {
 	if m != nil {
@@ -1418,7 +1759,7 @@ type SchnorrECProofRandomData struct {
 func (m *SchnorrECProofRandomData) Reset()                    { *m = SchnorrECProofRandomData{} }
 func (m *SchnorrECProofRandomData) String() string            { return proto1.CompactTextString(m) }
 func (*SchnorrECProofRandomData) ProtoMessage()               {}
-func (*SchnorrECProofRandomData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*SchnorrECProofRandomData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *SchnorrECProofRandomData) GetX() *ECGroupElement {
 	if m != nil {
@@ -1457,7 +1798,7 @@ func (m *PseudonymsysNymGenProofRandomData) Reset()         { *m = PseudonymsysN
 func (m *PseudonymsysNymGenProofRandomData) String() string { return proto1.CompactTextString(m) }
 func (*PseudonymsysNymGenProofRandomData) ProtoMessage()    {}
 func (*PseudonymsysNymGenProofRandomData) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{21}
+	return fileDescriptor0, []int{25}
 }
 
 func (m *PseudonymsysNymGenProofRandomData) GetX1() []byte {
@@ -1539,7 +1880,7 @@ func (m *PseudonymsysNymGenProofRandomDataEC) Reset()         { *m = Pseudonymsy
 func (m *PseudonymsysNymGenProofRandomDataEC) String() string { return proto1.CompactTextString(m) }
 func (*PseudonymsysNymGenProofRandomDataEC) ProtoMessage()    {}
 func (*PseudonymsysNymGenProofRandomDataEC) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{22}
+	return fileDescriptor0, []int{26}
 }
 
 func (m *PseudonymsysNymGenProofRandomDataEC) GetX1() *ECGroupElement {
@@ -1615,7 +1956,7 @@ type PseudonymsysCACertificate struct {
 func (m *PseudonymsysCACertificate) Reset()                    { *m = PseudonymsysCACertificate{} }
 func (m *PseudonymsysCACertificate) String() string            { return proto1.CompactTextString(m) }
 func (*PseudonymsysCACertificate) ProtoMessage()               {}
-func (*PseudonymsysCACertificate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (*PseudonymsysCACertificate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 func (m *PseudonymsysCACertificate) GetBlindedA() []byte {
 	if m != nil {
@@ -1655,7 +1996,7 @@ type PseudonymsysCACertificateEC struct {
 func (m *PseudonymsysCACertificateEC) Reset()                    { *m = PseudonymsysCACertificateEC{} }
 func (m *PseudonymsysCACertificateEC) String() string            { return proto1.CompactTextString(m) }
 func (*PseudonymsysCACertificateEC) ProtoMessage()               {}
-func (*PseudonymsysCACertificateEC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+func (*PseudonymsysCACertificateEC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
 func (m *PseudonymsysCACertificateEC) GetBlindedA() *ECGroupElement {
 	if m != nil {
@@ -1698,7 +2039,7 @@ func (m *PseudonymsysIssueProofRandomData) Reset()         { *m = PseudonymsysIs
 func (m *PseudonymsysIssueProofRandomData) String() string { return proto1.CompactTextString(m) }
 func (*PseudonymsysIssueProofRandomData) ProtoMessage()    {}
 func (*PseudonymsysIssueProofRandomData) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{25}
+	return fileDescriptor0, []int{29}
 }
 
 func (m *PseudonymsysIssueProofRandomData) GetX11() []byte {
@@ -1756,7 +2097,7 @@ func (m *PseudonymsysIssueProofRandomDataEC) Reset()         { *m = Pseudonymsys
 func (m *PseudonymsysIssueProofRandomDataEC) String() string { return proto1.CompactTextString(m) }
 func (*PseudonymsysIssueProofRandomDataEC) ProtoMessage()    {}
 func (*PseudonymsysIssueProofRandomDataEC) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{26}
+	return fileDescriptor0, []int{30}
 }
 
 func (m *PseudonymsysIssueProofRandomDataEC) GetX11() *ECGroupElement {
@@ -1811,7 +2152,7 @@ type PseudonymsysTranscript struct {
 func (m *PseudonymsysTranscript) Reset()                    { *m = PseudonymsysTranscript{} }
 func (m *PseudonymsysTranscript) String() string            { return proto1.CompactTextString(m) }
 func (*PseudonymsysTranscript) ProtoMessage()               {}
-func (*PseudonymsysTranscript) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (*PseudonymsysTranscript) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
 
 func (m *PseudonymsysTranscript) GetA() []byte {
 	if m != nil {
@@ -1851,7 +2192,7 @@ type PseudonymsysTranscriptEC struct {
 func (m *PseudonymsysTranscriptEC) Reset()                    { *m = PseudonymsysTranscriptEC{} }
 func (m *PseudonymsysTranscriptEC) String() string            { return proto1.CompactTextString(m) }
 func (*PseudonymsysTranscriptEC) ProtoMessage()               {}
-func (*PseudonymsysTranscriptEC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+func (*PseudonymsysTranscriptEC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
 
 func (m *PseudonymsysTranscriptEC) GetA() *ECGroupElement {
 	if m != nil {
@@ -1893,7 +2234,7 @@ type PseudonymsysCredential struct {
 func (m *PseudonymsysCredential) Reset()                    { *m = PseudonymsysCredential{} }
 func (m *PseudonymsysCredential) String() string            { return proto1.CompactTextString(m) }
 func (*PseudonymsysCredential) ProtoMessage()               {}
-func (*PseudonymsysCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+func (*PseudonymsysCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
 
 func (m *PseudonymsysCredential) GetSmallAToGamma() []byte {
 	if m != nil {
@@ -1949,7 +2290,7 @@ type PseudonymsysCredentialEC struct {
 func (m *PseudonymsysCredentialEC) Reset()                    { *m = PseudonymsysCredentialEC{} }
 func (m *PseudonymsysCredentialEC) String() string            { return proto1.CompactTextString(m) }
 func (*PseudonymsysCredentialEC) ProtoMessage()               {}
-func (*PseudonymsysCredentialEC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+func (*PseudonymsysCredentialEC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
 
 func (m *PseudonymsysCredentialEC) GetSmallAToGamma() *ECGroupElement {
 	if m != nil {
@@ -2006,7 +2347,7 @@ func (m *PseudonymsysTransferCredentialData) Reset()         { *m = Pseudonymsys
 func (m *PseudonymsysTransferCredentialData) String() string { return proto1.CompactTextString(m) }
 func (*PseudonymsysTransferCredentialData) ProtoMessage()    {}
 func (*PseudonymsysTransferCredentialData) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{31}
+	return fileDescriptor0, []int{35}
 }
 
 func (m *PseudonymsysTransferCredentialData) GetOrgName() string {
@@ -2064,7 +2405,7 @@ func (m *PseudonymsysTransferCredentialDataEC) Reset()         { *m = Pseudonyms
 func (m *PseudonymsysTransferCredentialDataEC) String() string { return proto1.CompactTextString(m) }
 func (*PseudonymsysTransferCredentialDataEC) ProtoMessage()    {}
 func (*PseudonymsysTransferCredentialDataEC) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{32}
+	return fileDescriptor0, []int{36}
 }
 
 func (m *PseudonymsysTransferCredentialDataEC) GetOrgName() string {
@@ -2128,7 +2469,7 @@ type CSPaillierSecretKey struct {
 func (m *CSPaillierSecretKey) Reset()                    { *m = CSPaillierSecretKey{} }
 func (m *CSPaillierSecretKey) String() string            { return proto1.CompactTextString(m) }
 func (*CSPaillierSecretKey) ProtoMessage()               {}
-func (*CSPaillierSecretKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+func (*CSPaillierSecretKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
 
 func (m *CSPaillierSecretKey) GetN() []byte {
 	if m != nil {
@@ -2240,7 +2581,7 @@ type CSPaillierPubKey struct {
 func (m *CSPaillierPubKey) Reset()                    { *m = CSPaillierPubKey{} }
 func (m *CSPaillierPubKey) String() string            { return proto1.CompactTextString(m) }
 func (*CSPaillierPubKey) ProtoMessage()               {}
-func (*CSPaillierPubKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+func (*CSPaillierPubKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
 
 func (m *CSPaillierPubKey) GetN() []byte {
 	if m != nil {
@@ -2340,7 +2681,7 @@ type SessionKey struct {
 func (m *SessionKey) Reset()                    { *m = SessionKey{} }
 func (m *SessionKey) String() string            { return proto1.CompactTextString(m) }
 func (*SessionKey) ProtoMessage()               {}
-func (*SessionKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+func (*SessionKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
 
 func (m *SessionKey) GetValue() string {
 	if m != nil {
@@ -2349,14 +2690,99 @@ func (m *SessionKey) GetValue() string {
 	return ""
 }
 
+type SessionAttr struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *SessionAttr) Reset()                    { *m = SessionAttr{} }
+func (m *SessionAttr) String() string            { return proto1.CompactTextString(m) }
+func (*SessionAttr) ProtoMessage()               {}
+func (*SessionAttr) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *SessionAttr) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SessionAttr) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type SessionCred struct {
+	OrgName string `protobuf:"bytes,1,opt,name=orgName" json:"orgName,omitempty"`
+	// attrs are the revealed known attributes
+	Attrs []*SessionAttr `protobuf:"bytes,2,rep,name=attrs" json:"attrs,omitempty"`
+	// policy is the verification policy in JSON format (see cl.VerificationPolicy)
+	Policy string `protobuf:"bytes,3,opt,name=policy" json:"policy,omitempty"`
+}
+
+func (m *SessionCred) Reset()                    { *m = SessionCred{} }
+func (m *SessionCred) String() string            { return proto1.CompactTextString(m) }
+func (*SessionCred) ProtoMessage()               {}
+func (*SessionCred) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *SessionCred) GetOrgName() string {
+	if m != nil {
+		return m.OrgName
+	}
+	return ""
+}
+
+func (m *SessionCred) GetAttrs() []*SessionAttr {
+	if m != nil {
+		return m.Attrs
+	}
+	return nil
+}
+
+func (m *SessionCred) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+type SessionInfo struct {
+	Creds []*SessionCred `protobuf:"bytes,1,rep,name=creds" json:"creds,omitempty"`
+	// expiry is the time (Unix time) when the session expires
+	Expiry int64 `protobuf:"varint,2,opt,name=expiry" json:"expiry,omitempty"`
+}
+
+func (m *SessionInfo) Reset()                    { *m = SessionInfo{} }
+func (m *SessionInfo) String() string            { return proto1.CompactTextString(m) }
+func (*SessionInfo) ProtoMessage()               {}
+func (*SessionInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *SessionInfo) GetCreds() []*SessionCred {
+	if m != nil {
+		return m.Creds
+	}
+	return nil
+}
+
+func (m *SessionInfo) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
 type RegKey struct {
 	RegKey string `protobuf:"bytes,1,opt,name=RegKey" json:"RegKey,omitempty"`
+	// OrgName is the name of the CL organization which issues the credential
+	OrgName string `protobuf:"bytes,2,opt,name=OrgName" json:"OrgName,omitempty"`
 }
 
 func (m *RegKey) Reset()                    { *m = RegKey{} }
 func (m *RegKey) String() string            { return proto1.CompactTextString(m) }
 func (*RegKey) ProtoMessage()               {}
-func (*RegKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+func (*RegKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
 
 func (m *RegKey) GetRegKey() string {
 	if m != nil {
@@ -2365,6 +2791,30 @@ func (m *RegKey) GetRegKey() string {
 	return ""
 }
 
+func (m *RegKey) GetOrgName() string {
+	if m != nil {
+		return m.OrgName
+	}
+	return ""
+}
+
+// CLOrg names the CL organization (issuer) hosted by the server.
+type CLOrg struct {
+	OrgName string `protobuf:"bytes,1,opt,name=OrgName" json:"OrgName,omitempty"`
+}
+
+func (m *CLOrg) Reset()                    { *m = CLOrg{} }
+func (m *CLOrg) String() string            { return proto1.CompactTextString(m) }
+func (*CLOrg) ProtoMessage()               {}
+func (*CLOrg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *CLOrg) GetOrgName() string {
+	if m != nil {
+		return m.OrgName
+	}
+	return ""
+}
+
 type CLCredReq struct {
 	Nym                      []byte             `protobuf:"bytes,1,opt,name=Nym,proto3" json:"Nym,omitempty"`
 	KnownAttrs               [][]byte           `protobuf:"bytes,2,rep,name=KnownAttrs,proto3" json:"KnownAttrs,omitempty"`
@@ -2379,7 +2829,7 @@ type CLCredReq struct {
 func (m *CLCredReq) Reset()                    { *m = CLCredReq{} }
 func (m *CLCredReq) String() string            { return proto1.CompactTextString(m) }
 func (*CLCredReq) ProtoMessage()               {}
-func (*CLCredReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+func (*CLCredReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
 
 func (m *CLCredReq) GetNym() []byte {
 	if m != nil {
@@ -2438,16 +2888,17 @@ func (m *CLCredReq) GetNonce() []byte {
 }
 
 type CLCredential struct {
-	A      []byte             `protobuf:"bytes,1,opt,name=A,proto3" json:"A,omitempty"`
-	E      []byte             `protobuf:"bytes,2,opt,name=E,proto3" json:"E,omitempty"`
-	V11    []byte             `protobuf:"bytes,3,opt,name=V11,proto3" json:"V11,omitempty"`
-	AProof *FiatShamirAlsoNeg `protobuf:"bytes,4,opt,name=AProof" json:"AProof,omitempty"`
+	A       []byte             `protobuf:"bytes,1,opt,name=A,proto3" json:"A,omitempty"`
+	E       []byte             `protobuf:"bytes,2,opt,name=E,proto3" json:"E,omitempty"`
+	V11     []byte             `protobuf:"bytes,3,opt,name=V11,proto3" json:"V11,omitempty"`
+	AProof  *FiatShamirAlsoNeg `protobuf:"bytes,4,opt,name=AProof" json:"AProof,omitempty"`
+	Witness *CLWitness         `protobuf:"bytes,5,opt,name=Witness" json:"Witness,omitempty"`
 }
 
 func (m *CLCredential) Reset()                    { *m = CLCredential{} }
 func (m *CLCredential) String() string            { return proto1.CompactTextString(m) }
 func (*CLCredential) ProtoMessage()               {}
-func (*CLCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+func (*CLCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
 
 func (m *CLCredential) GetA() []byte {
 	if m != nil {
@@ -2477,16 +2928,26 @@ func (m *CLCredential) GetAProof() *FiatShamirAlsoNeg {
 	return nil
 }
 
+func (m *CLCredential) GetWitness() *CLWitness {
+	if m != nil {
+		return m.Witness
+	}
+	return nil
+}
+
 type UpdateCLCredential struct {
-	Nym           []byte   `protobuf:"bytes,1,opt,name=Nym,proto3" json:"Nym,omitempty"`
-	Nonce         []byte   `protobuf:"bytes,2,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
-	NewKnownAttrs [][]byte `protobuf:"bytes,3,rep,name=NewKnownAttrs,proto3" json:"NewKnownAttrs,omitempty"`
+	Nym                      []byte        `protobuf:"bytes,1,opt,name=Nym,proto3" json:"Nym,omitempty"`
+	Nonce                    []byte        `protobuf:"bytes,2,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	NewKnownAttrs            [][]byte      `protobuf:"bytes,3,rep,name=NewKnownAttrs,proto3" json:"NewKnownAttrs,omitempty"`
+	CommitmentsOfAttrs       [][]byte      `protobuf:"bytes,4,rep,name=CommitmentsOfAttrs,proto3" json:"CommitmentsOfAttrs,omitempty"`
+	CommitmentsOfAttrsProofs []*FiatShamir `protobuf:"bytes,5,rep,name=CommitmentsOfAttrsProofs" json:"CommitmentsOfAttrsProofs,omitempty"`
+	NymProof                 *FiatShamir   `protobuf:"bytes,7,opt,name=NymProof" json:"NymProof,omitempty"`
 }
 
 func (m *UpdateCLCredential) Reset()                    { *m = UpdateCLCredential{} }
 func (m *UpdateCLCredential) String() string            { return proto1.CompactTextString(m) }
 func (*UpdateCLCredential) ProtoMessage()               {}
-func (*UpdateCLCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+func (*UpdateCLCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
 
 func (m *UpdateCLCredential) GetNym() []byte {
 	if m != nil {
@@ -2509,19 +2970,48 @@ func (m *UpdateCLCredential) GetNewKnownAttrs() [][]byte {
 	return nil
 }
 
+func (m *UpdateCLCredential) GetCommitmentsOfAttrs() [][]byte {
+	if m != nil {
+		return m.CommitmentsOfAttrs
+	}
+	return nil
+}
+
+func (m *UpdateCLCredential) GetCommitmentsOfAttrsProofs() []*FiatShamir {
+	if m != nil {
+		return m.CommitmentsOfAttrsProofs
+	}
+	return nil
+}
+
+func (m *UpdateCLCredential) GetNymProof() *FiatShamir {
+	if m != nil {
+		return m.NymProof
+	}
+	return nil
+}
+
 type ProveCLCredential struct {
-	A                          []byte             `protobuf:"bytes,1,opt,name=A,proto3" json:"A,omitempty"`
-	Proof                      *FiatShamirAlsoNeg `protobuf:"bytes,2,opt,name=Proof" json:"Proof,omitempty"`
-	KnownAttrs                 [][]byte           `protobuf:"bytes,3,rep,name=KnownAttrs,proto3" json:"KnownAttrs,omitempty"`
-	CommitmentsOfAttrs         [][]byte           `protobuf:"bytes,4,rep,name=CommitmentsOfAttrs,proto3" json:"CommitmentsOfAttrs,omitempty"`
-	RevealedKnownAttrs         []int32            `protobuf:"varint,5,rep,packed,name=RevealedKnownAttrs" json:"RevealedKnownAttrs,omitempty"`
-	RevealedCommitmentsOfAttrs []int32            `protobuf:"varint,6,rep,packed,name=RevealedCommitmentsOfAttrs" json:"RevealedCommitmentsOfAttrs,omitempty"`
+	A                          []byte                  `protobuf:"bytes,1,opt,name=A,proto3" json:"A,omitempty"`
+	Proof                      *FiatShamirAlsoNeg      `protobuf:"bytes,2,opt,name=Proof" json:"Proof,omitempty"`
+	KnownAttrs                 [][]byte                `protobuf:"bytes,3,rep,name=KnownAttrs,proto3" json:"KnownAttrs,omitempty"`
+	CommitmentsOfAttrs         [][]byte                `protobuf:"bytes,4,rep,name=CommitmentsOfAttrs,proto3" json:"CommitmentsOfAttrs,omitempty"`
+	RevealedKnownAttrs         []int32                 `protobuf:"varint,5,rep,packed,name=RevealedKnownAttrs" json:"RevealedKnownAttrs,omitempty"`
+	RevealedCommitmentsOfAttrs []int32                 `protobuf:"varint,6,rep,packed,name=RevealedCommitmentsOfAttrs" json:"RevealedCommitmentsOfAttrs,omitempty"`
+	NonRevocationProof         *CLNonRevocationProof   `protobuf:"bytes,7,opt,name=NonRevocationProof" json:"NonRevocationProof,omitempty"`
+	PredicateProofs            []*CLPredicateProof     `protobuf:"bytes,8,rep,name=PredicateProofs" json:"PredicateProofs,omitempty"`
+	SetMembershipProofs        []*CLSetMembershipProof `protobuf:"bytes,9,rep,name=SetMembershipProofs" json:"SetMembershipProofs,omitempty"`
+	DomainPseudonymProof       *CLDomainPseudonymProof `protobuf:"bytes,10,opt,name=DomainPseudonymProof" json:"DomainPseudonymProof,omitempty"`
+	// KeyId is the ID of the key under which the credential was issued (the
+	// currently active key when empty)
+	KeyId       string         `protobuf:"bytes,11,opt,name=KeyId" json:"KeyId,omitempty"`
+	EscrowProof *CLEscrowProof `protobuf:"bytes,12,opt,name=EscrowProof" json:"EscrowProof,omitempty"`
 }
 
 func (m *ProveCLCredential) Reset()                    { *m = ProveCLCredential{} }
 func (m *ProveCLCredential) String() string            { return proto1.CompactTextString(m) }
 func (*ProveCLCredential) ProtoMessage()               {}
-func (*ProveCLCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+func (*ProveCLCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
 
 func (m *ProveCLCredential) GetA() []byte {
 	if m != nil {
@@ -2565,6 +3055,778 @@ func (m *ProveCLCredential) GetRevealedCommitmentsOfAttrs() []int32 {
 	return nil
 }
 
+func (m *ProveCLCredential) GetNonRevocationProof() *CLNonRevocationProof {
+	if m != nil {
+		return m.NonRevocationProof
+	}
+	return nil
+}
+
+func (m *ProveCLCredential) GetPredicateProofs() []*CLPredicateProof {
+	if m != nil {
+		return m.PredicateProofs
+	}
+	return nil
+}
+
+func (m *ProveCLCredential) GetSetMembershipProofs() []*CLSetMembershipProof {
+	if m != nil {
+		return m.SetMembershipProofs
+	}
+	return nil
+}
+
+func (m *ProveCLCredential) GetDomainPseudonymProof() *CLDomainPseudonymProof {
+	if m != nil {
+		return m.DomainPseudonymProof
+	}
+	return nil
+}
+
+func (m *ProveCLCredential) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *ProveCLCredential) GetEscrowProof() *CLEscrowProof {
+	if m != nil {
+		return m.EscrowProof
+	}
+	return nil
+}
+
+// CLProofRequest holds the nonce for a credential proof and the scope for which
+// a domain pseudonym is required (empty when no pseudonym is required).
+type CLProofRequest struct {
+	Nonce []byte `protobuf:"bytes,1,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	Scope []byte `protobuf:"bytes,2,opt,name=Scope,proto3" json:"Scope,omitempty"`
+	// Escrow (when set) requires the user to encrypt an attribute under the
+	// public key of the inspector
+	Escrow *CLEscrow `protobuf:"bytes,3,opt,name=Escrow" json:"Escrow,omitempty"`
+	// EscrowOrgName is the organization whose credentials need to contain the
+	// escrow proof (in proofs of several credentials)
+	EscrowOrgName string `protobuf:"bytes,4,opt,name=EscrowOrgName" json:"EscrowOrgName,omitempty"`
+	// AttrEqualities need to be proved when the credentials of both of their
+	// organizations are proved together
+	AttrEqualities []*CLAttrEquality `protobuf:"bytes,5,rep,name=AttrEqualities" json:"AttrEqualities,omitempty"`
+}
+
+func (m *CLProofRequest) Reset()                    { *m = CLProofRequest{} }
+func (m *CLProofRequest) String() string            { return proto1.CompactTextString(m) }
+func (*CLProofRequest) ProtoMessage()               {}
+func (*CLProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *CLProofRequest) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *CLProofRequest) GetScope() []byte {
+	if m != nil {
+		return m.Scope
+	}
+	return nil
+}
+
+func (m *CLProofRequest) GetEscrow() *CLEscrow {
+	if m != nil {
+		return m.Escrow
+	}
+	return nil
+}
+
+func (m *CLProofRequest) GetEscrowOrgName() string {
+	if m != nil {
+		return m.EscrowOrgName
+	}
+	return ""
+}
+
+func (m *CLProofRequest) GetAttrEqualities() []*CLAttrEquality {
+	if m != nil {
+		return m.AttrEqualities
+	}
+	return nil
+}
+
+// CLAttrEquality requires the attribute Attr1 in the credential of organization
+// Org1 to be equal to the attribute Attr2 in the credential of organization Org2.
+type CLAttrEquality struct {
+	Org1  string `protobuf:"bytes,1,opt,name=Org1" json:"Org1,omitempty"`
+	Attr1 string `protobuf:"bytes,2,opt,name=Attr1" json:"Attr1,omitempty"`
+	Org2  string `protobuf:"bytes,3,opt,name=Org2" json:"Org2,omitempty"`
+	Attr2 string `protobuf:"bytes,4,opt,name=Attr2" json:"Attr2,omitempty"`
+}
+
+func (m *CLAttrEquality) Reset()                    { *m = CLAttrEquality{} }
+func (m *CLAttrEquality) String() string            { return proto1.CompactTextString(m) }
+func (*CLAttrEquality) ProtoMessage()               {}
+func (*CLAttrEquality) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *CLAttrEquality) GetOrg1() string {
+	if m != nil {
+		return m.Org1
+	}
+	return ""
+}
+
+func (m *CLAttrEquality) GetAttr1() string {
+	if m != nil {
+		return m.Attr1
+	}
+	return ""
+}
+
+func (m *CLAttrEquality) GetOrg2() string {
+	if m != nil {
+		return m.Org2
+	}
+	return ""
+}
+
+func (m *CLAttrEquality) GetAttr2() string {
+	if m != nil {
+		return m.Attr2
+	}
+	return ""
+}
+
+// CLCredProof is a proof of a credential issued by organization OrgName.
+type CLCredProof struct {
+	OrgName string             `protobuf:"bytes,1,opt,name=OrgName" json:"OrgName,omitempty"`
+	Proof   *ProveCLCredential `protobuf:"bytes,2,opt,name=Proof" json:"Proof,omitempty"`
+}
+
+func (m *CLCredProof) Reset()                    { *m = CLCredProof{} }
+func (m *CLCredProof) String() string            { return proto1.CompactTextString(m) }
+func (*CLCredProof) ProtoMessage()               {}
+func (*CLCredProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *CLCredProof) GetOrgName() string {
+	if m != nil {
+		return m.OrgName
+	}
+	return ""
+}
+
+func (m *CLCredProof) GetProof() *ProveCLCredential {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+// ProveCLCredentials holds proofs of several credentials which share the challenge
+// and are bound to the same master secret.
+type ProveCLCredentials struct {
+	Proofs []*CLCredProof `protobuf:"bytes,1,rep,name=Proofs" json:"Proofs,omitempty"`
+}
+
+func (m *ProveCLCredentials) Reset()                    { *m = ProveCLCredentials{} }
+func (m *ProveCLCredentials) String() string            { return proto1.CompactTextString(m) }
+func (*ProveCLCredentials) ProtoMessage()               {}
+func (*ProveCLCredentials) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *ProveCLCredentials) GetProofs() []*CLCredProof {
+	if m != nil {
+		return m.Proofs
+	}
+	return nil
+}
+
+type CLWitness struct {
+	W     []byte `protobuf:"bytes,1,opt,name=W,proto3" json:"W,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	Epoch int32  `protobuf:"varint,3,opt,name=Epoch" json:"Epoch,omitempty"`
+}
+
+func (m *CLWitness) Reset()                    { *m = CLWitness{} }
+func (m *CLWitness) String() string            { return proto1.CompactTextString(m) }
+func (*CLWitness) ProtoMessage()               {}
+func (*CLWitness) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *CLWitness) GetW() []byte {
+	if m != nil {
+		return m.W
+	}
+	return nil
+}
+
+func (m *CLWitness) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *CLWitness) GetEpoch() int32 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+type CLNonRevocationProof struct {
+	Epoch           int32    `protobuf:"varint,1,opt,name=Epoch" json:"Epoch,omitempty"`
+	Commitments     [][]byte `protobuf:"bytes,2,rep,name=Commitments,proto3" json:"Commitments,omitempty"`
+	ProofRandomData [][]byte `protobuf:"bytes,3,rep,name=ProofRandomData,proto3" json:"ProofRandomData,omitempty"`
+	ProofData       []string `protobuf:"bytes,4,rep,name=ProofData" json:"ProofData,omitempty"`
+}
+
+func (m *CLNonRevocationProof) Reset()                    { *m = CLNonRevocationProof{} }
+func (m *CLNonRevocationProof) String() string            { return proto1.CompactTextString(m) }
+func (*CLNonRevocationProof) ProtoMessage()               {}
+func (*CLNonRevocationProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *CLNonRevocationProof) GetEpoch() int32 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *CLNonRevocationProof) GetCommitments() [][]byte {
+	if m != nil {
+		return m.Commitments
+	}
+	return nil
+}
+
+func (m *CLNonRevocationProof) GetProofRandomData() [][]byte {
+	if m != nil {
+		return m.ProofRandomData
+	}
+	return nil
+}
+
+func (m *CLNonRevocationProof) GetProofData() []string {
+	if m != nil {
+		return m.ProofData
+	}
+	return nil
+}
+
+type CLPredicateProof struct {
+	AttrIndex             int32    `protobuf:"varint,1,opt,name=AttrIndex" json:"AttrIndex,omitempty"`
+	Min                   string   `protobuf:"bytes,2,opt,name=Min" json:"Min,omitempty"`
+	Max                   string   `protobuf:"bytes,3,opt,name=Max" json:"Max,omitempty"`
+	Commitment            []byte   `protobuf:"bytes,4,opt,name=Commitment,proto3" json:"Commitment,omitempty"`
+	ProofRandomData       []byte   `protobuf:"bytes,5,opt,name=ProofRandomData,proto3" json:"ProofRandomData,omitempty"`
+	ProofData             string   `protobuf:"bytes,6,opt,name=ProofData" json:"ProofData,omitempty"`
+	SmallCommitments1     [][]byte `protobuf:"bytes,7,rep,name=SmallCommitments1,proto3" json:"SmallCommitments1,omitempty"`
+	BigCommitments1       [][]byte `protobuf:"bytes,8,rep,name=BigCommitments1,proto3" json:"BigCommitments1,omitempty"`
+	SmallCommitments2     [][]byte `protobuf:"bytes,9,rep,name=SmallCommitments2,proto3" json:"SmallCommitments2,omitempty"`
+	BigCommitments2       [][]byte `protobuf:"bytes,10,rep,name=BigCommitments2,proto3" json:"BigCommitments2,omitempty"`
+	RangeProofRandomData1 [][]byte `protobuf:"bytes,11,rep,name=RangeProofRandomData1,proto3" json:"RangeProofRandomData1,omitempty"`
+	RangeProofRandomData2 [][]byte `protobuf:"bytes,12,rep,name=RangeProofRandomData2,proto3" json:"RangeProofRandomData2,omitempty"`
+	RangeProofData1       []string `protobuf:"bytes,13,rep,name=RangeProofData1" json:"RangeProofData1,omitempty"`
+	RangeProofData2       []string `protobuf:"bytes,14,rep,name=RangeProofData2" json:"RangeProofData2,omitempty"`
+}
+
+func (m *CLPredicateProof) Reset()                    { *m = CLPredicateProof{} }
+func (m *CLPredicateProof) String() string            { return proto1.CompactTextString(m) }
+func (*CLPredicateProof) ProtoMessage()               {}
+func (*CLPredicateProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+func (m *CLPredicateProof) GetAttrIndex() int32 {
+	if m != nil {
+		return m.AttrIndex
+	}
+	return 0
+}
+
+func (m *CLPredicateProof) GetMin() string {
+	if m != nil {
+		return m.Min
+	}
+	return ""
+}
+
+func (m *CLPredicateProof) GetMax() string {
+	if m != nil {
+		return m.Max
+	}
+	return ""
+}
+
+func (m *CLPredicateProof) GetCommitment() []byte {
+	if m != nil {
+		return m.Commitment
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetProofRandomData() []byte {
+	if m != nil {
+		return m.ProofRandomData
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetProofData() string {
+	if m != nil {
+		return m.ProofData
+	}
+	return ""
+}
+
+func (m *CLPredicateProof) GetSmallCommitments1() [][]byte {
+	if m != nil {
+		return m.SmallCommitments1
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetBigCommitments1() [][]byte {
+	if m != nil {
+		return m.BigCommitments1
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetSmallCommitments2() [][]byte {
+	if m != nil {
+		return m.SmallCommitments2
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetBigCommitments2() [][]byte {
+	if m != nil {
+		return m.BigCommitments2
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetRangeProofRandomData1() [][]byte {
+	if m != nil {
+		return m.RangeProofRandomData1
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetRangeProofRandomData2() [][]byte {
+	if m != nil {
+		return m.RangeProofRandomData2
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetRangeProofData1() []string {
+	if m != nil {
+		return m.RangeProofData1
+	}
+	return nil
+}
+
+func (m *CLPredicateProof) GetRangeProofData2() []string {
+	if m != nil {
+		return m.RangeProofData2
+	}
+	return nil
+}
+
+type CLSetMembershipProof struct {
+	AttrIndex         int32    `protobuf:"varint,1,opt,name=AttrIndex" json:"AttrIndex,omitempty"`
+	Values            [][]byte `protobuf:"bytes,2,rep,name=Values,proto3" json:"Values,omitempty"`
+	Commitment        []byte   `protobuf:"bytes,3,opt,name=Commitment,proto3" json:"Commitment,omitempty"`
+	ProofRandomData   []byte   `protobuf:"bytes,4,opt,name=ProofRandomData,proto3" json:"ProofRandomData,omitempty"`
+	ProofData         string   `protobuf:"bytes,5,opt,name=ProofData" json:"ProofData,omitempty"`
+	OrProofRandomData [][]byte `protobuf:"bytes,6,rep,name=OrProofRandomData,proto3" json:"OrProofRandomData,omitempty"`
+	OrChallenges      [][]byte `protobuf:"bytes,7,rep,name=OrChallenges,proto3" json:"OrChallenges,omitempty"`
+	OrProofData       []string `protobuf:"bytes,8,rep,name=OrProofData" json:"OrProofData,omitempty"`
+}
+
+func (m *CLSetMembershipProof) Reset()                    { *m = CLSetMembershipProof{} }
+func (m *CLSetMembershipProof) String() string            { return proto1.CompactTextString(m) }
+func (*CLSetMembershipProof) ProtoMessage()               {}
+func (*CLSetMembershipProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *CLSetMembershipProof) GetAttrIndex() int32 {
+	if m != nil {
+		return m.AttrIndex
+	}
+	return 0
+}
+
+func (m *CLSetMembershipProof) GetValues() [][]byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *CLSetMembershipProof) GetCommitment() []byte {
+	if m != nil {
+		return m.Commitment
+	}
+	return nil
+}
+
+func (m *CLSetMembershipProof) GetProofRandomData() []byte {
+	if m != nil {
+		return m.ProofRandomData
+	}
+	return nil
+}
+
+func (m *CLSetMembershipProof) GetProofData() string {
+	if m != nil {
+		return m.ProofData
+	}
+	return ""
+}
+
+func (m *CLSetMembershipProof) GetOrProofRandomData() [][]byte {
+	if m != nil {
+		return m.OrProofRandomData
+	}
+	return nil
+}
+
+func (m *CLSetMembershipProof) GetOrChallenges() [][]byte {
+	if m != nil {
+		return m.OrChallenges
+	}
+	return nil
+}
+
+func (m *CLSetMembershipProof) GetOrProofData() []string {
+	if m != nil {
+		return m.OrProofData
+	}
+	return nil
+}
+
+type CLDomainPseudonymProof struct {
+	Scope           []byte `protobuf:"bytes,1,opt,name=Scope,proto3" json:"Scope,omitempty"`
+	Pseudonym       []byte `protobuf:"bytes,2,opt,name=Pseudonym,proto3" json:"Pseudonym,omitempty"`
+	ProofRandomData []byte `protobuf:"bytes,3,opt,name=ProofRandomData,proto3" json:"ProofRandomData,omitempty"`
+}
+
+func (m *CLDomainPseudonymProof) Reset()                    { *m = CLDomainPseudonymProof{} }
+func (m *CLDomainPseudonymProof) String() string            { return proto1.CompactTextString(m) }
+func (*CLDomainPseudonymProof) ProtoMessage()               {}
+func (*CLDomainPseudonymProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+
+func (m *CLDomainPseudonymProof) GetScope() []byte {
+	if m != nil {
+		return m.Scope
+	}
+	return nil
+}
+
+func (m *CLDomainPseudonymProof) GetPseudonym() []byte {
+	if m != nil {
+		return m.Pseudonym
+	}
+	return nil
+}
+
+func (m *CLDomainPseudonymProof) GetProofRandomData() []byte {
+	if m != nil {
+		return m.ProofRandomData
+	}
+	return nil
+}
+
+type CLEscrow struct {
+	AttrIndex int32             `protobuf:"varint,1,opt,name=AttrIndex" json:"AttrIndex,omitempty"`
+	PubKey    *CSPaillierPubKey `protobuf:"bytes,2,opt,name=PubKey" json:"PubKey,omitempty"`
+	Label     []byte            `protobuf:"bytes,3,opt,name=Label,proto3" json:"Label,omitempty"`
+}
+
+func (m *CLEscrow) Reset()                    { *m = CLEscrow{} }
+func (m *CLEscrow) String() string            { return proto1.CompactTextString(m) }
+func (*CLEscrow) ProtoMessage()               {}
+func (*CLEscrow) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *CLEscrow) GetAttrIndex() int32 {
+	if m != nil {
+		return m.AttrIndex
+	}
+	return 0
+}
+
+func (m *CLEscrow) GetPubKey() *CSPaillierPubKey {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *CLEscrow) GetLabel() []byte {
+	if m != nil {
+		return m.Label
+	}
+	return nil
+}
+
+type CLEscrowProof struct {
+	Escrow          *CLEscrow `protobuf:"bytes,1,opt,name=Escrow" json:"Escrow,omitempty"`
+	U               []byte    `protobuf:"bytes,2,opt,name=U,proto3" json:"U,omitempty"`
+	E               []byte    `protobuf:"bytes,3,opt,name=E,proto3" json:"E,omitempty"`
+	V               []byte    `protobuf:"bytes,4,opt,name=V,proto3" json:"V,omitempty"`
+	ProofRandomData [][]byte  `protobuf:"bytes,5,rep,name=ProofRandomData,proto3" json:"ProofRandomData,omitempty"`
+	ProofData       string    `protobuf:"bytes,6,opt,name=ProofData" json:"ProofData,omitempty"`
+}
+
+func (m *CLEscrowProof) Reset()                    { *m = CLEscrowProof{} }
+func (m *CLEscrowProof) String() string            { return proto1.CompactTextString(m) }
+func (*CLEscrowProof) ProtoMessage()               {}
+func (*CLEscrowProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+func (m *CLEscrowProof) GetEscrow() *CLEscrow {
+	if m != nil {
+		return m.Escrow
+	}
+	return nil
+}
+
+func (m *CLEscrowProof) GetU() []byte {
+	if m != nil {
+		return m.U
+	}
+	return nil
+}
+
+func (m *CLEscrowProof) GetE() []byte {
+	if m != nil {
+		return m.E
+	}
+	return nil
+}
+
+func (m *CLEscrowProof) GetV() []byte {
+	if m != nil {
+		return m.V
+	}
+	return nil
+}
+
+func (m *CLEscrowProof) GetProofRandomData() [][]byte {
+	if m != nil {
+		return m.ProofRandomData
+	}
+	return nil
+}
+
+func (m *CLEscrowProof) GetProofData() string {
+	if m != nil {
+		return m.ProofData
+	}
+	return ""
+}
+
+type CLRevokeCredential struct {
+	Nym     []byte `protobuf:"bytes,1,opt,name=Nym,proto3" json:"Nym,omitempty"`
+	OrgName string `protobuf:"bytes,2,opt,name=OrgName" json:"OrgName,omitempty"`
+}
+
+func (m *CLRevokeCredential) Reset()                    { *m = CLRevokeCredential{} }
+func (m *CLRevokeCredential) String() string            { return proto1.CompactTextString(m) }
+func (*CLRevokeCredential) ProtoMessage()               {}
+func (*CLRevokeCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *CLRevokeCredential) GetNym() []byte {
+	if m != nil {
+		return m.Nym
+	}
+	return nil
+}
+
+func (m *CLRevokeCredential) GetOrgName() string {
+	if m != nil {
+		return m.OrgName
+	}
+	return ""
+}
+
+type CLAccumulatorUpdate struct {
+	Epoch int32  `protobuf:"varint,1,opt,name=Epoch" json:"Epoch,omitempty"`
+	E     []byte `protobuf:"bytes,2,opt,name=E,proto3" json:"E,omitempty"`
+	Value []byte `protobuf:"bytes,3,opt,name=Value,proto3" json:"Value,omitempty"`
+}
+
+func (m *CLAccumulatorUpdate) Reset()                    { *m = CLAccumulatorUpdate{} }
+func (m *CLAccumulatorUpdate) String() string            { return proto1.CompactTextString(m) }
+func (*CLAccumulatorUpdate) ProtoMessage()               {}
+func (*CLAccumulatorUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+func (m *CLAccumulatorUpdate) GetEpoch() int32 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *CLAccumulatorUpdate) GetE() []byte {
+	if m != nil {
+		return m.E
+	}
+	return nil
+}
+
+func (m *CLAccumulatorUpdate) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type CLWitnessUpdatesRequest struct {
+	Epoch int32 `protobuf:"varint,1,opt,name=Epoch" json:"Epoch,omitempty"`
+	// KeyId is the ID of the key whose accumulator is updated (the currently
+	// active key when empty)
+	KeyId   string `protobuf:"bytes,2,opt,name=KeyId" json:"KeyId,omitempty"`
+	OrgName string `protobuf:"bytes,3,opt,name=OrgName" json:"OrgName,omitempty"`
+}
+
+func (m *CLWitnessUpdatesRequest) Reset()                    { *m = CLWitnessUpdatesRequest{} }
+func (m *CLWitnessUpdatesRequest) String() string            { return proto1.CompactTextString(m) }
+func (*CLWitnessUpdatesRequest) ProtoMessage()               {}
+func (*CLWitnessUpdatesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *CLWitnessUpdatesRequest) GetEpoch() int32 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *CLWitnessUpdatesRequest) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *CLWitnessUpdatesRequest) GetOrgName() string {
+	if m != nil {
+		return m.OrgName
+	}
+	return ""
+}
+
+type CLWitnessUpdates struct {
+	Updates []*CLAccumulatorUpdate `protobuf:"bytes,1,rep,name=Updates" json:"Updates,omitempty"`
+}
+
+func (m *CLWitnessUpdates) Reset()                    { *m = CLWitnessUpdates{} }
+func (m *CLWitnessUpdates) String() string            { return proto1.CompactTextString(m) }
+func (*CLWitnessUpdates) ProtoMessage()               {}
+func (*CLWitnessUpdates) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+func (m *CLWitnessUpdates) GetUpdates() []*CLAccumulatorUpdate {
+	if m != nil {
+		return m.Updates
+	}
+	return nil
+}
+
+type CLThresholdMessage struct {
+	From int32 `protobuf:"varint,1,opt,name=From" json:"From,omitempty"`
+	To   int32 `protobuf:"varint,2,opt,name=To" json:"To,omitempty"`
+	// Values can be negative
+	Values []string `protobuf:"bytes,3,rep,name=Values" json:"Values,omitempty"`
+}
+
+func (m *CLThresholdMessage) Reset()                    { *m = CLThresholdMessage{} }
+func (m *CLThresholdMessage) String() string            { return proto1.CompactTextString(m) }
+func (*CLThresholdMessage) ProtoMessage()               {}
+func (*CLThresholdMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+
+func (m *CLThresholdMessage) GetFrom() int32 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *CLThresholdMessage) GetTo() int32 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *CLThresholdMessage) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type CLThresholdRound struct {
+	// KeyId is the ID of the public key whose secret key share is used
+	KeyId string `protobuf:"bytes,1,opt,name=KeyId" json:"KeyId,omitempty"`
+	// Party is the index of the share
+	Party     int32                 `protobuf:"varint,2,opt,name=Party" json:"Party,omitempty"`
+	SessionId string                `protobuf:"bytes,3,opt,name=SessionId" json:"SessionId,omitempty"`
+	Round     int32                 `protobuf:"varint,4,opt,name=Round" json:"Round,omitempty"`
+	Messages  []*CLThresholdMessage `protobuf:"bytes,5,rep,name=Messages" json:"Messages,omitempty"`
+}
+
+func (m *CLThresholdRound) Reset()                    { *m = CLThresholdRound{} }
+func (m *CLThresholdRound) String() string            { return proto1.CompactTextString(m) }
+func (*CLThresholdRound) ProtoMessage()               {}
+func (*CLThresholdRound) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+func (m *CLThresholdRound) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *CLThresholdRound) GetParty() int32 {
+	if m != nil {
+		return m.Party
+	}
+	return 0
+}
+
+func (m *CLThresholdRound) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+func (m *CLThresholdRound) GetRound() int32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *CLThresholdRound) GetMessages() []*CLThresholdMessage {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+type CLThresholdMessages struct {
+	Messages []*CLThresholdMessage `protobuf:"bytes,1,rep,name=Messages" json:"Messages,omitempty"`
+}
+
+func (m *CLThresholdMessages) Reset()                    { *m = CLThresholdMessages{} }
+func (m *CLThresholdMessages) String() string            { return proto1.CompactTextString(m) }
+func (*CLThresholdMessages) ProtoMessage()               {}
+func (*CLThresholdMessages) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+func (m *CLThresholdMessages) GetMessages() []*CLThresholdMessage {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
 func init() {
 	proto1.RegisterType((*Message)(nil), "proto.Message")
 	proto1.RegisterType((*ServiceInfo)(nil), "proto.ServiceInfo")
@@ -2573,6 +3835,10 @@ func init() {
 	proto1.RegisterType((*Attribute)(nil), "proto.Attribute")
 	proto1.RegisterType((*IntAttribute)(nil), "proto.IntAttribute")
 	proto1.RegisterType((*StringAttribute)(nil), "proto.StringAttribute")
+	proto1.RegisterType((*DateAttribute)(nil), "proto.DateAttribute")
+	proto1.RegisterType((*BoolAttribute)(nil), "proto.BoolAttribute")
+	proto1.RegisterType((*EnumAttribute)(nil), "proto.EnumAttribute")
+	proto1.RegisterType((*HashedStringAttribute)(nil), "proto.HashedStringAttribute")
 	proto1.RegisterType((*CredAttribute)(nil), "proto.CredAttribute")
 	proto1.RegisterType((*CredStructure)(nil), "proto.CredStructure")
 	proto1.RegisterType((*Status)(nil), "proto.Status")
@@ -2602,156 +3868,250 @@ func init() {
 	proto1.RegisterType((*CSPaillierSecretKey)(nil), "proto.CSPaillierSecretKey")
 	proto1.RegisterType((*CSPaillierPubKey)(nil), "proto.CSPaillierPubKey")
 	proto1.RegisterType((*SessionKey)(nil), "proto.SessionKey")
+	proto1.RegisterType((*SessionAttr)(nil), "proto.SessionAttr")
+	proto1.RegisterType((*SessionCred)(nil), "proto.SessionCred")
+	proto1.RegisterType((*SessionInfo)(nil), "proto.SessionInfo")
 	proto1.RegisterType((*RegKey)(nil), "proto.RegKey")
+	proto1.RegisterType((*CLOrg)(nil), "proto.CLOrg")
 	proto1.RegisterType((*CLCredReq)(nil), "proto.CLCredReq")
 	proto1.RegisterType((*CLCredential)(nil), "proto.CLCredential")
 	proto1.RegisterType((*UpdateCLCredential)(nil), "proto.UpdateCLCredential")
 	proto1.RegisterType((*ProveCLCredential)(nil), "proto.ProveCLCredential")
+	proto1.RegisterType((*CLProofRequest)(nil), "proto.CLProofRequest")
+	proto1.RegisterType((*CLAttrEquality)(nil), "proto.CLAttrEquality")
+	proto1.RegisterType((*CLCredProof)(nil), "proto.CLCredProof")
+	proto1.RegisterType((*ProveCLCredentials)(nil), "proto.ProveCLCredentials")
+	proto1.RegisterType((*CLWitness)(nil), "proto.CLWitness")
+	proto1.RegisterType((*CLNonRevocationProof)(nil), "proto.CLNonRevocationProof")
+	proto1.RegisterType((*CLPredicateProof)(nil), "proto.CLPredicateProof")
+	proto1.RegisterType((*CLSetMembershipProof)(nil), "proto.CLSetMembershipProof")
+	proto1.RegisterType((*CLDomainPseudonymProof)(nil), "proto.CLDomainPseudonymProof")
+	proto1.RegisterType((*CLEscrow)(nil), "proto.CLEscrow")
+	proto1.RegisterType((*CLEscrowProof)(nil), "proto.CLEscrowProof")
+	proto1.RegisterType((*CLRevokeCredential)(nil), "proto.CLRevokeCredential")
+	proto1.RegisterType((*CLAccumulatorUpdate)(nil), "proto.CLAccumulatorUpdate")
+	proto1.RegisterType((*CLWitnessUpdatesRequest)(nil), "proto.CLWitnessUpdatesRequest")
+	proto1.RegisterType((*CLWitnessUpdates)(nil), "proto.CLWitnessUpdates")
+	proto1.RegisterType((*CLThresholdMessage)(nil), "proto.CLThresholdMessage")
+	proto1.RegisterType((*CLThresholdRound)(nil), "proto.CLThresholdRound")
+	proto1.RegisterType((*CLThresholdMessages)(nil), "proto.CLThresholdMessages")
 }
 
 func init() { proto1.RegisterFile("messages.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2249 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x58, 0xcd, 0x73, 0x1b, 0x49,
-	0x15, 0xd7, 0x8c, 0x3e, 0x1c, 0xbf, 0xc8, 0x8e, 0xd3, 0x76, 0xbc, 0x93, 0x38, 0x1f, 0xca, 0xd8,
-	0xc6, 0x0e, 0xcb, 0xda, 0x91, 0xb2, 0x55, 0x7c, 0x6c, 0xed, 0x52, 0x92, 0xa2, 0xb5, 0x8c, 0x37,
-	0x5a, 0x33, 0x4a, 0x52, 0x76, 0x2e, 0x62, 0x3c, 0x6a, 0xcb, 0x53, 0x48, 0x33, 0x62, 0x66, 0x94,
-	0x45, 0x07, 0x28, 0x8a, 0x02, 0xaa, 0xb8, 0x01, 0x17, 0x8e, 0x9c, 0xf8, 0x1b, 0x38, 0x53, 0x14,
-	0xff, 0x03, 0x55, 0xc0, 0x3f, 0xc2, 0x89, 0xea, 0xd7, 0xdd, 0xa3, 0x19, 0x69, 0x46, 0x12, 0x54,
-	0x71, 0xe2, 0x24, 0xbd, 0xf7, 0x7e, 0xef, 0xb3, 0x5f, 0xf7, 0xbc, 0x6e, 0x58, 0x1f, 0x50, 0xdf,
-	0x37, 0x7b, 0xd4, 0x3f, 0x1a, 0x7a, 0x6e, 0xe0, 0x92, 0x3c, 0xfe, 0x3c, 0xd8, 0xe9, 0xb9, 0x6e,
-	0xaf, 0x4f, 0x8f, 0x91, 0xba, 0x1a, 0x5d, 0x1f, 0xd3, 0xc1, 0x30, 0x18, 0x73, 0x8c, 0xfe, 0xcf,
-	0x3b, 0xb0, 0xf2, 0x8a, 0xab, 0x91, 0x03, 0x28, 0x5c, 0xd9, 0x3d, 0xdb, 0x09, 0xb4, 0x5c, 0x49,
-	0x39, 0xbc, 0x5d, 0x59, 0xe3, 0x98, 0xa3, 0x9a, 0xdd, 0x3b, 0x75, 0x82, 0x66, 0xc6, 0x10, 0x62,
-	0x52, 0x85, 0x0d, 0x6a, 0x75, 0x7a, 0x9e, 0x3b, 0x1a, 0x76, 0x68, 0x9f, 0x0e, 0xa8, 0x13, 0x68,
-	0x79, 0x54, 0xb9, 0x27, 0x54, 0x1a, 0xf5, 0x13, 0x26, 0x6d, 0x70, 0x61, 0x33, 0x63, 0xac, 0x53,
-	0x2b, 0xca, 0x61, 0xbe, 0xfc, 0xc0, 0x0c, 0x46, 0xbe, 0x56, 0x88, 0xf9, 0x6a, 0x23, 0x93, 0xf9,
-	0xe2, 0x62, 0xf2, 0x29, 0xac, 0x0f, 0x69, 0x97, 0x7a, 0x3e, 0x75, 0x3a, 0xd7, 0xb6, 0xe7, 0x07,
-	0xda, 0x0a, 0x2a, 0x6c, 0x09, 0x85, 0x73, 0x21, 0xfc, 0x9c, 0xc9, 0x9a, 0x19, 0x63, 0x6d, 0x18,
-	0x65, 0x10, 0x03, 0xee, 0x85, 0xea, 0x5d, 0x6a, 0xb9, 0x83, 0x81, 0x1d, 0x60, 0xbc, 0xb7, 0xd0,
-	0xca, 0xce, 0x94, 0x95, 0x97, 0x11, 0x48, 0x33, 0x63, 0x6c, 0x0d, 0x13, 0xf8, 0xe4, 0x04, 0x88,
-	0x6f, 0xdd, 0x38, 0xae, 0xe7, 0x75, 0x86, 0x9e, 0xeb, 0x5e, 0x77, 0xba, 0x66, 0x60, 0x6a, 0xab,
-	0x68, 0xf0, 0x03, 0x99, 0x07, 0x07, 0x9c, 0x33, 0xf9, 0x4b, 0x33, 0x30, 0x9b, 0x19, 0x63, 0xc3,
-	0x9f, 0xe2, 0x91, 0x77, 0x70, 0x3f, 0x6e, 0xc8, 0x33, 0x9d, 0xae, 0x3b, 0xe0, 0xf6, 0x00, 0xed,
-	0x3d, 0x4a, 0xb0, 0x67, 0x20, 0x4a, 0x58, 0xdd, 0xf6, 0x13, 0x25, 0xc4, 0x84, 0x87, 0xd2, 0x36,
-	0xb5, 0x12, 0xcc, 0xdf, 0x46, 0xf3, 0x4f, 0xe2, 0xe6, 0x1b, 0xf5, 0x59, 0x07, 0x9a, 0x30, 0xd3,
-	0xb0, 0xa6, 0x5d, 0x5c, 0xc1, 0xce, 0xd0, 0xa7, 0xa3, 0xae, 0xeb, 0x8c, 0x07, 0xfe, 0xd8, 0xef,
-	0x58, 0x66, 0xc7, 0xa2, 0x5e, 0x60, 0x5f, 0xdb, 0x96, 0x19, 0x50, 0xed, 0x0e, 0x7a, 0x28, 0xc9,
-	0x0a, 0x47, 0x90, 0xf5, 0x6a, 0x7d, 0x82, 0x6b, 0x66, 0x8c, 0xfb, 0x51, 0x33, 0x75, 0x33, 0x22,
-	0x24, 0x3f, 0x81, 0xaf, 0xc5, 0x7c, 0x38, 0xe3, 0x41, 0xa7, 0x47, 0x9d, 0x84, 0x84, 0x36, 0xd0,
-	0xdd, 0x61, 0x82, 0xbb, 0xd6, 0x78, 0x70, 0x42, 0x9d, 0xd9, 0xcc, 0x9e, 0x0e, 0x17, 0x81, 0xc8,
-	0x18, 0xf6, 0x62, 0xee, 0x6d, 0xdf, 0x1f, 0xd1, 0x04, 0xe7, 0x77, 0xd1, 0xf9, 0x41, 0x82, 0xf3,
-	0x53, 0xa6, 0x31, 0xeb, 0xbb, 0x34, 0x5c, 0x80, 0x21, 0xdf, 0x81, 0xb5, 0xae, 0x3b, 0xba, 0xea,
-	0xd3, 0x8e, 0xd8, 0x94, 0x04, 0x7d, 0x6c, 0x0a, 0x1f, 0x2f, 0x51, 0x16, 0x6e, 0xcd, 0x62, 0x57,
-	0xd2, 0x6c, 0x83, 0xfe, 0x14, 0xf6, 0x63, 0x61, 0x07, 0x9e, 0xe9, 0xf8, 0xd7, 0xd4, 0xeb, 0x58,
-	0x1e, 0xed, 0x52, 0x27, 0xb0, 0xcd, 0x3e, 0x8f, 0x7b, 0x13, 0x6d, 0x3e, 0x4b, 0x88, 0xfb, 0xb5,
-	0x50, 0xa9, 0x87, 0x1a, 0x22, 0x72, 0x7d, 0xb8, 0x10, 0x45, 0x6c, 0x78, 0x3c, 0xa7, 0x33, 0x3a,
-	0xd4, 0xd2, 0xb6, 0xd0, 0xb1, 0xbe, 0xa8, 0x39, 0x1a, 0xf5, 0x66, 0xc6, 0xd8, 0x49, 0x6d, 0x8f,
-	0x86, 0x45, 0x7e, 0xa1, 0xc0, 0xb3, 0xe5, 0x3a, 0x84, 0xb9, 0xbd, 0x87, 0x6e, 0xbf, 0xbe, 0x6c,
-	0x93, 0xa0, 0xfb, 0xdd, 0x85, 0x6d, 0xd2, 0xb0, 0xc8, 0xcf, 0x14, 0x38, 0x58, 0xa6, 0x53, 0x58,
-	0x10, 0xdb, 0xa9, 0x45, 0x4f, 0x6a, 0x04, 0x8c, 0x41, 0x5f, 0xd4, 0x2e, 0x0d, 0x8b, 0xfc, 0x52,
-	0x81, 0xc3, 0xa5, 0x56, 0x9d, 0xc5, 0xf0, 0x01, 0xc6, 0xf0, 0xe1, 0xd2, 0x0b, 0x8f, 0x51, 0xec,
-	0x2d, 0x5e, 0xfa, 0x86, 0x45, 0x5e, 0x00, 0xb4, 0xa9, 0xef, 0xdb, 0xae, 0x73, 0x46, 0xc7, 0xda,
-	0x63, 0x74, 0x74, 0x57, 0x9e, 0x33, 0xa1, 0xa0, 0x99, 0x31, 0x22, 0x30, 0xf2, 0x1c, 0x56, 0xeb,
-	0x5f, 0x30, 0x53, 0x06, 0xfd, 0x91, 0xf6, 0x04, 0x75, 0x36, 0x84, 0x4e, 0xc8, 0x6f, 0x66, 0x8c,
-	0x09, 0x88, 0x7c, 0x1b, 0x8a, 0x9c, 0xe0, 0xce, 0xb5, 0x52, 0x6c, 0x7b, 0x44, 0x45, 0x6c, 0x7b,
-	0x44, 0x69, 0xf2, 0x0a, 0xb6, 0x46, 0xc3, 0x2e, 0xeb, 0x44, 0xab, 0x1f, 0x29, 0x8e, 0xf6, 0x14,
-	0x4d, 0xdc, 0x17, 0x26, 0xde, 0x20, 0x64, 0xca, 0x10, 0xe1, 0x8a, 0xf5, 0x7e, 0xc4, 0xdc, 0xf7,
-	0x60, 0x73, 0xe8, 0xb9, 0xef, 0xa7, 0xad, 0xe9, 0x68, 0x4d, 0x93, 0x25, 0x66, 0x88, 0x29, 0x63,
-	0x77, 0x51, 0x2d, 0x66, 0xeb, 0x00, 0x0a, 0x06, 0xed, 0xb1, 0xc2, 0xed, 0xc6, 0xbe, 0x8b, 0x9c,
-	0xc9, 0xbe, 0x8b, 0xfc, 0x1f, 0x79, 0x00, 0xb7, 0xac, 0xbe, 0x4d, 0x9d, 0xe0, 0xb4, 0xab, 0x3d,
-	0x2c, 0x29, 0x87, 0x79, 0x23, 0xa4, 0x6b, 0xab, 0xb0, 0x62, 0xb9, 0x4e, 0x40, 0x9d, 0x40, 0xef,
-	0xc0, 0xed, 0x36, 0xf5, 0xde, 0xdb, 0x16, 0x3d, 0x75, 0xae, 0x5d, 0x42, 0x20, 0xe7, 0x98, 0x03,
-	0xaa, 0x29, 0x25, 0xe5, 0x70, 0xd5, 0xc0, 0xff, 0xa4, 0x04, 0xb7, 0xbb, 0xd4, 0xb7, 0x3c, 0x7b,
-	0x18, 0xd8, 0xae, 0xa3, 0xa9, 0x28, 0x8a, 0xb2, 0x98, 0x2f, 0x16, 0xa9, 0xdd, 0xa5, 0x9e, 0x96,
-	0x45, 0x71, 0x48, 0xeb, 0xe7, 0xb0, 0x5e, 0xb5, 0x2c, 0x3a, 0x0c, 0xcc, 0xab, 0x3e, 0x65, 0x89,
-	0x10, 0x0d, 0x56, 0x5c, 0xaf, 0xd7, 0x9a, 0xb8, 0x91, 0x24, 0xd9, 0x83, 0x35, 0x8f, 0xbe, 0xa7,
-	0x66, 0x9f, 0x76, 0xab, 0x41, 0xe0, 0xf9, 0x9a, 0x5a, 0xca, 0x1e, 0xae, 0x1a, 0x71, 0xa6, 0xfe,
-	0x19, 0xdc, 0x89, 0x5b, 0xf4, 0xc9, 0x87, 0x90, 0x67, 0x85, 0xf5, 0x35, 0xa5, 0x94, 0x8d, 0x4c,
-	0x19, 0x71, 0x98, 0xc1, 0x31, 0xfa, 0x19, 0xac, 0x32, 0x43, 0xf6, 0xd5, 0x28, 0xa0, 0x64, 0x0b,
-	0xf2, 0xb6, 0xd3, 0xa5, 0x3f, 0xc6, 0x50, 0xf2, 0x06, 0x27, 0xc2, 0x32, 0xa8, 0x91, 0x32, 0x6c,
-	0x41, 0xfe, 0x87, 0x8e, 0xfb, 0x95, 0x83, 0xc3, 0xcf, 0x2d, 0x83, 0x13, 0xfa, 0xc7, 0x50, 0x3c,
-	0x75, 0x82, 0x89, 0xbd, 0x3d, 0xc8, 0x99, 0x41, 0xe0, 0xa1, 0xb9, 0x49, 0x8b, 0x86, 0x72, 0x03,
-	0xa5, 0xfa, 0x37, 0xe1, 0x4e, 0x3b, 0xf0, 0x6c, 0xa7, 0x37, 0xab, 0xa8, 0xce, 0x55, 0xfc, 0xb9,
-	0x02, 0x6b, 0x2c, 0x97, 0x89, 0xde, 0xb7, 0x00, 0xfc, 0xd0, 0x94, 0x70, 0xbb, 0x1d, 0x0e, 0x4b,
-	0x31, 0x1f, 0x6c, 0x4b, 0x4d, 0xb0, 0xe4, 0x18, 0x56, 0x6c, 0x1e, 0xba, 0x70, 0x2a, 0xf7, 0x46,
-	0x34, 0xa1, 0x66, 0xc6, 0x90, 0xa8, 0x5a, 0x01, 0x72, 0xc1, 0x78, 0x48, 0xf5, 0xdf, 0x8b, 0x20,
-	0xda, 0x81, 0x37, 0xb2, 0x82, 0x91, 0x47, 0xc9, 0x36, 0x14, 0x9c, 0x33, 0x2c, 0x0e, 0x2f, 0xa3,
-	0xa0, 0xc8, 0x63, 0x00, 0xa7, 0x8e, 0x83, 0x51, 0x40, 0xbb, 0xe8, 0x25, 0x6f, 0x44, 0x38, 0xac,
-	0x15, 0x9c, 0xa6, 0xdd, 0xed, 0x52, 0x07, 0xfb, 0x26, 0x6f, 0x48, 0x92, 0x7c, 0x0c, 0x60, 0xca,
-	0x18, 0x7c, 0x2d, 0x87, 0xcb, 0x2a, 0x47, 0xba, 0x58, 0x01, 0x8c, 0x08, 0x4e, 0xd7, 0xa1, 0xc0,
-	0x07, 0x44, 0x66, 0xb9, 0x3d, 0xb2, 0x2c, 0xea, 0xfb, 0x18, 0xd2, 0x2d, 0x43, 0x92, 0xba, 0x06,
-	0x05, 0xfe, 0x55, 0x24, 0xeb, 0xa0, 0x5e, 0x94, 0x51, 0x5c, 0x34, 0xd4, 0x8b, 0xb2, 0x7e, 0x04,
-	0xc5, 0xe8, 0x57, 0x73, 0x5a, 0x8e, 0x74, 0x05, 0xb3, 0x60, 0x74, 0x45, 0x7f, 0x04, 0x6b, 0xb1,
-	0xe9, 0x92, 0x14, 0x41, 0x69, 0x0a, 0xbc, 0xd2, 0xd4, 0x2b, 0xb0, 0x95, 0x34, 0x36, 0x32, 0xd4,
-	0x85, 0x44, 0x5d, 0x30, 0xca, 0x10, 0x36, 0x15, 0x43, 0xff, 0x06, 0xac, 0xc7, 0x47, 0xe3, 0x59,
-	0xf4, 0xa5, 0x44, 0x5f, 0xea, 0x3a, 0xe4, 0xce, 0x4d, 0xdb, 0x63, 0xdc, 0xaa, 0xc4, 0x54, 0x19,
-	0x55, 0x93, 0x98, 0x9a, 0x5e, 0x83, 0xed, 0xe4, 0xd9, 0x70, 0xd6, 0x72, 0x55, 0x6a, 0x09, 0x1b,
-	0x59, 0x69, 0xa3, 0x04, 0x1b, 0xd3, 0xf3, 0x2a, 0x43, 0xbc, 0x93, 0xda, 0xef, 0x74, 0x0f, 0xe0,
-	0x73, 0xdb, 0x0c, 0xda, 0x37, 0xe6, 0xc0, 0xf6, 0xc8, 0x21, 0xdc, 0x99, 0x72, 0x26, 0x90, 0xd3,
-	0x6c, 0xf2, 0x10, 0x56, 0xeb, 0x37, 0x66, 0xbf, 0x4f, 0x9d, 0x1e, 0x15, 0xde, 0x27, 0x0c, 0x26,
-	0x0d, 0x1d, 0x6a, 0xd9, 0x52, 0x96, 0x49, 0x43, 0x86, 0x3e, 0x86, 0xbb, 0x13, 0x9f, 0xd5, 0xbe,
-	0xef, 0xb6, 0x68, 0xef, 0x7f, 0xe7, 0x7a, 0x35, 0xea, 0xfa, 0xd7, 0x0a, 0x68, 0x69, 0x23, 0x31,
-	0xd9, 0x95, 0x75, 0x4d, 0xbb, 0xee, 0xb0, 0x72, 0xef, 0xca, 0x72, 0xa7, 0x83, 0xaa, 0x0c, 0xc4,
-	0x57, 0x21, 0x1d, 0x54, 0xd3, 0xff, 0xa4, 0xc0, 0xd3, 0x85, 0x83, 0x4a, 0x52, 0x2f, 0x57, 0xcb,
-	0xb2, 0x97, 0xab, 0x48, 0xd7, 0xca, 0x62, 0xc5, 0xd5, 0x9a, 0xec, 0xf5, 0x9c, 0xec, 0x75, 0xc4,
-	0x57, 0xf0, 0x12, 0xc7, 0xf0, 0x48, 0xd7, 0x2a, 0x78, 0x37, 0x63, 0xf8, 0x0a, 0x6f, 0xe3, 0x15,
-	0xd1, 0xc6, 0x8c, 0x6a, 0xe3, 0x0d, 0xaa, 0x68, 0x28, 0x6d, 0x76, 0x3a, 0x88, 0x6f, 0xd6, 0x2a,
-	0x9e, 0xa7, 0x82, 0xd2, 0xff, 0xa2, 0xc2, 0xee, 0x12, 0x23, 0x16, 0xd9, 0x0f, 0x63, 0x4f, 0xad,
-	0x03, 0x4b, 0x69, 0x3f, 0x4c, 0x29, 0x1d, 0x56, 0x45, 0x98, 0xc8, 0x34, 0x1d, 0x56, 0x43, 0x98,
-	0x28, 0xc0, 0x1c, 0xa7, 0x15, 0x74, 0x5a, 0x99, 0x7b, 0xb9, 0xc5, 0x72, 0xed, 0x87, 0xe5, 0x9a,
-	0xe3, 0xf4, 0xbf, 0xab, 0xa2, 0x0b, 0xf7, 0x53, 0xc7, 0x63, 0xf6, 0x65, 0xae, 0xf5, 0xd9, 0x37,
-	0xad, 0x2b, 0x0f, 0x88, 0x90, 0x8e, 0xc8, 0xe4, 0x71, 0x11, 0xd2, 0x3c, 0x90, 0x6c, 0x2c, 0x90,
-	0x9c, 0x08, 0x44, 0xff, 0x83, 0x02, 0x3b, 0x73, 0x06, 0x72, 0x52, 0x9e, 0xf2, 0x99, 0x9a, 0xf1,
-	0x24, 0x94, 0xf2, 0x54, 0x28, 0x0b, 0x55, 0xe6, 0x47, 0xf8, 0x2b, 0x05, 0x4a, 0x8b, 0xc6, 0x66,
-	0xb2, 0x01, 0xd9, 0x8b, 0xb2, 0xdc, 0x12, 0xec, 0x2f, 0xe7, 0xc8, 0x03, 0x9e, 0xfd, 0x45, 0x4e,
-	0x45, 0x6e, 0x0b, 0xf6, 0x97, 0x73, 0xe4, 0xc6, 0x60, 0x7f, 0xf9, 0xc1, 0x99, 0x8f, 0x1d, 0x9c,
-	0x05, 0x79, 0x70, 0xfe, 0x4e, 0x05, 0x7d, 0xf1, 0xfc, 0x4e, 0x0e, 0x26, 0xa1, 0xa4, 0x66, 0x8e,
-	0x11, 0x1e, 0x4c, 0x22, 0x9c, 0x07, 0xac, 0x20, 0xb0, 0xb2, 0xa0, 0xcb, 0x31, 0x9f, 0x83, 0x49,
-	0x3e, 0xf3, 0x80, 0x15, 0x7e, 0x60, 0xe5, 0x97, 0x39, 0xb0, 0x0a, 0x0b, 0x0e, 0xac, 0x1f, 0xc0,
-	0xf6, 0xcc, 0x7d, 0x02, 0x47, 0xc9, 0x79, 0xdf, 0x31, 0x36, 0x92, 0x35, 0x4d, 0xff, 0x46, 0xac,
-	0x05, 0xfe, 0x67, 0x5b, 0xe2, 0x5d, 0xb5, 0x3f, 0xbc, 0x31, 0xc5, 0x7a, 0x08, 0x4a, 0xff, 0x8d,
-	0x02, 0x5a, 0xb2, 0x8b, 0x46, 0x9d, 0x27, 0xa2, 0x2c, 0x93, 0xc8, 0xfc, 0xe3, 0xf9, 0x3f, 0x0b,
-	0xe9, 0x5f, 0x4a, 0x3c, 0xeb, 0xc8, 0x48, 0xbf, 0x07, 0x6b, 0xed, 0x81, 0xd9, 0xef, 0x57, 0x5f,
-	0xbb, 0x27, 0xe6, 0x60, 0x20, 0x3f, 0x58, 0x71, 0x66, 0x88, 0xaa, 0x49, 0x94, 0x1a, 0x41, 0x49,
-	0x26, 0xdb, 0xd3, 0xa1, 0x19, 0x1e, 0x56, 0x48, 0xe3, 0x7e, 0x97, 0xb2, 0x9c, 0xd8, 0xef, 0x52,
-	0xf6, 0x11, 0xa8, 0xaf, 0xcb, 0x62, 0x79, 0x1f, 0xa5, 0x5d, 0xfa, 0xb0, 0x82, 0x86, 0xfa, 0xba,
-	0x8c, 0x70, 0x79, 0x9c, 0x2d, 0x84, 0x57, 0xf4, 0x7f, 0xa8, 0xf1, 0xf5, 0x98, 0x24, 0xdf, 0xa8,
-	0x93, 0x4f, 0x92, 0xd2, 0x4f, 0x2d, 0xfb, 0x54, 0x55, 0x3e, 0x49, 0xaa, 0xca, 0x02, 0xe5, 0x30,
-	0xe9, 0xf2, 0x54, 0xb1, 0xd2, 0x4f, 0x9d, 0x6a, 0x44, 0x25, 0x56, 0xc3, 0x39, 0x07, 0x95, 0x54,
-	0x39, 0x8e, 0x94, 0xf6, 0xc9, 0xdc, 0x5a, 0x35, 0xea, 0x58, 0xdc, 0xe3, 0x48, 0x71, 0x97, 0x50,
-	0xa8, 0xe8, 0x7f, 0x55, 0xe2, 0xa7, 0x4c, 0xca, 0xa3, 0x8b, 0x06, 0x2b, 0x5f, 0xc6, 0xef, 0x5d,
-	0x82, 0x14, 0xc3, 0x81, 0x3a, 0x35, 0xe8, 0x66, 0xc3, 0x8f, 0x3f, 0x81, 0x5c, 0x6b, 0x3c, 0xa8,
-	0x8a, 0xae, 0xc1, 0xff, 0x82, 0x57, 0x13, 0x27, 0x1f, 0xfe, 0x27, 0x9f, 0x02, 0x44, 0x2e, 0xdc,
-	0xe9, 0xed, 0x31, 0x01, 0x19, 0x11, 0x05, 0xfd, 0x8f, 0x2a, 0xec, 0x2d, 0xf3, 0xd2, 0x30, 0x27,
-	0x93, 0xfd, 0x30, 0x93, 0x45, 0xa3, 0x82, 0x48, 0x70, 0xee, 0xc7, 0xfd, 0x59, 0x24, 0xef, 0x54,
-	0x20, 0x2f, 0xc7, 0xb3, 0x48, 0x39, 0xe6, 0x42, 0x6b, 0xe4, 0xbb, 0x09, 0x55, 0x7a, 0x32, 0xb7,
-	0x4a, 0x8d, 0x7a, 0xac, 0x4e, 0x7f, 0x57, 0x61, 0xb3, 0xde, 0x3e, 0x37, 0xed, 0x7e, 0xdf, 0xa6,
-	0x5e, 0x9b, 0x5a, 0x1e, 0x0d, 0xd8, 0x95, 0xbf, 0x08, 0x4a, 0x4b, 0x1e, 0x9f, 0x2d, 0x46, 0x9d,
-	0xc8, 0xe3, 0xf3, 0x44, 0x2c, 0x71, 0x76, 0x6a, 0x89, 0x63, 0xf3, 0xdd, 0xc5, 0x0b, 0x39, 0xdf,
-	0x5d, 0xbc, 0x60, 0xb7, 0xdd, 0x97, 0x5f, 0xb8, 0xbd, 0x73, 0xf1, 0x2d, 0xe3, 0x84, 0xe4, 0x9e,
-	0x88, 0x19, 0x85, 0x13, 0x92, 0xfb, 0x7d, 0x31, 0xab, 0x70, 0x82, 0x3c, 0x87, 0xcd, 0xb7, 0xd4,
-	0xb3, 0xaf, 0x6d, 0x76, 0xff, 0x6e, 0x38, 0xfc, 0x79, 0xbf, 0x85, 0xc3, 0x4b, 0xd1, 0x48, 0x12,
-	0x91, 0x0a, 0x6c, 0xcd, 0xb2, 0x4f, 0xca, 0xf8, 0xd2, 0x5d, 0x34, 0x12, 0x65, 0xc9, 0x3a, 0xcd,
-	0x32, 0x3e, 0x5f, 0x27, 0xea, 0x34, 0xcb, 0xac, 0x32, 0x67, 0x5a, 0x11, 0xef, 0x9b, 0xca, 0x19,
-	0xcb, 0xfc, 0xac, 0xac, 0xad, 0x21, 0xa9, 0x9e, 0x95, 0xf5, 0xbf, 0xa9, 0xb0, 0x31, 0xa9, 0xee,
-	0xf9, 0xe8, 0x6a, 0x89, 0xd2, 0x5e, 0x86, 0xa5, 0xbd, 0xc4, 0xd2, 0x5e, 0x86, 0xa5, 0xbd, 0xc4,
-	0xd2, 0x5e, 0x86, 0xa5, 0xbd, 0xfc, 0x7f, 0x2e, 0xad, 0x1e, 0x7d, 0xf9, 0x63, 0xb9, 0xbd, 0x37,
-	0xfb, 0x23, 0xb9, 0x87, 0x39, 0xa1, 0x97, 0xe4, 0x98, 0x1b, 0x19, 0x78, 0x95, 0xd8, 0xc0, 0xfb,
-	0x67, 0x35, 0xf2, 0x16, 0xc8, 0x06, 0xb2, 0xd6, 0x78, 0x20, 0xc7, 0xb8, 0xd6, 0x78, 0x40, 0x1e,
-	0x03, 0xe0, 0xeb, 0xc3, 0xe4, 0x09, 0xa9, 0x68, 0x44, 0x38, 0xe4, 0x08, 0x48, 0x3d, 0xbc, 0x8d,
-	0xfb, 0x5f, 0x5e, 0x73, 0x1c, 0xbf, 0x5e, 0x26, 0x48, 0xc8, 0x47, 0x70, 0xab, 0x35, 0x1e, 0xe0,
-	0xd4, 0x26, 0x4e, 0x02, 0xf9, 0x5a, 0x39, 0xb9, 0x7e, 0x1a, 0x21, 0x84, 0x95, 0xe0, 0x8d, 0x9c,
-	0x07, 0xdf, 0x90, 0xe7, 0x50, 0x78, 0xc3, 0x55, 0x0b, 0xb1, 0xe7, 0xbe, 0x99, 0x9b, 0xab, 0x21,
-	0x70, 0xe4, 0x15, 0x68, 0xb3, 0x41, 0xa0, 0xc8, 0xd7, 0x56, 0xf0, 0x1d, 0x24, 0xc1, 0x7d, 0xaa,
-	0x0a, 0xab, 0x72, 0xcb, 0x75, 0x2c, 0x2a, 0x3b, 0x08, 0x09, 0xdd, 0x89, 0x3f, 0x8e, 0xce, 0x4e,
-	0x5e, 0x0d, 0xd9, 0xdf, 0x0d, 0x56, 0xe1, 0xb7, 0xe5, 0x70, 0x08, 0x7e, 0x5b, 0x2e, 0xb3, 0xa4,
-	0xaa, 0xd1, 0x7a, 0xcc, 0x49, 0x8a, 0xe3, 0xf4, 0x2b, 0x20, 0xb3, 0xcf, 0xa5, 0x09, 0x6b, 0x17,
-	0x46, 0xab, 0x46, 0xa2, 0x65, 0xb3, 0x4f, 0x8b, 0x7e, 0x15, 0x59, 0x54, 0xbe, 0x58, 0x71, 0xa6,
-	0xfe, 0x5b, 0x15, 0xee, 0xce, 0xbc, 0xa2, 0x4e, 0x65, 0x76, 0x04, 0x79, 0x1e, 0xb8, 0xba, 0x20,
-	0x70, 0x0e, 0x9b, 0xea, 0xa5, 0xec, 0x92, 0xbd, 0x94, 0x4b, 0xed, 0xa5, 0x23, 0x20, 0x86, 0x78,
-	0xcc, 0x8c, 0xd8, 0xcd, 0x97, 0xb2, 0x87, 0x79, 0x23, 0x41, 0x42, 0x3e, 0x83, 0x07, 0x92, 0x9b,
-	0xe0, 0xa7, 0x80, 0x7a, 0x73, 0x10, 0x57, 0x05, 0xcc, 0xef, 0xc5, 0xbf, 0x03, 0x00, 0x00, 0xff,
-	0xff, 0x1e, 0x90, 0xe0, 0x69, 0xfb, 0x1d, 0x00, 0x00,
+	// 3407 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x5a, 0xcb, 0x73, 0xdb, 0xd6,
+	0xb9, 0x17, 0xc0, 0x87, 0xa4, 0x4f, 0x94, 0x2c, 0x1f, 0xc9, 0x0a, 0xfc, 0x48, 0x4c, 0x43, 0x76,
+	0x2c, 0xe7, 0x61, 0x87, 0xb4, 0x73, 0x73, 0x6f, 0x32, 0xc9, 0xbd, 0x24, 0xcd, 0x88, 0x8e, 0x64,
+	0x49, 0x39, 0xf2, 0x4b, 0xde, 0xe8, 0x42, 0xe0, 0x11, 0x85, 0x09, 0x09, 0x30, 0x00, 0xe8, 0x84,
+	0x8b, 0x7b, 0xa7, 0x8b, 0xb6, 0x33, 0xdd, 0xb5, 0x5d, 0x64, 0xd1, 0x4d, 0x57, 0x9d, 0x69, 0x3b,
+	0xed, 0xba, 0xeb, 0x4e, 0xa7, 0x9b, 0xfe, 0x05, 0x9d, 0x69, 0xff, 0x82, 0xfe, 0x09, 0x5d, 0x75,
+	0xce, 0x0b, 0x38, 0x00, 0x01, 0x52, 0xee, 0x4c, 0x57, 0x5d, 0x11, 0xdf, 0x77, 0x7e, 0xdf, 0xf3,
+	0xbc, 0xbe, 0x73, 0x0e, 0x61, 0x65, 0x40, 0x82, 0xc0, 0xea, 0x91, 0xe0, 0xee, 0xd0, 0xf7, 0x42,
+	0x0f, 0x95, 0xd8, 0xcf, 0x95, 0xab, 0x3d, 0xcf, 0xeb, 0xf5, 0xc9, 0x3d, 0x46, 0x9d, 0x8c, 0x4e,
+	0xef, 0x91, 0xc1, 0x30, 0x1c, 0x73, 0x8c, 0xf9, 0xdd, 0x45, 0x98, 0x7f, 0xcc, 0xc5, 0xd0, 0x6d,
+	0x28, 0x9f, 0x38, 0x3d, 0xc7, 0x0d, 0x8d, 0x62, 0x55, 0xdb, 0x5a, 0xaa, 0x2f, 0x73, 0xcc, 0xdd,
+	0xa6, 0xd3, 0x7b, 0xe4, 0x86, 0x9d, 0x39, 0x2c, 0x9a, 0x51, 0x03, 0x56, 0x89, 0x7d, 0xdc, 0xf3,
+	0xbd, 0xd1, 0xf0, 0x98, 0xf4, 0xc9, 0x80, 0xb8, 0xa1, 0x51, 0x62, 0x22, 0x97, 0x84, 0x48, 0xbb,
+	0xb5, 0x4d, 0x5b, 0xdb, 0xbc, 0xb1, 0x33, 0x87, 0x57, 0x88, 0xad, 0x72, 0xa8, 0xad, 0x20, 0xb4,
+	0xc2, 0x51, 0x60, 0x94, 0x13, 0xb6, 0x0e, 0x19, 0x93, 0xda, 0xe2, 0xcd, 0xe8, 0x53, 0x58, 0x19,
+	0x92, 0x2e, 0xf1, 0x03, 0xe2, 0x1e, 0x9f, 0x3a, 0x7e, 0x10, 0x1a, 0xf3, 0x4c, 0x60, 0x5d, 0x08,
+	0x1c, 0x88, 0xc6, 0xcf, 0x69, 0x5b, 0x67, 0x0e, 0x2f, 0x0f, 0x55, 0x06, 0xc2, 0x70, 0x29, 0x12,
+	0xef, 0x12, 0xdb, 0x1b, 0x0c, 0x9c, 0x90, 0xf9, 0xbb, 0xc0, 0xb4, 0x5c, 0x4d, 0x69, 0x79, 0xa8,
+	0x40, 0x3a, 0x73, 0x78, 0x7d, 0x98, 0xc1, 0x47, 0xdb, 0x80, 0x02, 0xfb, 0xcc, 0xf5, 0x7c, 0xff,
+	0x78, 0xe8, 0x7b, 0xde, 0xe9, 0x71, 0xd7, 0x0a, 0x2d, 0x63, 0x91, 0x29, 0x7c, 0x43, 0xc6, 0xc1,
+	0x01, 0x07, 0xb4, 0xfd, 0xa1, 0x15, 0x5a, 0x9d, 0x39, 0xbc, 0x1a, 0xa4, 0x78, 0xe8, 0x25, 0x5c,
+	0x4e, 0x2a, 0xf2, 0x2d, 0xb7, 0xeb, 0x0d, 0xb8, 0x3e, 0x60, 0xfa, 0xde, 0xcc, 0xd0, 0x87, 0x19,
+	0x4a, 0x68, 0xdd, 0x08, 0x32, 0x5b, 0x90, 0x05, 0xd7, 0xa4, 0x6e, 0x62, 0x67, 0xa8, 0x5f, 0x62,
+	0xea, 0xaf, 0x27, 0xd5, 0xb7, 0x5b, 0x93, 0x06, 0x0c, 0xa1, 0xa6, 0x6d, 0xa7, 0x4d, 0x9c, 0xc0,
+	0xd5, 0x61, 0x40, 0x46, 0x5d, 0xcf, 0x1d, 0x0f, 0x82, 0x71, 0x70, 0x6c, 0x5b, 0xc7, 0x36, 0xf1,
+	0x43, 0xe7, 0xd4, 0xb1, 0xad, 0x90, 0x18, 0x17, 0x98, 0x85, 0xaa, 0xcc, 0xb0, 0x82, 0x6c, 0x35,
+	0x5a, 0x31, 0xae, 0x33, 0x87, 0x2f, 0xab, 0x6a, 0x5a, 0x96, 0xd2, 0x88, 0xfe, 0x0f, 0xde, 0x4e,
+	0xd8, 0x70, 0xc7, 0x83, 0xe3, 0x1e, 0x71, 0x33, 0x02, 0x5a, 0x65, 0xe6, 0xb6, 0x32, 0xcc, 0xed,
+	0x8d, 0x07, 0xdb, 0xc4, 0x9d, 0x8c, 0xec, 0xc6, 0x70, 0x16, 0x08, 0x8d, 0xe1, 0x66, 0xc2, 0xbc,
+	0x13, 0x04, 0x23, 0x92, 0x61, 0xfc, 0x22, 0x33, 0x7e, 0x3b, 0xc3, 0xf8, 0x23, 0x2a, 0x31, 0x69,
+	0xbb, 0x3a, 0x9c, 0x81, 0x41, 0x1f, 0xc3, 0x72, 0xd7, 0x1b, 0x9d, 0xf4, 0xc9, 0xb1, 0x98, 0x94,
+	0x88, 0xd9, 0x58, 0x13, 0x36, 0x1e, 0xb2, 0xb6, 0x68, 0x6a, 0x56, 0xba, 0x92, 0xa6, 0x13, 0xf4,
+	0xff, 0xe1, 0x56, 0xc2, 0xed, 0xd0, 0xb7, 0xdc, 0xe0, 0x94, 0xf8, 0xc7, 0xb6, 0x4f, 0xba, 0xc4,
+	0x0d, 0x1d, 0xab, 0xcf, 0xfd, 0x5e, 0x63, 0x3a, 0xef, 0x64, 0xf8, 0xfd, 0x44, 0x88, 0xb4, 0x22,
+	0x09, 0xe1, 0xb9, 0x39, 0x9c, 0x89, 0x42, 0x0e, 0xbc, 0x35, 0x65, 0x64, 0x1c, 0x13, 0xdb, 0x58,
+	0x67, 0x86, 0xcd, 0x59, 0x83, 0xa3, 0xdd, 0xea, 0xcc, 0xe1, 0xab, 0xb9, 0xc3, 0xa3, 0x6d, 0xa3,
+	0xef, 0x6b, 0x70, 0xe7, 0x7c, 0x23, 0x84, 0x9a, 0xbd, 0xc4, 0xcc, 0xbe, 0x73, 0xde, 0x41, 0xc2,
+	0xcc, 0x6f, 0xce, 0x1c, 0x26, 0x6d, 0x1b, 0x7d, 0x4f, 0x83, 0xdb, 0xe7, 0x19, 0x29, 0xd4, 0x89,
+	0x8d, 0xdc, 0xa4, 0x67, 0x0d, 0x84, 0x76, 0x2b, 0x9d, 0xf4, 0x4c, 0x94, 0x8d, 0x7e, 0xa0, 0xc1,
+	0xd6, 0xb9, 0x7a, 0x9d, 0xfa, 0xf0, 0x06, 0xf3, 0xe1, 0xdd, 0x73, 0x77, 0x3c, 0xf3, 0xe2, 0xe6,
+	0xec, 0xae, 0x6f, 0xdb, 0xe8, 0x3e, 0xc0, 0x21, 0x09, 0x02, 0xc7, 0x73, 0x77, 0xc8, 0xd8, 0x78,
+	0x8b, 0x19, 0xba, 0x28, 0xd7, 0x99, 0xa8, 0xa1, 0x33, 0x87, 0x15, 0x18, 0xfa, 0x00, 0x16, 0x5b,
+	0xbb, 0x54, 0x15, 0x26, 0x5f, 0x1b, 0xd7, 0x99, 0xcc, 0xaa, 0x90, 0x89, 0xf8, 0x9d, 0x39, 0x1c,
+	0x83, 0xd0, 0x7f, 0x41, 0xa5, 0xb5, 0x1b, 0x1b, 0x37, 0xaa, 0x89, 0xe9, 0xa1, 0x36, 0xd1, 0xe9,
+	0xa1, 0xd2, 0xe8, 0x31, 0xac, 0x8f, 0x86, 0x5d, 0x3a, 0x12, 0xed, 0xbe, 0x92, 0x1c, 0xe3, 0x06,
+	0x53, 0x71, 0x59, 0xa8, 0x78, 0xca, 0x20, 0x29, 0x45, 0x88, 0x0b, 0xb6, 0xfa, 0x8a, 0xba, 0x2f,
+	0x60, 0x6d, 0xe8, 0x7b, 0xaf, 0xd2, 0xda, 0x4c, 0xa6, 0xcd, 0x90, 0x29, 0xa6, 0x88, 0x94, 0xb2,
+	0x8b, 0x4c, 0x2c, 0xa1, 0xeb, 0x36, 0x94, 0x31, 0xe9, 0xd1, 0xc4, 0x6d, 0x26, 0xf6, 0x45, 0xce,
+	0xa4, 0xfb, 0x22, 0xff, 0xa2, 0x31, 0x64, 0x18, 0x0d, 0x8c, 0x9b, 0x89, 0x18, 0x26, 0xac, 0xd2,
+	0xad, 0x15, 0x4d, 0x98, 0x0d, 0xe8, 0x96, 0x6e, 0xf7, 0xe5, 0x70, 0x25, 0x5f, 0x8f, 0x48, 0x10,
+	0x1a, 0xb7, 0x12, 0x5b, 0x7a, 0x6b, 0x97, 0x0f, 0x39, 0xde, 0x48, 0xb7, 0x74, 0xbb, 0xaf, 0x72,
+	0xd0, 0x2d, 0x28, 0xdb, 0xfd, 0x63, 0xcf, 0xef, 0x19, 0x6f, 0x33, 0xc1, 0x4a, 0x24, 0xb8, 0xef,
+	0xf7, 0x3a, 0x73, 0xb8, 0x64, 0xf7, 0xf7, 0xfd, 0x1e, 0xba, 0x02, 0x0b, 0x76, 0xdf, 0x21, 0x6e,
+	0xf8, 0xa8, 0x6b, 0x5c, 0xab, 0x6a, 0x5b, 0x25, 0x1c, 0xd1, 0xcd, 0x45, 0x98, 0xb7, 0x3d, 0x37,
+	0x24, 0x6e, 0x68, 0x1e, 0xc3, 0xd2, 0x21, 0xf1, 0x5f, 0x39, 0x36, 0x79, 0xe4, 0x9e, 0x7a, 0x08,
+	0x41, 0xd1, 0xb5, 0x06, 0xc4, 0xd0, 0xaa, 0xda, 0xd6, 0x22, 0x66, 0xdf, 0xa8, 0x0a, 0x4b, 0x5d,
+	0x12, 0xd8, 0xbe, 0x33, 0x0c, 0x1d, 0xcf, 0x35, 0x74, 0xd6, 0xa4, 0xb2, 0xa8, 0x2d, 0x1a, 0xab,
+	0xd3, 0x25, 0xbe, 0x51, 0x60, 0xcd, 0x11, 0x6d, 0x9e, 0xc1, 0x4a, 0xc3, 0xb6, 0xc9, 0x30, 0xb4,
+	0x4e, 0xfa, 0x84, 0xa6, 0x02, 0x19, 0x30, 0xef, 0xf9, 0xbd, 0xbd, 0xd8, 0x8c, 0x24, 0xd1, 0x4d,
+	0x58, 0xf6, 0xc9, 0x2b, 0x62, 0xf5, 0x49, 0xb7, 0x11, 0x86, 0x7e, 0x60, 0xe8, 0xd5, 0xc2, 0xd6,
+	0x22, 0x4e, 0x32, 0xd1, 0x06, 0x94, 0x87, 0x5e, 0xdf, 0xb1, 0xc7, 0xc2, 0x96, 0xa0, 0xcc, 0xcf,
+	0xe0, 0x42, 0xd2, 0x52, 0x80, 0xde, 0x85, 0x12, 0xed, 0xb4, 0xc0, 0xd0, 0xaa, 0x05, 0x25, 0xc7,
+	0x49, 0x18, 0xe6, 0x18, 0xd3, 0x86, 0x45, 0x6a, 0xc0, 0x39, 0x19, 0x85, 0x04, 0xad, 0x43, 0xc9,
+	0x71, 0xbb, 0xe4, 0x5b, 0xe6, 0x62, 0x09, 0x73, 0x22, 0x4a, 0x8f, 0xae, 0xa4, 0x67, 0x1d, 0x4a,
+	0x5f, 0xb9, 0xde, 0x37, 0x2e, 0xab, 0xe6, 0x16, 0x30, 0x27, 0xa8, 0x93, 0x67, 0x4e, 0xb7, 0x4b,
+	0x5c, 0x56, 0xb1, 0x2d, 0x60, 0x41, 0x99, 0x0f, 0xa0, 0xf2, 0xc8, 0x0d, 0x63, 0x3b, 0x37, 0xa1,
+	0x68, 0x85, 0xa1, 0x6f, 0x68, 0x89, 0xb9, 0x18, 0xb5, 0x63, 0xd6, 0x6a, 0x7e, 0x04, 0x17, 0x0e,
+	0x43, 0xdf, 0x71, 0x7b, 0x93, 0x82, 0xfa, 0x54, 0xc1, 0x0f, 0x61, 0xf9, 0xa1, 0x15, 0x92, 0xd7,
+	0xb5, 0xf7, 0x21, 0x2c, 0x37, 0x3d, 0xaf, 0xff, 0xba, 0x62, 0x8f, 0x61, 0xb9, 0xed, 0x8e, 0x06,
+	0xaf, 0x29, 0x46, 0x73, 0xf5, 0xca, 0xea, 0x8f, 0x88, 0xec, 0x6f, 0x41, 0x99, 0x9f, 0xc2, 0xa5,
+	0x8e, 0x15, 0x9c, 0x91, 0x6e, 0x5e, 0xec, 0xd3, 0xbd, 0xf9, 0x9b, 0x0e, 0xcb, 0xb4, 0x7f, 0x63,
+	0xb9, 0xff, 0x04, 0x08, 0x22, 0x55, 0x42, 0x7a, 0x23, 0xaa, 0x88, 0x13, 0x36, 0xe8, 0xba, 0x19,
+	0x63, 0xd1, 0x3d, 0x98, 0x77, 0x78, 0xb7, 0x19, 0x7a, 0x62, 0x01, 0x54, 0x3b, 0xb3, 0x33, 0x87,
+	0x25, 0x0a, 0xd5, 0x61, 0xa1, 0x2b, 0x12, 0x6f, 0x14, 0x12, 0x95, 0x74, 0xa2, 0x3f, 0x3a, 0x73,
+	0x38, 0xc2, 0x51, 0x99, 0x13, 0x91, 0x75, 0xa3, 0x98, 0x90, 0x49, 0x74, 0x06, 0x95, 0x91, 0x38,
+	0x2a, 0x43, 0x44, 0xca, 0x8d, 0x52, 0x42, 0x26, 0xd1, 0x13, 0x54, 0x46, 0xe2, 0xd0, 0x17, 0xb0,
+	0x7a, 0x96, 0xca, 0xab, 0x38, 0x1e, 0x5c, 0x13, 0xb2, 0x99, 0x69, 0xa7, 0xb5, 0x75, 0x5a, 0xae,
+	0x59, 0x86, 0x62, 0x38, 0x1e, 0x12, 0xf3, 0xb7, 0x1a, 0x4f, 0xf6, 0x61, 0xe8, 0x8f, 0xec, 0x70,
+	0xe4, 0x13, 0xda, 0xab, 0xee, 0x0e, 0x9b, 0x18, 0x7c, 0x0a, 0x09, 0x0a, 0xbd, 0x05, 0xe0, 0xb6,
+	0x58, 0x95, 0x1f, 0x92, 0x2e, 0xcb, 0x66, 0x09, 0x2b, 0x1c, 0xba, 0x3c, 0xb8, 0x1d, 0x3e, 0x75,
+	0x0a, 0xac, 0x51, 0x92, 0xe8, 0x01, 0x80, 0x25, 0x9d, 0x09, 0x8c, 0x62, 0xb5, 0xa0, 0x44, 0x9b,
+	0xe8, 0x68, 0xac, 0xe0, 0xd8, 0xfc, 0x24, 0xe3, 0x47, 0x5d, 0x96, 0x9e, 0x45, 0xcc, 0x09, 0xd3,
+	0x84, 0x32, 0x3f, 0x03, 0x51, 0x7b, 0x87, 0x23, 0xdb, 0x26, 0x41, 0xc0, 0x1c, 0x5d, 0xc0, 0x92,
+	0x34, 0x0d, 0x28, 0xf3, 0xc2, 0x0f, 0xad, 0x80, 0xfe, 0xa2, 0xc6, 0x9a, 0x2b, 0x58, 0x7f, 0x51,
+	0x33, 0xef, 0x42, 0x45, 0x2d, 0x0c, 0xd3, 0xed, 0x8c, 0xae, 0x1b, 0xba, 0xa0, 0xeb, 0xe6, 0x9b,
+	0xb0, 0x9c, 0x38, 0x40, 0xa1, 0x0a, 0x68, 0x1d, 0x81, 0xd7, 0x3a, 0x66, 0x1d, 0xd6, 0xb3, 0x4e,
+	0x46, 0x14, 0xf5, 0x42, 0xa2, 0x5e, 0x50, 0x0a, 0x0b, 0x9d, 0x1a, 0x36, 0xdf, 0x83, 0x95, 0xe4,
+	0xe9, 0x6f, 0x12, 0x7d, 0x24, 0xd1, 0x47, 0xa6, 0x09, 0xc5, 0x03, 0xcb, 0xf1, 0x29, 0xb7, 0x21,
+	0x31, 0x0d, 0x4a, 0x35, 0x25, 0xa6, 0x69, 0x36, 0x61, 0x23, 0xfb, 0xf8, 0x33, 0xa9, 0xb9, 0x61,
+	0xe8, 0x09, 0x1d, 0x05, 0xa9, 0xa3, 0x0a, 0xab, 0xe9, 0x23, 0x19, 0x45, 0xbc, 0x94, 0xd2, 0x2f,
+	0x4d, 0x1f, 0xe0, 0x73, 0xc7, 0x0a, 0x0f, 0xcf, 0xac, 0x81, 0xe3, 0xa3, 0x2d, 0xb8, 0x90, 0x32,
+	0x26, 0x90, 0x69, 0x36, 0xba, 0x06, 0x8b, 0xad, 0x33, 0xab, 0xdf, 0x27, 0x6e, 0x8f, 0x08, 0xeb,
+	0x31, 0x83, 0xb6, 0x46, 0x06, 0x8d, 0x42, 0xb5, 0x40, 0x5b, 0x23, 0x86, 0x39, 0x86, 0x8b, 0xb1,
+	0xcd, 0x46, 0x3f, 0xf0, 0xf6, 0x48, 0xef, 0x5f, 0x67, 0x7a, 0x51, 0x35, 0xfd, 0x23, 0x0d, 0x8c,
+	0xbc, 0x53, 0x1f, 0xda, 0x94, 0x79, 0xcd, 0x3b, 0xd1, 0xd3, 0x74, 0x6f, 0xca, 0x74, 0xe7, 0x83,
+	0x1a, 0x68, 0x53, 0xf6, 0x42, 0x3e, 0xa8, 0x69, 0xfe, 0x4e, 0x83, 0x1b, 0x33, 0x6b, 0xf1, 0xac,
+	0xb1, 0xdc, 0xa8, 0xc9, 0xb1, 0xdc, 0x60, 0x74, 0xb3, 0x26, 0x7a, 0x5c, 0x6f, 0xca, 0xb1, 0x5e,
+	0x94, 0x63, 0x9d, 0xe1, 0xeb, 0x46, 0x49, 0xe0, 0x19, 0xdd, 0xac, 0x1b, 0x65, 0x81, 0xaf, 0xf3,
+	0x61, 0x3c, 0x2f, 0x86, 0x31, 0xa5, 0x0e, 0xd9, 0x25, 0x41, 0x05, 0x6b, 0x87, 0x74, 0xcd, 0x10,
+	0x65, 0xd9, 0x22, 0xdf, 0xda, 0x39, 0x65, 0xfe, 0x41, 0x87, 0xcd, 0x73, 0x9c, 0x22, 0xd0, 0xad,
+	0xc8, 0xf7, 0xdc, 0x3c, 0xd0, 0x90, 0x6e, 0x45, 0x21, 0xe5, 0xc3, 0x1a, 0x0c, 0x26, 0x22, 0xcd,
+	0x87, 0x35, 0x19, 0x4c, 0x24, 0x60, 0x8a, 0xd1, 0x3a, 0xba, 0x15, 0xe5, 0x65, 0x8a, 0x51, 0x06,
+	0x13, 0xe9, 0x9a, 0x62, 0xf4, 0x9f, 0xcb, 0xa2, 0x07, 0x97, 0x73, 0x4f, 0x80, 0xb4, 0x86, 0x6b,
+	0xf6, 0x69, 0x95, 0xd3, 0x95, 0x0b, 0x44, 0x44, 0x2b, 0x6d, 0x72, 0xb9, 0x88, 0x68, 0xee, 0x48,
+	0x21, 0xe1, 0x48, 0x51, 0x38, 0x62, 0xfe, 0x5c, 0x83, 0xab, 0x53, 0xce, 0x9c, 0xa8, 0x96, 0xb2,
+	0x99, 0x1b, 0x71, 0xec, 0x4a, 0x2d, 0xe5, 0xca, 0x4c, 0x91, 0xe9, 0x1e, 0xfe, 0x50, 0x83, 0xea,
+	0xac, 0x93, 0x21, 0x5a, 0x85, 0xc2, 0x8b, 0x9a, 0x9c, 0x12, 0xf4, 0x93, 0x73, 0xe4, 0x02, 0x4f,
+	0x3f, 0x19, 0xa7, 0x2e, 0xa7, 0x05, 0xfd, 0xe4, 0x1c, 0x39, 0x31, 0xe8, 0x27, 0x5f, 0x38, 0x4b,
+	0x89, 0x85, 0xb3, 0x2c, 0x17, 0xce, 0x9f, 0xea, 0x60, 0xce, 0x3e, 0xa2, 0xa2, 0xdb, 0xb1, 0x2b,
+	0xb9, 0x91, 0x33, 0x0f, 0x6f, 0xc7, 0x1e, 0x4e, 0x03, 0xd6, 0xd1, 0xed, 0xd8, 0xf1, 0x29, 0xc0,
+	0x3a, 0xd7, 0x58, 0x9f, 0x31, 0xce, 0x59, 0x98, 0x9b, 0x32, 0xcc, 0x99, 0x0b, 0x56, 0x79, 0xc6,
+	0x82, 0xf5, 0xbf, 0xb0, 0x31, 0x71, 0x64, 0x66, 0x87, 0x8e, 0x69, 0xfb, 0x18, 0x2d, 0xd2, 0x69,
+	0xfd, 0x22, 0xfa, 0x82, 0x7d, 0xd3, 0x29, 0xf1, 0xb2, 0xd1, 0x1f, 0x9e, 0x59, 0xa2, 0x3f, 0x04,
+	0x65, 0xfe, 0x58, 0x03, 0x23, 0xdb, 0x44, 0xbb, 0x85, 0x36, 0xa5, 0x91, 0x99, 0x81, 0x4c, 0x5f,
+	0x9e, 0x5f, 0xcf, 0xa5, 0xbf, 0x6b, 0xc9, 0xa8, 0x95, 0x53, 0xeb, 0x4d, 0x58, 0x3e, 0x1c, 0x58,
+	0xfd, 0x7e, 0xe3, 0x89, 0xb7, 0x6d, 0x0d, 0x06, 0x72, 0xc3, 0x4a, 0x32, 0x23, 0x54, 0x53, 0xa2,
+	0x74, 0x05, 0x25, 0x99, 0x74, 0x4e, 0x47, 0x6a, 0xb8, 0x5b, 0x0b, 0x0d, 0xa5, 0x2d, 0x12, 0x2e,
+	0x8a, 0xf9, 0x2e, 0xdb, 0xde, 0x07, 0xfd, 0x49, 0xcd, 0x28, 0x25, 0x6e, 0x4d, 0xb3, 0x33, 0x88,
+	0xf5, 0x27, 0x35, 0x06, 0x97, 0xcb, 0xd9, 0x4c, 0x78, 0xdd, 0xfc, 0xab, 0x0e, 0x46, 0x76, 0xf0,
+	0xed, 0x16, 0xfa, 0x24, 0x2b, 0xfc, 0xdc, 0xb4, 0xa7, 0xb2, 0xf2, 0x49, 0x56, 0x56, 0x66, 0x08,
+	0x47, 0x41, 0xd7, 0x52, 0xc9, 0xca, 0x5f, 0x75, 0x1a, 0x8a, 0x48, 0x22, 0x87, 0x53, 0x16, 0x2a,
+	0x29, 0x72, 0x4f, 0x49, 0xed, 0xf5, 0xa9, 0xb9, 0x6a, 0xb7, 0x58, 0x72, 0xef, 0x29, 0xc9, 0x3d,
+	0x87, 0x40, 0xdd, 0xfc, 0xa3, 0x06, 0xe6, 0x04, 0x60, 0xf2, 0x5e, 0xd1, 0x80, 0xf9, 0xfd, 0xe4,
+	0x09, 0x5d, 0x90, 0xa2, 0x38, 0xd0, 0x53, 0x85, 0x6e, 0x21, 0xda, 0xfc, 0x11, 0x14, 0xf7, 0xc6,
+	0x83, 0x86, 0x18, 0x35, 0xec, 0x5b, 0xf0, 0x9a, 0x62, 0xe5, 0x63, 0xdf, 0xe8, 0x53, 0x80, 0xd8,
+	0xe6, 0x94, 0xe1, 0x11, 0x83, 0xb0, 0x22, 0x60, 0xfe, 0x42, 0x87, 0x9b, 0xe7, 0xb9, 0x4c, 0x9b,
+	0x12, 0xc9, 0xad, 0x28, 0x92, 0x59, 0xa5, 0x82, 0x08, 0x70, 0xea, 0xe6, 0x7e, 0x47, 0x89, 0x3b,
+	0x17, 0xc8, 0xd3, 0x71, 0x47, 0x49, 0xc7, 0x54, 0x68, 0x13, 0xfd, 0x77, 0x46, 0x96, 0xae, 0x4f,
+	0xcd, 0x52, 0xbb, 0x95, 0xc8, 0xd3, 0x5f, 0x74, 0x58, 0x6b, 0x1d, 0x1e, 0x58, 0x4e, 0xbf, 0xef,
+	0x10, 0xff, 0x90, 0xd8, 0x3e, 0x09, 0xe9, 0xad, 0x56, 0x05, 0xb4, 0x3d, 0xb9, 0x7c, 0xee, 0x51,
+	0x6a, 0x5b, 0x2e, 0x9f, 0xdb, 0xa2, 0x8b, 0x0b, 0xa9, 0x2e, 0x4e, 0xd4, 0x77, 0x2f, 0xee, 0xcb,
+	0xfa, 0xee, 0xc5, 0x7d, 0x7a, 0xbe, 0x7a, 0xb8, 0xeb, 0xf5, 0x0e, 0xc4, 0x5e, 0xc6, 0x09, 0xc9,
+	0xdd, 0x16, 0x35, 0x0a, 0x27, 0x24, 0xf7, 0x4b, 0x51, 0xab, 0x70, 0x02, 0x7d, 0x00, 0x6b, 0xcf,
+	0x88, 0xef, 0x9c, 0x3a, 0xf4, 0x46, 0xa6, 0xed, 0xf2, 0x17, 0xac, 0x3d, 0x56, 0xbc, 0x54, 0x70,
+	0x56, 0x13, 0xaa, 0xc3, 0xfa, 0x24, 0x7b, 0xbb, 0xc6, 0x1e, 0x73, 0x2a, 0x38, 0xb3, 0x2d, 0x5b,
+	0xa6, 0x53, 0x33, 0x96, 0xf2, 0x64, 0x3a, 0x35, 0x9a, 0x99, 0x1d, 0xa3, 0xc2, 0x4e, 0xa1, 0xda,
+	0x0e, 0x8d, 0x7c, 0xa7, 0x66, 0x2c, 0x33, 0x52, 0xdf, 0xa9, 0x99, 0x7f, 0xd6, 0x61, 0x35, 0xce,
+	0xee, 0xc1, 0xe8, 0xe4, 0x1c, 0xa9, 0x3d, 0x8a, 0x52, 0x7b, 0xc4, 0x52, 0x7b, 0x14, 0xa5, 0xf6,
+	0x88, 0xa5, 0xf6, 0x28, 0x4a, 0xed, 0xd1, 0xbf, 0x73, 0x6a, 0x4d, 0xf5, 0x72, 0x9b, 0xc6, 0xc6,
+	0xae, 0x84, 0xc4, 0x1c, 0xe6, 0x84, 0xf9, 0x11, 0x2c, 0x09, 0x0c, 0xbb, 0xd5, 0xc8, 0xba, 0xba,
+	0x8c, 0x04, 0x75, 0x55, 0xd0, 0x89, 0x04, 0x67, 0xdc, 0x47, 0x6e, 0x41, 0xc9, 0x8a, 0xee, 0x21,
+	0x97, 0xea, 0x28, 0x79, 0xbb, 0x4e, 0xad, 0x62, 0x0e, 0xc8, 0xbd, 0x93, 0xdc, 0x8f, 0x4c, 0xb1,
+	0xeb, 0xd5, 0xad, 0xe4, 0x7d, 0x64, 0x4a, 0xa1, 0x72, 0x19, 0x49, 0x15, 0x92, 0x6f, 0x87, 0x8e,
+	0x3f, 0x66, 0xae, 0x17, 0xb0, 0xa0, 0xcc, 0x8f, 0x65, 0x6d, 0xaf, 0x54, 0xf9, 0x9a, 0x5a, 0xe5,
+	0xab, 0x4b, 0x9e, 0x9e, 0x58, 0xf2, 0xcc, 0x1b, 0x50, 0x62, 0x97, 0xc4, 0xf9, 0xab, 0xa2, 0xf9,
+	0x7b, 0x5d, 0x79, 0x20, 0xa0, 0x25, 0xec, 0xde, 0x78, 0x20, 0x0b, 0xdf, 0xbd, 0xf1, 0x80, 0x5e,
+	0xde, 0xb0, 0x5b, 0x9c, 0xf8, 0x7a, 0xb6, 0x82, 0x15, 0x0e, 0xba, 0x0b, 0xa8, 0x15, 0xdd, 0x5f,
+	0x04, 0xfb, 0xa7, 0x1c, 0xc7, 0x0f, 0xe4, 0x19, 0x2d, 0xe8, 0x7d, 0x58, 0xd8, 0x1b, 0x0f, 0x58,
+	0x9d, 0x6b, 0x14, 0x13, 0x4f, 0x18, 0xf1, 0x81, 0x1d, 0x47, 0x10, 0x3a, 0x68, 0x9e, 0xca, 0x0a,
+	0xfa, 0x29, 0xfa, 0x00, 0xca, 0x4f, 0xb9, 0x68, 0x39, 0xf1, 0x06, 0x30, 0x71, 0xd6, 0xc7, 0x02,
+	0x87, 0x1e, 0x83, 0x31, 0xe9, 0x04, 0x6b, 0x0a, 0x8c, 0xf9, 0x6a, 0x21, 0xdb, 0x7c, 0xae, 0x08,
+	0x1d, 0x5e, 0x7b, 0x9e, 0x6b, 0x13, 0x39, 0xe7, 0x18, 0x61, 0xfe, 0x4c, 0x4b, 0x3e, 0x99, 0x4c,
+	0x16, 0xab, 0x6d, 0xb9, 0x24, 0xb4, 0x69, 0x8a, 0x9f, 0xd5, 0xa2, 0x73, 0xc3, 0xb3, 0x5a, 0x8d,
+	0x46, 0xd5, 0x50, 0x13, 0x32, 0x25, 0x2a, 0x8e, 0x43, 0xef, 0xc0, 0xfc, 0x73, 0x27, 0x74, 0xe9,
+	0x0d, 0x56, 0x29, 0xf5, 0xa4, 0x23, 0xf8, 0x58, 0x02, 0xcc, 0xef, 0x74, 0x40, 0x93, 0x2f, 0x2e,
+	0x19, 0x3d, 0x1d, 0xc5, 0xa6, 0x2b, 0xb1, 0xd1, 0xda, 0x72, 0x8f, 0x7c, 0xa3, 0x0c, 0x01, 0xde,
+	0xb5, 0x49, 0x66, 0xce, 0x28, 0x28, 0xe6, 0x8e, 0x82, 0x69, 0xdd, 0x52, 0x7a, 0xfd, 0x6e, 0x51,
+	0x07, 0xd5, 0xfc, 0xcc, 0x41, 0xf5, 0x45, 0x71, 0xa1, 0xbc, 0x3a, 0x6f, 0xfe, 0xa4, 0x04, 0x17,
+	0x27, 0x9e, 0x71, 0x52, 0x5d, 0x77, 0x17, 0x4a, 0x5c, 0xab, 0x3e, 0xa3, 0x67, 0x38, 0x2c, 0x35,
+	0x5b, 0x0a, 0xe7, 0x9c, 0x2d, 0xf9, 0x79, 0xba, 0x0b, 0x08, 0x8b, 0xa7, 0x10, 0x45, 0x2f, 0xcd,
+	0x50, 0x09, 0x67, 0xb4, 0xa0, 0xcf, 0xe0, 0x8a, 0xe4, 0x66, 0xd8, 0x29, 0x33, 0xb9, 0x29, 0x08,
+	0xb4, 0x03, 0x68, 0xcf, 0x73, 0x31, 0x79, 0xe5, 0xd9, 0x16, 0x7d, 0xe8, 0x51, 0x53, 0x7a, 0x35,
+	0x1a, 0x63, 0x93, 0x10, 0x9c, 0x21, 0x86, 0x1a, 0xf4, 0xbe, 0x8d, 0x74, 0xd9, 0xd9, 0x5f, 0xf4,
+	0xed, 0x42, 0xb5, 0xa0, 0xfc, 0x97, 0xa3, 0xb5, 0x9b, 0x6c, 0xc7, 0x69, 0x3c, 0x7a, 0x0c, 0x6b,
+	0x87, 0x24, 0x7c, 0x4c, 0x06, 0x27, 0xc4, 0x0f, 0xce, 0x9c, 0xa1, 0x50, 0xb3, 0x58, 0x2d, 0x24,
+	0x1c, 0x9a, 0xc4, 0xe0, 0x2c, 0x39, 0xf4, 0x25, 0xac, 0x3f, 0xf4, 0x06, 0x96, 0xe3, 0x46, 0xb5,
+	0x14, 0x0f, 0x30, 0xf9, 0x97, 0x90, 0xd6, 0x6e, 0x16, 0x08, 0x67, 0x8a, 0xd2, 0x59, 0xb3, 0xc3,
+	0x2e, 0x9b, 0x97, 0xf8, 0x86, 0xc3, 0x08, 0xf4, 0x1f, 0xb0, 0xd4, 0x0e, 0x6c, 0xdf, 0xfb, 0x86,
+	0xeb, 0xaf, 0x24, 0xee, 0xe9, 0x5b, 0xbb, 0x4a, 0x1b, 0x56, 0x81, 0xe6, 0x9f, 0x34, 0x58, 0x49,
+	0xbe, 0x07, 0xc6, 0xd3, 0x52, 0x53, 0xa7, 0xe5, 0x3a, 0x94, 0x0e, 0x6d, 0x6f, 0x18, 0x4d, 0x56,
+	0x46, 0xd0, 0x47, 0x4e, 0xae, 0x4d, 0xd4, 0xaf, 0x17, 0x52, 0x16, 0xb1, 0x68, 0xa6, 0xb3, 0x9a,
+	0x7f, 0xc9, 0x5d, 0xa1, 0xc8, 0xbc, 0x4f, 0x32, 0xe9, 0x5f, 0x84, 0xe8, 0xb0, 0x68, 0x7f, 0x3d,
+	0xb2, 0xfa, 0x4e, 0xe8, 0x10, 0x39, 0x37, 0xe3, 0x97, 0x4b, 0xa5, 0x79, 0x8c, 0x53, 0x60, 0xb3,
+	0x4b, 0x63, 0x51, 0x11, 0x74, 0xc7, 0xde, 0xf7, 0x7b, 0x35, 0xb9, 0x63, 0xd3, 0x6f, 0x1a, 0x09,
+	0xc5, 0xd4, 0xe4, 0x8e, 0xcd, 0x08, 0x81, 0xac, 0x8b, 0xcd, 0x95, 0x7d, 0x4b, 0x64, 0x5d, 0x38,
+	0xcb, 0x09, 0xf3, 0x39, 0x2c, 0xf1, 0x09, 0xcc, 0xfb, 0x23, 0xbf, 0xfe, 0xcf, 0x99, 0xcb, 0x13,
+	0x4b, 0x80, 0x98, 0xcb, 0xe6, 0xff, 0x00, 0x9a, 0x68, 0x0b, 0xd0, 0x3b, 0x50, 0x16, 0x83, 0x30,
+	0xb9, 0xa3, 0x2b, 0x3e, 0x60, 0x81, 0x30, 0xdb, 0x74, 0x6b, 0x15, 0xeb, 0x30, 0x5d, 0x58, 0x9e,
+	0xcb, 0x85, 0xe5, 0x39, 0x8d, 0xe5, 0x59, 0x54, 0xa7, 0x54, 0x30, 0x27, 0x28, 0xb7, 0x3d, 0xf4,
+	0xec, 0x33, 0xf1, 0x0e, 0xc2, 0x09, 0xf3, 0x3b, 0x0d, 0xd6, 0xb3, 0x26, 0x5d, 0x0c, 0xd7, 0x14,
+	0x38, 0x7d, 0xbd, 0x55, 0x66, 0xb6, 0xd8, 0xb2, 0x55, 0x56, 0xd6, 0x45, 0x38, 0x5f, 0xaa, 0xb2,
+	0x2e, 0xc2, 0xe3, 0xab, 0xee, 0x62, 0xfa, 0xaa, 0xfb, 0x37, 0x45, 0x58, 0x4d, 0xcf, 0x61, 0x2a,
+	0x42, 0x3b, 0xe6, 0x91, 0xf2, 0x96, 0x1a, 0x33, 0xe8, 0xb6, 0xf3, 0xd8, 0x91, 0x4f, 0xca, 0xf4,
+	0x93, 0x71, 0xac, 0x6f, 0x45, 0x47, 0xd3, 0x4f, 0xba, 0x88, 0xc6, 0xde, 0x8a, 0x62, 0x59, 0xe1,
+	0x64, 0xb9, 0x5f, 0xca, 0xbd, 0xc7, 0x8f, 0xdd, 0x2f, 0x33, 0x0b, 0x31, 0x03, 0xbd, 0x07, 0x17,
+	0xd9, 0xa1, 0x5f, 0x49, 0x4d, 0x8d, 0x15, 0x05, 0x15, 0x3c, 0xd9, 0x40, 0xad, 0x36, 0x9d, 0x5e,
+	0x02, 0xbb, 0xc0, 0x93, 0x96, 0x62, 0x67, 0xe9, 0xad, 0x1b, 0x8b, 0xd9, 0x7a, 0xeb, 0x93, 0x7a,
+	0xeb, 0x06, 0x64, 0xe9, 0xad, 0xa3, 0x07, 0x70, 0x09, 0x5b, 0x6e, 0x2f, 0x7d, 0x49, 0x48, 0xab,
+	0x6e, 0x8a, 0xcf, 0x6e, 0xcc, 0x93, 0xaa, 0x1b, 0x95, 0x7c, 0x29, 0xe6, 0x55, 0xdc, 0xc0, 0xad,
+	0x2c, 0xb3, 0xee, 0x4f, 0xb3, 0x27, 0x91, 0x75, 0x63, 0x25, 0x0b, 0x59, 0x37, 0x7f, 0xa9, 0xd3,
+	0x71, 0x3c, 0xb9, 0x2e, 0xcf, 0x18, 0x32, 0x1b, 0x50, 0x7e, 0x16, 0x3f, 0x16, 0x57, 0xb0, 0xa0,
+	0x52, 0xc3, 0xa4, 0x70, 0x9e, 0x61, 0x52, 0x3c, 0xc7, 0x30, 0x29, 0x65, 0x0c, 0x93, 0xfd, 0xf4,
+	0x03, 0x19, 0xdb, 0x4a, 0x2b, 0x78, 0xb2, 0x01, 0x99, 0x50, 0xd9, 0xf7, 0xa3, 0xb7, 0xa2, 0x40,
+	0x8c, 0xa7, 0x04, 0x8f, 0xce, 0xd0, 0xfd, 0xf8, 0xb9, 0x8c, 0x0d, 0xa3, 0x45, 0xac, 0xb2, 0xcc,
+	0x57, 0xb0, 0x91, 0xbd, 0x0b, 0xc5, 0x0b, 0xbf, 0xa6, 0x2e, 0xfc, 0x34, 0x02, 0x89, 0x93, 0x0f,
+	0x56, 0x11, 0x23, 0x7b, 0xbe, 0x67, 0x65, 0xc2, 0xf4, 0x60, 0x41, 0xee, 0x15, 0x33, 0x7a, 0xe5,
+	0x1e, 0x94, 0xf9, 0xf9, 0x57, 0x2c, 0xa7, 0xd1, 0x9e, 0x9e, 0x3a, 0x1e, 0x63, 0x01, 0xa3, 0x8e,
+	0xef, 0x5a, 0x27, 0xa4, 0x2f, 0x4c, 0x73, 0xc2, 0xfc, 0x35, 0x7d, 0x45, 0x56, 0xf7, 0x43, 0x65,
+	0x0f, 0xd3, 0xa6, 0xef, 0x61, 0xec, 0x68, 0xa0, 0xcb, 0xa3, 0x01, 0x2b, 0xb2, 0x0b, 0xb2, 0xc8,
+	0xae, 0x80, 0xf6, 0x4c, 0xde, 0xf9, 0x3f, 0xcb, 0x5e, 0x30, 0x0a, 0xaf, 0xbd, 0x60, 0xd0, 0x1d,
+	0xa1, 0xb5, 0x4b, 0x17, 0xe1, 0xaf, 0xc8, 0xd4, 0x4a, 0x3a, 0xff, 0x40, 0xb6, 0x0f, 0x6b, 0xad,
+	0xdd, 0x86, 0x6d, 0x8f, 0x06, 0xa3, 0xbe, 0x15, 0x7a, 0x3e, 0x2f, 0xcc, 0x73, 0x16, 0xf2, 0xe4,
+	0xb9, 0x21, 0xda, 0x31, 0x0a, 0xca, 0x8e, 0x61, 0x1e, 0xc3, 0x1b, 0xd1, 0x16, 0xc3, 0x95, 0x05,
+	0x4a, 0xe1, 0x90, 0xa1, 0x34, 0xaa, 0x57, 0x74, 0xb5, 0x5e, 0x51, 0x3c, 0x2e, 0x24, 0x3d, 0xee,
+	0xc0, 0x6a, 0xda, 0x00, 0x7a, 0x00, 0xf3, 0xe2, 0x53, 0x6c, 0x82, 0x57, 0xe2, 0x82, 0x20, 0x1d,
+	0x1b, 0x96, 0x50, 0xf3, 0x80, 0x66, 0xef, 0xc9, 0x99, 0x4f, 0x82, 0x33, 0xaf, 0xdf, 0x95, 0xff,
+	0x8d, 0x46, 0x50, 0xfc, 0xdc, 0xf7, 0x06, 0xc2, 0x49, 0xf6, 0x4d, 0xef, 0x06, 0x9e, 0x78, 0xe2,
+	0x8f, 0x02, 0xfa, 0x13, 0x4f, 0x59, 0x01, 0xf8, 0x6b, 0xab, 0xa0, 0xcc, 0x5f, 0x69, 0xb0, 0xaa,
+	0xa8, 0xc4, 0xde, 0xc8, 0xed, 0xc6, 0x01, 0x6a, 0x6a, 0x80, 0xeb, 0x50, 0x3a, 0xb0, 0xfc, 0x70,
+	0x2c, 0xb4, 0x72, 0x82, 0x76, 0xb7, 0x3c, 0xac, 0x77, 0x45, 0xe0, 0x31, 0x83, 0xca, 0x30, 0x95,
+	0x6c, 0x20, 0x95, 0x30, 0x27, 0xd0, 0x87, 0xb0, 0x20, 0x7c, 0x97, 0xe5, 0xd0, 0xe5, 0x28, 0xfa,
+	0x74, 0x74, 0x38, 0x82, 0x9a, 0xbb, 0xb0, 0x36, 0xd9, 0x1e, 0x24, 0xb4, 0x69, 0xe7, 0xd6, 0x76,
+	0x52, 0x66, 0x98, 0xfb, 0xff, 0x18, 0x00, 0x5f, 0xb8, 0x47, 0xe0, 0x9a, 0x2e, 0x00, 0x00,
 }
@@ -611,11 +611,14 @@ var _PseudonymSystem_serviceDesc = grpc.ServiceDesc{
 // Client API for CL service
 
 type CLClient interface {
-	GetCredentialStructure(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*CredStructure, error)
+	GetCredentialStructure(ctx context.Context, in *CLOrg, opts ...grpc.CallOption) (*CredStructure, error)
 	GetAcceptableCredentials(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*AcceptableCreds, error)
 	IssueCredential(ctx context.Context, opts ...grpc.CallOption) (CL_IssueCredentialClient, error)
 	UpdateCredential(ctx context.Context, opts ...grpc.CallOption) (CL_UpdateCredentialClient, error)
 	ProveCredential(ctx context.Context, opts ...grpc.CallOption) (CL_ProveCredentialClient, error)
+	ProveCredentials(ctx context.Context, opts ...grpc.CallOption) (CL_ProveCredentialsClient, error)
+	RevokeCredential(ctx context.Context, in *CLRevokeCredential, opts ...grpc.CallOption) (*CLAccumulatorUpdate, error)
+	GetWitnessUpdates(ctx context.Context, in *CLWitnessUpdatesRequest, opts ...grpc.CallOption) (*CLWitnessUpdates, error)
 }
 
 type cLClient struct {
@@ -626,7 +629,7 @@ func NewCLClient(cc *grpc.ClientConn) CLClient {
 	return &cLClient{cc}
 }
 
-func (c *cLClient) GetCredentialStructure(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*CredStructure, error) {
+func (c *cLClient) GetCredentialStructure(ctx context.Context, in *CLOrg, opts ...grpc.CallOption) (*CredStructure, error) {
 	out := new(CredStructure)
 	err := grpc.Invoke(ctx, "/proto.CL/GetCredentialStructure", in, out, c.cc, opts...)
 	if err != nil {
@@ -737,14 +740,66 @@ func (x *cLProveCredentialClient) Recv() (*Message, error) {
 	return m, nil
 }
 
+func (c *cLClient) ProveCredentials(ctx context.Context, opts ...grpc.CallOption) (CL_ProveCredentialsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_CL_serviceDesc.Streams[3], c.cc, "/proto.CL/ProveCredentials", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cLProveCredentialsClient{stream}
+	return x, nil
+}
+
+type CL_ProveCredentialsClient interface {
+	Send(*Message) error
+	Recv() (*Message, error)
+	grpc.ClientStream
+}
+
+type cLProveCredentialsClient struct {
+	grpc.ClientStream
+}
+
+func (x *cLProveCredentialsClient) Send(m *Message) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *cLProveCredentialsClient) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cLClient) RevokeCredential(ctx context.Context, in *CLRevokeCredential, opts ...grpc.CallOption) (*CLAccumulatorUpdate, error) {
+	out := new(CLAccumulatorUpdate)
+	err := grpc.Invoke(ctx, "/proto.CL/RevokeCredential", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cLClient) GetWitnessUpdates(ctx context.Context, in *CLWitnessUpdatesRequest, opts ...grpc.CallOption) (*CLWitnessUpdates, error) {
+	out := new(CLWitnessUpdates)
+	err := grpc.Invoke(ctx, "/proto.CL/GetWitnessUpdates", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for CL service
 
 type CLServer interface {
-	GetCredentialStructure(context.Context, *google_protobuf.Empty) (*CredStructure, error)
+	GetCredentialStructure(context.Context, *CLOrg) (*CredStructure, error)
 	GetAcceptableCredentials(context.Context, *google_protobuf.Empty) (*AcceptableCreds, error)
 	IssueCredential(CL_IssueCredentialServer) error
 	UpdateCredential(CL_UpdateCredentialServer) error
 	ProveCredential(CL_ProveCredentialServer) error
+	ProveCredentials(CL_ProveCredentialsServer) error
+	RevokeCredential(context.Context, *CLRevokeCredential) (*CLAccumulatorUpdate, error)
+	GetWitnessUpdates(context.Context, *CLWitnessUpdatesRequest) (*CLWitnessUpdates, error)
 }
 
 func RegisterCLServer(s *grpc.Server, srv CLServer) {
@@ -752,7 +807,7 @@ func RegisterCLServer(s *grpc.Server, srv CLServer) {
 }
 
 func _CL_GetCredentialStructure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(google_protobuf.Empty)
+	in := new(CLOrg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -764,7 +819,7 @@ func _CL_GetCredentialStructure_Handler(srv interface{}, ctx context.Context, de
 		FullMethod: "/proto.CL/GetCredentialStructure",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CLServer).GetCredentialStructure(ctx, req.(*google_protobuf.Empty))
+		return srv.(CLServer).GetCredentialStructure(ctx, req.(*CLOrg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -865,6 +920,68 @@ func (x *cLProveCredentialServer) Recv() (*Message, error) {
 	return m, nil
 }
 
+func _CL_ProveCredentials_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CLServer).ProveCredentials(&cLProveCredentialsServer{stream})
+}
+
+type CL_ProveCredentialsServer interface {
+	Send(*Message) error
+	Recv() (*Message, error)
+	grpc.ServerStream
+}
+
+type cLProveCredentialsServer struct {
+	grpc.ServerStream
+}
+
+func (x *cLProveCredentialsServer) Send(m *Message) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *cLProveCredentialsServer) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CL_RevokeCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CLRevokeCredential)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CLServer).RevokeCredential(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CL/RevokeCredential",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CLServer).RevokeCredential(ctx, req.(*CLRevokeCredential))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CL_GetWitnessUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CLWitnessUpdatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CLServer).GetWitnessUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CL/GetWitnessUpdates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CLServer).GetWitnessUpdates(ctx, req.(*CLWitnessUpdatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CL_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.CL",
 	HandlerType: (*CLServer)(nil),
@@ -877,6 +994,14 @@ var _CL_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetAcceptableCredentials",
 			Handler:    _CL_GetAcceptableCredentials_Handler,
 		},
+		{
+			MethodName: "RevokeCredential",
+			Handler:    _CL_RevokeCredential_Handler,
+		},
+		{
+			MethodName: "GetWitnessUpdates",
+			Handler:    _CL_GetWitnessUpdates_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -897,7 +1022,174 @@ var _CL_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ProveCredentials",
+			Handler:       _CL_ProveCredentials_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "services.proto",
+}
+
+// Client API for CLThresholdParty service
+
+type CLThresholdPartyClient interface {
+	Round(ctx context.Context, in *CLThresholdRound, opts ...grpc.CallOption) (*CLThresholdMessages, error)
+}
+
+type cLThresholdPartyClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCLThresholdPartyClient(cc *grpc.ClientConn) CLThresholdPartyClient {
+	return &cLThresholdPartyClient{cc}
+}
+
+func (c *cLThresholdPartyClient) Round(ctx context.Context, in *CLThresholdRound, opts ...grpc.CallOption) (*CLThresholdMessages, error) {
+	out := new(CLThresholdMessages)
+	err := grpc.Invoke(ctx, "/proto.CLThresholdParty/Round", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for CLThresholdParty service
+
+type CLThresholdPartyServer interface {
+	Round(context.Context, *CLThresholdRound) (*CLThresholdMessages, error)
+}
+
+func RegisterCLThresholdPartyServer(s *grpc.Server, srv CLThresholdPartyServer) {
+	s.RegisterService(&_CLThresholdParty_serviceDesc, srv)
+}
+
+func _CLThresholdParty_Round_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CLThresholdRound)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CLThresholdPartyServer).Round(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CLThresholdParty/Round",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CLThresholdPartyServer).Round(ctx, req.(*CLThresholdRound))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CLThresholdParty_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.CLThresholdParty",
+	HandlerType: (*CLThresholdPartyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Round",
+			Handler:    _CLThresholdParty_Round_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// Client API for Session service
+
+type SessionClient interface {
+	GetSession(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*SessionInfo, error)
+	Logout(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+}
+
+type sessionClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSessionClient(cc *grpc.ClientConn) SessionClient {
+	return &sessionClient{cc}
+}
+
+func (c *sessionClient) GetSession(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*SessionInfo, error) {
+	out := new(SessionInfo)
+	err := grpc.Invoke(ctx, "/proto.Session/GetSession", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) Logout(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/proto.Session/Logout", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Session service
+
+type SessionServer interface {
+	GetSession(context.Context, *google_protobuf.Empty) (*SessionInfo, error)
+	Logout(context.Context, *google_protobuf.Empty) (*google_protobuf.Empty, error)
+}
+
+func RegisterSessionServer(s *grpc.Server, srv SessionServer) {
+	s.RegisterService(&_Session_serviceDesc, srv)
+}
+
+func _Session_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).GetSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Session/GetSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).GetSession(ctx, req.(*google_protobuf.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Session/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).Logout(ctx, req.(*google_protobuf.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Session_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.Session",
+	HandlerType: (*SessionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSession",
+			Handler:    _Session_GetSession_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Session_Logout_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
@@ -968,28 +1260,37 @@ var _Info_serviceDesc = grpc.ServiceDesc{
 func init() { proto1.RegisterFile("services.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 357 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xd1, 0x6a, 0xea, 0x40,
-	0x10, 0x86, 0xa3, 0x9c, 0x73, 0x2e, 0xe6, 0x40, 0xb4, 0x6b, 0x2b, 0x25, 0xbd, 0xcb, 0x55, 0xaf,
-	0x62, 0x51, 0xa8, 0x52, 0xa9, 0x20, 0xc1, 0x5a, 0xc1, 0xb6, 0x82, 0xed, 0x75, 0xd9, 0xc4, 0x89,
-	0x04, 0x4c, 0x36, 0xec, 0x4e, 0x84, 0xbc, 0x45, 0x1f, 0xab, 0x8f, 0xd3, 0x47, 0x28, 0x49, 0xb4,
-	0x5a, 0x5b, 0x21, 0xb9, 0x0a, 0x33, 0x99, 0xef, 0x9f, 0xd9, 0xd9, 0x7f, 0x41, 0x57, 0x28, 0xd7,
-	0xbe, 0x8b, 0xca, 0x8a, 0xa4, 0x20, 0xc1, 0xfe, 0x66, 0x1f, 0x43, 0x0f, 0x50, 0x29, 0xbe, 0xdc,
-	0xa6, 0x8d, 0x8b, 0xa5, 0x10, 0xcb, 0x15, 0xb6, 0xb2, 0xc8, 0x89, 0xbd, 0x16, 0x06, 0x11, 0x25,
-	0xf9, 0xcf, 0xf6, 0x5b, 0x05, 0x4e, 0x66, 0x0a, 0xe3, 0x85, 0x08, 0x93, 0x60, 0x9e, 0x28, 0xc2,
-	0xc0, 0x1e, 0xb2, 0x3e, 0x34, 0xc6, 0x18, 0xa2, 0xe4, 0x84, 0x36, 0x4a, 0xf2, 0x3d, 0xdf, 0xe5,
-	0x84, 0x4c, 0xcf, 0x21, 0xeb, 0x21, 0x6f, 0x60, 0x1c, 0xc4, 0xa6, 0x76, 0x59, 0xb9, 0xaa, 0xb0,
-	0x01, 0x34, 0x7f, 0x81, 0x5f, 0x47, 0x76, 0x31, 0xbe, 0xfd, 0x51, 0x85, 0xda, 0xc1, 0x48, 0xac,
-	0x03, 0xff, 0xb7, 0x9a, 0x8f, 0x49, 0x50, 0x70, 0x90, 0x6b, 0xd0, 0xf7, 0xa0, 0xc2, 0x03, 0xb0,
-	0x1e, 0xd4, 0x9f, 0x1c, 0xe2, 0x7e, 0x68, 0x4b, 0x5c, 0x60, 0x48, 0x3e, 0x5f, 0x15, 0x24, 0xfb,
-	0xd0, 0x38, 0x24, 0x8b, 0xb7, 0xbd, 0x01, 0xf6, 0x2c, 0x79, 0xa8, 0x3c, 0x94, 0xa5, 0x1b, 0xdf,
-	0xc2, 0xd9, 0x4f, 0xb6, 0xf8, 0xca, 0xdf, 0xab, 0x50, 0xb5, 0xa7, 0xec, 0x3e, 0xbd, 0x39, 0xda,
-	0x09, 0xcc, 0x49, 0xc6, 0x2e, 0xc5, 0x12, 0x59, 0xd3, 0xca, 0x4d, 0x64, 0x6d, 0x4d, 0x64, 0x8d,
-	0x52, 0x13, 0x19, 0xa7, 0x1b, 0xb9, 0x94, 0xf9, 0xaa, 0x36, 0x35, 0x36, 0x85, 0xf3, 0x31, 0xd2,
-	0xd0, 0x75, 0x31, 0x22, 0xee, 0xac, 0x70, 0xa7, 0xa9, 0x8e, 0x6a, 0x35, 0x37, 0x5a, 0xdf, 0x29,
-	0x65, 0x6a, 0xac, 0x0b, 0xb5, 0x89, 0x52, 0x31, 0x96, 0x5e, 0x4b, 0x0f, 0xea, 0x2f, 0xd1, 0x22,
-	0x35, 0x62, 0x59, 0xb2, 0x0b, 0xb5, 0x99, 0x14, 0xeb, 0xd2, 0x60, 0xfb, 0x0e, 0xfe, 0x4c, 0x42,
-	0x4f, 0xb0, 0x41, 0x6a, 0x3e, 0x9a, 0xe7, 0x2f, 0x34, 0xcb, 0x1c, 0x3b, 0x37, 0xdb, 0xe8, 0xec,
-	0xd5, 0x9a, 0x9a, 0xf3, 0x2f, 0x4b, 0x76, 0x3e, 0x03, 0x00, 0x00, 0xff, 0xff, 0x91, 0x0d, 0x5e,
-	0x79, 0xe5, 0x03, 0x00, 0x00,
+	// 497 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xcf, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0x9d, 0xaa, 0x2d, 0xd2, 0x80, 0xd2, 0x74, 0x0b, 0x01, 0x16, 0x89, 0x83, 0x4f, 0x9c,
+	0x52, 0x94, 0x4a, 0xb4, 0x4a, 0x45, 0xa4, 0xc8, 0x2a, 0x51, 0x45, 0x68, 0xa3, 0xa4, 0x88, 0x23,
+	0xda, 0xd8, 0x13, 0xd7, 0xc2, 0xf6, 0x86, 0xdd, 0xd9, 0x48, 0x3e, 0xf2, 0x06, 0x5c, 0x78, 0x4f,
+	0x1e, 0x01, 0xc5, 0xff, 0xd2, 0xba, 0x2d, 0x38, 0x9c, 0xac, 0x99, 0x6f, 0x7e, 0xdf, 0xcc, 0xae,
+	0x47, 0x0b, 0x4d, 0x8d, 0x6a, 0x19, 0xb8, 0xa8, 0x3b, 0x0b, 0x25, 0x49, 0xb2, 0x9d, 0xf4, 0xc3,
+	0x9b, 0x11, 0x6a, 0x2d, 0xfc, 0x22, 0xcd, 0x5f, 0xf9, 0x52, 0xfa, 0x21, 0x1e, 0xa6, 0xd1, 0xcc,
+	0xcc, 0x0f, 0x31, 0x5a, 0x50, 0x92, 0x89, 0xdd, 0x9f, 0x0d, 0xd8, 0x1f, 0x6b, 0x34, 0x9e, 0x8c,
+	0x93, 0x68, 0x9a, 0x68, 0xc2, 0xc8, 0x19, 0xb0, 0x53, 0x38, 0x18, 0x62, 0x8c, 0x4a, 0x10, 0x3a,
+	0xa8, 0x28, 0x98, 0x07, 0xae, 0x20, 0x64, 0xcd, 0x0c, 0xea, 0x7c, 0xca, 0x1a, 0xf0, 0x4a, 0x6c,
+	0x5b, 0x6f, 0x1a, 0x6f, 0x1b, 0xac, 0x0f, 0xed, 0x7b, 0xe0, 0xaf, 0x67, 0x4e, 0x3d, 0xbe, 0xfb,
+	0x7b, 0x0b, 0xf6, 0x2a, 0x23, 0xb1, 0x23, 0x78, 0x5c, 0x78, 0x5e, 0x24, 0x51, 0xcd, 0x41, 0xde,
+	0x41, 0xf3, 0x06, 0x54, 0x7b, 0x00, 0x76, 0x02, 0xad, 0xcb, 0x19, 0x89, 0x20, 0x76, 0x14, 0x7a,
+	0x18, 0x53, 0x20, 0xc2, 0x9a, 0xe4, 0x29, 0x1c, 0x54, 0xc9, 0xfa, 0x6d, 0x7b, 0xc0, 0xae, 0x94,
+	0x88, 0xf5, 0x1c, 0xd5, 0xc6, 0x8d, 0xdf, 0xc3, 0xb3, 0xbb, 0x6c, 0xfd, 0x2b, 0xff, 0xb5, 0x0d,
+	0x5b, 0xce, 0x28, 0xfb, 0x73, 0xb4, 0x36, 0x98, 0x92, 0x32, 0x2e, 0x19, 0x85, 0xec, 0x49, 0x8e,
+	0x39, 0xa3, 0x4b, 0xe5, 0xf3, 0xa7, 0x45, 0xa4, 0xd0, 0x2b, 0x6b, 0x6c, 0x8b, 0x8d, 0xe0, 0xc5,
+	0x10, 0x69, 0xe0, 0xba, 0xb8, 0x20, 0x31, 0x0b, 0x71, 0xed, 0xa4, 0x59, 0xbb, 0x93, 0xad, 0x61,
+	0xa7, 0x58, 0xc3, 0xce, 0xd9, 0x6a, 0x0d, 0x79, 0x3b, 0xf7, 0xba, 0x4d, 0x69, 0xdb, 0x62, 0xc7,
+	0xb0, 0x77, 0xae, 0xb5, 0xc1, 0x8d, 0x2f, 0xe3, 0x04, 0x5a, 0x9f, 0x17, 0x9e, 0xa0, 0xcd, 0xc9,
+	0x63, 0xd8, 0x1b, 0x2b, 0xb9, 0xfc, 0xaf, 0x96, 0x15, 0x50, 0xd7, 0x24, 0x3f, 0x42, 0x6b, 0x82,
+	0x4b, 0xf9, 0xed, 0x66, 0xcf, 0x97, 0xe5, 0x6d, 0x57, 0x25, 0xce, 0x4b, 0x69, 0xe0, 0xba, 0x26,
+	0x32, 0xa1, 0x20, 0xa9, 0xb2, 0xd3, 0xda, 0x16, 0xbb, 0x80, 0xfd, 0x21, 0xd2, 0x97, 0x80, 0x62,
+	0xd4, 0x3a, 0xcb, 0x6a, 0xf6, 0xba, 0x44, 0x6e, 0x0b, 0x13, 0xfc, 0x6e, 0x50, 0x13, 0x7f, 0xfe,
+	0x80, 0x6e, 0x5b, 0xdd, 0x09, 0xb4, 0x9c, 0xd1, 0xd5, 0xb5, 0x42, 0x7d, 0x2d, 0x43, 0x6f, 0x2c,
+	0x14, 0x25, 0xac, 0x0f, 0x3b, 0x13, 0x69, 0x62, 0x8f, 0xad, 0xb9, 0xb2, 0x22, 0x15, 0x38, 0xbf,
+	0x2b, 0xe4, 0x67, 0x5e, 0x79, 0xfe, 0x68, 0xc0, 0xa3, 0x29, 0x6a, 0x1d, 0xc8, 0x98, 0xf5, 0x00,
+	0x86, 0x48, 0x45, 0xf4, 0xd0, 0x8a, 0xb0, 0xdc, 0x2f, 0xaf, 0x3b, 0x8f, 0xe7, 0xd2, 0xb6, 0x58,
+	0x0f, 0x76, 0x47, 0xd2, 0x97, 0x86, 0xfe, 0xb2, 0x5a, 0xf7, 0xe6, 0x6d, 0xab, 0xfb, 0x01, 0xb6,
+	0x57, 0x2e, 0xac, 0xbf, 0x7a, 0x21, 0x68, 0x9a, 0x3d, 0xa3, 0x69, 0xe6, 0xdf, 0x33, 0x94, 0xb5,
+	0xb6, 0x35, 0xdb, 0x4d, 0x93, 0x47, 0x7f, 0x06, 0x00, 0x84, 0x85, 0x65, 0xac, 0x8a, 0x05, 0x00,
+	0x00,
 }
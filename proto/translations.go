@@ -20,11 +20,13 @@ package proto
 import (
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/xlab-si/emmy/crypto/cl"
 	"github.com/xlab-si/emmy/crypto/common"
 	"github.com/xlab-si/emmy/crypto/df"
 	"github.com/xlab-si/emmy/crypto/ec"
+	"github.com/xlab-si/emmy/crypto/encryption"
 	"github.com/xlab-si/emmy/crypto/qr"
 	"github.com/xlab-si/emmy/crypto/schnorr"
 )
@@ -69,52 +71,22 @@ func ToPbCredRequest(r *cl.CredRequest) *CLCredReq {
 		commitmentsOfAttrs[i] = a.Bytes()
 	}
 
-	pData := make([][]byte, len(r.NymProof.ProofData))
-	for i, p := range r.NymProof.ProofData {
-		pData[i] = p.Bytes()
-	}
-	nymProof := &FiatShamir{
-		ProofRandomData: r.NymProof.ProofRandomData.Bytes(),
-		Challenge:       r.NymProof.Challenge.Bytes(),
-		ProofData:       pData,
-	}
-
-	uData := make([]string, len(r.UProof.ProofData))
-	for i, p := range r.UProof.ProofData {
-		uData[i] = p.String()
-	}
-	UProof := &FiatShamirAlsoNeg{
-		ProofRandomData: r.UProof.ProofRandomData.Bytes(),
-		Challenge:       r.UProof.Challenge.Bytes(),
-		ProofData:       uData,
-	}
-
-	proofs := make([]*FiatShamir, len(r.CommitmentsOfAttrsProofs))
-	for i, proof := range r.CommitmentsOfAttrsProofs {
-		pData = make([][]byte, 2)
-		pData[0] = proof.ProofData1.Bytes()
-		pData[1] = proof.ProofData2.Bytes()
-		fs := &FiatShamir{
-			ProofRandomData: proof.ProofRandomData.Bytes(),
-			Challenge:       proof.Challenge.Bytes(),
-			ProofData:       pData,
-		}
-		proofs[i] = fs
-	}
-
 	return &CLCredReq{
 		Nym:                      r.Nym.Bytes(),
 		KnownAttrs:               knownAttrs,
 		CommitmentsOfAttrs:       commitmentsOfAttrs,
-		NymProof:                 nymProof,
+		NymProof:                 toPbNymProof(r.NymProof),
 		U:                        r.U.Bytes(),
-		UProof:                   UProof,
-		CommitmentsOfAttrsProofs: proofs,
+		UProof:                   ToPbRepresentationProof(r.UProof),
+		CommitmentsOfAttrsProofs: toPbOpeningProofs(r.CommitmentsOfAttrsProofs),
 		Nonce:                    r.Nonce.Bytes(),
 	}
 }
 
 func (r *CLCredReq) GetNativeType() (*cl.CredRequest, error) {
+	if r.NymProof == nil {
+		return nil, fmt.Errorf("credential request misses the proof of the nym")
+	}
 	nym := new(big.Int).SetBytes(r.Nym)
 	knownAttrs := make([]*big.Int, len(r.KnownAttrs))
 	for i, a := range r.KnownAttrs {
@@ -125,102 +97,181 @@ func (r *CLCredReq) GetNativeType() (*cl.CredRequest, error) {
 		commitmentsOfAttrs[i] = new(big.Int).SetBytes(a)
 	}
 
-	pData := make([]*big.Int, len(r.NymProof.ProofData))
-	for i, p := range r.NymProof.ProofData {
-		pData[i] = new(big.Int).SetBytes(p)
-	}
-	nymProof := schnorr.NewProof(new(big.Int).SetBytes(r.NymProof.ProofRandomData),
-		new(big.Int).SetBytes(r.NymProof.Challenge), pData)
+	nymProof := getNativeNymProof(r.NymProof)
 
 	U := new(big.Int).SetBytes(r.U)
 
-	pData = make([]*big.Int, len(r.UProof.ProofData))
-	for i, p := range r.UProof.ProofData {
-		si, success := new(big.Int).SetString(p, 10)
-		if !success {
-			return nil, fmt.Errorf("error when initializing big.Int from string")
-		}
-		pData[i] = si
+	UProof, err := r.UProof.GetNativeType()
+	if err != nil {
+		return nil, err
 	}
-	UProof := qr.NewRepresentationProof(new(big.Int).SetBytes(r.UProof.ProofRandomData),
-		new(big.Int).SetBytes(r.UProof.Challenge), pData)
 
-	commitmentsOfAttrsProofs := make([]*df.OpeningProof, len(r.CommitmentsOfAttrsProofs))
-	for i, proof := range r.CommitmentsOfAttrsProofs {
-		openingProof := df.NewOpeningProof(new(big.Int).SetBytes(proof.ProofRandomData),
-			new(big.Int).SetBytes(proof.Challenge), new(big.Int).SetBytes(proof.ProofData[0]),
-			new(big.Int).SetBytes(proof.ProofData[1]))
-		commitmentsOfAttrsProofs[i] = openingProof
+	commitmentsOfAttrsProofs, err := getNativeOpeningProofs(r.CommitmentsOfAttrsProofs)
+	if err != nil {
+		return nil, err
 	}
 
 	return cl.NewCredRequest(nym, knownAttrs, commitmentsOfAttrs, nymProof, U, UProof,
 		commitmentsOfAttrsProofs, new(big.Int).SetBytes(r.Nonce)), nil
 }
 
-func ToPbCLCredential(c *cl.Cred, AProof *qr.RepresentationProof) *CLCredential {
-	AProofFS := &FiatShamirAlsoNeg{
-		ProofRandomData: AProof.ProofRandomData.Bytes(),
-		Challenge:       AProof.Challenge.Bytes(),
-		ProofData:       []string{AProof.ProofData[0].String()},
+func toPbNymProof(proof *schnorr.Proof) *FiatShamir {
+	pData := make([][]byte, len(proof.ProofData))
+	for i, p := range proof.ProofData {
+		pData[i] = p.Bytes()
+	}
+
+	return &FiatShamir{
+		ProofRandomData: proof.ProofRandomData.Bytes(),
+		Challenge:       proof.Challenge.Bytes(),
+		ProofData:       pData,
+	}
+}
+
+func getNativeNymProof(proof *FiatShamir) *schnorr.Proof {
+	pData := make([]*big.Int, len(proof.ProofData))
+	for i, p := range proof.ProofData {
+		pData[i] = new(big.Int).SetBytes(p)
 	}
 
+	return schnorr.NewProof(new(big.Int).SetBytes(proof.ProofRandomData),
+		new(big.Int).SetBytes(proof.Challenge), pData)
+}
+
+func ToPbCLCredential(c *cl.Cred, AProof *qr.RepresentationProof) *CLCredential {
 	return &CLCredential{
 		A:      c.A.Bytes(),
 		E:      c.E.Bytes(),
 		V11:    c.V11.Bytes(),
-		AProof: AProofFS,
+		AProof: ToPbRepresentationProof(AProof),
 	}
 }
 
 func (c *CLCredential) GetNativeType() (*cl.Cred, *qr.RepresentationProof, error) {
-	si, success := new(big.Int).SetString(c.AProof.ProofData[0], 10)
-	if !success {
-		return nil, nil, fmt.Errorf("error when initializing big.Int from string")
+	AProof, err := c.AProof.GetNativeType()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	AProof := qr.NewRepresentationProof(new(big.Int).SetBytes(c.AProof.ProofRandomData),
-		new(big.Int).SetBytes(c.AProof.Challenge), []*big.Int{si})
-
 	return cl.NewCred(new(big.Int).SetBytes(c.A), new(big.Int).SetBytes(c.E),
 		new(big.Int).SetBytes(c.V11)), AProof, nil
 }
 
-func ToPbUpdateCLCredential(nym, nonce *big.Int, newKnownAttrs []*big.Int) *UpdateCLCredential {
-	knownAttrs := make([][]byte, len(newKnownAttrs))
-	for i, a := range newKnownAttrs {
+// ToPbRepresentationProof translates the proof into FiatShamirAlsoNeg message - proof data
+// can be negative, so it is encoded as decimal strings.
+func ToPbRepresentationProof(p *qr.RepresentationProof) *FiatShamirAlsoNeg {
+	return &FiatShamirAlsoNeg{
+		ProofRandomData: p.ProofRandomData.Bytes(),
+		Challenge:       p.Challenge.Bytes(),
+		ProofData:       bigIntsToStrings(p.ProofData),
+	}
+}
+
+// GetNativeType returns the representation proof translated by ToPbRepresentationProof.
+func (p *FiatShamirAlsoNeg) GetNativeType() (*qr.RepresentationProof, error) {
+	if p == nil {
+		return nil, fmt.Errorf("representation proof is missing")
+	}
+	proofData, err := stringsToBigInts(p.ProofData)
+	if err != nil {
+		return nil, err
+	}
+
+	return qr.NewRepresentationProof(new(big.Int).SetBytes(p.ProofRandomData),
+		new(big.Int).SetBytes(p.Challenge), proofData), nil
+}
+
+// toPbOpeningProofs translates proofs of the knowledge of commitment openings
+// into FiatShamir messages.
+func toPbOpeningProofs(proofs []*df.OpeningProof) []*FiatShamir {
+	pbProofs := make([]*FiatShamir, len(proofs))
+	for i, proof := range proofs {
+		pbProofs[i] = &FiatShamir{
+			ProofRandomData: proof.ProofRandomData.Bytes(),
+			Challenge:       proof.Challenge.Bytes(),
+			ProofData:       [][]byte{proof.ProofData1.Bytes(), proof.ProofData2.Bytes()},
+		}
+	}
+
+	return pbProofs
+}
+
+// getNativeOpeningProofs translates FiatShamir messages into proofs of the
+// knowledge of commitment openings.
+func getNativeOpeningProofs(pbProofs []*FiatShamir) ([]*df.OpeningProof, error) {
+	proofs := make([]*df.OpeningProof, len(pbProofs))
+	for i, proof := range pbProofs {
+		if len(proof.ProofData) != 2 {
+			return nil, fmt.Errorf("proof of commitment opening needs two proof data values")
+		}
+		proofs[i] = df.NewOpeningProof(new(big.Int).SetBytes(proof.ProofRandomData),
+			new(big.Int).SetBytes(proof.Challenge), new(big.Int).SetBytes(proof.ProofData[0]),
+			new(big.Int).SetBytes(proof.ProofData[1]))
+	}
+
+	return proofs, nil
+}
+
+// ToPbUpdateCLCredential translates the request for a CL credential with new attribute values.
+func ToPbUpdateCLCredential(ur *cl.CredUpdateRequest) *UpdateCLCredential {
+	knownAttrs := make([][]byte, len(ur.NewKnownAttrs))
+	for i, a := range ur.NewKnownAttrs {
 		knownAttrs[i] = a.Bytes()
 	}
 
-	return &UpdateCLCredential{
-		Nym:           nym.Bytes(),
-		Nonce:         nonce.Bytes(),
+	u := &UpdateCLCredential{
+		Nym:           ur.Nym.Bytes(),
+		NymProof:      toPbNymProof(ur.NymProof),
+		Nonce:         ur.Nonce.Bytes(),
 		NewKnownAttrs: knownAttrs,
 	}
+	if ur.CommitmentsOfAttrs != nil {
+		u.CommitmentsOfAttrs = make([][]byte, len(ur.CommitmentsOfAttrs))
+		for i, c := range ur.CommitmentsOfAttrs {
+			u.CommitmentsOfAttrs[i] = c.Bytes()
+		}
+		u.CommitmentsOfAttrsProofs = toPbOpeningProofs(ur.CommitmentsOfAttrsProofs)
+	}
+
+	return u
 }
 
-func (u *UpdateCLCredential) GetNativeType() (*big.Int, *big.Int, []*big.Int) {
+// GetNativeType returns the request for the updated credential. The new commitments of
+// attributes and the proofs of their openings are nil when the commitments of attributes
+// are not to be updated.
+func (u *UpdateCLCredential) GetNativeType() (*cl.CredUpdateRequest, error) {
+	if u.NymProof == nil {
+		return nil, fmt.Errorf("credential update request misses the proof of the nym")
+	}
 	attrs := make([]*big.Int, len(u.NewKnownAttrs))
 	for i, a := range u.NewKnownAttrs {
 		attrs[i] = new(big.Int).SetBytes(a)
 	}
 
-	return new(big.Int).SetBytes(u.Nym), new(big.Int).SetBytes(u.Nonce), attrs
+	var commitmentsOfAttrs []*big.Int
+	var proofs []*df.OpeningProof
+	if len(u.CommitmentsOfAttrs) > 0 {
+		commitmentsOfAttrs = make([]*big.Int, len(u.CommitmentsOfAttrs))
+		for i, c := range u.CommitmentsOfAttrs {
+			commitmentsOfAttrs[i] = new(big.Int).SetBytes(c)
+		}
+		var err error
+		proofs, err = getNativeOpeningProofs(u.CommitmentsOfAttrsProofs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cl.NewCredUpdateRequest(new(big.Int).SetBytes(u.Nym), getNativeNymProof(u.NymProof),
+		attrs, commitmentsOfAttrs, proofs, new(big.Int).SetBytes(u.Nonce)), nil
 }
 
 func ToPbProveCLCredential(A *big.Int, proof *qr.RepresentationProof,
-	knownAttrs, commitmentsOfAttrs []*big.Int,
+	nonRevProof *cl.NonRevocationProof, predicateProofs []*cl.PredicateProof,
+	setMembershipProofs []*cl.SetMembershipProof, domainPseudonymProof *cl.DomainPseudonymProof,
+	escrowProof *cl.EscrowProof, knownAttrs, commitmentsOfAttrs []*big.Int,
 	revealedKnownAttrsIndices, revealedCommitmentsOfAttrsIndices []int) *ProveCLCredential {
 
-	pData := make([]string, len(proof.ProofData))
-	for i, p := range proof.ProofData {
-		pData[i] = p.String()
-	}
-	proofFS := &FiatShamirAlsoNeg{
-		ProofRandomData: proof.ProofRandomData.Bytes(),
-		Challenge:       proof.Challenge.Bytes(),
-		ProofData:       pData,
-	}
-
 	kAttrs := make([][]byte, len(knownAttrs))
 	for i, a := range knownAttrs {
 		kAttrs[i] = a.Bytes()
@@ -241,13 +292,28 @@ func ToPbProveCLCredential(A *big.Int, proof *qr.RepresentationProof,
 		revealedCommitmentsOfAttrs[i] = int32(a)
 	}
 
+	pbPredicateProofs := make([]*CLPredicateProof, len(predicateProofs))
+	for i, p := range predicateProofs {
+		pbPredicateProofs[i] = ToPbCLPredicateProof(p)
+	}
+
+	pbSetMembershipProofs := make([]*CLSetMembershipProof, len(setMembershipProofs))
+	for i, p := range setMembershipProofs {
+		pbSetMembershipProofs[i] = ToPbCLSetMembershipProof(p)
+	}
+
 	return &ProveCLCredential{
 		A:                          A.Bytes(),
-		Proof:                      proofFS,
+		Proof:                      ToPbRepresentationProof(proof),
 		KnownAttrs:                 kAttrs,
 		CommitmentsOfAttrs:         cAttrs,
 		RevealedKnownAttrs:         revealedKnownAttrs,
 		RevealedCommitmentsOfAttrs: revealedCommitmentsOfAttrs,
+		NonRevocationProof:         ToPbCLNonRevocationProof(nonRevProof),
+		PredicateProofs:            pbPredicateProofs,
+		SetMembershipProofs:        pbSetMembershipProofs,
+		DomainPseudonymProof:       ToPbCLDomainPseudonymProof(domainPseudonymProof),
+		EscrowProof:                ToPbCLEscrowProof(escrowProof),
 	}
 }
 
@@ -263,16 +329,10 @@ func (p *ProveCLCredential) GetNativeType() (*big.Int, *qr.RepresentationProof,
 		cAttrs[i] = new(big.Int).SetBytes(a)
 	}
 
-	pData := make([]*big.Int, len(p.Proof.ProofData))
-	for i, p := range p.Proof.ProofData {
-		si, success := new(big.Int).SetString(p, 10)
-		if !success {
-			return nil, nil, nil, nil, nil, nil, fmt.Errorf("error when initializing big.Int from string")
-		}
-		pData[i] = si
+	proof, err := p.Proof.GetNativeType()
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
 	}
-	proof := qr.NewRepresentationProof(new(big.Int).SetBytes(p.Proof.ProofRandomData),
-		new(big.Int).SetBytes(p.Proof.Challenge), pData)
 
 	revealedKnownAttrsIndices := make([]int, len(p.RevealedKnownAttrs))
 	for i, a := range p.RevealedKnownAttrs {
@@ -287,3 +347,443 @@ func (p *ProveCLCredential) GetNativeType() (*big.Int, *qr.RepresentationProof,
 	return new(big.Int).SetBytes(p.A), proof, attrs, cAttrs, revealedKnownAttrsIndices,
 		revealedCommitmentsOfAttrsIndices, nil
 }
+
+func ToPbCLCredProof(p *cl.CredProof) *ProveCLCredential {
+	return ToPbProveCLCredential(p.RandCred.A, p.Proof, p.NonRevProof, p.PredicateProofs,
+		p.SetMembershipProofs, p.DomainPseudonymProof, p.EscrowProof, p.RevealedKnownAttrs,
+		p.RevealedCommitmentsOfAttrs, p.RevealedKnownAttrsIndices,
+		p.RevealedCommitmentsOfAttrsIndices)
+}
+
+// GetCredProof returns all the data of the credential proof (including non-revocation,
+// predicate, set membership, domain pseudonym and escrow proofs) as cl.CredProof.
+func (p *ProveCLCredential) GetCredProof() (*cl.CredProof, error) {
+	A, proof, knownAttrs, commitmentsOfAttrs, revealedKnownAttrsIndices,
+		revealedCommitmentsOfAttrsIndices, err := p.GetNativeType()
+	if err != nil {
+		return nil, err
+	}
+
+	nonRevProof, err := p.GetNonRevocationProof().GetNativeType()
+	if err != nil {
+		return nil, err
+	}
+
+	predicateProofs := make([]*cl.PredicateProof, len(p.GetPredicateProofs()))
+	for i, pp := range p.GetPredicateProofs() {
+		predicateProofs[i], err = pp.GetNativeType()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	setMembershipProofs := make([]*cl.SetMembershipProof, len(p.GetSetMembershipProofs()))
+	for i, sp := range p.GetSetMembershipProofs() {
+		setMembershipProofs[i], err = sp.GetNativeType()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	escrowProof, err := p.GetEscrowProof().GetNativeType()
+	if err != nil {
+		return nil, err
+	}
+
+	return &cl.CredProof{
+		RandCred:                          &cl.Cred{A: A},
+		Proof:                             proof,
+		NonRevProof:                       nonRevProof,
+		PredicateProofs:                   predicateProofs,
+		SetMembershipProofs:               setMembershipProofs,
+		DomainPseudonymProof:              p.GetDomainPseudonymProof().GetNativeType(),
+		EscrowProof:                       escrowProof,
+		RevealedKnownAttrsIndices:         revealedKnownAttrsIndices,
+		RevealedCommitmentsOfAttrsIndices: revealedCommitmentsOfAttrsIndices,
+		RevealedKnownAttrs:                knownAttrs,
+		RevealedCommitmentsOfAttrs:        commitmentsOfAttrs,
+	}, nil
+}
+
+// ToPbCLWitness returns nil if w is nil (the issuer does not support revocation).
+func ToPbCLWitness(w *cl.Witness) *CLWitness {
+	if w == nil {
+		return nil
+	}
+
+	return &CLWitness{
+		W:     w.W.Bytes(),
+		Value: w.Value.Bytes(),
+		Epoch: int32(w.Epoch),
+	}
+}
+
+func (w *CLWitness) GetNativeType() *cl.Witness {
+	if w == nil {
+		return nil
+	}
+
+	return cl.NewWitness(new(big.Int).SetBytes(w.W), new(big.Int).SetBytes(w.Value),
+		int(w.Epoch))
+}
+
+// ToPbCLNonRevocationProof returns nil if p is nil (the issuer does not support revocation).
+func ToPbCLNonRevocationProof(p *cl.NonRevocationProof) *CLNonRevocationProof {
+	if p == nil {
+		return nil
+	}
+
+	commitments := make([][]byte, len(p.Commitments))
+	for i, c := range p.Commitments {
+		commitments[i] = c.Bytes()
+	}
+
+	proofRandomData := make([][]byte, len(p.ProofRandomData))
+	for i, t := range p.ProofRandomData {
+		proofRandomData[i] = t.Bytes()
+	}
+
+	proofData := make([]string, len(p.ProofData))
+	for i, s := range p.ProofData {
+		proofData[i] = s.String()
+	}
+
+	return &CLNonRevocationProof{
+		Epoch:           int32(p.Epoch),
+		Commitments:     commitments,
+		ProofRandomData: proofRandomData,
+		ProofData:       proofData,
+	}
+}
+
+func (p *CLNonRevocationProof) GetNativeType() (*cl.NonRevocationProof, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	commitments := make([]*big.Int, len(p.Commitments))
+	for i, c := range p.Commitments {
+		commitments[i] = new(big.Int).SetBytes(c)
+	}
+
+	proofRandomData := make([]*big.Int, len(p.ProofRandomData))
+	for i, t := range p.ProofRandomData {
+		proofRandomData[i] = new(big.Int).SetBytes(t)
+	}
+
+	proofData := make([]*big.Int, len(p.ProofData))
+	for i, s := range p.ProofData {
+		si, success := new(big.Int).SetString(s, 10)
+		if !success {
+			return nil, fmt.Errorf("error when initializing big.Int from string")
+		}
+		proofData[i] = si
+	}
+
+	return cl.NewNonRevocationProof(int(p.Epoch), commitments, proofRandomData, proofData), nil
+}
+
+func ToPbCLAccumulatorUpdate(u *cl.AccumulatorUpdate) *CLAccumulatorUpdate {
+	return &CLAccumulatorUpdate{
+		Epoch: int32(u.Epoch),
+		E:     u.E.Bytes(),
+		Value: u.Value.Bytes(),
+	}
+}
+
+func (u *CLAccumulatorUpdate) GetNativeType() *cl.AccumulatorUpdate {
+	return cl.NewAccumulatorUpdate(int(u.Epoch), new(big.Int).SetBytes(u.E),
+		new(big.Int).SetBytes(u.Value))
+}
+
+func ToPbCLPredicateProof(p *cl.PredicateProof) *CLPredicateProof {
+	return &CLPredicateProof{
+		AttrIndex:             int32(p.Predicate.AttrIndex),
+		Min:                   p.Predicate.Min.String(),
+		Max:                   p.Predicate.Max.String(),
+		Commitment:            p.Commitment.Bytes(),
+		ProofRandomData:       p.ProofRandomData.Bytes(),
+		ProofData:             p.ProofData.String(),
+		SmallCommitments1:     bigIntsToBytes(p.SmallCommitments1),
+		BigCommitments1:       bigIntsToBytes(p.BigCommitments1),
+		SmallCommitments2:     bigIntsToBytes(p.SmallCommitments2),
+		BigCommitments2:       bigIntsToBytes(p.BigCommitments2),
+		RangeProofRandomData1: bigIntsToBytes(p.RangeProofRandomData1),
+		RangeProofRandomData2: bigIntsToBytes(p.RangeProofRandomData2),
+		RangeProofData1:       bigIntsToStrings(p.RangeProofData1),
+		RangeProofData2:       bigIntsToStrings(p.RangeProofData2),
+	}
+}
+
+func (p *CLPredicateProof) GetNativeType() (*cl.PredicateProof, error) {
+	vals, err := stringsToBigInts([]string{p.Min, p.Max, p.ProofData})
+	if err != nil {
+		return nil, err
+	}
+	rangeProofData1, err := stringsToBigInts(p.RangeProofData1)
+	if err != nil {
+		return nil, err
+	}
+	rangeProofData2, err := stringsToBigInts(p.RangeProofData2)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cl.PredicateProof{
+		Predicate:             cl.NewRangePredicate(int(p.AttrIndex), vals[0], vals[1]),
+		Commitment:            new(big.Int).SetBytes(p.Commitment),
+		ProofRandomData:       new(big.Int).SetBytes(p.ProofRandomData),
+		ProofData:             vals[2],
+		SmallCommitments1:     bytesToBigInts(p.SmallCommitments1),
+		BigCommitments1:       bytesToBigInts(p.BigCommitments1),
+		SmallCommitments2:     bytesToBigInts(p.SmallCommitments2),
+		BigCommitments2:       bytesToBigInts(p.BigCommitments2),
+		RangeProofRandomData1: bytesToBigInts(p.RangeProofRandomData1),
+		RangeProofRandomData2: bytesToBigInts(p.RangeProofRandomData2),
+		RangeProofData1:       rangeProofData1,
+		RangeProofData2:       rangeProofData2,
+	}, nil
+}
+
+func ToPbCLSetMembershipProof(p *cl.SetMembershipProof) *CLSetMembershipProof {
+	return &CLSetMembershipProof{
+		AttrIndex:         int32(p.SetMembership.AttrIndex),
+		Values:            bigIntsToBytes(p.SetMembership.Values),
+		Commitment:        p.Commitment.Bytes(),
+		ProofRandomData:   p.ProofRandomData.Bytes(),
+		ProofData:         p.ProofData.String(),
+		OrProofRandomData: bigIntsToBytes(p.OrProofRandomData),
+		OrChallenges:      bigIntsToBytes(p.OrChallenges),
+		OrProofData:       bigIntsToStrings(p.OrProofData),
+	}
+}
+
+func (p *CLSetMembershipProof) GetNativeType() (*cl.SetMembershipProof, error) {
+	proofData, ok := new(big.Int).SetString(p.ProofData, 10)
+	if !ok {
+		return nil, fmt.Errorf("error when initializing big.Int from string")
+	}
+	orProofData, err := stringsToBigInts(p.OrProofData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cl.SetMembershipProof{
+		SetMembership:     cl.NewSetMembership(int(p.AttrIndex), bytesToBigInts(p.Values)),
+		Commitment:        new(big.Int).SetBytes(p.Commitment),
+		ProofRandomData:   new(big.Int).SetBytes(p.ProofRandomData),
+		ProofData:         proofData,
+		OrProofRandomData: bytesToBigInts(p.OrProofRandomData),
+		OrChallenges:      bytesToBigInts(p.OrChallenges),
+		OrProofData:       orProofData,
+	}, nil
+}
+
+// ToPbCLDomainPseudonymProof returns nil if p is nil (no domain pseudonym is required).
+func ToPbCLDomainPseudonymProof(p *cl.DomainPseudonymProof) *CLDomainPseudonymProof {
+	if p == nil {
+		return nil
+	}
+
+	return &CLDomainPseudonymProof{
+		Scope:           p.Scope,
+		Pseudonym:       p.Pseudonym.Bytes(),
+		ProofRandomData: p.ProofRandomData.Bytes(),
+	}
+}
+
+func (p *CLDomainPseudonymProof) GetNativeType() *cl.DomainPseudonymProof {
+	if p == nil {
+		return nil
+	}
+
+	return &cl.DomainPseudonymProof{
+		Scope:           p.Scope,
+		Pseudonym:       new(big.Int).SetBytes(p.Pseudonym),
+		ProofRandomData: new(big.Int).SetBytes(p.ProofRandomData),
+	}
+}
+
+// ToPbCSPaillierPubKey returns nil if k is nil.
+func ToPbCSPaillierPubKey(k *encryption.CSPaillierPubKey) *CSPaillierPubKey {
+	if k == nil {
+		return nil
+	}
+
+	pbKey := &CSPaillierPubKey{
+		N:  k.N.Bytes(),
+		G:  k.G.Bytes(),
+		Y1: k.Y1.Bytes(),
+		Y2: k.Y2.Bytes(),
+		Y3: k.Y3.Bytes(),
+		K:  int32(k.K),
+		K1: int32(k.K1),
+	}
+	if k.Gamma != nil {
+		pbKey.DLogP = k.Gamma.P.Bytes()
+		pbKey.DLogG = k.Gamma.G.Bytes()
+		pbKey.DLogQ = k.Gamma.Q.Bytes()
+	}
+	if k.VerifiableEncGroupN != nil {
+		pbKey.VerifiableEncGroupN = k.VerifiableEncGroupN.Bytes()
+		pbKey.VerifiableEncGroupG1 = k.VerifiableEncGroupG1.Bytes()
+		pbKey.VerifiableEncGroupH1 = k.VerifiableEncGroupH1.Bytes()
+	}
+
+	return pbKey
+}
+
+func (k *CSPaillierPubKey) GetNativeType() *encryption.CSPaillierPubKey {
+	if k == nil {
+		return nil
+	}
+
+	return &encryption.CSPaillierPubKey{
+		N:  new(big.Int).SetBytes(k.N),
+		G:  new(big.Int).SetBytes(k.G),
+		Y1: new(big.Int).SetBytes(k.Y1),
+		Y2: new(big.Int).SetBytes(k.Y2),
+		Y3: new(big.Int).SetBytes(k.Y3),
+		Gamma: schnorr.NewGroupFromParams(new(big.Int).SetBytes(k.DLogP),
+			new(big.Int).SetBytes(k.DLogG), new(big.Int).SetBytes(k.DLogQ)),
+		VerifiableEncGroupN:  new(big.Int).SetBytes(k.VerifiableEncGroupN),
+		VerifiableEncGroupG1: new(big.Int).SetBytes(k.VerifiableEncGroupG1),
+		VerifiableEncGroupH1: new(big.Int).SetBytes(k.VerifiableEncGroupH1),
+		K:                    int(k.K),
+		K1:                   int(k.K1),
+	}
+}
+
+// ToPbCLEscrow returns nil if e is nil (no escrow is required).
+func ToPbCLEscrow(e *cl.Escrow) *CLEscrow {
+	if e == nil {
+		return nil
+	}
+
+	return &CLEscrow{
+		AttrIndex: int32(e.AttrIndex),
+		PubKey:    ToPbCSPaillierPubKey(e.PubKey),
+		Label:     e.Label,
+	}
+}
+
+func (e *CLEscrow) GetNativeType() *cl.Escrow {
+	if e == nil {
+		return nil
+	}
+
+	return cl.NewEscrow(int(e.AttrIndex), e.PubKey.GetNativeType(), e.Label)
+}
+
+// GetNativeAttrEqualities returns the attribute equalities of the proof request for the
+// proofs of the credentials of organizations orgNames (in this order). The equalities
+// for organizations whose credentials are not proved are left out.
+func (r *CLProofRequest) GetNativeAttrEqualities(orgNames []string) []*cl.AttrEquality {
+	position := func(name string) int {
+		for i, n := range orgNames {
+			if strings.EqualFold(n, name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var equalities []*cl.AttrEquality
+	for _, e := range r.GetAttrEqualities() {
+		cred1, cred2 := position(e.Org1), position(e.Org2)
+		if cred1 < 0 || cred2 < 0 || cred1 == cred2 {
+			continue
+		}
+		equalities = append(equalities, cl.NewAttrEquality(cred1, e.Attr1, cred2, e.Attr2))
+	}
+
+	return equalities
+}
+
+// ToPbCLEscrowProof returns nil if p is nil (no escrow is required).
+func ToPbCLEscrowProof(p *cl.EscrowProof) *CLEscrowProof {
+	if p == nil {
+		return nil
+	}
+
+	return &CLEscrowProof{
+		Escrow:          ToPbCLEscrow(p.Escrow),
+		U:               p.U.Bytes(),
+		E:               p.E.Bytes(),
+		V:               p.V.Bytes(),
+		ProofRandomData: bigIntsToBytes(p.ProofRandomData),
+		ProofData:       p.ProofData.String(),
+	}
+}
+
+func (p *CLEscrowProof) GetNativeType() (*cl.EscrowProof, error) {
+	if p == nil {
+		return nil, nil
+	}
+	proofData, ok := new(big.Int).SetString(p.ProofData, 10)
+	if !ok {
+		return nil, fmt.Errorf("error when initializing big.Int from string")
+	}
+
+	return &cl.EscrowProof{
+		Escrow:          p.Escrow.GetNativeType(),
+		U:               new(big.Int).SetBytes(p.U),
+		E:               new(big.Int).SetBytes(p.E),
+		V:               new(big.Int).SetBytes(p.V),
+		ProofRandomData: bytesToBigInts(p.ProofRandomData),
+		ProofData:       proofData,
+	}, nil
+}
+
+func bigIntsToBytes(vals []*big.Int) [][]byte {
+	b := make([][]byte, len(vals))
+	for i, v := range vals {
+		b[i] = v.Bytes()
+	}
+	return b
+}
+
+func bytesToBigInts(b [][]byte) []*big.Int {
+	vals := make([]*big.Int, len(b))
+	for i, v := range b {
+		vals[i] = new(big.Int).SetBytes(v)
+	}
+	return vals
+}
+
+func bigIntsToStrings(vals []*big.Int) []string {
+	s := make([]string, len(vals))
+	for i, v := range vals {
+		s[i] = v.String()
+	}
+	return s
+}
+
+func stringsToBigInts(s []string) ([]*big.Int, error) {
+	vals := make([]*big.Int, len(s))
+	for i, v := range s {
+		si, success := new(big.Int).SetString(v, 10)
+		if !success {
+			return nil, fmt.Errorf("error when initializing big.Int from string")
+		}
+		vals[i] = si
+	}
+	return vals, nil
+}
+
+func ToPbCLThresholdMessage(m *cl.ThresholdMessage) *CLThresholdMessage {
+	return &CLThresholdMessage{
+		From:   int32(m.From),
+		To:     int32(m.To),
+		Values: bigIntsToStrings(m.Values),
+	}
+}
+
+func (m *CLThresholdMessage) GetNativeType() (*cl.ThresholdMessage, error) {
+	values, err := stringsToBigInts(m.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.NewThresholdMessage(int(m.From), int(m.To), values...), nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package proto
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/crypto/qr"
+)
+
+func TestCLTranslations(t *testing.T) {
+	params := cl.GetDefaultParamSizes()
+	attrCount := cl.NewAttrCount(1, 1, 0)
+	org, err := cl.NewOrg(params, attrCount)
+	require.NoError(t, err)
+
+	rawCred := cl.NewRawCred(attrCount)
+	_ = rawCred.AddStrAttr("Name", "Jack", true)
+	_ = rawCred.AddInt64Attr("Age", 25, false)
+	credMgr, err := cl.NewCredManager(params, org.Keys.Pub,
+		org.Keys.Pub.GenerateUserMasterSecret(), rawCred)
+	require.NoError(t, err)
+	nonce, err := org.GetCredIssueNonce()
+	require.NoError(t, err)
+	credReq, err := credMgr.GetCredRequest(nonce)
+	require.NoError(t, err)
+
+	req, err := ToPbCredRequest(credReq).GetNativeType()
+	require.NoError(t, err)
+	assert.Equal(t, credReq, req)
+
+	res, err := org.IssueCred(req, nonce)
+	require.NoError(t, err)
+	cred, AProof, err := ToPbCLCredential(res.Cred, res.AProof).GetNativeType()
+	require.NoError(t, err)
+	assert.Equal(t, res.Cred, cred)
+	assert.Equal(t, res.AProof, AProof)
+
+	// proof data of representation proofs can be negative
+	proof := qr.NewRepresentationProof(big.NewInt(11), big.NewInt(7),
+		[]*big.Int{big.NewInt(-5), big.NewInt(3)})
+	p, err := ToPbRepresentationProof(proof).GetNativeType()
+	require.NoError(t, err)
+	assert.Equal(t, proof, p)
+
+	_, _, err = (&CLCredential{A: res.Cred.A.Bytes()}).GetNativeType()
+	assert.Error(t, err, "credential without the proof should not be translated")
+	_, err = (&CLCredReq{Nym: credReq.Nym.Bytes()}).GetNativeType()
+	assert.Error(t, err, "credential request without the proofs should not be translated")
+}
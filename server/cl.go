@@ -19,31 +19,48 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/xlab-si/emmy/config"
 	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/crypto/encryption"
 	pb "github.com/xlab-si/emmy/proto"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-func (s *Server) GetCredentialStructure(ctx context.Context, _ *empty.Empty) (*pb.CredStructure, error) {
-	s.Logger.Info("Client requested credential structure information")
+// CL_REVOCATION_TOKEN_METADATA is the key of the gRPC metadata holding the token which
+// permits the revocation of CL credentials (the token of the issuer or the admin token).
+const CL_REVOCATION_TOKEN_METADATA = "revocation-token"
 
-	structure, err := config.LoadCredentialStructure()
+// GetCredentialStructure returns the structure of the credentials issued by the requested
+// organization, together with the ID of the key under which they are currently issued.
+func (s *Server) GetCredentialStructure(ctx context.Context, req *pb.CLOrg) (*pb.CredStructure, error) {
+	s.Logger.Infof("Client requested credential structure information of %s", req.OrgName)
+
+	org, err := s.getActiveCLOrg(req.OrgName)
 	if err != nil {
 		return nil, err
 	}
 
-	attrs, attrCount, err := cl.ParseAttrs(structure)
+	attrs, attrCount, err := loadCLAttrs(req.OrgName)
+	if err != nil {
+		return nil, err
+	}
 	credAttrs := make([]*pb.CredAttribute, len(attrs))
 
 	for i, a := range attrs {
 		attr := &pb.Attribute{
-			Name:  a.GetName(),
-			Known: a.IsKnown(),
+			Name:   a.GetName(),
+			Known:  a.IsKnown(),
+			Hidden: a.IsHidden(),
 		}
 		switch a.(type) {
 		case *cl.StrAttr:
@@ -62,6 +79,39 @@ func (s *Server) GetCredentialStructure(ctx context.Context, _ *empty.Empty) (*p
 					},
 				},
 			}
+		case *cl.DateAttr:
+			credAttrs[i] = &pb.CredAttribute{
+				Type: &pb.CredAttribute_DateAttr{
+					DateAttr: &pb.DateAttribute{
+						Attr: attr,
+					},
+				},
+			}
+		case *cl.BoolAttr:
+			credAttrs[i] = &pb.CredAttribute{
+				Type: &pb.CredAttribute_BoolAttr{
+					BoolAttr: &pb.BoolAttribute{
+						Attr: attr,
+					},
+				},
+			}
+		case *cl.EnumAttr:
+			credAttrs[i] = &pb.CredAttribute{
+				Type: &pb.CredAttribute_EnumAttr{
+					EnumAttr: &pb.EnumAttribute{
+						Attr:   attr,
+						Values: a.(*cl.EnumAttr).Values,
+					},
+				},
+			}
+		case *cl.HashedStrAttr:
+			credAttrs[i] = &pb.CredAttribute{
+				Type: &pb.CredAttribute_HashedStringAttr{
+					HashedStringAttr: &pb.HashedStringAttribute{
+						Attr: attr,
+					},
+				},
+			}
 		}
 	}
 
@@ -70,21 +120,27 @@ func (s *Server) GetCredentialStructure(ctx context.Context, _ *empty.Empty) (*p
 		NCommitted: int32(attrCount.Committed),
 		NHidden:    int32(attrCount.Hidden),
 		Attributes: credAttrs,
+		KeyId:      org.Keys.Pub.GetID(),
 	}, nil
 }
 
 func (s *Server) GetAcceptableCredentials(ctx context.Context, _ *empty.Empty) (*pb.AcceptableCreds, error) {
 	s.Logger.Info("Client requested acceptable credentials information")
-	accCreds, err := config.LoadAcceptableCredentials()
+	policies, err := loadCLVerificationPolicies()
 	if err != nil {
 		return nil, err
 	}
 
 	var credentials []*pb.AcceptableCred
-	for name, attrs := range accCreds {
+	for name, policy := range policies {
+		p, err := json.Marshal(policy)
+		if err != nil {
+			return nil, err
+		}
 		cred := &pb.AcceptableCred{
 			OrgName:       name,
-			RevealedAttrs: attrs,
+			RevealedAttrs: policy.Revealed,
+			Policy:        string(p),
 		}
 		credentials = append(credentials, cred)
 	}
@@ -100,10 +156,14 @@ func (s *Server) IssueCredential(stream pb.CL_IssueCredentialServer) error {
 		return err
 	}
 
-	// TOD0: for known attributes IssueCredential should fill the values - attributes
-	// are stored (under registration key) in the DB and then obtained by Org.
-
 	initReq := req.GetRegKey()
+	// the organization is checked first, so that the registration key is not used up
+	// by a request for an organization which cannot issue the credential
+	org, err := s.getActiveCLOrg(initReq.OrgName)
+	if err != nil {
+		return err
+	}
+
 	regKeyOk, err := s.RegistrationManager.CheckRegistrationKey(initReq.RegKey)
 	if !regKeyOk || err != nil {
 		s.Logger.Debugf("registration key %s ok=%t, error=%v",
@@ -111,12 +171,18 @@ func (s *Server) IssueCredential(stream pb.CL_IssueCredentialServer) error {
 		return status.Error(codes.NotFound, "registration key verification failed")
 	}
 
-	org, err := cl.LoadOrg("../client/testdata/clPubKey.gob", "../client/testdata/clSecKey.gob")
+	// known attributes which were provisioned for the registration key are
+	// not taken from the client - the credential request needs to contain them
+	provisionedAttrs, err := s.RegistrationManager.GetAttributes(initReq.RegKey)
 	if err != nil {
 		return err
 	}
 
-	nonce := org.GetCredIssueNonce()
+	nonce, err := org.GetCredIssueNonce()
+	if err != nil {
+		s.Logger.Debug(err)
+		return status.Error(codes.Internal, "failed to obtain nonce")
+	}
 	resp := &pb.Message{
 		Content: &pb.Message_Bigint{
 			&pb.BigInt{
@@ -139,18 +205,27 @@ func (s *Server) IssueCredential(stream pb.CL_IssueCredentialServer) error {
 	if err != nil {
 		return err
 	}
+	provisionedInds, err := checkProvisionedAttrs(initReq.OrgName, provisionedAttrs,
+		credReq.KnownAttrs)
+	if err != nil {
+		return err
+	}
 
 	// Issue the credential
-	res, err := org.IssueCred(credReq)
+	res, err := org.IssueCred(credReq, nonce)
 	if err != nil {
 		return fmt.Errorf("error when issuing credential: %v", err)
 	}
+	// the provisioned attributes cannot be changed when the credential is updated
+	res.Record.ProvisionedAttrs = provisionedInds
 	// Store the newly obtained receiver record to the database
-	if err = s.clRecordManager.Store(credReq.Nym, res.Record); err != nil {
+	if err = s.clRecordManager.Store(strings.ToLower(initReq.OrgName), credReq.Nym,
+		res.Record); err != nil {
 		return err
 	}
 
 	pbCred := pb.ToPbCLCredential(res.Cred, res.AProof)
+	pbCred.Witness = pb.ToPbCLWitness(res.Witness)
 	resp = &pb.Message{
 		Content: &pb.Message_CLCredential{pbCred},
 	}
@@ -162,37 +237,124 @@ func (s *Server) IssueCredential(stream pb.CL_IssueCredentialServer) error {
 	return nil
 }
 
+// checkProvisionedAttrs checks that the values of known attributes in the credential
+// request for the organization orgName match the values provisioned for the registration
+// key. The indices of the provisioned attributes among the known attributes are returned.
+func checkProvisionedAttrs(orgName string, provisioned map[string]string,
+	knownAttrs []*big.Int) ([]int, error) {
+	if len(provisioned) == 0 {
+		return nil, nil
+	}
+
+	attrs, _, err := loadCLAttrs(orgName)
+	if err != nil {
+		return nil, err
+	}
+
+	var inds []int
+	knownInd := 0
+	for _, a := range attrs {
+		if !a.IsKnown() {
+			continue
+		}
+		if val, ok := provisioned[a.GetName()]; ok {
+			if err := cl.UpdateValueFromString(a, val); err != nil {
+				return nil, status.Errorf(codes.Internal, "invalid provisioned attribute: %v", err)
+			}
+			if knownInd >= len(knownAttrs) || a.InternalValue().Cmp(knownAttrs[knownInd]) != 0 {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"attribute %s does not match the value provisioned for the registration key",
+					a.GetName())
+			}
+			inds = append(inds, knownInd)
+		}
+		knownInd++
+	}
+
+	if len(inds) != len(provisioned) {
+		return nil, status.Error(codes.Internal,
+			"provisioned attributes are not known attributes of the credential")
+	}
+
+	return inds, nil
+}
+
 func (s *Server) UpdateCredential(stream pb.CL_UpdateCredentialServer) error {
 	req, err := s.receive(stream)
 	if err != nil {
 		return err
 	}
 
-	org, err := cl.LoadOrg("../client/testdata/clPubKey.gob", "../client/testdata/clSecKey.gob")
+	// the user needs to have proved a credential of the organization in the session
+	orgName := req.GetClOrg().GetOrgName()
+	cs, err := getCallSession(stream.Context())
 	if err != nil {
 		return err
 	}
+	if !cs.session.hasCred(orgName) {
+		return status.Errorf(codes.PermissionDenied,
+			"session does not hold a credential of organization %s", orgName)
+	}
+	keyRing, err := s.getCLKeyRing(orgName)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := keyRing.GetCredIssueNonce()
+	if err != nil {
+		s.Logger.Debug(err)
+		return status.Error(codes.Internal, "failed to obtain nonce")
+	}
+	resp := &pb.Message{
+		Content: &pb.Message_Bigint{
+			&pb.BigInt{
+				X1: nonce.Bytes(),
+			},
+		},
+	}
+	if err := s.send(resp, stream); err != nil {
+		return err
+	}
 
-	u := req.GetUpdateClCredential()
-	nym, nonce, newKnownAttrs := u.GetNativeType()
+	req, err = s.receive(stream)
+	if err != nil {
+		return err
+	}
+	updateReq, err := req.GetUpdateClCredential().GetNativeType()
+	if err != nil {
+		return err
+	}
 
 	// Retrieve the receiver record from the database
-	rec, err := s.clRecordManager.Load(nym)
+	rec, err := s.clRecordManager.Load(strings.ToLower(orgName), updateReq.Nym)
 	if err != nil {
 		return err
 	}
+
+	// the credential is updated under the key it was issued under
+	v, err := keyRing.GetRecordVersion(rec)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if !v.IsValid(time.Now()) || v.Org.Keys.Sec == nil {
+		return status.Error(codes.FailedPrecondition,
+			"key of the credential has been retired, a new credential needs to be issued")
+	}
+	org := v.Org
 	// Do credential update
-	res, err := org.UpdateCred(nym, rec, nonce, newKnownAttrs)
+	res, err := org.UpdateCred(updateReq, rec, nonce)
 	if err != nil {
 		return fmt.Errorf("error when updating credential: %v", err)
 	}
 	// Store the updated receiver record to the database
-	if err = s.clRecordManager.Store(nym, res.Record); err != nil {
+	if err = s.clRecordManager.Store(strings.ToLower(orgName), updateReq.Nym,
+		res.Record); err != nil {
 		return err
 	}
 
 	pbCred := pb.ToPbCLCredential(res.Cred, res.AProof)
-	resp := &pb.Message{
+	pbCred.Witness = pb.ToPbCLWitness(res.Witness)
+	resp = &pb.Message{
 		Content: &pb.Message_CLCredential{pbCred},
 	}
 
@@ -209,16 +371,46 @@ func (s *Server) ProveCredential(stream pb.CL_ProveCredentialServer) error {
 		return err
 	}
 
-	org, err := cl.LoadOrg("../client/testdata/clPubKey.gob", "../client/testdata/clSecKey.gob")
+	name := strings.ToLower(req.GetClOrg().GetOrgName())
+	keyRing, err := s.getCLKeyRing(name)
+	if err != nil {
+		return err
+	}
+	policies, err := loadCLVerificationPolicies()
 	if err != nil {
 		return err
 	}
+	policy, ok := policies[name]
+	if !ok {
+		return status.Errorf(codes.FailedPrecondition,
+			"credentials of organization %s are not accepted", name)
+	}
 
-	nonce := org.GetProveCredNonce()
+	scope, err := s.loadCLPseudonymScope()
+	if err != nil {
+		return err
+	}
+
+	// when the escrow is set, the user needs to encrypt the attribute for the inspector
+	escrow, err := loadCLEscrow(name)
+	if err != nil {
+		return err
+	}
+	if escrow != nil && s.clEscrowRecords == nil {
+		return status.Error(codes.FailedPrecondition, "escrowed attributes cannot be stored")
+	}
+
+	nonce, err := keyRing.GetProveCredNonce()
+	if err != nil {
+		s.Logger.Debug(err)
+		return status.Error(codes.Internal, "failed to obtain nonce")
+	}
 	resp := &pb.Message{
-		Content: &pb.Message_Bigint{
-			&pb.BigInt{
-				X1: nonce.Bytes(),
+		Content: &pb.Message_ClProofRequest{
+			&pb.CLProofRequest{
+				Nonce:  nonce.Bytes(),
+				Scope:  scope,
+				Escrow: pb.ToPbCLEscrow(escrow),
 			},
 		},
 	}
@@ -232,15 +424,21 @@ func (s *Server) ProveCredential(stream pb.CL_ProveCredentialServer) error {
 		return err
 	}
 
-	pReq := req.GetProveClCredential()
-	A, proof, knownAttrs, commitmentsOfAttrs, revealedKnownAttrsIndices,
-		revealedCommitmentsOfAttrsIndices, err := pReq.GetNativeType()
+	pbProof := req.GetProveClCredential()
+	org, err := getCLOrgForProof(keyRing, pbProof)
+	if err != nil {
+		s.Logger.Debug(err)
+		return status.Error(codes.Unauthenticated, "credential was issued under an invalid key")
+	}
+
+	p, err := pbProof.GetCredProof()
 	if err != nil {
 		return err
 	}
 
-	verified, err := org.ProveCred(A, proof, revealedKnownAttrsIndices,
-		revealedCommitmentsOfAttrsIndices, knownAttrs, commitmentsOfAttrs)
+	policy.Scope = scope
+	policy.Escrow = escrow
+	verified, pseudonym, err := org.ProveCred(p, policy, nonce)
 	if err != nil {
 		s.Logger.Debug(err)
 		return status.Error(codes.Internal, "error when proving credential")
@@ -251,13 +449,277 @@ func (s *Server) ProveCredential(stream pb.CL_ProveCredentialServer) error {
 		return status.Error(codes.Unauthenticated, "user authentication failed")
 	}
 
-	sessionKey, err := s.GenerateSessionKey()
+	if err := s.claimCLPseudonyms(scope, pseudonym); err != nil {
+		return err
+	}
+
+	if escrow != nil {
+		s.Logger.Infof("Stored escrowed attribute %s", cl.NewEscrowRecord(p.EscrowProof).GetID())
+	}
+
+	sessionCred, err := newCLSessionCred(name, policy, p.RevealedKnownAttrsIndices,
+		p.RevealedKnownAttrs)
+	if err != nil {
+		return err
+	}
+	sessionKey, err := s.newSession([]*SessionCred{sessionCred})
 	if err != nil {
 		s.Logger.Debug(err)
 		return status.Error(codes.Internal, "failed to obtain session key")
 	}
 
-	// TODO: here session key needs to be stored to enable validation
+	resp = &pb.Message{
+		Content: &pb.Message_SessionKey{
+			SessionKey: &pb.SessionKey{
+				Value: *sessionKey,
+			},
+		},
+	}
+
+	if err = s.send(resp, stream); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeCredential revokes the credential that was issued (or last updated) for the given nym.
+// Only the issuer of the credential or the admin (which present the configured tokens) can
+// call it. The returned accumulator update is also available to the holders of other
+// credentials through GetWitnessUpdates.
+func (s *Server) RevokeCredential(ctx context.Context, req *pb.CLRevokeCredential) (*pb.CLAccumulatorUpdate,
+	error) {
+	nym := new(big.Int).SetBytes(req.Nym)
+	s.Logger.Infof("Client requested revocation of credential for nym %v", nym)
+
+	if err := checkCLRevocationToken(ctx, req.OrgName); err != nil {
+		s.Logger.Infof("Revocation of credential for nym %v refused: %v", nym, err)
+		return nil, err
+	}
+
+	rec, err := s.clRecordManager.Load(strings.ToLower(req.OrgName), nym)
+	if err != nil {
+		s.Logger.Debug(err)
+		return nil, status.Error(codes.NotFound, "credential for the given nym not found")
+	}
+
+	// credentials issued under retired keys can be revoked as well
+	keyRing, err := s.getCLKeyRing(req.OrgName)
+	if err != nil {
+		return nil, err
+	}
+	v, err := keyRing.GetRecordVersion(rec)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	org := v.Org
+	if org.Accumulator == nil {
+		return nil, status.Error(codes.FailedPrecondition, "revocation is not supported")
+	}
+
+	update, err := org.RevokeCred(rec)
+	if err != nil {
+		s.Logger.Debug(err)
+		return nil, status.Error(codes.FailedPrecondition, "credential cannot be revoked")
+	}
+
+	return pb.ToPbCLAccumulatorUpdate(update), nil
+}
+
+// checkCLRevocationToken checks that the call presents the token of the issuer of
+// the organization orgName or the admin token.
+func checkCLRevocationToken(ctx context.Context, orgName string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(CL_REVOCATION_TOKEN_METADATA)
+	if len(tokens) != 1 || tokens[0] == "" {
+		return status.Error(codes.Unauthenticated, "revocation token is missing")
+	}
+
+	issuerToken, adminToken := config.LoadCLRevocationTokens(orgName)
+	for _, token := range []string{issuerToken, adminToken} {
+		if token != "" && subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(token)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.PermissionDenied, "revocation is not permitted")
+}
+
+// GetWitnessUpdates returns all accumulator updates since the requested epoch, which
+// are needed by the credential holders to update their witnesses.
+func (s *Server) GetWitnessUpdates(ctx context.Context, req *pb.CLWitnessUpdatesRequest) (*pb.CLWitnessUpdates,
+	error) {
+	s.Logger.Infof("Client requested witness updates since epoch %d", req.Epoch)
+
+	keyRing, err := s.getCLKeyRing(req.OrgName)
+	if err != nil {
+		return nil, err
+	}
+	// updates are available also for the credentials issued under retired keys
+	var org *cl.Org
+	if req.KeyId == "" {
+		org, err = keyRing.GetActiveOrg(time.Now())
+	} else {
+		var v *cl.KeyVersion
+		if v, err = keyRing.Get(req.KeyId); err == nil {
+			org = v.Org
+		}
+	}
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if org.Accumulator == nil {
+		return nil, status.Error(codes.FailedPrecondition, "revocation is not supported")
+	}
+
+	updates, err := org.Accumulator.GetUpdates(int(req.Epoch))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	pbUpdates := make([]*pb.CLAccumulatorUpdate, len(updates))
+	for i, u := range updates {
+		pbUpdates[i] = pb.ToPbCLAccumulatorUpdate(u)
+	}
+
+	return &pb.CLWitnessUpdates{
+		Updates: pbUpdates,
+	}, nil
+}
+
+// ProveCredentials verifies proofs of several credentials (issued by organizations
+// listed in acceptable credentials) which are bound to the same master secret.
+func (s *Server) ProveCredentials(stream pb.CL_ProveCredentialsServer) error {
+	req, err := s.receive(stream)
+	if err != nil {
+		return err
+	}
+
+	// the nonce is stored for all organizations, so the one named in the first message
+	// is able to check it
+	keyRing, err := s.getCLKeyRing(req.GetClOrg().GetOrgName())
+	if err != nil {
+		return err
+	}
+
+	scope, err := s.loadCLPseudonymScope()
+	if err != nil {
+		return err
+	}
+
+	// when the escrow is set, the attribute needs to be escrowed in the credentials of
+	// the organization which requires it
+	escrowOrgName := config.LoadEscrow().OrgName
+	escrow, err := loadCLEscrow(escrowOrgName)
+	if err != nil {
+		return err
+	}
+	if escrow != nil && s.clEscrowRecords == nil {
+		return status.Error(codes.FailedPrecondition, "escrowed attributes cannot be stored")
+	}
+
+	attrEqualities, err := loadCLAttrEqualities()
+	if err != nil {
+		return err
+	}
+
+	nonce, err := keyRing.GetProveCredNonce()
+	if err != nil {
+		s.Logger.Debug(err)
+		return status.Error(codes.Internal, "failed to obtain nonce")
+	}
+	proofReq := &pb.CLProofRequest{
+		Nonce:          nonce.Bytes(),
+		Scope:          scope,
+		Escrow:         pb.ToPbCLEscrow(escrow),
+		EscrowOrgName:  escrowOrgName,
+		AttrEqualities: attrEqualities,
+	}
+	resp := &pb.Message{
+		Content: &pb.Message_ClProofRequest{proofReq},
+	}
+
+	if err := s.send(resp, stream); err != nil {
+		return err
+	}
+
+	req, err = s.receive(stream)
+	if err != nil {
+		return err
+	}
+
+	policies, err := loadCLVerificationPolicies()
+	if err != nil {
+		return err
+	}
+
+	pbProofs := req.GetProveClCredentials().GetProofs()
+	orgNames := make([]string, len(pbProofs))
+	orgs := make([]*cl.Org, len(pbProofs))
+	orgPolicies := make([]*cl.VerificationPolicy, len(pbProofs))
+	proofs := make([]*cl.CredProof, len(pbProofs))
+	for i, p := range pbProofs {
+		name := strings.ToLower(p.OrgName)
+		orgNames[i] = name
+		policy, ok := policies[name]
+		if !ok || p.Proof == nil {
+			return status.Errorf(codes.InvalidArgument,
+				"credentials of organization %s are not accepted", p.OrgName)
+		}
+		keyRing, err := s.getCLKeyRing(name)
+		if err != nil {
+			return err
+		}
+		if orgs[i], err = getCLOrgForProof(keyRing, p.Proof); err != nil {
+			s.Logger.Debug(err)
+			return status.Errorf(codes.Unauthenticated,
+				"credential of organization %s was issued under an invalid key", p.OrgName)
+		}
+		if proofs[i], err = p.Proof.GetCredProof(); err != nil {
+			return err
+		}
+		policy.Scope = scope
+		if name == escrowOrgName {
+			policy.Escrow = escrow
+		}
+		orgPolicies[i] = policy
+	}
+
+	verified, pseudonyms, err := cl.ProveMultiCred(orgs, orgPolicies,
+		proofReq.GetNativeAttrEqualities(orgNames), proofs, nonce)
+	if err != nil {
+		s.Logger.Debug(err)
+		return status.Error(codes.Internal, "error when proving credentials")
+	}
+
+	if !verified {
+		s.Logger.Debug("User authentication failed")
+		return status.Error(codes.Unauthenticated, "user authentication failed")
+	}
+
+	if err := s.claimCLPseudonyms(scope, pseudonyms...); err != nil {
+		return err
+	}
+
+	for i, p := range proofs {
+		if orgPolicies[i].Escrow != nil {
+			s.Logger.Infof("Stored escrowed attribute %s", cl.NewEscrowRecord(p.EscrowProof).GetID())
+		}
+	}
+
+	sessionCreds := make([]*SessionCred, len(pbProofs))
+	for i, p := range pbProofs {
+		sessionCreds[i], err = newCLSessionCred(strings.ToLower(p.OrgName), orgPolicies[i],
+			proofs[i].RevealedKnownAttrsIndices, proofs[i].RevealedKnownAttrs)
+		if err != nil {
+			return err
+		}
+	}
+	sessionKey, err := s.newSession(sessionCreds)
+	if err != nil {
+		s.Logger.Debug(err)
+		return status.Error(codes.Internal, "failed to obtain session key")
+	}
 
 	resp = &pb.Message{
 		Content: &pb.Message_SessionKey{
@@ -273,3 +735,306 @@ func (s *Server) ProveCredential(stream pb.CL_ProveCredentialServer) error {
 
 	return nil
 }
+
+// loadCLPseudonymScope returns the scope for which the users need to present a domain
+// pseudonym when proving credentials (nil when no scope is set).
+func (s *Server) loadCLPseudonymScope() ([]byte, error) {
+	name := config.LoadPseudonymScope()
+	if name == "" {
+		return nil, nil
+	}
+	if s.clPseudonyms == nil {
+		return nil, status.Error(codes.FailedPrecondition, "domain pseudonyms cannot be stored")
+	}
+
+	return []byte(name), nil
+}
+
+// claimCLPseudonyms records the domain pseudonyms for the given scope (nil pseudonyms are
+// skipped). The credentials of a multi-credential proof share the master secret, so their
+// pseudonyms are the same when the keys share the Pedersen group - such a pseudonym is
+// recorded once. It fails if a pseudonym has already been recorded.
+func (s *Server) claimCLPseudonyms(scope []byte, pseudonyms ...*big.Int) error {
+	claimed := []*big.Int{}
+	for _, pseudonym := range pseudonyms {
+		if pseudonym == nil || containsBigInt(claimed, pseudonym) {
+			continue
+		}
+		ok, err := s.clPseudonyms.ClaimPseudonym(scope, pseudonym)
+		if err != nil {
+			s.Logger.Debug(err)
+			return status.Error(codes.Internal, "failed to record domain pseudonym")
+		}
+		if !ok {
+			s.Logger.Debugf("Domain pseudonym %v has already been used", pseudonym)
+			return status.Error(codes.PermissionDenied, "credential has already been used")
+		}
+		claimed = append(claimed, pseudonym)
+	}
+
+	return nil
+}
+
+// containsBigInt returns true if values contain x.
+func containsBigInt(values []*big.Int, x *big.Int) bool {
+	for _, v := range values {
+		if v.Cmp(x) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadCLAttrs returns the attributes of the credentials of the organization orgName, as
+// given by its credential structure in the configuration.
+func loadCLAttrs(orgName string) ([]cl.CredAttr, *cl.AttrCount, error) {
+	structure, err := config.LoadCredentialStructure(orgName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cl.ParseAttrs(structure)
+}
+
+// loadCLAttrEqualities returns the equalities of attributes which need to be proved in
+// proofs of several credentials.
+func loadCLAttrEqualities() ([]*pb.CLAttrEquality, error) {
+	equalities, err := config.LoadAttrEqualities()
+	if err != nil {
+		return nil, err
+	}
+
+	pbEqualities := make([]*pb.CLAttrEquality, len(equalities))
+	for i, e := range equalities {
+		pbEqualities[i] = &pb.CLAttrEquality{
+			Org1:  e.Org1,
+			Attr1: e.Attr1,
+			Org2:  e.Org2,
+			Attr2: e.Attr2,
+		}
+	}
+
+	return pbEqualities, nil
+}
+
+// loadCLVerificationPolicies loads the verification policies for the credentials of
+// the accepted organizations (each policy refers to the attributes from the credential
+// structure of its organization).
+func loadCLVerificationPolicies() (map[string]*cl.VerificationPolicy, error) {
+	accCreds, err := config.LoadAcceptableCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]*cl.VerificationPolicy)
+	for name, data := range accCreds {
+		attrs, _, err := loadCLAttrs(name)
+		if err != nil {
+			return nil, err
+		}
+		policy, err := cl.ParseVerificationPolicy(attrs, data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verification policy for %s: %v", name, err)
+		}
+		policies[name] = policy
+	}
+
+	return policies, nil
+}
+
+// newCLSessionCred describes the credential of the organization orgName which satisfied
+// the verification policy (the attributes are taken from the credential structure of the
+// organization). The revealed known attributes (given by their indices among
+// known attributes) are decoded to the format accepted by cl.UpdateValueFromString.
+func newCLSessionCred(orgName string, policy *cl.VerificationPolicy, indices []int,
+	values []*big.Int) (*SessionCred, error) {
+	if len(indices) != len(values) {
+		return nil, fmt.Errorf("the number of revealed attributes does not match")
+	}
+
+	attrs, _, err := loadCLAttrs(orgName)
+	if err != nil {
+		return nil, err
+	}
+	var known []cl.CredAttr
+	for _, a := range attrs {
+		if a != nil && a.IsKnown() {
+			known = append(known, a)
+		}
+	}
+
+	revealed := make(map[string]string, len(indices))
+	for i, ind := range indices {
+		if ind < 0 || ind >= len(known) {
+			return nil, fmt.Errorf("revealed attribute %d is not a known attribute", ind)
+		}
+		val, err := known[ind].FromInternalValue(values[i])
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := val.(time.Time); ok {
+			val = t.Format(time.RFC3339)
+		}
+		revealed[known[ind].GetName()] = fmt.Sprint(val)
+	}
+
+	p, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionCred{
+		OrgName: orgName,
+		Attrs:   revealed,
+		Policy:  string(p),
+	}, nil
+}
+
+// loadCLEscrow loads the identity escrow required for the credentials of the organization
+// with the given name (nil when no escrow is required).
+func loadCLEscrow(orgName string) (*cl.Escrow, error) {
+	conf := config.LoadEscrow()
+	if conf.Attr == "" || strings.ToLower(orgName) != conf.OrgName {
+		return nil, nil
+	}
+
+	attrs, _, err := loadCLAttrs(orgName)
+	if err != nil {
+		return nil, err
+	}
+	// the escrowed attribute is given by its index among known attributes
+	ind, known := -1, 0
+	for _, a := range attrs {
+		if a != nil && a.IsKnown() {
+			if a.GetName() == conf.Attr {
+				ind = known
+			}
+			known++
+		}
+	}
+	if ind == -1 {
+		return nil, fmt.Errorf("escrowed attribute %s is not a known attribute", conf.Attr)
+	}
+
+	pubKey, err := encryption.ReadCSPaillierPubKey(conf.PubKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.NewEscrow(ind, pubKey, []byte(conf.Label)), nil
+}
+
+// LoadCLOrgs loads all versions of the keys of all CL organizations configured in cl_keys
+// and keeps them in memory. It is called when the server is created and needs to be called
+// again when the configured keys change (for example after the keys are rotated).
+func (s *Server) LoadCLOrgs() error {
+	orgs := make(map[string]*cl.KeyRing)
+	for _, name := range config.LoadCLOrgNames() {
+		keyRing, err := s.loadCLKeyRing(name)
+		if err != nil {
+			return fmt.Errorf("error when loading CL organization %s: %v", name, err)
+		}
+		orgs[name] = keyRing
+	}
+
+	s.clOrgsMutex.Lock()
+	s.clOrgs = orgs
+	s.clOrgsMutex.Unlock()
+
+	return nil
+}
+
+// loadCLKeyRing loads all versions of the keys of the CL organization with the given
+// (lowercase) name. The revocation accumulators are preserved when the organizations are
+// reloaded, as their state needs to be kept, and restored from the record store when they
+// are first loaded. All organizations share the nonces, which are issued and used in
+// different requests, and the storage of escrowed attributes.
+func (s *Server) loadCLKeyRing(name string) (*cl.KeyRing, error) {
+	versions, err := config.LoadCLKeyVersions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.clAccumulatorsMutex.Lock()
+	defer s.clAccumulatorsMutex.Unlock()
+
+	keyRing := cl.NewKeyRing()
+	for _, v := range versions {
+		org, err := cl.LoadOrg(v.PubKeyPath, v.SecKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		org.Nonces = s.clNonces
+		org.EscrowRecords = s.clEscrowRecords
+		org.AccumulatorRecords = s.clAccumulatorRecords
+		if v.Threshold != 0 {
+			if org.Signer, err = s.getCLThresholdSigner(org, v); err != nil {
+				return nil, err
+			}
+		}
+		if org.Accumulator != nil {
+			keyID := org.Keys.Pub.GetID()
+			// the server does not change an accumulator without the secret key, so it is replaced when
+			// the secret key is loaded (for example by the coordinator of threshold issuance)
+			acc, ok := s.clAccumulators[keyID]
+			if ok && (acc.Group.P != nil || org.Accumulator.Group.P == nil) {
+				org.Accumulator = acc
+			} else {
+				if err := org.LoadAccumulator(); err != nil {
+					return nil, err
+				}
+				s.clAccumulators[keyID] = org.Accumulator
+			}
+		}
+		if err := keyRing.Add(org, v.NotBefore, v.NotAfter); err != nil {
+			return nil, err
+		}
+	}
+
+	return keyRing, nil
+}
+
+// getCLKeyRing returns the loaded keys of the CL organization with the given name.
+func (s *Server) getCLKeyRing(name string) (*cl.KeyRing, error) {
+	s.clOrgsMutex.RLock()
+	defer s.clOrgsMutex.RUnlock()
+
+	keyRing, ok := s.clOrgs[strings.ToLower(name)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "organization %s is not hosted by the server",
+			name)
+	}
+
+	return keyRing, nil
+}
+
+// getActiveCLOrg returns the CL organization with the given name, holding the keys under
+// which the credentials are currently issued.
+func (s *Server) getActiveCLOrg(name string) (*cl.Org, error) {
+	keyRing, err := s.getCLKeyRing(name)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := keyRing.GetActiveOrg(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if org.Keys.Sec == nil {
+		return nil, fmt.Errorf("secret key of the active key %s is not configured",
+			org.Keys.Pub.GetID())
+	}
+
+	return org, nil
+}
+
+// getCLOrgForProof returns the organization with the (valid) key under which the
+// credential in the proof was issued.
+func getCLOrgForProof(keyRing *cl.KeyRing, p *pb.ProveCLCredential) (*cl.Org, error) {
+	if p.KeyId == "" {
+		return keyRing.GetActiveOrg(time.Now())
+	}
+
+	return keyRing.GetValidOrg(p.KeyId, time.Now())
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/crypto/cl"
+	pb "github.com/xlab-si/emmy/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CL_THRESHOLD_TOKEN_METADATA is the key of the gRPC metadata holding the token which
+// the coordinator of the threshold issuance presents to the parties.
+const CL_THRESHOLD_TOKEN_METADATA = "threshold-token"
+
+// loadCLThresholdParties loads the shares of CL issuer secret keys held by the server.
+func (s *Server) loadCLThresholdParties() error {
+	shares, err := config.LoadCLThresholdShares()
+	if err != nil {
+		return err
+	}
+
+	s.clThresholdParties = make(map[string]*cl.ThresholdParty)
+	for _, sh := range shares {
+		params, pubKey, err := cl.ReadPubKey(sh.PubKeyPath)
+		if err != nil {
+			return err
+		}
+		share, err := cl.ReadThresholdKeyShare(sh.SharePath, pubKey)
+		if err != nil {
+			return err
+		}
+		party, err := cl.NewThresholdParty(params, pubKey, share)
+		if err != nil {
+			return err
+		}
+		s.clThresholdParties[clThresholdPartyID(pubKey.GetID(), share.Index)] = party
+		s.Logger.Infof("Loaded share %d of CL key %s", share.Index, pubKey.GetID())
+	}
+
+	return nil
+}
+
+func clThresholdPartyID(keyID string, index int) string {
+	return fmt.Sprintf("%s/%d", keyID, index)
+}
+
+// Round executes a round of the threshold issuance with the share of the secret key given
+// in req. Only the coordinator (which presents the configured token) can call it.
+func (s *Server) Round(ctx context.Context, req *pb.CLThresholdRound) (*pb.CLThresholdMessages,
+	error) {
+	token := config.LoadCLThresholdToken()
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(CL_THRESHOLD_TOKEN_METADATA)
+	if token == "" || len(tokens) != 1 ||
+		subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(token)) != 1 {
+		return nil, status.Error(codes.PermissionDenied, "threshold issuance is not permitted")
+	}
+
+	party, ok := s.clThresholdParties[clThresholdPartyID(req.KeyId, int(req.Party))]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "share %d of key %s is not held by the server",
+			req.Party, req.KeyId)
+	}
+
+	in := make([]*cl.ThresholdMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		var err error
+		if in[i], err = m.GetNativeType(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	out, err := party.Round(req.SessionId, int(req.Round), in)
+	if err != nil {
+		s.Logger.Debugf("Round %d of threshold issuance failed: %v", req.Round, err)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	resp := &pb.CLThresholdMessages{
+		Messages: make([]*pb.CLThresholdMessage, len(out)),
+	}
+	for i, m := range out {
+		resp.Messages[i] = pb.ToPbCLThresholdMessage(m)
+	}
+
+	return resp, nil
+}
+
+// clThresholdPartyConn is the connection of the coordinator to a party of the threshold
+// issuance.
+type clThresholdPartyConn struct {
+	client pb.CLThresholdPartyClient
+	keyID  string
+	index  int
+}
+
+func (c *clThresholdPartyConn) Round(sessionID string, round int,
+	in []*cl.ThresholdMessage) ([]*cl.ThresholdMessage, error) {
+	req := &pb.CLThresholdRound{
+		KeyId:     c.keyID,
+		Party:     int32(c.index),
+		SessionId: sessionID,
+		Round:     int32(round),
+		Messages:  make([]*pb.CLThresholdMessage, len(in)),
+	}
+	for i, m := range in {
+		req.Messages[i] = pb.ToPbCLThresholdMessage(m)
+	}
+
+	timeout := time.Duration(config.LoadTimeout()) * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, CL_THRESHOLD_TOKEN_METADATA,
+		config.LoadCLThresholdToken())
+	resp, err := c.client.Round(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*cl.ThresholdMessage, len(resp.Messages))
+	for i, m := range resp.Messages {
+		if out[i], err = m.GetNativeType(); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// getCLThresholdSigner returns the signer which issues the credentials of org together
+// with the parties of the threshold issuance configured in v.
+func (s *Server) getCLThresholdSigner(org *cl.Org, v *config.CLKeyVersion) (*cl.ThresholdSigner,
+	error) {
+	parties := make(map[int]cl.ThresholdPartyConn)
+	for i, addr := range v.Parties {
+		conn, err := s.getCLThresholdConn(addr)
+		if err != nil {
+			return nil, err
+		}
+		parties[i+1] = &clThresholdPartyConn{
+			client: pb.NewCLThresholdPartyClient(conn),
+			keyID:  org.Keys.Pub.GetID(),
+			index:  i + 1,
+		}
+	}
+
+	return cl.NewThresholdSigner(org.Params, org.Keys.Pub, v.Threshold, parties)
+}
+
+// getCLThresholdConn returns the connection to the party at addr. The connections are
+// kept when the CL organizations are reloaded.
+func (s *Server) getCLThresholdConn(addr string) (*grpc.ClientConn, error) {
+	s.clThresholdConnsMutex.Lock()
+	defer s.clThresholdConnsMutex.Unlock()
+
+	if conn, ok := s.clThresholdConns[addr]; ok {
+		return conn, nil
+	}
+
+	creds, err := credentials.NewClientTLSFromFile(config.LoadCLThresholdCACert(), "")
+	if err != nil {
+		return nil, fmt.Errorf("error when reading CA certificate of threshold issuance: %v", err)
+	}
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("error when connecting to party %s: %v", addr, err)
+	}
+	s.clThresholdConns[addr] = conn
+
+	return conn, nil
+}
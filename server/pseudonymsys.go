@@ -35,7 +35,10 @@ func (s *Server) GenerateNym(stream pb.PseudonymSystem_GenerateNymServer) error
 	}
 
 	group := config.LoadSchnorrGroup()
-	caPubKey := config.LoadPseudonymsysCAPubKey()
+	caPubKey, err := config.LoadPseudonymsysCAPubKey()
+	if err != nil {
+		return err
+	}
 	org := pseudsys.NewNymGenerator(group, caPubKey)
 
 	proofRandData := req.GetPseudonymsysNymGenProofRandomData()
@@ -103,7 +106,10 @@ func (s *Server) ObtainCredential(stream pb.PseudonymSystem_ObtainCredentialServ
 	}
 
 	group := config.LoadSchnorrGroup()
-	secKey := config.LoadPseudonymsysOrgSecrets("org1", "dlog")
+	secKey, err := config.LoadPseudonymsysOrgSecrets("org1", "dlog")
+	if err != nil {
+		return err
+	}
 	org := pseudsys.NewCredIssuer(group, secKey)
 
 	sProofRandData := req.GetSchnorrProofRandomData()
@@ -187,7 +193,10 @@ func (s *Server) TransferCredential(stream pb.PseudonymSystem_TransferCredential
 	}
 
 	group := config.LoadSchnorrGroup()
-	secKey := config.LoadPseudonymsysOrgSecrets("org1", "dlog")
+	secKey, err := config.LoadPseudonymsysOrgSecrets("org1", "dlog")
+	if err != nil {
+		return err
+	}
 	org := pseudsys.NewCredVerifier(group, secKey)
 
 	data := req.GetPseudonymsysTransferCredentialData()
@@ -240,7 +249,10 @@ func (s *Server) TransferCredential(stream pb.PseudonymSystem_TransferCredential
 	}
 
 	// PubKeys of the organization that issue a credential:
-	orgPubKeys := config.LoadPseudonymsysOrgPubKeys(orgName)
+	orgPubKeys, err := config.LoadPseudonymsysOrgPubKeys(orgName)
+	if err != nil {
+		return err
+	}
 
 	proofData := req.GetBigint()
 	z := new(big.Int).SetBytes(proofData.X1)
@@ -36,8 +36,14 @@ func (s *Server) GenerateCertificate(stream pb.PseudonymSystemCA_GenerateCertifi
 	}
 
 	group := config.LoadSchnorrGroup()
-	d := config.LoadPseudonymsysCASecret()
-	pubKey := config.LoadPseudonymsysCAPubKey()
+	d, err := config.LoadPseudonymsysCASecret()
+	if err != nil {
+		return err
+	}
+	pubKey, err := config.LoadPseudonymsysCAPubKey()
+	if err != nil {
+		return err
+	}
 	ca := pseudsys.NewCA(group, d, pubKey)
 
 	sProofRandData := req.GetSchnorrProofRandomData()
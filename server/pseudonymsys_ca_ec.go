@@ -33,8 +33,14 @@ func (s *Server) GenerateCertificate_EC(stream pb.PseudonymSystemCA_GenerateCert
 		return err
 	}
 
-	d := config.LoadPseudonymsysCASecret()
-	pubKey := config.LoadPseudonymsysCAPubKey()
+	d, err := config.LoadPseudonymsysCASecret()
+	if err != nil {
+		return err
+	}
+	pubKey, err := config.LoadPseudonymsysCAPubKey()
+	if err != nil {
+		return err
+	}
 	ca := ecpseudsys.NewCA(d, pubKey, curve)
 
 	sProofRandData := req.GetSchnorrEcProofRandomData()
@@ -34,7 +34,10 @@ func (s *Server) GenerateNym_EC(stream pb.PseudonymSystem_GenerateNym_ECServer)
 		return err
 	}
 
-	caPubKey := config.LoadPseudonymsysCAPubKey()
+	caPubKey, err := config.LoadPseudonymsysCAPubKey()
+	if err != nil {
+		return err
+	}
 	org := ecpseudsys.NewNymGenerator(caPubKey, curve)
 
 	proofRandData := req.GetPseudonymsysNymGenProofRandomDataEc()
@@ -105,7 +108,10 @@ func (s *Server) ObtainCredential_EC(stream pb.PseudonymSystem_ObtainCredential_
 	a := proofRandData.A.GetNativeType()
 	b := proofRandData.B.GetNativeType()
 
-	secKey := config.LoadPseudonymsysOrgSecrets("org1", "ecdlog")
+	secKey, err := config.LoadPseudonymsysOrgSecrets("org1", "ecdlog")
+	if err != nil {
+		return err
+	}
 	org := ecpseudsys.NewCredIssuer(secKey, curve)
 	challenge := org.GetChallenge(a, b, x)
 
@@ -184,7 +190,10 @@ func (s *Server) TransferCredential_EC(stream pb.PseudonymSystem_TransferCredent
 		return err
 	}
 
-	secKey := config.LoadPseudonymsysOrgSecrets("org1", "ecdlog")
+	secKey, err := config.LoadPseudonymsysOrgSecrets("org1", "ecdlog")
+	if err != nil {
+		return err
+	}
 	org := ecpseudsys.NewCredVerifier(secKey, curve)
 
 	data := req.GetPseudonymsysTransferCredentialDataEc()
@@ -239,7 +248,10 @@ func (s *Server) TransferCredential_EC(stream pb.PseudonymSystem_TransferCredent
 	}
 
 	// PubKeys of the organization that issue a credential:
-	orgPubKeys := config.LoadPseudonymsysOrgPubKeysEC(orgName)
+	orgPubKeys, err := config.LoadPseudonymsysOrgPubKeysEC(orgName)
+	if err != nil {
+		return err
+	}
 
 	proofData := req.GetBigint()
 	z := new(big.Int).SetBytes(proofData.X1)
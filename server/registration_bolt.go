@@ -0,0 +1,103 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package server
+
+import (
+	"encoding/json"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltRegKeysBucket = []byte("registration_keys")
+	boltAttrsBucket   = []byte("registration_attrs")
+)
+
+// BoltRegistrationManager keeps the registration keys (and the attributes provisioned
+// for them) in an embedded BoltDB database. It is safe for concurrent use.
+type BoltRegistrationManager struct {
+	db *bolt.DB
+}
+
+// NewBoltRegistrationManager creates the buckets for registration keys in db (if they
+// do not exist yet) and returns an instance of BoltRegistrationManager.
+func NewBoltRegistrationManager(db *bolt.DB) (*BoltRegistrationManager, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{boltRegKeysBucket, boltAttrsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoltRegistrationManager{
+		db: db,
+	}, nil
+}
+
+// AddRegistrationKey stores the registration key regKey together with the values of
+// credential attributes provisioned for it (attrs can be empty).
+func (m *BoltRegistrationManager) AddRegistrationKey(regKey string,
+	attrs map[string]string) error {
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		return err
+	}
+
+	return m.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(boltRegKeysBucket).Put([]byte(regKey), []byte{}); err != nil {
+			return err
+		}
+		return tx.Bucket(boltAttrsBucket).Put([]byte(regKey), data)
+	})
+}
+
+// CheckRegistrationKey checks whether the registration key is present and deletes it.
+func (m *BoltRegistrationManager) CheckRegistrationKey(regKey string) (bool, error) {
+	present := false
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltRegKeysBucket)
+		present = b.Get([]byte(regKey)) != nil
+		return b.Delete([]byte(regKey))
+	})
+
+	return present, err
+}
+
+// GetAttributes returns the values of credential attributes provisioned for the
+// registration key regKey and deletes them.
+func (m *BoltRegistrationManager) GetAttributes(regKey string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltAttrsBucket)
+		if data := b.Get([]byte(regKey)); data != nil {
+			if err := json.Unmarshal(data, &attrs); err != nil {
+				return err
+			}
+		}
+		return b.Delete([]byte(regKey))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attrs, nil
+}
@@ -26,8 +26,13 @@ import (
 // The bolean return argument indicates success (registration key
 // present and subsequently deleted) or failure (absence of registration
 // key).
+// Besides registration keys, RegistrationManager holds the values of the known
+// credential attributes that the operator provisioned for a registration key.
+// GetAttributes returns them (mapped by attribute names) and removes them,
+// an empty map means that no attributes were provisioned for the key.
 type RegistrationManager interface {
 	CheckRegistrationKey(string) (bool, error)
+	GetAttributes(string) (map[string]string, error)
 }
 
 type RedisClient struct {
@@ -54,3 +59,49 @@ func (c *RedisClient) CheckRegistrationKey(key string) (bool, error) {
 
 	return resp.Val() == 1, nil // one deleted entry indicates that the key was present in the DB
 }
+
+// attrsKey returns the key of the redis hash holding the attributes
+// provisioned for the registration key regKey.
+func attrsKey(regKey string) string {
+	return "attrs:" + regKey
+}
+
+// SetAttributes stores the values of credential attributes for the registration key regKey.
+// The values need to be given as accepted by cl.UpdateValueFromString.
+func (c *RedisClient) SetAttributes(regKey string, attrs map[string]string) error {
+	fields := make(map[string]interface{}, len(attrs))
+	for name, val := range attrs {
+		fields[name] = val
+	}
+
+	return c.HMSet(attrsKey(regKey), fields).Err()
+}
+
+// AddRegistrationKey stores the registration key regKey together with the values of
+// credential attributes provisioned for it (attrs can be empty).
+func (c *RedisClient) AddRegistrationKey(regKey string, attrs map[string]string) error {
+	if err := c.Set(regKey, regKey, 0).Err(); err != nil {
+		return err
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	return c.SetAttributes(regKey, attrs)
+}
+
+// GetAttributes returns the values of credential attributes provisioned for the
+// registration key regKey and deletes them.
+func (c *RedisClient) GetAttributes(regKey string) (map[string]string, error) {
+	var get *redis.StringStringMapCmd
+	_, err := c.TxPipelined(func(pipe redis.Pipeliner) error {
+		get = pipe.HGetAll(attrsKey(regKey))
+		pipe.Del(attrsKey(regKey))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return get.Val(), nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// sqlRegistrationTables are the statements creating the tables for registration keys (they
+// work with PostgreSQL and SQLite). Registration keys can also be inserted directly into
+// registration_keys, with the provisioned attributes in registration_attrs as a JSON object.
+var sqlRegistrationTables = []string{
+	`CREATE TABLE IF NOT EXISTS registration_keys (
+		regkey TEXT PRIMARY KEY
+	)`,
+	`CREATE TABLE IF NOT EXISTS registration_attrs (
+		regkey TEXT PRIMARY KEY,
+		attrs TEXT NOT NULL
+	)`,
+}
+
+// SQLRegistrationManager keeps the registration keys (and the attributes provisioned
+// for them) in an SQL database accessed through database/sql. It is safe for concurrent use.
+type SQLRegistrationManager struct {
+	db *sql.DB
+}
+
+// NewSQLRegistrationManager creates the tables for registration keys in db (if they do
+// not exist yet) and returns an instance of SQLRegistrationManager.
+func NewSQLRegistrationManager(db *sql.DB) (*SQLRegistrationManager, error) {
+	for _, stmt := range sqlRegistrationTables {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SQLRegistrationManager{
+		db: db,
+	}, nil
+}
+
+// AddRegistrationKey stores the registration key regKey together with the values of
+// credential attributes provisioned for it (attrs can be empty).
+func (m *SQLRegistrationManager) AddRegistrationKey(regKey string,
+	attrs map[string]string) error {
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		return err
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`INSERT INTO registration_keys (regkey) VALUES ($1)
+		ON CONFLICT (regkey) DO NOTHING`, regKey); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO registration_attrs (regkey, attrs) VALUES ($1, $2)
+		ON CONFLICT (regkey) DO UPDATE SET attrs = excluded.attrs`, regKey,
+		string(data)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CheckRegistrationKey checks whether the registration key is present and deletes it.
+func (m *SQLRegistrationManager) CheckRegistrationKey(regKey string) (bool, error) {
+	res, err := m.db.Exec(`DELETE FROM registration_keys WHERE regkey = $1`, regKey)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// GetAttributes returns the values of credential attributes provisioned for the
+// registration key regKey and deletes them.
+func (m *SQLRegistrationManager) GetAttributes(regKey string) (map[string]string, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	attrs := make(map[string]string)
+	var data string
+	err = tx.QueryRow(`SELECT attrs FROM registration_attrs WHERE regkey = $1`,
+		regKey).Scan(&data)
+	if err == sql.ErrNoRows {
+		return attrs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(data), &attrs); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM registration_attrs WHERE regkey = $1`,
+		regKey); err != nil {
+		return nil, err
+	}
+
+	return attrs, tx.Commit()
+}
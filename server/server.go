@@ -22,6 +22,7 @@ import (
 	"io"
 	"math"
 	"net"
+	"sync"
 
 	"net/http"
 
@@ -47,6 +48,7 @@ type EmmyServer interface {
 	pb.PseudonymSystemServer
 	pb.PseudonymSystemCAServer
 	pb.InfoServer
+	pb.SessionServer
 }
 
 // Server struct implements the EmmyServer interface.
@@ -56,8 +58,34 @@ type Server struct {
 	GrpcServer *grpc.Server
 	Logger     log.Logger
 	SessionManager
+	// Sessions maps the session keys to the sessions of the users who proved credentials
+	Sessions SessionStore
 	RegistrationManager
 	clRecordManager cl.ReceiverRecordManager
+	// clEscrowRecords stores the attributes escrowed in CL credential proofs (nil when
+	// the record manager cannot store them)
+	clEscrowRecords cl.EscrowRecordManager
+	// CL organizations (their key rings by lowercase names) hosted by the server
+	clOrgs      map[string]*cl.KeyRing
+	clOrgsMutex sync.RWMutex
+	// nonces issued by all CL organization instances
+	clNonces cl.NonceStore
+	// revocation accumulators (per key ID) preserved when CL organizations are reloaded
+	clAccumulators      map[string]*cl.Accumulator
+	clAccumulatorsMutex sync.Mutex
+	// clAccumulatorRecords stores the updates of the revocation accumulators, which are
+	// restored when the CL organizations are loaded (nil when the record manager cannot
+	// store them, then the revocations are lost when the server is restarted)
+	clAccumulatorRecords cl.AccumulatorRecordManager
+	// clPseudonyms records the domain pseudonyms of the CL credentials which have already
+	// been accepted (nil when the record manager cannot store them)
+	clPseudonyms cl.PseudonymRecordManager
+	// shares of CL issuer secret keys held by the server as a party of threshold issuance
+	// (by key ID and index of the share)
+	clThresholdParties map[string]*cl.ThresholdParty
+	// connections to the parties of threshold issuance (by their addresses)
+	clThresholdConns      map[string]*grpc.ClientConn
+	clThresholdConnsMutex sync.Mutex
 }
 
 // NewServer initializes an instance of the Server struct and returns a pointer.
@@ -81,18 +109,52 @@ func NewServer(certFile, keyFile string, regMgr RegistrationManager,
 		logger.Warning(err)
 	}
 
+	// Sessions are stored to redis when the registration manager uses it,
+	// otherwise they are kept in memory.
+	var sessions SessionStore = NewMemSessionStore()
+	if store, ok := regMgr.(SessionStore); ok {
+		sessions = store
+	}
+
 	// Allow as much concurrent streams as possible and register a gRPC stream interceptor
-	// for logging and monitoring purposes.
+	// for logging and monitoring purposes, and interceptors for validating the session keys.
 	server := &Server{
 		GrpcServer: grpc.NewServer(
 			grpc.Creds(creds),
 			grpc.MaxConcurrentStreams(math.MaxUint32),
-			grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
+			grpc.ChainStreamInterceptor(grpc_prometheus.StreamServerInterceptor,
+				sessionStreamInterceptor(sessions)),
+			grpc.UnaryInterceptor(sessionInterceptor(sessions)),
 		),
 		Logger:              logger,
 		SessionManager:      sessionManager,
+		Sessions:            sessions,
 		RegistrationManager: regMgr,
 		clRecordManager:     recMgr,
+		clAccumulators:      make(map[string]*cl.Accumulator),
+		clThresholdConns:    make(map[string]*grpc.ClientConn),
+	}
+
+	if escrowRecords, ok := recMgr.(cl.EscrowRecordManager); ok {
+		server.clEscrowRecords = escrowRecords
+	}
+	if pseudonyms, ok := recMgr.(cl.PseudonymRecordManager); ok {
+		server.clPseudonyms = pseudonyms
+	}
+	if accumulators, ok := recMgr.(cl.AccumulatorRecordManager); ok {
+		server.clAccumulatorRecords = accumulators
+	}
+	// nonces are stored by the record manager when it supports it (to be shared by several
+	// server instances), otherwise they are kept in memory
+	server.clNonces = cl.NewMemNonceStore()
+	if nonces, ok := recMgr.(cl.NonceStore); ok {
+		server.clNonces = nonces
+	}
+	if err := server.loadCLThresholdParties(); err != nil {
+		return nil, err
+	}
+	if err := server.LoadCLOrgs(); err != nil {
+		return nil, err
 	}
 
 	// Disable tracing by default, as is used for debugging purposes.
@@ -153,6 +215,8 @@ func (s *Server) registerServices() {
 	pb.RegisterPseudonymSystemServer(s.GrpcServer, s)
 	pb.RegisterPseudonymSystemCAServer(s.GrpcServer, s)
 	pb.RegisterCLServer(s.GrpcServer, s)
+	pb.RegisterCLThresholdPartyServer(s.GrpcServer, s)
+	pb.RegisterSessionServer(s.GrpcServer, s)
 
 	s.Logger.Notice("Registered gRPC Services")
 }
@@ -1,9 +1,19 @@
 package server
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/xlab-si/emmy/config"
+	pb "github.com/xlab-si/emmy/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // SessionManager generates a new session key.
@@ -60,3 +70,161 @@ func (m *RandSessionKeyGen) GenerateSessionKey() (*string, error) {
 	sessionKey := base64.URLEncoding.EncodeToString(randBytes)
 	return &sessionKey, nil
 }
+
+// SESSION_KEY_METADATA is the key of the gRPC metadata holding the session key
+// on the calls which require a session.
+const SESSION_KEY_METADATA = "session-key"
+
+// sessionMethods are the gRPC methods which require a valid session key - the methods of
+// the Session service and the methods of the CL service which are called by the users
+// after they proved the possession of credentials. The other CL methods are called before
+// the session is established (they are public or protected by a registration key or a token).
+var sessionMethods = map[string]bool{
+	"/proto.Session/GetSession":  true,
+	"/proto.Session/Logout":      true,
+	"/proto.CL/UpdateCredential": true,
+}
+
+// sessionContextKey is the key of the context value holding the session of the call.
+type sessionContextKey struct{}
+
+// callSession is the session (with its key) of a call which required a session.
+type callSession struct {
+	key     string
+	session *Session
+}
+
+// checkCallSession validates the session key of the call of the given method when the
+// method is one of sessionMethods. It returns the context which holds the session of
+// the call (or the unchanged context when no session is required).
+func checkCallSession(ctx context.Context, store SessionStore, method string) (context.Context,
+	error) {
+	if !sessionMethods[method] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get(SESSION_KEY_METADATA)
+	if len(keys) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "session key is missing")
+	}
+	session, err := store.LoadSession(keys[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid session key")
+	}
+
+	return context.WithValue(ctx, sessionContextKey{}, &callSession{
+		key:     keys[0],
+		session: session,
+	}), nil
+}
+
+// sessionInterceptor returns a gRPC interceptor which validates the session key on the
+// unary calls of sessionMethods. The calls with a missing, expired or logged out session
+// key are refused, otherwise the session is passed to the handler in the context.
+func sessionInterceptor(store SessionStore) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := checkCallSession(ctx, store, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// sessionServerStream is a server stream whose context holds the session of the call.
+type sessionServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *sessionServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// sessionStreamInterceptor returns a gRPC interceptor which validates the session key on
+// the streaming calls of sessionMethods (see sessionInterceptor).
+func sessionStreamInterceptor(store SessionStore) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		ctx, err := checkCallSession(stream.Context(), store, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &sessionServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// getCallSession returns the session of the call validated by sessionInterceptor.
+func getCallSession(ctx context.Context) (*callSession, error) {
+	s, ok := ctx.Value(sessionContextKey{}).(*callSession)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "session key is missing")
+	}
+
+	return s, nil
+}
+
+// newSession generates a session key and stores the session of the user who proved
+// the credentials creds. The session expires after the time given in the configuration.
+func (s *Server) newSession(creds []*SessionCred) (*string, error) {
+	sessionKey, err := s.GenerateSessionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	session := NewSession(creds, time.Now().Add(config.LoadSessionTTL()))
+	if err := s.Sessions.StoreSession(*sessionKey, session); err != nil {
+		return nil, err
+	}
+
+	return sessionKey, nil
+}
+
+// GetSession returns the data of the session whose key is given in the metadata.
+func (s *Server) GetSession(ctx context.Context, _ *empty.Empty) (*pb.SessionInfo, error) {
+	cs, err := getCallSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]*pb.SessionCred, len(cs.session.Creds))
+	for i, c := range cs.session.Creds {
+		attrs := make([]*pb.SessionAttr, 0, len(c.Attrs))
+		for name, val := range c.Attrs {
+			attrs = append(attrs, &pb.SessionAttr{
+				Name:  name,
+				Value: val,
+			})
+		}
+		creds[i] = &pb.SessionCred{
+			OrgName: c.OrgName,
+			Attrs:   attrs,
+			Policy:  c.Policy,
+		}
+	}
+
+	return &pb.SessionInfo{
+		Creds:  creds,
+		Expiry: cs.session.Expiry.Unix(),
+	}, nil
+}
+
+// Logout ends the session whose key is given in the metadata.
+func (s *Server) Logout(ctx context.Context, _ *empty.Empty) (*empty.Empty, error) {
+	cs, err := getCallSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Sessions.DeleteSession(cs.key); err != nil {
+		s.Logger.Debug(err)
+		return nil, status.Error(codes.Internal, "error when ending the session")
+	}
+	s.Logger.Info("Session ended")
+
+	return &empty.Empty{}, nil
+}
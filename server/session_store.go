@@ -0,0 +1,191 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package server
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// Session holds what the server learned about the user who obtained the session key
+// by proving the possession of credentials.
+type Session struct {
+	Creds  []*SessionCred
+	Expiry time.Time
+}
+
+// SessionCred describes a credential proved when the session was established.
+type SessionCred struct {
+	OrgName string
+	// Attrs are the revealed known attributes (mapped by attribute names)
+	Attrs map[string]string
+	// Policy is the verification policy (in JSON format) satisfied by the credential
+	Policy string
+}
+
+func NewSession(creds []*SessionCred, expiry time.Time) *Session {
+	return &Session{
+		Creds:  creds,
+		Expiry: expiry,
+	}
+}
+
+// IsExpired returns true when the session has expired.
+func (s *Session) IsExpired() bool {
+	return time.Now().After(s.Expiry)
+}
+
+// hasCred returns true when a credential of the organization orgName was proved in
+// the session.
+func (s *Session) hasCred(orgName string) bool {
+	for _, c := range s.Creds {
+		if strings.EqualFold(c.OrgName, orgName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Session) MarshalBinary() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *Session) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, s)
+}
+
+// SessionStore maps session keys to sessions.
+// LoadSession returns an error when there is no session for the key or when
+// the session has expired. DeleteSession ends the session (for example on logout).
+type SessionStore interface {
+	StoreSession(string, *Session) error
+	LoadSession(string) (*Session, error)
+	DeleteSession(string) error
+}
+
+// sessionExpiry is an entry of the queue of sessions ordered by their expiry.
+type sessionExpiry struct {
+	key    string
+	expiry time.Time
+}
+
+// sessionQueue is a min-heap of sessions by their expiry (see container/heap).
+type sessionQueue []sessionExpiry
+
+func (q sessionQueue) Len() int            { return len(q) }
+func (q sessionQueue) Less(i, j int) bool  { return q[i].expiry.Before(q[j].expiry) }
+func (q sessionQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *sessionQueue) Push(x interface{}) { *q = append(*q, x.(sessionExpiry)) }
+func (q *sessionQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
+
+// MemSessionStore keeps the sessions in memory. Expired sessions are removed when
+// a new session is stored - only the sessions which have expired are visited, as they
+// are taken from a queue ordered by expiry (like in cl.MemNonceStore).
+type MemSessionStore struct {
+	sessions map[string]*Session
+	queue    sessionQueue
+	mutex    sync.Mutex
+}
+
+func NewMemSessionStore() *MemSessionStore {
+	return &MemSessionStore{
+		sessions: make(map[string]*Session),
+	}
+}
+
+func (m *MemSessionStore) StoreSession(key string, s *Session) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := time.Now()
+	for len(m.queue) > 0 && now.After(m.queue[0].expiry) {
+		e := heap.Pop(&m.queue).(sessionExpiry)
+		// the session may have been deleted (and a session with the same key stored again)
+		if v, ok := m.sessions[e.key]; ok && v.Expiry.Equal(e.expiry) {
+			delete(m.sessions, e.key)
+		}
+	}
+	m.sessions[key] = s
+	heap.Push(&m.queue, sessionExpiry{key: key, expiry: s.Expiry})
+
+	return nil
+}
+
+func (m *MemSessionStore) LoadSession(key string) (*Session, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	s, ok := m.sessions[key]
+	if !ok || s.IsExpired() {
+		return nil, fmt.Errorf("session does not exist or has expired")
+	}
+
+	return s, nil
+}
+
+func (m *MemSessionStore) DeleteSession(key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.sessions, key)
+
+	return nil
+}
+
+// sessionKey returns the key of the redis entry holding the session
+// with the session key key.
+func sessionKey(key string) string {
+	return "session:" + key
+}
+
+// StoreSession stores the session, which is removed by redis when it expires.
+func (c *RedisClient) StoreSession(key string, s *Session) error {
+	return c.Set(sessionKey(key), s, time.Until(s.Expiry)).Err()
+}
+
+func (c *RedisClient) LoadSession(key string) (*Session, error) {
+	r, err := c.Get(sessionKey(key)).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("session does not exist or has expired")
+	} else if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := s.UnmarshalBinary([]byte(r)); err != nil {
+		return nil, err
+	}
+	if s.IsExpired() {
+		return nil, fmt.Errorf("session does not exist or has expired")
+	}
+
+	return &s, nil
+}
+
+func (c *RedisClient) DeleteSession(key string) error {
+	return c.Del(sessionKey(key)).Err()
+}
@@ -0,0 +1,500 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package wallet implements a client-side store of credentials. Credentials of
+// different schemes (CL, pseudonym system) are stored together with the secrets
+// of the holder and the public keys of the issuers in a single file, which is
+// encrypted with a key derived from a passphrase.
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/crypto/ec"
+	"github.com/xlab-si/emmy/crypto/ecpseudsys"
+	"github.com/xlab-si/emmy/crypto/pseudsys"
+)
+
+// FileVersion is the version of the wallet file format.
+const FileVersion = 1
+
+// KDFIterations is the number of PBKDF2 iterations used to derive the
+// encryption key of new wallets.
+const KDFIterations = 100000
+
+const saltLen = 16
+
+// Types of credentials held in the wallet.
+const (
+	CLType             = "cl"
+	PseudonymsysType   = "pseudonymsys"
+	PseudonymsysECType = "pseudonymsys_ec"
+)
+
+// CLCred is a CL credential together with the credential manager, which holds
+// the attributes, the secrets of the holder and the public key of the issuer.
+type CLCred struct {
+	Cred        *cl.Cred
+	CredManager *cl.CredManager
+}
+
+// PseudonymsysCred is a credential of the pseudonym system (modular arithmetic)
+// together with the nym it was issued to, the secret of the holder and the public
+// key of the issuing organization.
+type PseudonymsysCred struct {
+	OrgName    string
+	UserSecret *big.Int
+	Nym        *pseudsys.Nym
+	Cred       *pseudsys.Cred
+	PubKey     *pseudsys.PubKey
+}
+
+// PseudonymsysECCred is a credential of the pseudonym system (elliptic curves)
+// together with the nym it was issued to, the secret of the holder and the public
+// key of the issuing organization.
+type PseudonymsysECCred struct {
+	OrgName    string
+	Curve      ec.Curve
+	UserSecret *big.Int
+	Nym        *ecpseudsys.Nym
+	Cred       *ecpseudsys.Cred
+	PubKey     *ecpseudsys.PubKey
+}
+
+// Credential is an entry in the wallet. Type tells which of CL, Pseudonymsys
+// and PseudonymsysEC is set.
+type Credential struct {
+	Name           string              `json:"name"`
+	Type           string              `json:"type"`
+	CL             *CLCred             `json:"cl,omitempty"`
+	Pseudonymsys   *PseudonymsysCred   `json:"pseudonymsys,omitempty"`
+	PseudonymsysEC *PseudonymsysECCred `json:"pseudonymsys_ec,omitempty"`
+}
+
+func NewCLCredential(name string, cred *cl.Cred, credManager *cl.CredManager) *Credential {
+	return &Credential{
+		Name: name,
+		Type: CLType,
+		CL: &CLCred{
+			Cred:        cred,
+			CredManager: credManager,
+		},
+	}
+}
+
+func NewPseudonymsysCredential(name, orgName string, userSecret *big.Int, nym *pseudsys.Nym,
+	cred *pseudsys.Cred, pubKey *pseudsys.PubKey) *Credential {
+	return &Credential{
+		Name: name,
+		Type: PseudonymsysType,
+		Pseudonymsys: &PseudonymsysCred{
+			OrgName:    orgName,
+			UserSecret: userSecret,
+			Nym:        nym,
+			Cred:       cred,
+			PubKey:     pubKey,
+		},
+	}
+}
+
+func NewPseudonymsysECCredential(name, orgName string, curve ec.Curve, userSecret *big.Int,
+	nym *ecpseudsys.Nym, cred *ecpseudsys.Cred, pubKey *ecpseudsys.PubKey) *Credential {
+	return &Credential{
+		Name: name,
+		Type: PseudonymsysECType,
+		PseudonymsysEC: &PseudonymsysECCred{
+			OrgName:    orgName,
+			Curve:      curve,
+			UserSecret: userSecret,
+			Nym:        nym,
+			Cred:       cred,
+			PubKey:     pubKey,
+		},
+	}
+}
+
+// validate checks that the credential has a name and holds the data of its type.
+func (c *Credential) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("credential name cannot be empty")
+	}
+
+	var ok bool
+	switch c.Type {
+	case CLType:
+		ok = c.CL != nil && c.CL.Cred != nil && c.CL.CredManager != nil
+	case PseudonymsysType:
+		ok = c.Pseudonymsys != nil && c.Pseudonymsys.Cred != nil
+	case PseudonymsysECType:
+		ok = c.PseudonymsysEC != nil && c.PseudonymsysEC.Cred != nil
+	default:
+		return fmt.Errorf("unsupported credential type %s", c.Type)
+	}
+	if !ok {
+		return fmt.Errorf("credential %s does not hold %s credential data", c.Name, c.Type)
+	}
+
+	return nil
+}
+
+// contents is the plaintext of the wallet file.
+type contents struct {
+	Selected string        `json:"selected"`
+	Creds    []*Credential `json:"creds"`
+}
+
+// walletFile is the on-disk format of the wallet. Data holds the contents
+// encrypted with AES-GCM under the key derived from the passphrase by PBKDF2
+// (HMAC-SHA256) with the given salt and number of iterations.
+type walletFile struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Iterations int    `json:"iterations"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// Wallet holds credentials in an encrypted file. Changes (adding, updating,
+// deleting and selecting credentials) are written to the file immediately.
+type Wallet struct {
+	path       string
+	key        []byte
+	salt       []byte
+	iterations int
+	selected   string
+	creds      map[string]*Credential
+	mux        sync.Mutex
+}
+
+// Create creates a new empty wallet at path, protected by passphrase. An existing
+// file is never overwritten.
+func Create(path, passphrase string) (*Wallet, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("wallet %s already exists", path)
+	}
+
+	salt, err := randomBytes(saltLen)
+	if err != nil {
+		return nil, err
+	}
+	w := &Wallet{
+		path:       path,
+		key:        deriveKey(passphrase, salt, KDFIterations),
+		salt:       salt,
+		iterations: KDFIterations,
+		creds:      make(map[string]*Credential),
+	}
+	if err := w.save(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Open opens the wallet at path and decrypts it with the key derived from passphrase.
+func Open(path, passphrase string) (*Wallet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f walletFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("error when reading wallet %s: %v", path, err)
+	}
+	if f.Version != FileVersion {
+		return nil, fmt.Errorf("wallet %s has unsupported version %d", path, f.Version)
+	}
+	if f.Iterations < 1 {
+		return nil, fmt.Errorf("wallet %s has invalid key derivation parameters", path)
+	}
+
+	key := deriveKey(passphrase, f.Salt, f.Iterations)
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("wallet %s is corrupted", path)
+	}
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Data, additionalData(&f))
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase or corrupted wallet %s", path)
+	}
+
+	var c contents
+	if err := json.Unmarshal(plaintext, &c); err != nil {
+		return nil, fmt.Errorf("error when reading wallet %s: %v", path, err)
+	}
+
+	w := &Wallet{
+		path:       path,
+		key:        key,
+		salt:       f.Salt,
+		iterations: f.Iterations,
+		selected:   c.Selected,
+		creds:      make(map[string]*Credential, len(c.Creds)),
+	}
+	for _, cred := range c.Creds {
+		if err := cred.validate(); err != nil {
+			return nil, fmt.Errorf("error when reading wallet %s: %v", path, err)
+		}
+		w.creds[cred.Name] = cred
+	}
+
+	return w, nil
+}
+
+// List returns the credentials in the wallet ordered by their names.
+func (w *Wallet) List() []*Credential {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	creds := make([]*Credential, 0, len(w.creds))
+	for _, c := range w.creds {
+		creds = append(creds, c)
+	}
+	sort.Slice(creds, func(i, j int) bool {
+		return creds[i].Name < creds[j].Name
+	})
+
+	return creds
+}
+
+// Get returns the credential with the given name.
+func (w *Wallet) Get(name string) (*Credential, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	c, ok := w.creds[name]
+	if !ok {
+		return nil, fmt.Errorf("no credential %s in the wallet", name)
+	}
+
+	return c, nil
+}
+
+// Add adds a new credential to the wallet.
+func (w *Wallet) Add(c *Credential) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if _, ok := w.creds[c.Name]; ok {
+		return fmt.Errorf("credential %s already exists in the wallet", c.Name)
+	}
+
+	return w.modify(func() {
+		w.creds[c.Name] = c
+	})
+}
+
+// Update replaces the credential with the name of c, for example after the
+// credential was updated by the issuer or the witness of a CL credential changed.
+func (w *Wallet) Update(c *Credential) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if _, ok := w.creds[c.Name]; !ok {
+		return fmt.Errorf("no credential %s in the wallet", c.Name)
+	}
+
+	return w.modify(func() {
+		w.creds[c.Name] = c
+	})
+}
+
+// Delete removes the credential with the given name from the wallet.
+func (w *Wallet) Delete(name string) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if _, ok := w.creds[name]; !ok {
+		return fmt.Errorf("no credential %s in the wallet", name)
+	}
+
+	return w.modify(func() {
+		delete(w.creds, name)
+		if w.selected == name {
+			w.selected = ""
+		}
+	})
+}
+
+// Select marks the credential with the given name as the one to be used
+// by default (see Selected).
+func (w *Wallet) Select(name string) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if _, ok := w.creds[name]; !ok {
+		return fmt.Errorf("no credential %s in the wallet", name)
+	}
+
+	return w.modify(func() {
+		w.selected = name
+	})
+}
+
+// Selected returns the credential which was selected by Select.
+func (w *Wallet) Selected() (*Credential, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if w.selected == "" {
+		return nil, fmt.Errorf("no credential is selected")
+	}
+
+	return w.creds[w.selected], nil
+}
+
+// modify applies change and writes the wallet, the change is reverted when
+// the wallet cannot be written.
+func (w *Wallet) modify(change func()) error {
+	selected := w.selected
+	creds := make(map[string]*Credential, len(w.creds))
+	for name, c := range w.creds {
+		creds[name] = c
+	}
+
+	change()
+	if err := w.save(); err != nil {
+		w.selected, w.creds = selected, creds
+		return err
+	}
+
+	return nil
+}
+
+// save encrypts the contents of the wallet and writes them to a temporary file,
+// which then replaces the wallet file.
+func (w *Wallet) save() error {
+	c := &contents{
+		Selected: w.selected,
+		Creds:    make([]*Credential, 0, len(w.creds)),
+	}
+	for _, cred := range w.creds {
+		c.Creds = append(c.Creds, cred)
+	}
+	sort.Slice(c.Creds, func(i, j int) bool {
+		return c.Creds[i].Name < c.Creds[j].Name
+	})
+	plaintext, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(w.key)
+	if err != nil {
+		return err
+	}
+	nonce, err := randomBytes(gcm.NonceSize())
+	if err != nil {
+		return err
+	}
+	f := &walletFile{
+		Version:    FileVersion,
+		Salt:       w.salt,
+		Iterations: w.iterations,
+		Nonce:      nonce,
+	}
+	f.Data = gcm.Seal(nil, f.Nonce, plaintext, additionalData(f))
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(w.path), filepath.Base(w.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), w.path)
+}
+
+// additionalData binds the key derivation parameters to the encrypted contents.
+func additionalData(f *walletFile) []byte {
+	return []byte(fmt.Sprintf("%d:%x:%d", f.Version, f.Salt, f.Iterations))
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives a 256-bit key from passphrase by PBKDF2 with HMAC-SHA256
+// (a single output block of PBKDF2 is needed).
+func deriveKey(passphrase string, salt []byte, iterations int) []byte {
+	prf := hmac.New(sha256.New, []byte(passphrase))
+	prf.Write(salt)
+	prf.Write([]byte{0, 0, 0, 1})
+	u := prf.Sum(nil)
+	key := make([]byte, len(u))
+	copy(key, u)
+	for i := 1; i < iterations; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2017 XLAB d.o.o.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package wallet
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xlab-si/emmy/crypto/cl"
+	"github.com/xlab-si/emmy/crypto/pseudsys"
+	"github.com/xlab-si/emmy/crypto/schnorr"
+)
+
+func TestDeriveKey(t *testing.T) {
+	// PBKDF2-HMAC-SHA256 test vectors
+	vectors := []struct {
+		iterations int
+		key        string
+	}{
+		{1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	}
+	for _, v := range vectors {
+		key := deriveKey("password", []byte("salt"), v.iterations)
+		assert.Equal(t, v.key, hex.EncodeToString(key))
+	}
+}
+
+func TestWallet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "wallet.json")
+
+	params := cl.GetDefaultParamSizes()
+	attrCount := cl.NewAttrCount(2, 1, 0)
+	org, err := cl.NewOrg(params, cl.NewAttrCount(2, 1, 1))
+	require.NoError(t, err)
+
+	rawCred := cl.NewRawCred(attrCount)
+	_ = rawCred.AddStrAttr("Name", "Jack", true)
+	_ = rawCred.AddStrAttr("Gender", "M", true)
+	_ = rawCred.AddInt64Attr("Age", 25, false)
+	credMgr, err := cl.NewCredManager(params, org.Keys.Pub, org.Keys.Pub.GenerateUserMasterSecret(),
+		rawCred)
+	require.NoError(t, err)
+	issueNonce, err := org.GetCredIssueNonce()
+	require.NoError(t, err)
+	credReq, err := credMgr.GetCredRequest(issueNonce)
+	require.NoError(t, err)
+	res, err := org.IssueCred(credReq, issueNonce)
+	require.NoError(t, err)
+	require.NoError(t, credMgr.SetWitness(res.Cred, res.Witness))
+
+	w, err := Create(path, "passphrase")
+	require.NoError(t, err)
+	_, err = Create(path, "passphrase")
+	assert.Error(t, err, "existing wallet should not be overwritten")
+
+	require.NoError(t, w.Add(NewCLCredential("cl", res.Cred, credMgr)))
+	assert.Error(t, w.Add(NewCLCredential("cl", res.Cred, credMgr)),
+		"credential with the same name should not be added twice")
+	nym := pseudsys.NewNym(big.NewInt(2), big.NewInt(3))
+	trans := schnorr.NewBlindedTrans(big.NewInt(4), big.NewInt(5), big.NewInt(6), big.NewInt(7))
+	pseudsysCred := pseudsys.NewCred(big.NewInt(8), big.NewInt(9), big.NewInt(10), big.NewInt(11),
+		trans, trans)
+	require.NoError(t, w.Add(NewPseudonymsysCredential("pseudsys", "org1", big.NewInt(1), nym,
+		pseudsysCred, pseudsys.NewPubKey(big.NewInt(12), big.NewInt(13)))))
+	require.NoError(t, w.Select("cl"))
+
+	_, err = Open(path, "wrong passphrase")
+	assert.Error(t, err, "wallet should not be opened with a wrong passphrase")
+
+	w, err = Open(path, "passphrase")
+	require.NoError(t, err)
+	creds := w.List()
+	require.Len(t, creds, 2)
+	assert.Equal(t, "cl", creds[0].Name)
+	assert.Equal(t, "pseudsys", creds[1].Name)
+	assert.Equal(t, pseudsysCred, creds[1].Pseudonymsys.Cred)
+	assert.Equal(t, nym, creds[1].Pseudonymsys.Nym)
+
+	// the restored credential manager can prove the possession of the credential
+	c, err := w.Selected()
+	require.NoError(t, err)
+	require.Equal(t, CLType, c.Type)
+	m := c.CL.CredManager
+	assert.Equal(t, credMgr.Nym, m.Nym)
+	nonce, err := org.GetProveCredNonce()
+	require.NoError(t, err)
+	revealed := []int{1}
+	proof, err := m.BuildProof(c.CL.Cred, revealed, []int{0}, nil, nonce)
+	require.NoError(t, err)
+	verified, _, err := org.ProveCred(proof, nil, nonce)
+	require.NoError(t, err)
+	assert.True(t, verified, "credential from the wallet not valid")
+
+	// update and delete
+	c.Name = "unknown"
+	assert.Error(t, w.Update(c), "only existing credentials can be updated")
+	c.Name = "cl"
+	require.NoError(t, w.Update(c))
+	require.NoError(t, w.Delete("cl"))
+	_, err = w.Selected()
+	assert.Error(t, err, "deleted credential should not be selected")
+
+	w, err = Open(path, "passphrase")
+	require.NoError(t, err)
+	assert.Len(t, w.List(), 1)
+	_, err = w.Get("cl")
+	assert.Error(t, err)
+}